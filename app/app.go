@@ -1,42 +1,128 @@
 package app
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/ynmhu/YnM-Go/chanlog"
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/control"
+	"github.com/ynmhu/YnM-Go/httpapi"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logindex"
+	"github.com/ynmhu/YnM-Go/scheduler"
+	"github.com/ynmhu/YnM-Go/sdnotify"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
 type App struct {
-	config        *config.Config
+	live          *config.Live // az éppen érvényes config (!rehash cseréli)
 	bot           *irc.Client
 	pluginManager *PluginManager
 	eventHandler  *EventHandler
-	logger        *Logger
+	chanlog       *chanlog.Logger
+	logIndex      *logindex.Index
+	control       *control.Server
+	httpAPI       *httpapi.Server
+	webSessions   *httpapi.Sessions
+	storage       *storage.DB
+	backupMu      sync.Mutex // a !backup és az időzített mentés ne fusson egyszerre
+	stopOnce      sync.Once  // a leállítás egyszer fut (jel és !restart egyszerre is jöhet)
+	started       time.Time
 }
 
 func New(cfg *config.Config) *App {
 	return &App{
-		config: cfg,
+		live:    config.NewLive(cfg),
+		started: time.Now(),
 	}
 }
 
+// cfg az éppen érvényes config
+func (a *App) cfg() *config.Config {
+	return a.live.Get()
+}
+
 func (a *App) Run() error {
+	// Socket átadásos újraindítás: az előző folyamat kapcsolata és listenerei
+	handover := handoverFiles()
+
 	// Előkészítés
 	if err := a.initialize(); err != nil {
 		return err
 	}
 
-	// Bot indítása
-	if err := a.bot.Connect(); err != nil {
+	// systemd (Type=notify): READY=1, STATUS=, watchdog
+	a.setupSystemd()
+
+	// Bot indítása (vagy az átvett kapcsolat folytatása)
+	if handover != nil {
+		if err := a.adoptSession(handover); err != nil {
+			return err
+		}
+	} else if err := a.bot.Connect(); err != nil {
 		return err
 	}
 	defer a.bot.Disconnect()
 
+	// Vezérlő socket (ynm-go send)
+	if path := a.cfg().ControlSocketPath(); path != "" {
+		a.control = control.NewServer(path)
+		a.control.Handle("send", a.handleControlSend)
+		// az előző folyamattól vagy a systemd socket aktiválásból (FileDescriptorName=control)
+		// kapott listener elsőbbséget kap
+		listener, err := handoverListener(handover, "control")
+		if listener == nil && err == nil {
+			listener, err = sdnotify.Listener("control")
+		}
+		if err != nil {
+			log.Printf("⚠️ Az átadott vezérlő socket nem használható: %v", err)
+		}
+		if listener != nil {
+			a.control.StartListener(listener)
+			defer a.control.Close()
+		} else if err := a.control.Start(); err != nil {
+			log.Printf("⚠️ Vezérlő socket nem indult: %v", err)
+			a.control = nil
+		} else {
+			defer a.control.Close()
+		}
+	}
+
+	// HTTP admin API (http.enabled)
+	if addr := a.cfg().HTTPListen(); addr != "" {
+		a.httpAPI = httpapi.NewServer(addr, a.cfg().HTTP.Token)
+		a.webSessions = httpapi.NewSessions()
+		a.registerHTTPAPI(a.httpAPI)
+		a.registerWebPages(a.httpAPI)
+		listener, err := handoverListener(handover, "http")
+		if err != nil {
+			log.Printf("⚠️ Az átadott HTTP port nem használható: %v", err)
+		}
+		if listener != nil {
+			a.httpAPI.StartListener(listener)
+		} else {
+			err = a.httpAPI.Start()
+		}
+		if err != nil && listener == nil {
+			log.Printf("⚠️ HTTP API nem indult: %v", err)
+			a.httpAPI = nil
+		} else {
+			a.pluginManager.webSessions = a.webSessions
+			defer a.httpAPI.Close()
+		}
+	}
+
+	// az előző folyamat ezután lép ki
+	a.finishHandover(handover)
+
 	// Graceful shutdown
 	a.setupGracefulShutdown()
 
@@ -55,23 +141,62 @@ func (a *App) initialize() error {
 	}
 
 	// Log mappa létrehozása
-	if err := os.MkdirAll(a.config.LogDir, 0o755); err != nil {
+	if err := os.MkdirAll(a.cfg().LogDir, 0o755); err != nil {
+		return err
+	}
+
+	// Fordítások; hibás katalógus esetén a beépítettek maradnak
+	if err := loadCatalogs(a.cfg().I18n.Dir); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+
+	// Közös adatbázis; a régi JSON és SQLite fájlok tartalmát egyszer átveszi
+	db, err := storage.Open(a.cfg().StoragePath())
+	if err != nil {
 		return err
 	}
+	a.storage = db
+	if _, err := db.Import(storage.LegacySources(a.cfg())); err != nil {
+		log.Printf("⚠️ Régi adatok átvétele részben sikertelen: %v", err)
+	}
 
 	// Komponensek inicializálása
-	a.bot = irc.NewClient(a.config)
-	a.logger = NewLogger(a.config.LogDir)
-	a.pluginManager = NewPluginManager()
-	a.eventHandler = NewEventHandler(a.bot, a.config, a.pluginManager, a.logger)
+	a.bot = irc.NewClient(a.cfg())
+	a.pluginManager = NewPluginManager(a.live, db)
+	chanLog, err := chanlog.New(chanlog.Options{
+		Dir:    a.cfg().LogDir,
+		Config: a.cfg().ChannelLog,
+		Nick:   a.bot.GetNick,
+		Enabled: func(channel string) bool {
+			return a.pluginManager.ctx.SettingBool(channel, "chanlog.enabled")
+		},
+	})
+	if err != nil {
+		return err
+	}
+	a.chanlog = chanLog
+	a.pluginManager.chanlog = chanLog
+
+	// Keresőindex (!grep); hiba esetén a bot nélküle is elindul
+	if path := a.cfg().LogIndexPath(); path != "" {
+		if a.logIndex, err = logindex.Open(path); err != nil {
+			log.Printf("❌ Naplóindex nem nyitható meg, a !grep nem elérhető: %v", err)
+		}
+		a.pluginManager.logIndex = a.logIndex
+	}
+
+	a.eventHandler = NewEventHandler(a.bot, a.live, a.pluginManager, a.chanlog)
 
 	// Event handlerek beállítása
 	a.eventHandler.Setup()
 
 	// Pluginok regisztrálása
-	if err := a.pluginManager.RegisterAll(a.bot, a.config); err != nil {
+	if err := a.pluginManager.RegisterAll(a.bot, a.cfg()); err != nil {
 		return err
 	}
+	a.pluginManager.adminPlugin.OnRestart = a.hotRestart
+	a.pluginManager.adminPlugin.OnShutdown = a.shutdown
+	a.pluginManager.adminPlugin.OnBackup = a.createBackup
 
 	// Időzített pluginok indítása
 	a.startScheduledTasks()
@@ -79,26 +204,130 @@ func (a *App) initialize() error {
 	return nil
 }
 
+// validateConfig a kézzel összerakott (nem config.Load-dal betöltött) configot is ellenőrzi
 func (a *App) validateConfig() error {
-	if a.config.LogDir == "" {
-		log.Fatal("Log könyvtár nincs megadva a configban!")
+	return a.cfg().Validate()
+}
+
+func (a *App) startScheduledTasks() {
+	// A pluginok OnTick hívása percenként, a közös ütemezőn
+	err := a.pluginManager.Scheduler().Add(scheduler.Job{
+		Name:   "plugins:tick",
+		Spec:   "* * * * *",
+		Missed: scheduler.MissedSkip,
+		Run: func() error {
+			a.pluginManager.HandleTick(a.bot)
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Plugin tick ütemezési hiba: %v", err)
 	}
-	if a.config.ConsoleChannel == "" {
-		log.Fatal("A 'console_channel' nincs megadva a config.yaml‑ben!")
+
+	// A csatornanaplók előző napi fájljainak lezárása és tömörítése. Óránként
+	// fut, így a scheduler.timezone eltérése sem késlelteti a napváltást.
+	err = a.pluginManager.Scheduler().Add(scheduler.Job{
+		Name:   "chanlog:rotate",
+		Spec:   "0 * * * *",
+		Missed: scheduler.MissedSkip,
+		Run: func() error {
+			a.chanlog.Rotate()
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Csatornanapló forgatás ütemezési hiba: %v", err)
+	}
+
+	// A parancskorlátozó lejárt hívásnyilvántartásainak takarítása
+	err = a.pluginManager.Scheduler().Add(scheduler.Job{
+		Name:   "ratelimit:sweep",
+		Spec:   "*/10 * * * *",
+		Missed: scheduler.MissedSkip,
+		Run: func() error {
+			a.pluginManager.limiter.Sweep()
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Korlátozó takarítás ütemezési hiba: %v", err)
+	}
+
+	// Időzített mentés (backup.schedule); a leállás alatt elmaradt egyszer pótlódik
+	if spec := a.cfg().Backup.Schedule; spec != "" {
+		err = a.pluginManager.Scheduler().Add(scheduler.Job{
+			Name:   "backup",
+			Spec:   spec,
+			Missed: scheduler.MissedOnce,
+			Run: func() error {
+				_, err := a.createBackup(i18n.Get(i18n.Default))
+				return err
+			},
+		})
+		if err != nil {
+			log.Printf("❌ Mentés ütemezési hiba: %v", err)
+		}
+	}
+
+	// A naplóindex percenként felveszi az új sorokat; induláskor a háttérben
+	// a meglévő naplókat is feldolgozza
+	if a.logIndex != nil {
+		go a.syncLogIndex()
+		err = a.pluginManager.Scheduler().Add(scheduler.Job{
+			Name:   "logindex:sync",
+			Spec:   "* * * * *",
+			Missed: scheduler.MissedSkip,
+			Run: func() error {
+				a.syncLogIndex()
+				return nil
+			},
+		})
+		if err != nil {
+			log.Printf("❌ Naplóindex ütemezési hiba: %v", err)
+		}
 	}
-	return nil
 }
 
-func (a *App) startScheduledTasks() {
-	// Időzített plugin ticker
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
+func (a *App) syncLogIndex() {
+	added, err := a.logIndex.Sync(a.cfg().LogDir)
+	if err != nil {
+		log.Printf("⚠️ Naplóindex hiba: %v", err)
+	}
+	if added > 0 {
+		appLog.Debug("Naplóindex frissítve", "sorok", added)
+	}
+}
 
-		for range ticker.C {
-			a.pluginManager.HandleTick(a.bot)
+// handleControlSend a "ynm-go send <cél> <szöveg>" kérése
+func (a *App) handleControlSend(req control.Request) error {
+	sent, err := a.sendText(req.Target, req.Text)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ Vezérlő üzenet → %s (%d sor)", req.Target, sent)
+	return nil
+}
+
+// sendText a többsoros szöveget soronként küldi a célnak (vezérlő socket, HTTP API)
+func (a *App) sendText(target, text string) (int, error) {
+	if target == "" || strings.ContainsAny(target, " \r\n") {
+		return 0, fmt.Errorf("hibás cél: %q", target)
+	}
+	if !a.bot.IsConnected() {
+		return 0, fmt.Errorf("a bot nincs csatlakozva")
+	}
+	sent := 0
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
 		}
-	}()
+		a.bot.SendMessage(target, line)
+		sent++
+	}
+	if sent == 0 {
+		return 0, fmt.Errorf("üres üzenet")
+	}
+	return sent, nil
 }
 
 func (a *App) setupGracefulShutdown() {
@@ -108,12 +337,41 @@ func (a *App) setupGracefulShutdown() {
 	go func() {
 		<-sigChan
 		log.Println("🛑 Leállítási jel érkezett...")
-		
-		// Pluginok leállítása
-		a.pluginManager.Shutdown()
-		
-		// Bot leállítása
-		a.bot.Disconnect()
+		sdnotify.Stopping("Leállás...")
+		a.shutdown()
 		os.Exit(0)
 	}()
+}
+
+// shutdown a normál leállítás: a vezérlő socket és a HTTP API, a pluginok, az
+// ütemező, az adatbázis és a naplók lezárása, végül az IRC kapcsolat bontása
+// (leállási jelre és a socket átadás nélküli !restart előtt)
+func (a *App) shutdown() {
+	a.stopOnce.Do(func() {
+		if a.control != nil {
+			a.control.Close()
+		}
+		if a.httpAPI != nil {
+			a.httpAPI.Close()
+		}
+		a.shutdownComponents()
+
+		// Bot leállítása
+		a.bot.Disconnect()
+	})
+}
+
+// shutdownComponents leállítja a pluginokat és az ütemezőt, és lezárja az
+// adatbázist és a naplókat (leálláskor és socket átadásos újraindításkor)
+func (a *App) shutdownComponents() {
+	// Pluginok leállítása
+	a.pluginManager.Shutdown()
+	if err := a.storage.Close(); err != nil {
+		log.Printf("❌ Adatbázis lezárási hiba: %v", err)
+	}
+
+	a.chanlog.Close()
+	if a.logIndex != nil {
+		a.logIndex.Close()
+	}
 }
\ No newline at end of file
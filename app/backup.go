@@ -0,0 +1,27 @@
+package app
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/ynmhu/YnM-Go/backup"
+	"github.com/ynmhu/YnM-Go/i18n"
+)
+
+// createBackup elkészíti a mentést (!backup és a backup.schedule feladat); a
+// válasz az admin parancsnak szól, loc nyelvén
+func (a *App) createBackup(loc *i18n.Locale) (string, error) {
+	a.backupMu.Lock()
+	defer a.backupMu.Unlock()
+
+	res, err := backup.Create(a.storage, backup.OptionsFromConfig(a.cfg()))
+	if res == nil {
+		log.Printf("❌ Mentési hiba: %v", err)
+		return "", err
+	}
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	log.Printf("✅ Mentés kész: %s (%d fájl, %d KB)", res.Path, len(res.Manifest.Files), res.Size/1024)
+	return loc.T("admin.backup_saved", filepath.Base(res.Path), len(res.Manifest.Files), res.Size/1024), nil
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ynmhu/YnM-Go/chanlog"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logindex"
+)
+
+const (
+	grepMaxHits    = 20  // ennyi találat kerül a lapozóba
+	grepMaxTextLen = 300 // a találat szövege eddig a hosszig (bájt)
+)
+
+func isGrepCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!grep" || strings.HasPrefix(text, "!grep ")
+}
+
+// handleGrepCommand: !grep [#csatorna] [nick:x] [since:7d] <keresés>
+// Csak azokban a csatornákban keres, ahol a kérdező (és a bot) bent van;
+// a találatok privátban, lapozva mennek ki.
+func (pm *PluginManager) handleGrepCommand(msg irc.Message) string {
+	loc := pm.ctx.LocaleFor(msg)
+	if pm.logIndex == nil || pm.chanlog == nil {
+		return loc.T("grep.disabled")
+	}
+	nick := strings.Split(msg.Sender, "!")[0]
+
+	q, channel, err := parseGrepArgs(loc, strings.Fields(msg.Text)[1:])
+	if err != nil {
+		return loc.T("grep.invalid", err, loc.T("grep.usage"))
+	}
+
+	allowed := pm.chanlog.ChannelsOf(nick)
+	if channel != "" {
+		found := false
+		for _, ch := range allowed {
+			found = found || strings.EqualFold(ch, channel)
+		}
+		if !found {
+			return loc.T("grep.not_member", channel)
+		}
+		allowed = []string{channel}
+	}
+	if len(allowed) == 0 {
+		return loc.T("grep.no_channels")
+	}
+	q.Channels = allowed
+
+	hits, err := pm.logIndex.Search(q)
+	if err != nil {
+		return loc.T("grep.failed", err)
+	}
+	if len(hits) == 0 {
+		return loc.T("grep.none", q.Text)
+	}
+
+	lines := []string{loc.N("grep.hits", len(hits), q.Text)}
+	for _, h := range hits {
+		lines = append(lines, formatGrepHit(loc, h))
+	}
+	pm.sendPaged(nick, loc, lines)
+
+	if strings.HasPrefix(msg.Channel, "#") || strings.HasPrefix(msg.Channel, "&") {
+		return loc.N("grep.sent", len(hits), nick)
+	}
+	return ""
+}
+
+// parseGrepArgs szétválasztja a szűrőket (#csatorna, nick:x, since:7d) és a keresett szavakat
+func parseGrepArgs(loc *i18n.Locale, args []string) (logindex.Query, string, error) {
+	q := logindex.Query{Limit: grepMaxHits}
+	channel := ""
+	var terms []string
+	for i, arg := range args {
+		lower := strings.ToLower(arg)
+		switch {
+		case i == 0 && (strings.HasPrefix(arg, "#") || strings.HasPrefix(arg, "&")):
+			channel = arg
+		case strings.HasPrefix(lower, "nick:") && len(arg) > len("nick:"):
+			q.Nick = arg[len("nick:"):]
+		case strings.HasPrefix(lower, "since:"):
+			d, ok := parseIgnoreDuration(arg[len("since:"):])
+			if !ok {
+				return q, "", errors.New(loc.T("grep.bad_since", arg))
+			}
+			q.Since = time.Now().Add(-d)
+		default:
+			terms = append(terms, arg)
+		}
+	}
+	if len(terms) == 0 {
+		return q, "", errors.New(loc.T("grep.no_text"))
+	}
+	q.Text = strings.Join(terms, " ")
+	return q, channel, nil
+}
+
+func formatGrepHit(loc *i18n.Locale, h logindex.Hit) string {
+	text := h.Text
+	if len(text) > grepMaxTextLen {
+		cut := grepMaxTextLen
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "…"
+	}
+	stamp := h.Time.Format("2006-01-02 15:04")
+	switch h.Type {
+	case chanlog.TypeAction:
+		return fmt.Sprintf("[%s] %s * %s %s", stamp, h.Channel, h.Nick, text)
+	case chanlog.TypeNotice:
+		return fmt.Sprintf("[%s] %s -%s- %s", stamp, h.Channel, h.Nick, text)
+	case chanlog.TypeTopic:
+		return loc.T("grep.topic", stamp, h.Channel, h.Nick, text)
+	}
+	return fmt.Sprintf("[%s] %s <%s> %s", stamp, h.Channel, h.Nick, text)
+}
@@ -3,68 +3,48 @@ package app
 import (
 	"fmt"
 	"log"
-	"os"
 	"time"
 
+	"github.com/ynmhu/YnM-Go/chanlog"
 	"github.com/ynmhu/YnM-Go/config"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logging"
 )
 
-// Logger struktúra és metódusok
-type Logger struct {
-	logDir string
-}
-
-func NewLogger(logDir string) *Logger {
-	return &Logger{
-		logDir: logDir,
-	}
-}
-
-func (l *Logger) LogMessage(msg irc.Message) {
-	date := time.Now().Format("2006-01-02")
-	logFile := fmt.Sprintf("%s/%s_%s.log", l.logDir, msg.Channel, date)
-	
-	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		log.Printf("Log fájl hiba: %v", err)
-		return
-	}
-	defer file.Close()
-	
-	logLine := fmt.Sprintf("[%s] <%s> %s\n",
-		time.Now().Format("15:04:05"),
-		msg.Sender,
-		msg.Text)
-		
-	if _, err := file.WriteString(logLine); err != nil {
-		log.Printf("Log írási hiba: %v", err)
-	}
-}
+// appLog az app komponens strukturált naplója (a log.Printf sorok is ide kerülnek)
+var appLog = logging.For("app")
 
 // EventHandler struktúra és metódusok
 type EventHandler struct {
 	bot                   *irc.Client
-	config                *config.Config
+	live                  *config.Live
 	pluginManager         *PluginManager
-	logger                *Logger
+	chanlog               *chanlog.Logger
 	loginSuccessHandled   bool
 }
 
-func NewEventHandler(bot *irc.Client, cfg *config.Config, pm *PluginManager, logger *Logger) *EventHandler {
+func NewEventHandler(bot *irc.Client, live *config.Live, pm *PluginManager, chanLog *chanlog.Logger) *EventHandler {
 	return &EventHandler{
 		bot:           bot,
-		config:        cfg,
+		live:          live,
 		pluginManager: pm,
-		logger:        logger,
+		chanlog:       chanLog,
 	}
 }
 
+// cfg az éppen érvényes config
+func (h *EventHandler) cfg() *config.Config {
+	return h.live.Get()
+}
+
 func (h *EventHandler) Setup() {
 	h.bot.OnConnect = h.handleConnect
 	h.bot.OnLoginSuccess = h.handleLoginSuccess
 	h.bot.OnLoginFailed = h.handleLoginFailed
 	h.bot.OnMessage = h.handleMessage
+	h.bot.OnEvent = h.handleEvent
+	h.bot.OnSend = h.chanlog.Sent
+	h.bot.OnNames = h.chanlog.Names
 }
 
 func (h *EventHandler) handleConnect() {
@@ -72,39 +52,39 @@ func (h *EventHandler) handleConnect() {
 
 	go func() {
 		time.Sleep(2 * time.Second)
-		h.bot.Join(h.config.ConsoleChannel)
+		h.bot.Join(h.cfg().ConsoleChannel)
 		time.Sleep(1 * time.Second)
 
 		log.Printf("DEBUG: AutoLogin=%v, AutoJoinWithoutLogin=%v, UseSASL=%v",
-			h.config.AutoLogin, h.config.AutoJoinWithoutLogin, h.config.UseSASL)
+			h.cfg().AutoLogin, h.cfg().AutoJoinWithoutLogin, h.cfg().UseSASL)
 
 		h.handleAuthenticationFlow()
 	}()
 }
 
 func (h *EventHandler) handleAuthenticationFlow() {
-	if h.config.UseSASL {
-		h.bot.SendMessage(h.config.ConsoleChannel, "🔑 SASL típusú azonosítás sikeresen létrejött.")
-	} else if h.config.AutoLogin {
-		h.bot.SendMessage(h.config.ConsoleChannel, "🔑 NickServ azonosítás folyamatban...")
+	if h.cfg().UseSASL {
+		h.bot.SendMessage(h.cfg().ConsoleChannel, "🔑 SASL típusú azonosítás sikeresen létrejött.")
+	} else if h.cfg().AutoLogin {
+		h.bot.SendMessage(h.cfg().ConsoleChannel, "🔑 NickServ azonosítás folyamatban...")
 		if err := h.bot.IdentifyNickServ(); err != nil {
 			log.Printf("NickServ azonosítás sikertelen: %v", err)
 		}
-	} else if h.config.AutoJoinWithoutLogin {
+	} else if h.cfg().AutoJoinWithoutLogin {
 		h.handleAutoJoinWithoutLogin()
 	} else {
-		h.bot.SendMessage(h.config.ConsoleChannel, "ℹ️ Minden automatikus funkció kikapcsolva. Csak console channelben vagyok.")
+		h.bot.SendMessage(h.cfg().ConsoleChannel, "ℹ️ Minden automatikus funkció kikapcsolva. Csak console channelben vagyok.")
 	}
 }
 
 func (h *EventHandler) handleAutoJoinWithoutLogin() {
-	h.bot.SendMessage(h.config.ConsoleChannel, "ℹ️ Nincs authentication, de autojoin engedélyezve — csatlakozás a csatornákhoz...")
-	for _, ch := range h.config.Channels {
-		if ch != h.config.ConsoleChannel {
+	h.bot.SendMessage(h.cfg().ConsoleChannel, "ℹ️ Nincs authentication, de autojoin engedélyezve — csatlakozás a csatornákhoz...")
+	for _, ch := range h.cfg().Channels {
+		if ch != h.cfg().ConsoleChannel {
 			h.bot.Join(ch)
 		}
 	}
-	h.bot.SendMessage(h.config.ConsoleChannel, "⚠️ Nincs azonosítás, így nem garantált minden funkció működése.")
+	h.bot.SendMessage(h.cfg().ConsoleChannel, "⚠️ Nincs azonosítás, így nem garantált minden funkció működése.")
 }
 
 func (h *EventHandler) handleLoginSuccess() {
@@ -117,29 +97,33 @@ func (h *EventHandler) handleLoginSuccess() {
 	log.Println("DEBUG: OnLoginSuccess - sikeres authentication, belépés a csatornákba")
 
 	// Ha már beléptünk autojoin_without_login-nal, ne csináljunk semmit
-	if h.config.AutoJoinWithoutLogin && !h.config.AutoLogin && !h.config.UseSASL {
+	if h.cfg().AutoJoinWithoutLogin && !h.cfg().AutoLogin && !h.cfg().UseSASL {
 		log.Println("DEBUG: OnLoginSuccess - már beléptünk autojoin_without_login-nal")
 		return
 	}
 
 	authMethod := h.getAuthMethod()
-	h.bot.SendMessage(h.config.ConsoleChannel, fmt.Sprintf("✅ Sikeres %s authentication, csatlakozom a csatornákhoz…", authMethod))
+	h.bot.SendMessage(h.cfg().ConsoleChannel, fmt.Sprintf("✅ Sikeres %s authentication, csatlakozom a csatornákhoz…", authMethod))
 
 	h.joinChannels()
 }
 
 func (h *EventHandler) handleLoginFailed(reason string) {
-	h.bot.SendMessage(h.config.ConsoleChannel, "❌ Authentication sikertelen: "+reason)
+	h.bot.SendMessage(h.cfg().ConsoleChannel, "❌ Authentication sikertelen: "+reason)
 
-	if h.config.AutoJoinWithoutLogin {
-		h.bot.SendMessage(h.config.ConsoleChannel, "ℹ️ Autojoin engedélyezve, belépés authentication nélkül...")
+	if h.cfg().AutoJoinWithoutLogin {
+		h.bot.SendMessage(h.cfg().ConsoleChannel, "ℹ️ Autojoin engedélyezve, belépés authentication nélkül...")
 		h.joinChannels()
-		h.bot.SendMessage(h.config.ConsoleChannel, "⚠️ Nincs authentication, így nem garantált minden funkció működése.")
+		h.bot.SendMessage(h.cfg().ConsoleChannel, "⚠️ Nincs authentication, így nem garantált minden funkció működése.")
 	}
 }
 
 func (h *EventHandler) handleMessage(msg irc.Message) {
-	fmt.Printf("IRC üzenet érkezett: [%s] <%s> %s\n", msg.Channel, msg.Sender, msg.Text)
+	// A figyelmen kívül hagyott küldő üzenete (ha a config kéri) a naplóba sem kerül
+	quiet := h.pluginManager.IgnoredInLogs(msg)
+	if !quiet {
+		appLog.Debug("IRC üzenet érkezett", "channel", msg.Channel, "sender", msg.Sender, "text", msg.Text)
+	}
 
 	// Plugin kezelés
 	if response := h.pluginManager.HandleMessage(msg); response != "" {
@@ -147,13 +131,21 @@ func (h *EventHandler) handleMessage(msg irc.Message) {
 	}
 
 	// Üzenet naplózása
-	h.logger.LogMessage(msg)
+	if !quiet {
+		h.chanlog.Message(msg)
+	}
+}
+
+func (h *EventHandler) handleEvent(ev irc.Event) {
+	// A tagságot a napló az ignorált küldőknél is követi, csak nem írja ki
+	h.chanlog.Event(ev, h.pluginManager.IgnoredEventInLogs(ev))
+	h.pluginManager.HandleEvent(ev)
 }
 
 func (h *EventHandler) getAuthMethod() string {
-	if h.config.UseSASL {
+	if h.cfg().UseSASL {
 		return "SASL"
-	} else if h.config.AutoLogin {
+	} else if h.cfg().AutoLogin {
 		return "NickServ"
 	}
 	return "sima IRC"
@@ -162,8 +154,8 @@ func (h *EventHandler) getAuthMethod() string {
 func (h *EventHandler) joinChannels() {
 	go func() {
 		time.Sleep(500 * time.Millisecond)
-		for _, ch := range h.config.Channels {
-			if ch != h.config.ConsoleChannel {
+		for _, ch := range h.cfg().Channels {
+			if ch != h.cfg().ConsoleChannel {
 				h.bot.Join(ch)
 			}
 		}
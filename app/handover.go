@@ -0,0 +1,270 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/sdnotify"
+)
+
+const (
+	// handoverEnv az új folyamatnak átadott fájlleírók nevei a 3-as fd-től
+	// kezdve, kettősponttal elválasztva (pl. "irc:state:ready:control:http")
+	handoverEnv = "YNM_HANDOVER"
+	// handoverTimeout ennyit vár a régi folyamat az új indulására
+	handoverTimeout = 30 * time.Second
+	handoverReady   = "ok"
+)
+
+// handoverFiles az előző folyamattól kapott fájlleírók név szerint. Egyszer
+// olvassa ki, és törli a változót, hogy a következő újraindítás ne örökölje.
+func handoverFiles() map[string]*os.File {
+	names := os.Getenv(handoverEnv)
+	os.Unsetenv(handoverEnv)
+	if names == "" {
+		return nil
+	}
+	files := make(map[string]*os.File)
+	for i, name := range strings.Split(names, ":") {
+		if name == "" {
+			continue
+		}
+		// a pluginok és scriptek folyamatai ne örököljék
+		syscall.CloseOnExec(3 + i)
+		files[name] = os.NewFile(uintptr(3+i), name)
+	}
+	return files
+}
+
+// newHandoverCmd az új folyamat parancsa: ugyanez a program ugyanazokkal az
+// argumentumokkal és a saját kimenetekkel (a tesztek cserélik)
+var newHandoverCmd = func() (*exec.Cmd, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd, nil
+}
+
+// hotRestart újraindítás socket átadással: az új folyamat megkapja az élő IRC
+// kapcsolatot a munkamenet állapotával, a vezérlő socketet és a HTTP portot, így
+// a bot nem lép ki az IRC-ről. A régi folyamat csak az új visszajelzése után áll
+// le és lép ki; hiba esetén minden fut tovább, a függvény visszatér, és a hívó
+// normál (újracsatlakozó) újraindítást végez.
+func (a *App) hotRestart() error {
+	// systemd alatt csak Type=notify esetén: az új folyamatot MAINPID=-del jelentjük be
+	if sdnotify.UnderSystemd() && !sdnotify.Enabled() {
+		return errors.New("systemd alatt a socket átadáshoz Type=notify kell")
+	}
+	cmd, err := newHandoverCmd()
+	if err != nil {
+		return err
+	}
+
+	session, conn, err := a.bot.Detach()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fail := func(err error) error {
+		if rerr := a.bot.Reattach(session); rerr != nil {
+			log.Printf("❌ Az IRC munkamenet nem folytatható: %v", rerr)
+		}
+		return err
+	}
+
+	state, err := writeSessionState(session)
+	if err != nil {
+		return fail(err)
+	}
+	defer state.Close()
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return fail(err)
+	}
+	defer ready.Close()
+
+	// a listenerek másolatát adjuk át; a régi folyamat az átvételig kiszolgál
+	names := []string{"irc", "state", "ready"}
+	files := []*os.File{conn, state, readyW}
+	if a.control != nil {
+		if f, err := a.control.Handoff(); err != nil {
+			log.Printf("⚠️ A vezérlő socket nem adható át: %v", err)
+		} else {
+			names, files = append(names, "control"), append(files, f)
+			defer f.Close()
+		}
+	}
+	if a.httpAPI != nil {
+		if f, err := a.httpAPI.Handoff(); err != nil {
+			log.Printf("⚠️ A HTTP port nem adható át: %v", err)
+		} else {
+			names, files = append(names, "http"), append(files, f)
+			defer f.Close()
+		}
+	}
+
+	cmd.ExtraFiles = files
+	cmd.Env = handoverEnviron(names)
+	err = cmd.Start()
+	// az indítás blokkolóra állítja az átadott fájlleírókat, és ez a közös
+	// socketekre is hat: a régi folyamat kapcsolata és listenerei ne akadjanak el
+	setNonblock(files[:1]...)
+	setNonblock(files[3:]...)
+	readyW.Close()
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := waitHandoverReady(ready, handoverTimeout); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fail(fmt.Errorf("az új folyamat nem vette át a kapcsolatot: %v", err))
+	}
+
+	// az új folyamat átvette a kapcsolatot: a régi ütemezője, pluginjai és
+	// listenerei csak most állnak le
+	log.Printf("✅ Socket átadás kész, az új folyamat (PID %d) folytatja a munkamenetet", cmd.Process.Pid)
+	if a.control != nil {
+		a.control.Release()
+		a.control = nil
+	}
+	if a.httpAPI != nil {
+		a.httpAPI.Close()
+		a.httpAPI = nil
+	}
+	a.shutdownComponents()
+	if sdnotify.Enabled() {
+		sdnotify.Notify(fmt.Sprintf("MAINPID=%d", cmd.Process.Pid))
+	}
+	os.Exit(0)
+	return nil
+}
+
+// setNonblock visszaállítja a fájlleírók nem blokkoló módját (a fájlhoz tartozó
+// socketen, a Go-s *os.File Fd() hívása nélkül)
+func setNonblock(files ...*os.File) {
+	for _, f := range files {
+		rc, err := f.SyscallConn()
+		if err != nil {
+			continue
+		}
+		rc.Control(func(fd uintptr) {
+			if err := syscall.SetNonblock(int(fd), true); err != nil {
+				log.Printf("⚠️ A fájlleíró nem állítható vissza nem blokkolóra: %v", err)
+			}
+		})
+	}
+}
+
+// adoptSession az előző folyamattól kapott IRC kapcsolaton folytatja a munkamenetet
+func (a *App) adoptSession(files map[string]*os.File) error {
+	conn, state := files["irc"], files["state"]
+	if conn == nil || state == nil {
+		return errors.New("hiányzó IRC kapcsolat vagy munkamenet állapot")
+	}
+	var session irc.Session
+	err := json.NewDecoder(state).Decode(&session)
+	state.Close()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("hibás munkamenet állapot: %v", err)
+	}
+	if err := a.bot.Adopt(conn, &session); err != nil {
+		return err
+	}
+	log.Printf("✅ Átvett IRC kapcsolat: %s @ %s, %d csatorna, %d kimenő sor",
+		session.Nick, session.Server, len(session.Channels), len(session.Queue))
+	return nil
+}
+
+// finishHandover jelzi a régi folyamatnak, hogy az új elindult, és átvette a kapcsolatot
+func (a *App) finishHandover(files map[string]*os.File) {
+	ready := files["ready"]
+	if ready == nil {
+		return
+	}
+	if _, err := io.WriteString(ready, handoverReady); err != nil {
+		log.Printf("⚠️ Az előző folyamat értesítése sikertelen: %v", err)
+	}
+	ready.Close()
+	// a 001 nem jön újra, így a READY=1-et itt küldjük
+	sdnotify.Ready(a.systemdStatus())
+	a.bot.SendMessage(a.cfg().ConsoleChannel, a.consoleLocale().T("handover.done"))
+}
+
+// writeSessionState a munkamenet állapotát egy törölt ideiglenes fájlba írja,
+// amelyet csak a nyitott fájlleíró ér el
+func writeSessionState(session *irc.Session) (*os.File, error) {
+	f, err := os.CreateTemp("", "ynm-handover-*.json")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	if err := json.NewEncoder(f).Encode(session); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// handoverEnviron a saját környezet az átadott fájlleírók neveivel; a
+// WATCHDOG_PID a régi folyamatra szól, az új nem örökölheti
+func handoverEnviron(names []string) []string {
+	env := []string{handoverEnv + "=" + strings.Join(names, ":")}
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, handoverEnv+"=") || strings.HasPrefix(kv, "WATCHDOG_PID=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// waitHandoverReady megvárja az új folyamat jelzését; ha az új folyamat kilép,
+// a cső bezárul, és hibát ad
+func waitHandoverReady(ready *os.File, timeout time.Duration) error {
+	result := make(chan error, 1)
+	go func() {
+		buf := make([]byte, len(handoverReady))
+		_, err := io.ReadFull(ready, buf)
+		if err == nil && string(buf) != handoverReady {
+			err = fmt.Errorf("váratlan válasz: %q", buf)
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(timeout):
+		return errors.New("időtúllépés")
+	}
+}
+
+// handoverListener az előző folyamattól kapott listener (nil, ha nincs)
+func handoverListener(files map[string]*os.File, name string) (net.Listener, error) {
+	f := files[name]
+	if f == nil {
+		return nil, nil
+	}
+	defer f.Close()
+	return net.FileListener(f)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/control"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/irctest"
+)
+
+// Ha az új folyamat nem jelez vissza, a régi változatlanul fut tovább: a
+// munkamenet visszaáll, a vezérlő socket él, a komponensek nem állnak le
+// (a nil pluginManager leállítása pánikolna)
+func TestHotRestartFailure(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	t.Setenv("INVOCATION_ID", "")
+	// a tesztprogram maga, teszt nélkül: visszajelzés nélkül kilép (a kimenete elvész)
+	orig := newHandoverCmd
+	newHandoverCmd = func() (*exec.Cmd, error) {
+		return exec.Command(os.Args[0], "-test.run=^$"), nil
+	}
+	t.Cleanup(func() { newHandoverCmd = orig })
+
+	srv := irctest.NewServer(t)
+	bot := irctest.NewBot(t, srv, nil)
+	bot.Handle(func(msg irc.Message) string { return "pong" })
+
+	path := filepath.Join(t.TempDir(), "control.sock")
+	ctl := control.NewServer(path)
+	if err := ctl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ctl.Close() })
+
+	a := &App{live: config.NewLive(bot.Config), bot: bot.Client, control: ctl}
+	if err := a.hotRestart(); err == nil {
+		t.Fatal("a sikertelen átadás nem adott hibát")
+	}
+
+	if !bot.IsConnected() {
+		t.Error("a munkamenet nem állt vissza")
+	}
+	if got := bot.Say(t, "alice", "#test", "ping"); len(got) != 1 || got[0] != "pong" {
+		t.Errorf("az átadás után nem dolgozza fel az üzeneteket: %q", got)
+	}
+	if a.control != ctl || !control.Running(path) {
+		t.Error("a vezérlő socket leállt")
+	}
+}
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/httpapi"
+	"github.com/ynmhu/YnM-Go/logging"
+	"github.com/ynmhu/YnM-Go/metrics"
+)
+
+// registerHTTPAPI a HTTP API végpontjai: olvasás (állapot, csatornák, pluginok,
+// ütemező, hibák, Prometheus mérőszámok) és műveletek (üzenet, join/part, rehash)
+func (a *App) registerHTTPAPI(s *httpapi.Server) {
+	s.HandlePublic("GET /health", func(*http.Request) (interface{}, error) {
+		return map[string]bool{"ok": true, "connected": a.bot.IsConnected()}, nil
+	})
+
+	s.Handle("GET /api/status", a.apiStatus)
+	s.Handle("GET /api/channels", a.apiChannels)
+	s.Handle("GET /api/plugins", a.apiPlugins)
+	s.Handle("GET /api/jobs", a.apiJobs)
+	s.Handle("GET /api/errors", func(*http.Request) (interface{}, error) {
+		return logging.RecentErrors(), nil
+	})
+
+	s.HandleHTTP("GET /metrics", metrics.Handler(), a.cfg().HTTP.PublicMetrics)
+
+	s.Handle("POST /api/send", a.apiSend)
+	s.Handle("POST /api/join", a.apiJoin)
+	s.Handle("POST /api/part", a.apiPart)
+	s.Handle("POST /api/rehash", a.apiRehash)
+}
+
+type apiStatus struct {
+	Nick      string    `json:"nick"`
+	Server    string    `json:"server"`
+	Connected bool      `json:"connected"`
+	LoggedIn  bool      `json:"logged_in"`
+	TLS       bool      `json:"tls"`
+	Lag       float64   `json:"lag_seconds"`
+	Started   time.Time `json:"started"`
+	Uptime    int64     `json:"uptime_seconds"`
+	Channels  int       `json:"channels"`
+	Plugins   int       `json:"plugins_running"`
+}
+
+func (a *App) apiStatus(*http.Request) (interface{}, error) {
+	running := 0
+	for _, info := range a.pluginManager.manager.List() {
+		if info.Running {
+			running++
+		}
+	}
+	return apiStatus{
+		Nick:      a.bot.GetNick(),
+		Server:    a.cfg().Server,
+		Connected: a.bot.IsConnected(),
+		LoggedIn:  a.bot.IsLoggedIn(),
+		TLS:       a.bot.IsTLS(),
+		Lag:       a.bot.Lag().Seconds(),
+		Started:   a.started,
+		Uptime:    int64(time.Since(a.started).Seconds()),
+		Channels:  len(a.bot.GetJoinedChannels()),
+		Plugins:   running,
+	}, nil
+}
+
+type apiChannel struct {
+	Name    string   `json:"name"`
+	Count   int      `json:"member_count"`
+	Members []string `json:"members"`
+}
+
+func (a *App) apiChannels(*http.Request) (interface{}, error) {
+	names := a.bot.GetJoinedChannels()
+	sort.Strings(names)
+	channels := make([]apiChannel, 0, len(names))
+	for _, name := range names {
+		members := a.chanlog.Members(name)
+		if members == nil {
+			members = []string{}
+		}
+		channels = append(channels, apiChannel{Name: name, Count: len(members), Members: members})
+	}
+	return channels, nil
+}
+
+type apiPlugin struct {
+	Name             string   `json:"name"`
+	Enabled          bool     `json:"enabled"`
+	Running          bool     `json:"running"`
+	Health           string   `json:"health"` // ok, disabled, failed (engedélyezve, de nem indult el)
+	DisabledChannels []string `json:"disabled_channels,omitempty"`
+}
+
+func (a *App) apiPlugins(*http.Request) (interface{}, error) {
+	infos := a.pluginManager.manager.List()
+	plugins := make([]apiPlugin, 0, len(infos))
+	for _, info := range infos {
+		health := "ok"
+		if !info.Enabled {
+			health = "disabled"
+		} else if !info.Running {
+			health = "failed"
+		}
+		plugins = append(plugins, apiPlugin{
+			Name: info.Name, Enabled: info.Enabled, Running: info.Running,
+			Health: health, DisabledChannels: info.DisabledChannels,
+		})
+	}
+	return plugins, nil
+}
+
+type apiJob struct {
+	Name       string     `json:"name"`
+	Spec       string     `json:"spec,omitempty"`
+	Next       *time.Time `json:"next,omitempty"`
+	LastRun    *time.Time `json:"last_run,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	Runs       int        `json:"runs"`
+	Paused     bool       `json:"paused"`
+	Running    bool       `json:"running"`
+}
+
+func (a *App) apiJobs(*http.Request) (interface{}, error) {
+	infos := a.pluginManager.Scheduler().List()
+	jobs := make([]apiJob, 0, len(infos))
+	for _, info := range infos {
+		jobs = append(jobs, apiJob{
+			Name: info.Name, Spec: info.Spec, Next: timeOrNil(info.Next), LastRun: timeOrNil(info.LastRun),
+			LastStatus: info.LastStatus, LastError: info.LastError,
+			Runs: info.Runs, Paused: info.Paused, Running: info.Running,
+		})
+	}
+	return jobs, nil
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (a *App) apiSend(r *http.Request) (interface{}, error) {
+	var req struct {
+		Target string `json:"target"`
+		Text   string `json:"text"`
+	}
+	if err := httpapi.DecodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	sent, err := a.sendText(req.Target, req.Text)
+	if err != nil {
+		return nil, httpapi.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	log.Printf("✅ HTTP API üzenet → %s (%d sor)", req.Target, sent)
+	return map[string]interface{}{"ok": true, "lines": sent}, nil
+}
+
+func (a *App) apiJoin(r *http.Request) (interface{}, error) {
+	channel, _, err := a.channelRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	a.bot.Join(channel)
+	return nil, nil
+}
+
+func (a *App) apiPart(r *http.Request) (interface{}, error) {
+	channel, reason, err := a.channelRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		a.bot.SendRaw("PART " + channel + " :" + reason)
+	} else {
+		a.bot.SendRaw("PART " + channel)
+	}
+	return nil, nil
+}
+
+// channelRequest a join/part kérés: {"channel": "#x", "reason": "..."}
+func (a *App) channelRequest(r *http.Request) (string, string, error) {
+	var req struct {
+		Channel string `json:"channel"`
+		Reason  string `json:"reason"`
+	}
+	if err := httpapi.DecodeJSON(r, &req); err != nil {
+		return "", "", err
+	}
+	if !strings.HasPrefix(req.Channel, "#") && !strings.HasPrefix(req.Channel, "&") ||
+		strings.ContainsAny(req.Channel, " ,\r\n") || strings.ContainsAny(req.Reason, "\r\n") {
+		return "", "", httpapi.Errorf(http.StatusBadRequest, "hibás csatorna: %q", req.Channel)
+	}
+	if !a.bot.IsConnected() {
+		return "", "", httpapi.Errorf(http.StatusServiceUnavailable, "a bot nincs csatlakozva")
+	}
+	return req.Channel, req.Reason, nil
+}
+
+func (a *App) apiRehash(*http.Request) (interface{}, error) {
+	result, err := a.pluginManager.Reload()
+	if err != nil {
+		log.Printf("❌ Config újratöltési hiba (HTTP API): %v", err)
+		return nil, httpapi.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	pending := dedupe(result.Pending)
+	live := []string{}
+	for _, key := range result.Changes.Keys(false) {
+		if !contains(pending, key) {
+			live = append(live, key)
+		}
+	}
+	if len(result.Changes) > 0 {
+		log.Printf("✅ Config újratöltve (HTTP API): %s", strings.Join(live, ", "))
+	}
+	return map[string]interface{}{
+		"changed":   live,
+		"restarted": result.Restarted,
+		"pending":   pending,
+		"errors":    result.Errors,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/chanlog"
+	"github.com/ynmhu/YnM-Go/httpapi"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/media"
+)
+
+// registerWebPages a böngészős oldalak: belépés (!weblogin link), kilépés, a
+// csatornanaplók (weblog.enabled) és a filmkérések listája (http.media_page)
+func (a *App) registerWebPages(s *httpapi.Server) {
+	s.HandleHTTP("GET /login", http.HandlerFunc(a.webLogin), true)
+	s.HandleHTTP("POST /logout", http.HandlerFunc(a.webLogout), true)
+
+	viewer := chanlog.NewViewer(a.chanlog, chanlog.WebOptions{
+		Channels: func() []string { return a.pluginManager.ctx.Channels("weblog.enabled") },
+		Redacted: func(channel string) []string {
+			return strings.FieldsFunc(a.pluginManager.ctx.Setting(channel, "weblog.redact"), func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+		},
+		Done: s.Done(),
+	})
+	s.HandleHTTP("GET /logs", http.HandlerFunc(viewer.ServeIndex), true)
+	s.HandleHTTP("GET /logs/{channel}", http.HandlerFunc(viewer.ServeChannel), true)
+	s.HandleHTTP("GET /logs/{channel}/events", http.HandlerFunc(viewer.ServeEvents), true)
+
+	if !a.cfg().HTTP.MediaPage {
+		return
+	}
+	var announce func(func(*i18n.Locale) string)
+	if ch := a.cfg().MovieRequestsChannel; ch != "" {
+		announce = func(text func(*i18n.Locale) string) {
+			a.bot.SendMessage(ch, text(a.pluginManager.ctx.Locale(ch)))
+		}
+	}
+	web := media.NewRequestsWeb(a.storage.Movies, a.webAdmin, announce)
+	s.HandleHTTP("GET /media", http.HandlerFunc(web.ServePage), true)
+	s.HandleHTTP("GET /media/requests.json", http.HandlerFunc(web.ServeJSON), true)
+	s.HandleHTTP("GET /media/requests.csv", http.HandlerFunc(web.ServeCSV), true)
+	s.HandlePublic("POST /media/requests/{pin}/complete", web.Complete)
+	s.HandlePublic("DELETE /media/requests/{pin}", web.Delete)
+}
+
+// webAdmin a módosító webes kérés küldője: az API token, vagy a !weblogin
+// munkamenet. Ez utóbbinál a nem GET kérésekhez az X-Requested-With fejléc is
+// kell, így más oldalról küldött űrlap nem élhet vissza a cookie-val.
+func (a *App) webAdmin(r *http.Request) (string, bool) {
+	if a.httpAPI.Authorized(r) {
+		return "api", true
+	}
+	user, ok := a.webSessions.User(r)
+	if !ok {
+		return "", false
+	}
+	if r.Method != http.MethodGet && r.Header.Get("X-Requested-With") == "" {
+		return "", false
+	}
+	return user, true
+}
+
+func (a *App) webLogin(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.webSessions.Login(w, r)
+	if !ok {
+		http.Error(w, "A belépő link lejárt vagy már fel lett használva; kérj újat: !weblogin", http.StatusForbidden)
+		return
+	}
+	log.Printf("✅ Webes belépés: %s", user)
+	next := r.URL.Query().Get("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+		next = "/media"
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+func (a *App) webLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Requested-With") == "" {
+		http.Error(w, "hiányzó X-Requested-With fejléc", http.StatusForbidden)
+		return
+	}
+	a.webSessions.Logout(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
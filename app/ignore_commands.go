@@ -0,0 +1,149 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/ignore"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+)
+
+// ignoreMiddleware a globálisan figyelmen kívül hagyott küldők üzeneteit eldobja,
+// mielőtt bármelyik plugin (vagy a korlátozó) megkapná. Az adminokra nem vonatkozik.
+func (pm *PluginManager) ignoreMiddleware(msg irc.Message, next func(irc.Message) string) string {
+	if pm.ignores.Ignored(msg.Sender, msg.Account, "") && !pm.isAdmin(msg.Sender) {
+		return ""
+	}
+	return next(msg)
+}
+
+// pluginIgnores a Manager számára: az adott plugin figyelmen kívül hagyja-e a küldőt
+func (pm *PluginManager) pluginIgnores(plugin, sender, account string) bool {
+	return pm.ignores.Ignored(sender, account, plugin) && !pm.isAdmin(sender)
+}
+
+// IgnoredInLogs true, ha a config szerint a figyelmen kívül hagyott küldő üzenetét naplózni sem kell
+func (pm *PluginManager) IgnoredInLogs(msg irc.Message) bool {
+	return pm.ignoreLogging.Load() && pm.ignores.Ignored(msg.Sender, msg.Account, "")
+}
+
+// IgnoredEventInLogs ugyanez a csatorna eseményekre (JOIN, PART, QUIT …)
+func (pm *PluginManager) IgnoredEventInLogs(ev irc.Event) bool {
+	return pm.ignoreLogging.Load() && ev.Sender != "" && pm.ignores.Ignored(ev.Sender, "", "")
+}
+
+func (pm *PluginManager) isAdmin(sender string) bool {
+	nick := strings.Split(sender, "!")[0]
+	return pm.adminPlugin != nil && pm.adminPlugin.GetAdminLevel(nick, sender) >= admin.AdminLevelAdmin
+}
+
+func isIgnoreCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!ignore" || strings.HasPrefix(text, "!ignore ")
+}
+
+// handleIgnoreCommand: !ignore add <maszk|$a:fiók> [időtartam] [plugin] [indok] | del <maszk> [plugin] | list
+func (pm *PluginManager) handleIgnoreCommand(msg irc.Message) string {
+	if !pm.isAdmin(msg.Sender) {
+		return ""
+	}
+	nick := strings.Split(msg.Sender, "!")[0]
+	loc := pm.ctx.LocaleFor(msg)
+
+	usage := loc.T("ignore.usage")
+	parts := strings.Fields(msg.Text)
+	if len(parts) < 2 {
+		return usage
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "add":
+		if len(parts) < 3 {
+			return usage
+		}
+		entry := ignore.Entry{Mask: parts[2], AddedBy: nick}
+		rest := parts[3:]
+		if len(rest) > 0 {
+			if d, ok := parseIgnoreDuration(rest[0]); ok {
+				entry.Until = time.Now().Add(d)
+				rest = rest[1:]
+			}
+		}
+		if len(rest) > 0 && pm.manager.lookup(strings.ToLower(rest[0])) != nil {
+			entry.Plugin = strings.ToLower(rest[0])
+			rest = rest[1:]
+		}
+		entry.Reason = strings.Join(rest, " ")
+
+		if err := pm.ignores.Add(entry); err != nil {
+			return loc.T("common.error", err)
+		}
+		mask, _ := ignore.NormalizeMask(entry.Mask)
+		log.Printf("✅ Ignore: %s (%s, %s)", mask, ignoreScope(i18n.Get(i18n.Default), entry.Plugin), nick)
+		if !entry.Until.IsZero() {
+			return loc.T("ignore.added_until", mask, ignoreScope(loc, entry.Plugin), formatScheduleTime(entry.Until))
+		}
+		return loc.T("ignore.added", mask, ignoreScope(loc, entry.Plugin))
+
+	case "del", "remove":
+		if len(parts) < 3 {
+			return usage
+		}
+		plugin := ""
+		if len(parts) >= 4 {
+			plugin = parts[3]
+		}
+		removed, err := pm.ignores.Remove(parts[2], plugin)
+		if err != nil {
+			return loc.T("common.error", err)
+		}
+		if removed == 0 {
+			return loc.T("ignore.not_found", parts[2])
+		}
+		log.Printf("✅ Ignore törölve: %s (%s)", parts[2], nick)
+		return loc.N("ignore.removed", removed, parts[2])
+
+	case "list":
+		entries := pm.ignores.Entries()
+		if len(entries) == 0 {
+			return loc.T("ignore.empty")
+		}
+		items := make([]string, 0, len(entries))
+		for _, e := range entries {
+			item := fmt.Sprintf("%s [%s]", e.Mask, ignoreScope(loc, e.Plugin))
+			if !e.Until.IsZero() {
+				item += " → " + formatScheduleTime(e.Until)
+			}
+			if e.Reason != "" {
+				item += " – " + e.Reason
+			}
+			items = append(items, item)
+		}
+		return pm.sendList(msg.Channel, loc.T("ignore.list", len(entries)), items)
+	}
+	return usage
+}
+
+func ignoreScope(loc *i18n.Locale, plugin string) string {
+	if plugin == "" {
+		return loc.T("ignore.all_plugins")
+	}
+	return plugin
+}
+
+// parseIgnoreDuration a Go időtartamokon túl a napot (3d) és hetet (2w) is elfogadja
+func parseIgnoreDuration(s string) (time.Duration, bool) {
+	s = strings.ToLower(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n > 0 {
+			return time.Duration(n) * unit, true
+		}
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d > 0
+}
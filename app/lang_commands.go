@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+// loadCatalogs betölti a fordításokat (beépítettek + i18n.dir), és naplózza a
+// nyelvenként hiányzó kulcsokat
+func loadCatalogs(dir string) error {
+	if err := i18n.LoadDir(dir); err != nil {
+		return fmt.Errorf("fordítások betöltése (%s): %w", dir, err)
+	}
+	for _, lang := range i18n.Languages() {
+		if missing := i18n.Missing(lang); len(missing) > 0 {
+			log.Printf("⚠️ Hiányzó fordítások (%s): %d kulcs, pl. %s", lang, len(missing), missing[0])
+		}
+	}
+	return nil
+}
+
+func isLangCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!lang" || strings.HasPrefix(text, "!lang ")
+}
+
+// handleLangCommand: !lang [nyelv|reset] – a felhasználó saját nyelve (fiókhoz,
+// ennek hiányában nickhez kötve); elsőbbséget élvez a csatorna nyelvével szemben
+func (pm *PluginManager) handleLangCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	parts := strings.Fields(msg.Text)
+	langs := strings.Join(i18n.Languages(), ", ")
+
+	if len(parts) < 2 {
+		loc := pm.ctx.LocaleFor(msg)
+		own, err := pm.ctx.Storage.Languages.Get(nick, msg.Account)
+		if err != nil {
+			log.Printf("❌ Nyelv lekérdezési hiba: %v", err)
+			return loc.T("lang.error")
+		}
+		channel := pm.ctx.Locale(msg.Channel)
+		if own == "" {
+			return loc.T("lang.show_channel", channel.Lang(), channel.T("language.name"), langs)
+		}
+		return loc.T("lang.show", loc.Lang(), loc.T("language.name"), channel.Lang(), langs)
+	}
+
+	arg := strings.ToLower(parts[1])
+	if arg == "reset" || arg == "-" {
+		if _, err := pm.ctx.Storage.Languages.Delete(nick, msg.Account); err != nil {
+			log.Printf("❌ Nyelv törlési hiba: %v", err)
+			return pm.ctx.LocaleFor(msg).T("lang.error")
+		}
+		return pm.ctx.LocaleFor(msg).T("lang.reset", pm.ctx.Locale(msg.Channel).Lang())
+	}
+
+	if !i18n.Supported(arg) {
+		return pm.ctx.LocaleFor(msg).T("lang.unknown", parts[1], langs)
+	}
+	if err := pm.ctx.Storage.Languages.Set(nick, msg.Account, arg, time.Now()); err != nil {
+		log.Printf("❌ Nyelv mentési hiba: %v", err)
+		return pm.ctx.LocaleFor(msg).T("lang.error")
+	}
+	loc := i18n.Get(arg)
+	return loc.T("lang.set", loc.T("language.name"))
+}
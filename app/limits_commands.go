@@ -0,0 +1,251 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/ratelimit"
+)
+
+// beépített szabályok (a korábbi pluginonkénti tiltások: 3 hívás 5 perc alatt → 24 óra)
+var defaultRatePolicies = map[string]ratelimit.Policy{
+	"!ping":   {Window: 5 * time.Minute, Limit: 3, Penalties: []time.Duration{24 * time.Hour}},
+	"!nevnap": {Window: 5 * time.Minute, Limit: 3, Penalties: []time.Duration{24 * time.Hour}},
+}
+
+// ha a szabály nem ad meg cooldown-t, ezek a csatornás beállítások adják
+var cooldownSettings = map[string]string{
+	"!ping": "ping.cooldown",
+}
+
+// ratePolicies a configból feldolgozott szabályok
+type ratePolicies struct {
+	def      *ratelimit.Policy
+	commands map[string]ratelimit.Policy
+	channels map[string]map[string]config.RateLimitPolicy // csatorna (kisbetűs) → parancs → felülírás
+}
+
+func newRatePolicies(cfg config.RateLimitConfig) *ratePolicies {
+	rp := &ratePolicies{
+		commands: make(map[string]ratelimit.Policy),
+		channels: make(map[string]map[string]config.RateLimitPolicy),
+	}
+	for cmd, p := range defaultRatePolicies {
+		rp.commands[cmd] = p
+	}
+	if cfg.Default != nil {
+		p := mergeRatePolicy(ratelimit.Policy{}, *cfg.Default)
+		rp.def = &p
+	}
+	for cmd, override := range cfg.Commands {
+		cmd = "!" + normalizeCommand(cmd)
+		base, ok := rp.commands[cmd]
+		if !ok && rp.def != nil {
+			base = *rp.def
+		}
+		rp.commands[cmd] = mergeRatePolicy(base, override)
+	}
+	for channel, cmds := range cfg.Channels {
+		m := make(map[string]config.RateLimitPolicy)
+		for cmd, override := range cmds {
+			m["!"+normalizeCommand(cmd)] = override
+		}
+		rp.channels[strings.ToLower(channel)] = m
+	}
+	return rp
+}
+
+// mergeRatePolicy a megadott mezőkkel felülírja az alap szabályt
+func mergeRatePolicy(base ratelimit.Policy, c config.RateLimitPolicy) ratelimit.Policy {
+	if c.Window > 0 {
+		base.Window = c.Window.Std()
+	}
+	if c.Cooldown > 0 {
+		base.Cooldown = c.Cooldown.Std()
+	}
+	if c.Limit != 0 {
+		base.Limit = c.Limit
+	}
+	if c.Burst != 0 {
+		base.Burst = c.Burst
+	}
+	if len(c.Penalties) > 0 {
+		base.Penalties = make([]time.Duration, 0, len(c.Penalties))
+		for _, d := range c.Penalties {
+			base.Penalties = append(base.Penalties, d.Std())
+		}
+	}
+	return base
+}
+
+// policy az adott csatornán érvényes szabály; ok=false, ha a parancs nincs korlátozva
+func (pm *PluginManager) ratePolicy(channel, command string) (ratelimit.Policy, bool) {
+	rates := pm.rates.Load()
+	p, ok := rates.commands[command]
+	if !ok && rates.def != nil {
+		p, ok = *rates.def, true
+	}
+	if override, found := rates.channels[strings.ToLower(channel)][command]; found {
+		p, ok = mergeRatePolicy(p, override), true
+	}
+	if key, found := cooldownSettings[command]; found && p.Cooldown == 0 && pm.ctx != nil {
+		p.Cooldown = pm.ctx.SettingDuration(channel, key)
+	}
+	return p, ok && p.Active()
+}
+
+// rateLimitMiddleware minden "!" parancsot a közös korlátozón enged át.
+// A VIP-ek és adminok mentesek.
+func (pm *PluginManager) rateLimitMiddleware(msg irc.Message, next func(irc.Message) string) string {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") || len(fields[0]) < 2 {
+		return next(msg)
+	}
+	command := "!" + normalizeCommand(fields[0])
+
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin != nil && pm.adminPlugin.GetAdminLevel(nick, msg.Sender) >= admin.AdminLevelVIP {
+		return next(msg)
+	}
+
+	policy, _ := pm.ratePolicy(msg.Channel, command)
+	result := pm.limiter.Check(ratelimit.Identity(msg.Sender, msg.Account), nick, command, policy)
+	switch result.Verdict {
+	case ratelimit.Denied:
+		log.Printf("⚠️ Korlátozás: %s (%s) – %s", nick, msg.Sender, command)
+		return rateLimitReply(pm.ctx.LocaleFor(msg), result)
+	case ratelimit.Drop:
+		return ""
+	}
+	return next(msg)
+}
+
+// rateLimitReply a tiltás jelzése a felhasználó nyelvén: az új tiltás hossza,
+// vagy egy meglévő tiltásból hátralévő idő
+func rateLimitReply(loc *i18n.Locale, r ratelimit.Result) string {
+	if r.NewBan {
+		return loc.T("limits.banned", r.Command, loc.Duration(r.Penalty))
+	}
+	return loc.T("limits.wait", r.Command, loc.Duration(time.Until(r.Until)))
+}
+
+func isLimitsCommand(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToLower(fields[0]) {
+	case "!limits", "!unban", "!banlist":
+		return true
+	}
+	return false
+}
+
+// handleLimitsCommand: !limits [parancs] | !unban <nick|fiók|maszk> | !banlist
+func (pm *PluginManager) handleLimitsCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin == nil || pm.adminPlugin.GetAdminLevel(nick, msg.Sender) < admin.AdminLevelAdmin {
+		return ""
+	}
+
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+	switch strings.ToLower(parts[0]) {
+	case "!limits":
+		if len(parts) >= 2 {
+			command := "!" + normalizeCommand(parts[1])
+			policy, ok := pm.ratePolicy(msg.Channel, command)
+			if !ok {
+				return loc.T("limits.not_limited", command)
+			}
+			return command + ": " + formatRatePolicy(loc, policy)
+		}
+		rates := pm.rates.Load()
+		commands := make(map[string]bool)
+		for cmd := range rates.commands {
+			commands[cmd] = true
+		}
+		for cmd := range rates.channels[strings.ToLower(msg.Channel)] {
+			commands[cmd] = true
+		}
+		names := make([]string, 0, len(commands))
+		for cmd := range commands {
+			names = append(names, cmd)
+		}
+		sort.Strings(names)
+
+		var items []string
+		for _, cmd := range names {
+			if policy, ok := pm.ratePolicy(msg.Channel, cmd); ok {
+				items = append(items, cmd+": "+formatRatePolicy(loc, policy))
+			}
+		}
+		if rates.def != nil {
+			items = append(items, loc.T("limits.default", formatRatePolicy(loc, *rates.def)))
+		}
+		if len(items) == 0 {
+			return loc.T("limits.none")
+		}
+		return pm.sendList(msg.Channel, loc.T("limits.list", msg.Channel), items)
+
+	case "!unban":
+		if len(parts) < 2 {
+			return loc.T("limits.usage_unban")
+		}
+		lifted := pm.limiter.Unban(parts[1])
+		if len(lifted) == 0 {
+			return loc.T("limits.no_ban", parts[1])
+		}
+		var commands []string
+		for _, b := range lifted {
+			commands = append(commands, b.Command)
+		}
+		log.Printf("✅ Tiltás feloldva: %s (%s) – %s", parts[1], nick, strings.Join(commands, ", "))
+		return loc.T("limits.unbanned", parts[1], strings.Join(commands, ", "))
+
+	case "!banlist":
+		bans := pm.limiter.Bans()
+		if len(bans) == 0 {
+			return loc.T("limits.no_bans")
+		}
+		items := make([]string, 0, len(bans))
+		for _, b := range bans {
+			items = append(items, fmt.Sprintf("%s [%s] %s → %s (%d.)",
+				b.Nick, b.Key, b.Command, formatScheduleTime(b.Until), b.Strikes))
+		}
+		return pm.sendList(msg.Channel, loc.T("limits.bans", len(bans)), items)
+	}
+	return ""
+}
+
+func formatRatePolicy(loc *i18n.Locale, p ratelimit.Policy) string {
+	var parts []string
+	if p.Limit > 0 && p.Window > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%s", p.Limit, p.Window))
+	}
+	if p.Cooldown > 0 {
+		burst := p.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		parts = append(parts, fmt.Sprintf("cooldown %s (burst %d)", p.Cooldown, burst))
+	}
+	if p.Limit > 0 && p.Window > 0 {
+		penalties := make([]string, 0, len(p.Penalties))
+		for _, d := range p.Penalties {
+			penalties = append(penalties, d.String())
+		}
+		if len(penalties) == 0 {
+			penalties = append(penalties, (24 * time.Hour).String())
+		}
+		parts = append(parts, loc.T("limits.penalty", strings.Join(penalties, " → ")))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logging"
+)
+
+func isLogLevelCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!loglevel" || strings.HasPrefix(text, "!loglevel ")
+}
+
+// handleLogLevelCommand: !loglevel [komponens] [debug|info|warn|error|reset]
+// A futás közbeni szint a !rehash után is megmarad, a reset a configra állít vissza.
+func (pm *PluginManager) handleLogLevelCommand(msg irc.Message) string {
+	if !pm.isAdmin(msg.Sender) {
+		return ""
+	}
+	nick := strings.Split(msg.Sender, "!")[0]
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+
+	switch len(parts) {
+	case 1:
+		items := []string{loc.T("loglevel.base", logging.LevelName(logging.BaseLevel()))}
+		for _, c := range logging.Levels() {
+			item := c.Component + "=" + logging.LevelName(c.Level)
+			if c.Source == "runtime" {
+				item = loc.T("loglevel.manual", item)
+			}
+			items = append(items, item)
+		}
+		return pm.sendList(msg.Channel, loc.T("loglevel.list"), items)
+
+	case 2:
+		component := strings.ToLower(parts[1])
+		for _, c := range logging.Levels() {
+			if c.Component == component {
+				return fmt.Sprintf("%s: %s (%s)", component, logging.LevelName(c.Level), c.Source)
+			}
+		}
+		return loc.T("loglevel.default", component, logging.LevelName(logging.BaseLevel()))
+
+	case 3:
+		component, level := strings.ToLower(parts[1]), strings.ToLower(parts[2])
+		if err := logging.SetLevel(component, level); err != nil {
+			return loc.T("common.error", err)
+		}
+		if level == "reset" {
+			log.Printf("✅ Naplózási szint visszaállítva: %s (%s)", component, nick)
+			return loc.T("loglevel.reset", component)
+		}
+		log.Printf("✅ Naplózási szint: %s → %s (%s)", component, level, nick)
+		return loc.T("loglevel.set", component, level)
+	}
+	return loc.T("loglevel.usage")
+}
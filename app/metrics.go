@@ -0,0 +1,57 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/metrics"
+)
+
+var (
+	commandCalls = metrics.NewCounter("ynm_commands_total",
+		"A beépített és a routerben regisztrált parancsok hívásai.", "command")
+	commandErrors = metrics.NewCounter("ynm_command_errors_total",
+		"A hibával (❌) válaszolt parancsok.", "command")
+	commandDuration = metrics.NewHistogram("ynm_command_duration_seconds",
+		"A parancsok feldolgozási ideje a válaszig.", nil, "command")
+)
+
+// builtinCommands a beépített (a szöveget maguk elemző) pluginok és az app
+// parancsai; a routerben regisztráltakkal együtt csak ezek kapnak címkét
+var builtinCommands = map[string]bool{
+	// app
+	"!backup": true, "!get": true, "!grep": true, "!ignore": true, "!lang": true, "!limits": true,
+	"!loglevel": true, "!more": true, "!plugin": true, "!schedule": true, "!script": true,
+	"!set": true, "!unset": true, "!weblogin": true,
+	// admin
+	"!addadmin": true, "!admininfo": true, "!banlist": true, "!deladmin": true, "!die": true,
+	"!hello": true, "!help": true, "!listadmins": true, "!rehash": true, "!restart": true,
+	"!unban": true, "!whoami": true,
+	// ynm
+	"!delora": true, "!inbox": true, "!nevnap": true, "!ora": true, "!orak": true, "!ping": true,
+	"!seen": true, "!status": true, "!tell": true, "!vicc": true, "!vicc_refresh": true, "!vicc_stat": true,
+	// media és egyebek
+	"!del": true, "!film": true, "!keresek": true, "!kell": true, "!kisallat": true, "!ok": true,
+	"!tamagotchi": true,
+}
+
+// observeCommand egy "!parancs" üzenet feldolgozását rögzíti. A felhasználó
+// bármit beírhat, ezért csak a beépített és a routerben regisztrált parancsok
+// kapnak saját sorozatot (különben a címkék száma korlátlanul nőne).
+func (pm *PluginManager) observeCommand(text, reply string, start time.Time) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") {
+		return
+	}
+	command := strings.ToLower(fields[0])
+	if !builtinCommands[command] {
+		if rt, _ := pm.manager.router.match(text); rt == nil {
+			return
+		}
+	}
+	commandCalls.Inc(command)
+	commandDuration.ObserveSince(start, command)
+	if strings.HasPrefix(reply, "❌") {
+		commandErrors.Inc(command)
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+const (
+	pageSize = 5                // ennyi sor megy ki egyszerre privátban
+	pageTTL  = 15 * time.Minute // ennyi ideig kérhető le a folytatás (!more)
+)
+
+// pager a hosszú válaszok (pl. !grep találatok) privát lapozása: az első oldal
+// azonnal kimegy, a többit a felhasználó a !more paranccsal kéri le
+type pager struct {
+	mu      sync.Mutex
+	pending map[string]*pagedReply // kisbetűs nick → a még el nem küldött sorok
+}
+
+type pagedReply struct {
+	lines   []string
+	expires time.Time
+}
+
+func newPager() *pager {
+	return &pager{pending: make(map[string]*pagedReply)}
+}
+
+// next a nick következő oldala és a utána maradt sorok száma
+func (p *pager) next(nick string) ([]string, int) {
+	key := strings.ToLower(nick)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reply := p.pending[key]
+	if reply == nil || time.Now().After(reply.expires) {
+		delete(p.pending, key)
+		return nil, 0
+	}
+	n := min(pageSize, len(reply.lines))
+	page := reply.lines[:n]
+	reply.lines = reply.lines[n:]
+	if len(reply.lines) == 0 {
+		delete(p.pending, key)
+	}
+	return page, len(reply.lines)
+}
+
+// store lecseréli a nick lapozható válaszát
+func (p *pager) store(nick string, lines []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, reply := range p.pending {
+		if time.Now().After(reply.expires) {
+			delete(p.pending, key)
+		}
+	}
+	p.pending[strings.ToLower(nick)] = &pagedReply{lines: lines, expires: time.Now().Add(pageTTL)}
+}
+
+// sendPaged privátban elküldi a sorok első oldalát, a többit a !more-ra tartogatja
+func (pm *PluginManager) sendPaged(nick string, loc *i18n.Locale, lines []string) {
+	pm.pager.store(nick, lines)
+	pm.sendNextPage(nick, loc)
+}
+
+func (pm *PluginManager) sendNextPage(nick string, loc *i18n.Locale) bool {
+	page, rest := pm.pager.next(nick)
+	if page == nil {
+		return false
+	}
+	for _, line := range page {
+		pm.bot.SendMessage(nick, line)
+	}
+	if rest > 0 {
+		pm.bot.SendMessage(nick, loc.N("pager.more", rest))
+	}
+	return true
+}
+
+func isMoreCommand(text string) bool {
+	return strings.TrimSpace(text) == "!more"
+}
+
+// handleMoreCommand: !more – a lapozott válasz következő oldala (privátban)
+func (pm *PluginManager) handleMoreCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	loc := pm.ctx.LocaleFor(msg)
+	if !pm.sendNextPage(nick, loc) {
+		return loc.T("pager.none")
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+)
+
+// az admin plugin nem kapcsolható ki, különben a !plugin parancs sem működne
+const adminPluginName = "admin"
+
+func isPluginCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!plugin" || strings.HasPrefix(text, "!plugin ")
+}
+
+// handlePluginCommand: !plugin list | enable <név> [#csatorna] | disable <név> [#csatorna]
+func (pm *PluginManager) handlePluginCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin == nil || pm.adminPlugin.GetAdminLevel(nick, msg.Sender) < admin.AdminLevelAdmin {
+		return ""
+	}
+
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+	if len(parts) < 2 {
+		return loc.T("plugin.usage")
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "list":
+		return pm.pluginListText(loc)
+
+	case "enable", "disable":
+		if len(parts) < 3 {
+			return loc.T("plugin.usage_toggle", parts[1])
+		}
+		name := strings.ToLower(parts[2])
+		channel := ""
+		if len(parts) >= 4 {
+			channel = parts[3]
+			if !strings.HasPrefix(channel, "#") {
+				return loc.T("plugin.bad_channel")
+			}
+		}
+		enable := strings.ToLower(parts[1]) == "enable"
+
+		if name == adminPluginName && !enable {
+			return loc.T("plugin.admin_locked")
+		}
+		if err := pm.manager.SetEnabled(name, channel, enable); err != nil {
+			return loc.T("plugin.error", err)
+		}
+
+		key := "plugin.disabled"
+		if enable {
+			key = "plugin.enabled"
+		}
+		if channel != "" {
+			return loc.T(key+"_channel", name, channel)
+		}
+		return loc.T(key, name)
+	}
+
+	return loc.T("plugin.usage")
+}
+
+func (pm *PluginManager) pluginListText(loc *i18n.Locale) string {
+	var items []string
+	for _, info := range pm.manager.List() {
+		status := "✅"
+		if !info.Enabled {
+			status = "❌"
+		} else if !info.Running {
+			status = "⚠️" // engedélyezve, de nem sikerült elindítani
+		}
+		item := info.Name + " " + status
+		if len(info.DisabledChannels) > 0 {
+			item = loc.T("plugin.disabled_in", item, strings.Join(info.DisabledChannels, ", "))
+		}
+		items = append(items, item)
+	}
+	return loc.T("plugin.list", strings.Join(items, ", "))
+}
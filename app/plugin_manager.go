@@ -1,16 +1,30 @@
 package app
 
 import (
+	"fmt"
 	"log"
+	"strings"
 	"time"
-    "os"
-	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"github.com/ynmhu/YnM-Go/chanlog"
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/extplugin"
+	"github.com/ynmhu/YnM-Go/httpapi"
+	"github.com/ynmhu/YnM-Go/ignore"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logindex"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	"github.com/ynmhu/YnM-Go/plugins"
 	"github.com/ynmhu/YnM-Go/plugins/media"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
 		"github.com/ynmhu/YnM-Go/plugins/ynm"
+	"github.com/ynmhu/YnM-Go/pluginsdk"
+	"github.com/ynmhu/YnM-Go/ratelimit"
+	"github.com/ynmhu/YnM-Go/scheduler"
+	"github.com/ynmhu/YnM-Go/scripting"
+	"github.com/ynmhu/YnM-Go/settings"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
 // Plugin interfészek
@@ -19,31 +33,139 @@ type Plugin interface {
 	OnTick() []irc.Message
 }
 
-type ScheduledPlugin interface {
-	Start()
-	Stop()
-	Name() string
-}
+// PluginFactory létrehozza (és szükség esetén elindítja) a plugint.
+// Globális tiltás utáni újraengedélyezéskor újra meghívódik.
+type PluginFactory func() (Plugin, error)
 
 type ScheduledMessage = irc.Message
 
+// pluginEntry egy névvel regisztrált plugin
+type pluginEntry struct {
+	name    string
+	factory PluginFactory
+	plugin  Plugin // nil, ha globálisan tiltva van (erőforrások felszabadítva)
+}
+
 // Manager struktúra - alapvető plugin kezeléshez
 type Manager struct {
-	plugins []Plugin
+	mu      sync.RWMutex
+	entries []*pluginEntry
+	state   *PluginState
+	router  *Router
+
+	// ignored megmondja, hogy a küldőt az adott plugin figyelmen kívül hagyja-e
+	ignored func(plugin, sender, account string) bool
+
+	// hold a plugin létrehozása alatt visszatartja az ütemezett feladatokat
+	// (scheduler.Hold), hogy a konstruktorban felvett feladat ne fusson szűrő nélkül
+	hold func() (release func())
 }
 
-func NewManager() *Manager {
+func NewManager(state *PluginState) *Manager {
 	return &Manager{
-		plugins: make([]Plugin, 0),
+		entries: make([]*pluginEntry, 0),
+		state:   state,
+		router:  NewRouter(),
 	}
 }
 
-func (m *Manager) Register(plugin Plugin) {
-	m.plugins = append(m.plugins, plugin)
+// Register felveszi a plugint; ha nincs globálisan tiltva, azonnal létre is hozza.
+func (m *Manager) Register(name string, factory PluginFactory) error {
+	entry := &pluginEntry{name: name, factory: factory}
+
+	m.mu.Lock()
+	m.entries = append(m.entries, entry)
+	m.mu.Unlock()
+
+	if !m.state.GloballyEnabled(name) {
+		log.Printf("⏸️ %s plugin globálisan tiltva, nem indul el", name)
+		return nil
+	}
+	return m.start(entry)
+}
+
+func (m *Manager) start(entry *pluginEntry) error {
+	if m.hold != nil {
+		release := m.hold()
+		defer release()
+	}
+
+	plugin, err := entry.factory()
+	if err != nil {
+		return fmt.Errorf("%s plugin inicializálás hiba: %v", entry.name, err)
+	}
+
+	if filterable, ok := plugin.(pluginapi.ChannelFilterable); ok {
+		name := entry.name
+		filterable.SetChannelFilter(func(channel string) bool {
+			return m.state.Enabled(name, channel)
+		})
+	}
+
+	m.mu.Lock()
+	entry.plugin = plugin
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Manager) release(entry *pluginEntry) {
+	m.mu.Lock()
+	plugin := entry.plugin
+	entry.plugin = nil
+	m.mu.Unlock()
+
+	if plugin != nil {
+		releasePlugin(plugin)
+		m.router.UnregisterCommands(entry.name)
+		log.Printf("🛑 %s plugin leállítva", entry.name)
+	}
+}
+
+func (m *Manager) lookup(name string) *pluginEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, entry := range m.entries {
+		if entry.name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// active visszaadja a futó és az adott csatornán engedélyezett pluginokat.
+func (m *Manager) active(channel string) []Plugin {
+	return m.activeFor(channel, "", "")
+}
+
+// activeFor mint az active, de kihagyja azokat a pluginokat, amelyek a küldőt
+// figyelmen kívül hagyják (üres küldő esetén nincs szűrés).
+func (m *Manager) activeFor(channel, sender, account string) []Plugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plugins := make([]Plugin, 0, len(m.entries))
+	for _, entry := range m.entries {
+		if entry.plugin != nil && m.state.Enabled(entry.name, channel) && !m.ignores(entry.name, sender, account) {
+			plugins = append(plugins, entry.plugin)
+		}
+	}
+	return plugins
+}
+
+func (m *Manager) ignores(plugin, sender, account string) bool {
+	return sender != "" && m.ignored != nil && m.ignored(plugin, sender, account)
 }
 
 func (m *Manager) HandleMessage(msg irc.Message) string {
-	for _, plugin := range m.plugins {
+	// A routerben regisztrált parancsok a tulajdonos plugin engedélyezése szerint futnak
+	if rt, args := m.router.match(msg.Text); rt != nil && m.state.Enabled(rt.owner, msg.Channel) &&
+		!m.ignores(rt.owner, msg.Sender, msg.Account) {
+		if response := rt.handler(msg, args); response != "" {
+			return response
+		}
+	}
+
+	for _, plugin := range m.activeFor(msg.Channel, msg.Sender, msg.Account) {
 		if response := plugin.HandleMessage(msg); response != "" {
 			return response
 		}
@@ -51,29 +173,218 @@ func (m *Manager) HandleMessage(msg irc.Message) string {
 	return ""
 }
 
+// Observe minden üzenetet megmutat a megfigyelő pluginoknak (a válaszkereséstől függetlenül)
+func (m *Manager) Observe(msg irc.Message) {
+	for _, plugin := range m.activeFor(msg.Channel, msg.Sender, msg.Account) {
+		if observer, ok := plugin.(pluginapi.MessageObserver); ok {
+			observer.ObserveMessage(msg)
+		}
+	}
+}
+
+// HandleEvent a csatorna eseményeket az azokat fogadó pluginoknak adja tovább
+func (m *Manager) HandleEvent(ev irc.Event) {
+	for _, plugin := range m.activeFor(ev.Channel, ev.Sender, "") {
+		if handler, ok := plugin.(pluginapi.EventHandler); ok {
+			handler.HandleEvent(ev)
+		}
+	}
+}
+
 func (m *Manager) GetPlugins() []Plugin {
-	return m.plugins
+	return m.active("")
+}
+
+// SetEnabled globálisan vagy egy csatornán engedélyezi/tiltja a plugint.
+// Globális tiltáskor a plugin erőforrásai felszabadulnak, engedélyezéskor újra létrejön.
+func (m *Manager) SetEnabled(name, channel string, enabled bool) error {
+	entry := m.lookup(name)
+	if entry == nil {
+		return fmt.Errorf("nincs ilyen plugin: %s", name)
+	}
+	if err := m.state.Set(name, channel, enabled); err != nil {
+		return err
+	}
+	if channel != "" {
+		return nil
+	}
+
+	m.mu.RLock()
+	running := entry.plugin != nil
+	m.mu.RUnlock()
+
+	if !enabled && running {
+		m.release(entry)
+	}
+	if enabled && !running {
+		return m.start(entry)
+	}
+	return nil
+}
+
+// PluginInfo a !plugin list kimenetéhez
+type PluginInfo struct {
+	Name             string
+	Running          bool
+	Enabled          bool
+	DisabledChannels []string
+}
+
+func (m *Manager) List() []PluginInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]PluginInfo, 0, len(m.entries))
+	for _, entry := range m.entries {
+		infos = append(infos, PluginInfo{
+			Name:             entry.name,
+			Running:          entry.plugin != nil,
+			Enabled:          m.state.GloballyEnabled(entry.name),
+			DisabledChannels: m.state.DisabledChannels(entry.name),
+		})
+	}
+	return infos
+}
+
+// ReleaseAll minden futó plugint leállít
+func (m *Manager) ReleaseAll() {
+	m.mu.RLock()
+	entries := append([]*pluginEntry(nil), m.entries...)
+	m.mu.RUnlock()
+
+	for _, entry := range entries {
+		m.release(entry)
+	}
+}
+
+// pongHook a ping plugin a kliens PONG kezelőjével együtt: tiltáskor vagy
+// leállításkor a kezelő is törlődik, így a régi példány nem kap több PONG-ot
+type pongHook struct {
+	*ynm.PingPlugin
+	bot *irc.Client
+}
+
+func (h pongHook) Stop() {
+	h.bot.SetPongHandler(nil)
+}
+
+// releasePlugin lezárja a plugin adatbázisait, tickereit, goroutine-jait
+func releasePlugin(plugin Plugin) {
+	if p, ok := plugin.(interface{ Stop() }); ok {
+		p.Stop()
+	}
+	if p, ok := plugin.(interface{ Close() error }); ok {
+		if err := p.Close(); err != nil {
+			log.Printf("⚠️ Plugin lezárási hiba: %v", err)
+		}
+	}
+	if p, ok := plugin.(interface{ Shutdown() error }); ok {
+		if err := p.Shutdown(); err != nil {
+			log.Printf("⚠️ Plugin leállítási hiba: %v", err)
+		}
+	}
 }
 
 // PluginManager - magasabb szintű plugin kezelés az app-ban
 type PluginManager struct {
-	manager          *Manager
-	scheduledPlugins []ScheduledPlugin
+	live        *config.Live
+	reloadMu    sync.Mutex
+	bot         *irc.Client
+	manager     *Manager
+	state       *PluginState
+	ctx         *pluginapi.Context
+	adminPlugin *admin.AdminPlugin
+	scripts     *scripting.Engine
+	scheduler   *scheduler.Scheduler
+	limiter     *ratelimit.Limiter
+	rates       atomic.Pointer[ratePolicies]
+
+	ignores       *ignore.List
+	ignoreLogging atomic.Bool
+
+	// csatornanapló és keresőindex (!grep); az App állítja be
+	chanlog  *chanlog.Logger
+	logIndex *logindex.Index
+	pager    *pager
+
+	// a HTTP szerver böngészős munkamenetei (!weblogin); nil, ha nincs HTTP szerver
+	webSessions *httpapi.Sessions
+
+	failed []string // az induláskor hibával leállt pluginok (RegisterAll összesíti)
 }
 
-func NewPluginManager() *PluginManager {
-	return &PluginManager{
-		manager:          NewManager(),
-		scheduledPlugins: make([]ScheduledPlugin, 0),
+func NewPluginManager(live *config.Live, db *storage.DB) *PluginManager {
+	cfg := live.Get()
+	state := NewPluginState(cfg.DataPath("plugins.json"))
+	if err := state.Load(); err != nil {
+		log.Printf("❌ Plugin állapot betöltési hiba: %v", err)
+	}
+
+	store := settings.NewStore(cfg, cfg.DataPath("settings.json"))
+	if err := store.Load(); err != nil {
+		log.Printf("❌ Beállítások betöltési hiba: %v", err)
+	}
+
+	loc := time.Local
+	if cfg.Scheduler.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Scheduler.TimeZone); err != nil {
+			log.Printf("❌ Ismeretlen időzóna (%s), helyi idő használata: %v", cfg.Scheduler.TimeZone, err)
+			loc = time.Local
+		}
+	}
+	sched := scheduler.New(loc, cfg.DataPath("scheduler.json"))
+	sched.Start()
+
+	limiter := ratelimit.New(cfg.DataPath("bans.json"))
+	if err := limiter.Load(); err != nil {
+		log.Printf("❌ Tiltások betöltési hiba: %v", err)
+	}
+
+	ignores := ignore.New(cfg.DataPath("ignore.json"))
+	if err := ignores.Load(); err != nil {
+		log.Printf("❌ Ignore lista betöltési hiba: %v", err)
 	}
+
+	pm := &PluginManager{
+		live:      live,
+		manager:   NewManager(state),
+		state:     state,
+		ctx:       pluginapi.NewContext(store, sched, db, cfg.Scheduler.Jobs),
+		scheduler: sched,
+		limiter:   limiter,
+
+		ignores: ignores,
+		pager:   newPager(),
+	}
+	pm.rates.Store(newRatePolicies(cfg.RateLimit))
+	pm.ignoreLogging.Store(cfg.Ignore.ApplyToLogging)
+	pm.manager.ignored = pm.pluginIgnores
+	pm.manager.hold = sched.Hold
+	pm.manager.router.Use(pm.ignoreMiddleware)
+	pm.manager.router.Use(pm.rateLimitMiddleware)
+	return pm
+}
+
+// cfg az éppen érvényes config. A plugin factory-k is ezt olvassák, hogy a
+// config újratöltés utáni újraindítás már az új értékekkel történjen.
+func (pm *PluginManager) cfg() *config.Config {
+	return pm.live.Get()
 }
 
 func (pm *PluginManager) RegisterAll(bot *irc.Client, cfg *config.Config) error {
+	pm.bot = bot
+
 	// Admin plugin (először, mert mások függnek tőle)
-	adminPlugin := pm.registerAdminPlugin(bot, cfg)
+	adminPlugin, err := pm.registerAdminPlugin(bot, cfg)
+	if err != nil {
+		return err
+	}
 
 	// Core pluginok
-	pm.registerCorePlugins(bot, cfg, adminPlugin)
+	if err := pm.registerCorePlugins(bot, cfg, adminPlugin); err != nil {
+		return err
+	}
 
 	// Movie pluginok
 	pm.registerMoviePlugins(bot, cfg, adminPlugin)
@@ -82,210 +393,318 @@ func (pm *PluginManager) RegisterAll(bot *irc.Client, cfg *config.Config) error
 	pm.registerMediaPlugins(bot, cfg, adminPlugin)
 
 	// Időzített pluginok
-	pm.registerScheduledPlugins(bot, cfg)
+	if err := pm.registerScheduledPlugins(bot, cfg); err != nil {
+		return err
+	}
 
+	// Külső pluginok és scriptek
+	pm.registerExternalPlugins(bot, cfg)
+	pm.registerScriptEngine(bot, cfg)
+
+	// a nem kötelező pluginok hibája nem akadályozza az indulást, de összesítve jelezzük
+	if len(pm.failed) > 0 {
+		log.Printf("⚠️ Nem indult el %d plugin: %s (!plugin enable <név> újrapróbálja)",
+			len(pm.failed), strings.Join(pm.failed, ", "))
+	}
 	return nil
 }
 
-func (pm *PluginManager) registerAdminPlugin(bot *irc.Client, cfg *config.Config) *admin.AdminPlugin {
-	adminPlugin := admin.NewAdminPlugin(cfg)
+// register naplózza és megjegyzi az indítási hibát, és továbbadja a hívónak.
+// A hívó dönti el, hogy a hiba megállítja-e az indulást (ping, névnap);
+// a többi plugin hibáját a RegisterAll a végén összesíti.
+func (pm *PluginManager) register(name string, factory PluginFactory) error {
+	if err := pm.manager.Register(name, factory); err != nil {
+		log.Printf("❌ %v", err)
+		pm.failed = append(pm.failed, name)
+		return err
+	}
+	return nil
+}
+
+func (pm *PluginManager) registerAdminPlugin(bot *irc.Client, cfg *config.Config) (*admin.AdminPlugin, error) {
+	adminPlugin := admin.NewAdminPlugin(cfg, pm.ctx.Storage.Admins, pm.ctx)
 	adminPlugin.Initialize(bot)
-	pm.manager.Register(adminPlugin)
-	
-	for _, admin := range cfg.Admins {
-		adminPlugin.AddAdmin(admin)
+	pm.adminPlugin = adminPlugin
+	adminPlugin.OnRehash = pm.rehashReply
+
+	// Az admin plugin nem tiltható, mindig fut
+	if err := pm.manager.Register(adminPluginName, func() (Plugin, error) { return adminPlugin, nil }); err != nil {
+		return nil, err
 	}
-	
+
+	// a configból törölt adminok a leállás alatt is kikerülhettek a fájlból
+	adminPlugin.SetConfigAdmins(cfg.Admins)
+
 	log.Printf("✅ Admin plugin regisztrálva")
-	return adminPlugin
+	return adminPlugin, nil
 }
 
-func (pm *PluginManager) registerCorePlugins(bot *irc.Client, cfg *config.Config, adminPlugin *admin.AdminPlugin) {
+func (pm *PluginManager) registerCorePlugins(bot *irc.Client, cfg *config.Config, adminPlugin *admin.AdminPlugin) error {
 	// Ping plugin
-	duration, err := time.ParseDuration(cfg.PingCommandCooldown)
-	if err != nil {
-		log.Fatalf("Ping cooldown parsing hiba: %v", err)
+	if err := pm.register("ping", func() (Plugin, error) {
+		pingPlugin := ynm.NewPingPlugin(bot, adminPlugin, pm.ctx)
+		bot.SetPongHandler(pingPlugin.HandlePong)
+		return pongHook{pingPlugin, bot}, nil
+	}); err != nil {
+		return err
 	}
-	pingPlugin := ynm.NewPingPlugin(bot, duration, adminPlugin)
-	bot.OnPong = func(pongID string) { pingPlugin.HandlePong(pongID) }
-	pm.manager.Register(pingPlugin)
 
 	// Névnap plugin
-	nameDayPlugin, err := ynm.NewNameDayPlugin(cfg.NevnapChannels, cfg.NevnapReggel, cfg.NevnapEste)
-	if err != nil {
-		log.Fatalf("Névnap plugin inicializálás hiba: %v", err)
+	if err := pm.register("nevnap", func() (Plugin, error) {
+		return ynm.NewNameDayPlugin(bot, pm.ctx), nil
+	}); err != nil {
+		return err
 	}
-	pm.manager.Register(nameDayPlugin)
 
 	// Óra plugin
-	oraPlugin := ynm.NewOraPlugin(bot, adminPlugin, cfg)
-	pm.manager.Register(oraPlugin)
+	pm.register("ora", func() (Plugin, error) {
+		return ynm.NewOraPlugin(bot, adminPlugin, pm.ctx), nil
+	})
+
+	// Seen plugin
+	pm.register("seen", func() (Plugin, error) {
+		return ynm.NewSeenPlugin(bot, pm.ctx.Storage.Seen, pm.ctx), nil
+	})
+
+	// Tell plugin
+	pm.register("tell", func() (Plugin, error) {
+		return ynm.NewTellPlugin(bot, pm.cfg(), pm.ctx.Storage.Memos, pm.ctx), nil
+	})
 
 	// Test plugin
 
 
 	// Státusz plugin
-	statusPlugin := ynm.NewStatusPlugin(bot)
-	pm.manager.Register(statusPlugin)
+	pm.register("status", func() (Plugin, error) {
+		return ynm.NewStatusPlugin(bot, pm.ctx), nil
+	})
 
 	// Vicc plugin
-	viccPlugin := ynm.NewViccPlugin(bot, adminPlugin)
-	pm.manager.Register(viccPlugin)
-	
+	pm.register("vicc", func() (Plugin, error) {
+		return ynm.NewViccPlugin(bot, adminPlugin, pm.ctx), nil
+	})
+
 	// Tamagotchi plugin
-	dataDir := filepath.Join(cfg.DataDirectory, "data") // vagy használj egy fix útvonalat
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		log.Printf("❌ Nem sikerült létrehozni a Tamagotchi adatkönyvtárat: %v", err)
-		return
-	}
+	pm.register("tamagotchi", func() (Plugin, error) {
+		tamagotchiPlugin := plugins.NewTamagotchiPlugin(pm.ctx.Storage.Pets, bot, pm.ctx)
+		if err := tamagotchiPlugin.Initialize(bot, cfg); err != nil {
+			return nil, err
+		}
+		return tamagotchiPlugin, nil
+	})
+
 
-	tamagotchiPlugin := plugins.NewTamagotchiPlugin(dataDir, bot)
-	if err := tamagotchiPlugin.Initialize(bot, cfg); err != nil {
-		log.Printf("❌ Tamagotchi plugin inicializálás hiba: %v", err)
-	} else {
-		pm.manager.Register(tamagotchiPlugin)
-		log.Printf("✅ Tamagotchi plugin regisztrálva")
-	}
-	
-	
 
 	log.Printf("✅ Core pluginok regisztrálva")
+	return nil
 }
 
 func (pm *PluginManager) registerMoviePlugins(bot *irc.Client, cfg *config.Config, adminPlugin *admin.AdminPlugin) {
 	// Movie plugin
-	moviePlugin := media.NewMoviePlugin(
-		bot, adminPlugin, cfg.JellyfinDBPath, cfg.MovieDBPath,
-		cfg.MovieRequestsChannel, cfg.MoviePlugin.PostTime,
-		cfg.MoviePlugin.PostChan, cfg.MoviePlugin.PostNick,
-	)
-	pm.manager.Register(moviePlugin)
+	pm.register("kell", func() (Plugin, error) {
+		cfg := pm.cfg()
+		return media.NewMoviePlugin(
+			bot, adminPlugin, pm.ctx, cfg.JellyfinDBPath,
+			cfg.MovieRequestsChannel, string(cfg.MoviePlugin.PostTime),
+			cfg.MoviePlugin.PostChan, cfg.MoviePlugin.PostNick, cfg.MediaListURL(),
+		), nil
+	})
 
 	// Movie request plugin
-	movieRequestPlugin := media.NewMovieRequestPlugin(bot, adminPlugin, cfg.MovieDBPath)
-	if movieRequestPlugin != nil {
-		pm.manager.Register(movieRequestPlugin)
-	}
+	pm.register("keresek", func() (Plugin, error) {
+		return media.NewMovieRequestPlugin(bot, adminPlugin, pm.ctx.Storage.Movies, pm.ctx), nil
+	})
 
 	// Movie completion plugin
-	movieCompletionPlugin := media.NewMovieCompletionPlugin(bot, adminPlugin, cfg.MovieDBPath)
-	if movieCompletionPlugin != nil {
-		pm.manager.Register(movieCompletionPlugin)
-	}
+	pm.register("ok", func() (Plugin, error) {
+		return media.NewMovieCompletionPlugin(bot, adminPlugin, pm.ctx.Storage.Movies, pm.ctx), nil
+	})
 
 	// Movie deletion plugin
-	movieDeletionPlugin := media.NewMovieDeletionPlugin(bot, adminPlugin, cfg.MovieDBPath)
-	if movieDeletionPlugin != nil {
-		pm.manager.Register(movieDeletionPlugin)
-	}
+	pm.register("del", func() (Plugin, error) {
+		return media.NewMovieDeletionPlugin(bot, adminPlugin, pm.ctx.Storage.Movies, pm.ctx), nil
+	})
 
 	log.Printf("✅ Movie pluginok regisztrálva")
 }
 
 func (pm *PluginManager) registerMediaPlugins(bot *irc.Client, cfg *config.Config, adminPlugin *admin.AdminPlugin) {
 	// Media upload plugin
-	mediaUploadPlugin := media.NewMediaUploadPlugin(bot, cfg)
-	pm.manager.Register(mediaUploadPlugin)
-	if err := mediaUploadPlugin.Start(); err != nil {
-		log.Printf("❌ Media upload plugin indítási hiba: %v", err)
-	}
+	pm.register("upload", func() (Plugin, error) {
+		mediaUploadPlugin := media.NewMediaUploadPlugin(bot, pm.cfg(), pm.ctx)
+		if err := mediaUploadPlugin.Start(); err != nil {
+			log.Printf("❌ Media upload plugin indítási hiba: %v", err)
+		}
+		return mediaUploadPlugin, nil
+	})
 
 	// Media ajánló plugin
-	mediaPlugin := media.NewMediaAjanlatPlugin(
-		bot, cfg.JellyfinDBPath, cfg.MediaAjanlat.Channel, cfg.MediaAjanlat.Time,
-	)
-	pm.manager.Register(mediaPlugin)
+	pm.register("film", func() (Plugin, error) {
+		return media.NewMediaAjanlatPlugin(bot, pm.ctx, pm.cfg().JellyfinDBPath), nil
+	})
 
 	// Viccek plugin
-	jokePlugin := ynm.NewJokePlugin(bot, cfg.JokeChannels, cfg.JokeSendTime)
-	pm.manager.Register(jokePlugin)
-	jokePlugin.Start()
+	pm.register("napivicc", func() (Plugin, error) {
+		jokePlugin := ynm.NewJokePlugin(bot, pm.ctx)
+		jokePlugin.Start()
+		return jokePlugin, nil
+	})
 
 	log.Printf("✅ Media pluginok regisztrálva")
 }
 
-func (pm *PluginManager) registerScheduledPlugins(bot *irc.Client, cfg *config.Config) {
-	// Székelyhon plugin
-	if cfg.SzekelyhonInterval != "" && len(cfg.SzekelyhonChannels) > 0 {
-		interval, err := time.ParseDuration(cfg.SzekelyhonInterval)
-		if err != nil {
-			log.Fatalf("❌ Hibás Székelyhon időzítés: %v", err)
+func (pm *PluginManager) registerScheduledPlugins(bot *irc.Client, cfg *config.Config) error {
+	// Székelyhon plugin (az értékeket a config.Load már ellenőrizte)
+	if cfg.SzekelyhonInterval > 0 {
+		if err := pm.register("szekelyhon", func() (Plugin, error) {
+			// újraindításkor (config újratöltés) az aktuális értékkel
+			szekelyhonPlugin := ynm.NewSzekelyhonPlugin(bot, pm.ctx, pm.cfg().SzekelyhonInterval.Std())
+			szekelyhonPlugin.Start()
+			return szekelyhonPlugin, nil
+		}); err != nil {
+			return err
 		}
+		log.Printf("✅ Székelyhon plugin regisztrálva")
+	}
+	return nil
+}
 
-		if err := pm.validateSzekelyhonConfig(cfg); err != nil {
-			log.Fatalf("❌ Székelyhon config hiba: %v", err)
+func (pm *PluginManager) registerExternalPlugins(bot *irc.Client, cfg *config.Config) {
+	botInfo := func() pluginsdk.InitializeParams {
+		return pluginsdk.InitializeParams{
+			Protocol: pluginsdk.ProtocolVersion,
+			BotNick:  bot.GetNick(),
+			Channels: bot.GetJoinedChannels(),
 		}
+	}
 
-		szekelyhonPlugin := ynm.NewSzekelyhonPlugin(
-			bot, cfg.SzekelyhonChannels, interval,
-			cfg.SzekelyhonStartHour, cfg.SzekelyhonEndHour,
-		)
-		pm.scheduledPlugins = append(pm.scheduledPlugins, szekelyhonPlugin)
-		szekelyhonPlugin.Start()
-		log.Printf("✅ Székelyhon plugin regisztrálva")
+	for _, spec := range cfg.ExternalPlugins {
+		spec := spec
+		if pm.manager.lookup(spec.Name) != nil {
+			log.Printf("❌ %s külső plugin: a név már foglalt", spec.Name)
+			continue
+		}
+		pm.register(spec.Name, func() (Plugin, error) {
+			process, err := extplugin.New(spec, bot, pm.manager.router, botInfo)
+			if err != nil {
+				return nil, err
+			}
+			process.Start()
+			return process, nil
+		})
 	}
-}
 
-func (pm *PluginManager) validateSzekelyhonConfig(cfg *config.Config) error {
-	if cfg.SzekelyhonStartHour < 0 || cfg.SzekelyhonStartHour > 23 {
-		log.Fatalf("❌ Hibás Székelyhon kezdő óra: %d", cfg.SzekelyhonStartHour)
+	if len(cfg.ExternalPlugins) > 0 {
+		log.Printf("✅ Külső pluginok regisztrálva (%d)", len(cfg.ExternalPlugins))
 	}
-	if cfg.SzekelyhonEndHour < 0 || cfg.SzekelyhonEndHour > 23 {
-		log.Fatalf("❌ Hibás Székelyhon befejező óra: %d", cfg.SzekelyhonEndHour)
+}
+
+func (pm *PluginManager) registerScriptEngine(bot *irc.Client, cfg *config.Config) {
+	dir := cfg.Scripting.Dir
+	if dir == "" {
+		dir = "scripts"
 	}
-	if cfg.SzekelyhonStartHour >= cfg.SzekelyhonEndHour {
-		log.Fatalf("❌ Székelyhon kezdő óra nem lehet >= befejező óránál")
+	limits := scripting.Limits{
+		CPU:    cfg.Scripting.CPULimit.Std(),
+		Memory: uint64(cfg.Scripting.MemoryLimitMB) << 20,
 	}
-	return nil
+
+	pm.register(scriptPluginName, func() (Plugin, error) {
+		engine := scripting.New(dir, cfg.DataPath("scripts.json"), scriptPluginName, limits,
+			bot, pm.manager.router, bot.GetNick)
+		engine.Reload()
+		pm.scripts = engine
+		return engine, nil
+	})
 }
 
 func (pm *PluginManager) HandleMessage(msg irc.Message) string {
+	if !pm.ignores.Ignored(msg.Sender, msg.Account, "") {
+		pm.manager.Observe(msg)
+	}
+	start := time.Now()
+	reply := pm.manager.router.Dispatch(msg, pm.dispatch)
+	pm.observeCommand(msg.Text, reply, start)
+	return reply
+}
+
+// dispatch a middleware-eken átjutott üzenetet a megfelelő kezelőhöz irányítja
+func (pm *PluginManager) dispatch(msg irc.Message) string {
+	if isPluginCommand(msg.Text) {
+		return pm.handlePluginCommand(msg)
+	}
+	if isSettingsCommand(msg.Text) {
+		return pm.handleSettingsCommand(msg)
+	}
+	if isScriptCommand(msg.Text) {
+		return pm.handleScriptCommand(msg)
+	}
+	if isScheduleCommand(msg.Text) {
+		return pm.handleScheduleCommand(msg)
+	}
+	if isLimitsCommand(msg.Text) {
+		return pm.handleLimitsCommand(msg)
+	}
+	if isIgnoreCommand(msg.Text) {
+		return pm.handleIgnoreCommand(msg)
+	}
+	if isLogLevelCommand(msg.Text) {
+		return pm.handleLogLevelCommand(msg)
+	}
+	if isGrepCommand(msg.Text) {
+		return pm.handleGrepCommand(msg)
+	}
+	if isWebLoginCommand(msg.Text) {
+		return pm.handleWebLoginCommand(msg)
+	}
+	if isMoreCommand(msg.Text) {
+		return pm.handleMoreCommand(msg)
+	}
+	if isLangCommand(msg.Text) {
+		return pm.handleLangCommand(msg)
+	}
 	return pm.manager.HandleMessage(msg)
 }
 
+func (pm *PluginManager) HandleEvent(ev irc.Event) {
+	if ev.Sender != "" && pm.ignores.Ignored(ev.Sender, "", "") {
+		return
+	}
+	pm.manager.HandleEvent(ev)
+}
+
 func (pm *PluginManager) HandleTick(bot *irc.Client) {
 	// Név nap és egyéb tick pluginok
 	for _, plugin := range pm.manager.GetPlugins() {
 		if tickablePlugin, ok := plugin.(interface{ OnTick() []ScheduledMessage }); ok {
 			for _, msg := range tickablePlugin.OnTick() {
+				if !pm.channelAllowed(plugin, msg.Channel) {
+					continue
+				}
 				bot.SendMessage(msg.Channel, msg.Text)
 			}
 		}
 	}
 }
 
-func (pm *PluginManager) Shutdown() {
-	// Időzített pluginok leállítása
-	for _, plugin := range pm.scheduledPlugins {
-		plugin.Stop()
-		log.Printf("🛑 %s leállítva", plugin.Name())
+// channelAllowed megkeresi a plugin nevét, és ellenőrzi a csatornánkénti tiltást
+func (pm *PluginManager) channelAllowed(plugin Plugin, channel string) bool {
+	pm.manager.mu.RLock()
+	defer pm.manager.mu.RUnlock()
+	for _, entry := range pm.manager.entries {
+		if entry.plugin == plugin {
+			return pm.state.Enabled(entry.name, channel)
+		}
 	}
+	return true
+}
 
-	// Normál pluginok cleanup
-	for _, plugin := range pm.manager.GetPlugins() {
-		pm.shutdownPlugin(plugin)
-	}
-}
-
-func (pm *PluginManager) shutdownPlugin(plugin interface{}) {
-	switch p := plugin.(type) {
-	case *media.MoviePlugin:
-		p.Close()
-		log.Printf("🛑 Movie plugin leállítva")
-	case *media.MovieCompletionPlugin:
-		p.Close()
-		log.Printf("🛑 Movie completion plugin leállítva")
-	case *media.MovieDeletionPlugin:
-		p.Close()
-		log.Printf("🛑 Movie deletion plugin leállítva")
-	case *media.MovieRequestPlugin:
-		p.Close()
-		log.Printf("🛑 Movie request plugin leállítva")
-	case *media.MediaUploadPlugin:
-		p.Stop()
-		log.Printf("🛑 Media upload plugin leállítva")
-	case *plugins.TamagotchiPlugin:
-		p.Shutdown()
-		log.Printf("🛑 Tamagotchi plugin leállítva")
-	}
-}
\ No newline at end of file
+// Scheduler a közös ütemező
+func (pm *PluginManager) Scheduler() *scheduler.Scheduler {
+	return pm.scheduler
+}
+
+func (pm *PluginManager) Shutdown() {
+	pm.manager.ReleaseAll()
+	pm.scheduler.Stop()
+}
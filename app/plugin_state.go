@@ -0,0 +1,123 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PluginState a pluginok globális és csatornánkénti tiltását tárolja,
+// a data könyvtárban lévő JSON fájlba mentve.
+type PluginState struct {
+	mu       sync.RWMutex
+	filePath string
+	Disabled map[string]bool            `json:"disabled"` // plugin → globálisan tiltva
+	Channels map[string]map[string]bool `json:"channels"` // plugin → csatorna → tiltva
+}
+
+func NewPluginState(path string) *PluginState {
+	return &PluginState{
+		filePath: path,
+		Disabled: make(map[string]bool),
+		Channels: make(map[string]map[string]bool),
+	}
+}
+
+func (s *PluginState) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // első futás
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+	if s.Disabled == nil {
+		s.Disabled = make(map[string]bool)
+	}
+	if s.Channels == nil {
+		s.Channels = make(map[string]map[string]bool)
+	}
+	return nil
+}
+
+func (s *PluginState) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filePath, data, 0644)
+}
+
+// Enabled true, ha a plugin globálisan és az adott csatornán is engedélyezett.
+// Üres csatorna esetén csak a globális állapot számít.
+func (s *PluginState) Enabled(name, channel string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.Disabled[name] {
+		return false
+	}
+	if channel == "" {
+		return true
+	}
+	return !s.Channels[name][strings.ToLower(channel)]
+}
+
+// GloballyEnabled true, ha a plugin nincs globálisan tiltva.
+func (s *PluginState) GloballyEnabled(name string) bool {
+	return s.Enabled(name, "")
+}
+
+// Set beállítja a plugin állapotát globálisan (channel == "") vagy egy csatornán.
+func (s *PluginState) Set(name, channel string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if channel == "" {
+		if enabled {
+			delete(s.Disabled, name)
+		} else {
+			s.Disabled[name] = true
+		}
+		return s.save()
+	}
+
+	channel = strings.ToLower(channel)
+	if enabled {
+		delete(s.Channels[name], channel)
+		if len(s.Channels[name]) == 0 {
+			delete(s.Channels, name)
+		}
+	} else {
+		if s.Channels[name] == nil {
+			s.Channels[name] = make(map[string]bool)
+		}
+		s.Channels[name][channel] = true
+	}
+	return s.save()
+}
+
+// DisabledChannels visszaadja azokat a csatornákat, ahol a plugin tiltva van.
+func (s *PluginState) DisabledChannels(name string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var channels []string
+	for ch := range s.Channels[name] {
+		channels = append(channels, ch)
+	}
+	sort.Strings(channels)
+	return channels
+}
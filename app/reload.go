@@ -0,0 +1,222 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/logging"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+)
+
+// pluginConfigKeys mely config kulcsokat olvassa a plugin a létrehozásakor.
+// Ha ezek változnak és a plugin nem tudja élőben alkalmazni őket
+// (pluginapi.ConfigReloader), a manager újraindítja (pl. új adatbázis útvonal).
+var pluginConfigKeys = map[string][]string{
+	"ora":        {"orachan", "ora_dates_file"},
+	"kell":       {"jellyfin_db_path", "movie_requests_channel", "movie_plugin"},
+	"film":       {"jellyfin_db_path"},
+	"upload":     {"media_upload"},
+	"szekelyhon": {"SzekelyhonInterval"},
+}
+
+// ReloadResult egy config újratöltés eredménye
+type ReloadResult struct {
+	Changes   config.Changes
+	Restarted []string // újraindított pluginok
+	Pending   []string // csak a bot újraindítása után érvényes kulcsok
+	Errors    []string
+}
+
+// Reload újraolvassa és ellenőrzi a configot, majd a változásokat mindenhová
+// eljuttatja: csatornák, adminok, beállítási rétegek (és így az időzítések),
+// korlátozások, végül a pluginok (élőben vagy újraindítással).
+func (pm *PluginManager) Reload() (*ReloadResult, error) {
+	pm.reloadMu.Lock()
+	defer pm.reloadMu.Unlock()
+
+	old := pm.cfg()
+	newCfg, err := config.Load(old.Path()) // ellenőrzéssel együtt
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReloadResult{Changes: config.Diff(old, newCfg)}
+	// a fordításokat a config változásától függetlenül újraolvassuk
+	if err := loadCatalogs(newCfg.I18n.Dir); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	// Az új configot egyben tesszük közzé: a régit senki nem módosítja, így
+	// a párhuzamos olvasók (irc kliens, eseménykezelő, pluginok) vagy a régi,
+	// vagy az új példányt látják. A configot eltároló pluginok a ReloadEvent-ből
+	// (ConfigReloader) veszik át.
+	pm.live.Set(newCfg)
+	pm.bot.SetConfig(newCfg)
+	ev := pluginapi.ReloadEvent{Old: old, New: newCfg, Changes: result.Changes}
+
+	if ev.Changes.Has("Console", "Channels") {
+		pm.syncChannels(old, newCfg)
+	}
+	if ev.Changes.Has("logging") {
+		if err := logging.ApplyLevels(newCfg.Logging); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	pm.ctx.SetJobPolicies(newCfg.Scheduler.Jobs)
+	pm.rates.Store(newRatePolicies(newCfg.RateLimit))
+	pm.ignoreLogging.Store(newCfg.Ignore.ApplyToLogging)
+	pm.ctx.Reconfigure(newCfg) // a beállítások feliratkozói (időzítések) újrahangolnak
+
+	pm.reloadPlugins(ev, result)
+
+	for _, change := range result.Changes {
+		if change.Restart {
+			result.Pending = append(result.Pending, change.Key)
+		}
+	}
+	sort.Strings(result.Pending)
+	return result, nil
+}
+
+// reloadPlugins eljuttatja az eseményt a feliratkozott pluginoknak, és
+// újraindítja azokat, amelyek kulcsai változtak, de élőben nem tudják alkalmazni.
+func (pm *PluginManager) reloadPlugins(ev pluginapi.ReloadEvent, result *ReloadResult) {
+	pm.manager.mu.RLock()
+	entries := append([]*pluginEntry(nil), pm.manager.entries...)
+	pm.manager.mu.RUnlock()
+
+	registered := make(map[string]bool)
+	for _, entry := range entries {
+		registered[entry.name] = true
+
+		pm.manager.mu.RLock()
+		plugin := entry.plugin
+		pm.manager.mu.RUnlock()
+		if plugin == nil {
+			continue // globálisan tiltva: újraengedélyezéskor már az új configgal indul
+		}
+
+		restart := ev.Changes.Has(pluginConfigKeys[entry.name]...)
+		if reloader, ok := plugin.(pluginapi.ConfigReloader); ok {
+			err := reloader.ReloadConfig(ev)
+			if err == nil {
+				continue
+			}
+			log.Printf("⚠️ %s plugin nem tudta élőben alkalmazni a configot: %v", entry.name, err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.name, err))
+			restart = true
+		}
+		if !restart {
+			continue
+		}
+
+		pm.manager.release(entry)
+		if err := pm.manager.start(entry); err != nil {
+			log.Printf("❌ %v", err)
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Restarted = append(result.Restarted, entry.name)
+		log.Printf("✅ %s plugin újraindítva az új configgal", entry.name)
+	}
+
+	// Egy pluginhoz tartozó kulcs, de a plugin induláskor nem jött létre (pl. most kapott értéket)
+	for name, keys := range pluginConfigKeys {
+		if !registered[name] {
+			for _, change := range result.Changes {
+				if (config.Changes{change}).Has(keys...) {
+					result.Pending = append(result.Pending, change.Key)
+				}
+			}
+		}
+	}
+}
+
+// syncChannels kilép a törölt csatornákról, és belép az újakba
+func (pm *PluginManager) syncChannels(old, new *config.Config) {
+	channels := func(cfg *config.Config) map[string]string {
+		m := make(map[string]string)
+		for _, ch := range append([]string{cfg.ConsoleChannel}, cfg.Channels...) {
+			if ch != "" {
+				m[strings.ToLower(ch)] = ch
+			}
+		}
+		return m
+	}
+	before, after := channels(old), channels(new)
+
+	for lc, ch := range before {
+		if _, ok := after[lc]; !ok {
+			pm.bot.SendRaw("PART " + ch)
+		}
+	}
+	for lc, ch := range after {
+		if _, ok := before[lc]; !ok {
+			pm.bot.Join(ch)
+		}
+	}
+}
+
+// rehashReply a !rehash válasza loc nyelvén: mi változott, mi indult újra, mihez kell újraindítás
+func (pm *PluginManager) rehashReply(loc *i18n.Locale) string {
+	result, err := pm.Reload()
+	if err != nil {
+		log.Printf("❌ Config újratöltési hiba: %v", err)
+		return loc.T("rehash.failed", err)
+	}
+	if len(result.Changes) == 0 && len(result.Errors) == 0 {
+		return loc.T("rehash.unchanged")
+	}
+
+	pending := dedupe(result.Pending)
+	var live []string
+	for _, key := range result.Changes.Keys(false) {
+		if !contains(pending, key) {
+			live = append(live, key)
+		}
+	}
+	log.Printf("✅ Config újratöltve: %s", strings.Join(live, ", "))
+
+	reply := loc.T("rehash.done")
+	if len(live) > 0 {
+		reply += " " + loc.T("rehash.changed", strings.Join(live, ", "))
+	}
+	if len(result.Restarted) > 0 {
+		reply += " " + loc.T("rehash.restarted", strings.Join(result.Restarted, ", "))
+	}
+	if len(pending) > 0 {
+		reply += " " + loc.T("rehash.pending", strings.Join(pending, ", "))
+	}
+	if len(result.Errors) > 0 {
+		reply += " " + loc.T("rehash.errors", strings.Join(result.Errors, "; "))
+	}
+	return reply
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
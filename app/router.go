@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+)
+
+type route struct {
+	owner   string // a regisztráló plugin neve (ez alapján érvényes a !plugin tiltás)
+	name    string
+	help    string
+	handler pluginapi.CommandHandler
+}
+
+// Middleware a bejövő üzenetek feldolgozása köré épülő réteg (pl. korlátozás).
+// A next hívásával engedi tovább az üzenetet, különben maga dönt a válaszról.
+type Middleware func(msg irc.Message, next func(irc.Message) string) string
+
+// Router a "!parancs" → kezelő hozzárendelés. A régi pluginok továbbra is maguk
+// elemzik a szöveget; a router az új (pl. külső) pluginok parancsait szolgálja ki.
+// A middleware-ek viszont minden üzenetre lefutnak, a régi pluginokéra is.
+type Router struct {
+	mu         sync.RWMutex
+	routes     map[string]*route
+	middleware []Middleware
+}
+
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]*route)}
+}
+
+// RegisterCommand felvesz egy parancsot ("dice" vagy "!dice" alakban).
+// Más plugin által már foglalt nevet nem ír felül.
+func (r *Router) RegisterCommand(owner, name, help string, handler pluginapi.CommandHandler) error {
+	name = normalizeCommand(name)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("érvénytelen parancsnév: %q", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.routes[name]; ok && existing.owner != owner {
+		return fmt.Errorf("a !%s parancsot már a(z) %s plugin regisztrálta", name, existing.owner)
+	}
+	r.routes[name] = &route{owner: owner, name: name, help: help, handler: handler}
+	return nil
+}
+
+// UnregisterCommands a plugin összes parancsát törli (leállításkor, újraindításkor)
+func (r *Router) UnregisterCommands(owner string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, rt := range r.routes {
+		if rt.owner == owner {
+			delete(r.routes, name)
+		}
+	}
+}
+
+// Use egy middleware-t fűz a lánc végére (a korábban felvett fut először)
+func (r *Router) Use(mw Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw)
+}
+
+// Dispatch a middleware láncon át a final kezelőnek adja az üzenetet
+func (r *Router) Dispatch(msg irc.Message, final func(irc.Message) string) string {
+	r.mu.RLock()
+	chain := r.middleware
+	r.mu.RUnlock()
+
+	var next func(i int) func(irc.Message) string
+	next = func(i int) func(irc.Message) string {
+		if i == len(chain) {
+			return final
+		}
+		return func(msg irc.Message) string {
+			return chain[i](msg, next(i+1))
+		}
+	}
+	return next(0)(msg)
+}
+
+// match visszaadja a szöveghez tartozó útvonalat és a paramétereket
+func (r *Router) match(text string) (*route, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") {
+		return nil, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rt := r.routes[normalizeCommand(fields[0])]
+	if rt == nil {
+		return nil, nil
+	}
+	return rt, fields[1:]
+}
+
+// Commands a regisztrált parancsok "!név – súgó" formában, ábécérendben
+func (r *Router) Commands() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]string, 0, len(r.routes))
+	for _, rt := range r.routes {
+		line := "!" + rt.name
+		if rt.help != "" {
+			line += " – " + rt.help
+		}
+		list = append(list, line)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func normalizeCommand(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "!"))
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+)
+
+// egy IRC sorba ennyi karakternyi feladatot teszünk
+const scheduleListLineLimit = 350
+
+func isScheduleCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!schedule" || strings.HasPrefix(text, "!schedule ")
+}
+
+// handleScheduleCommand: !schedule list [előtag] | run <feladat> | pause <feladat> | resume <feladat>
+func (pm *PluginManager) handleScheduleCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin == nil || pm.adminPlugin.GetAdminLevel(nick, msg.Sender) < admin.AdminLevelAdmin {
+		return ""
+	}
+
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+	if len(parts) < 2 {
+		return loc.T("schedule.usage")
+	}
+
+	sub := strings.ToLower(parts[1])
+	if sub == "list" {
+		prefix := ""
+		if len(parts) >= 3 {
+			prefix = strings.ToLower(parts[2])
+		}
+		return pm.scheduleListText(loc, msg.Channel, prefix)
+	}
+
+	if len(parts) < 3 {
+		return loc.T("schedule.usage_job", sub)
+	}
+	name := strings.ToLower(parts[2])
+
+	switch sub {
+	case "run":
+		if err := pm.scheduler.RunNow(name); err != nil {
+			return loc.T("common.error", err)
+		}
+		return loc.T("schedule.started", name)
+	case "pause", "resume":
+		if err := pm.scheduler.SetPaused(name, sub == "pause"); err != nil {
+			return loc.T("common.error", err)
+		}
+		if sub == "pause" {
+			return loc.T("schedule.paused", name)
+		}
+		return loc.T("schedule.resumed", name)
+	}
+	return loc.T("schedule.usage")
+}
+
+// scheduleListText a hosszabb listát több sorban közvetlenül küldi, az utolsó sort adja vissza
+func (pm *PluginManager) scheduleListText(loc *i18n.Locale, channel, prefix string) string {
+	tz := pm.scheduler.Location()
+	var items []string
+	for _, job := range pm.scheduler.List() {
+		if !strings.HasPrefix(job.Name, prefix) {
+			continue
+		}
+		item := fmt.Sprintf("%s (%s)", job.Name, job.Spec)
+		switch {
+		case job.Paused:
+			item += " ⏸️"
+		case job.Running:
+			item = loc.T("schedule.running", item)
+		case !job.Next.IsZero():
+			item += " → " + formatScheduleTime(job.Next.In(tz))
+		}
+		if job.LastStatus == "hiba" {
+			item += " ❌"
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return loc.T("schedule.none")
+	}
+
+	return pm.sendList(channel, loc.T("schedule.list", len(items), tz), items)
+}
+
+// sendList " | "-vel elválasztva, sorokra tördelve küldi az elemeket;
+// az utolsó sort visszaadja (ez lesz a parancs válasza)
+func (pm *PluginManager) sendList(channel, header string, items []string) string {
+	var lines []string
+	line := header
+	for i, item := range items {
+		if i > 0 && len(line)+len(item) > scheduleListLineLimit {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" && !strings.HasSuffix(line, ": ") {
+			line += " | "
+		}
+		line += item
+	}
+	for _, l := range lines {
+		pm.bot.SendMessage(channel, l)
+	}
+	return line
+}
+
+func formatScheduleTime(t time.Time) string {
+	now := time.Now().In(t.Location())
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("01-02 15:04")
+}
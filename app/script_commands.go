@@ -0,0 +1,66 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+)
+
+// a scriptek a routerben ezen a néven szerepelnek; !plugin disable script is működik
+const scriptPluginName = "script"
+
+func isScriptCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "!script" || strings.HasPrefix(text, "!script ")
+}
+
+// handleScriptCommand: !script list | reload
+func (pm *PluginManager) handleScriptCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin == nil || pm.adminPlugin.GetAdminLevel(nick, msg.Sender) < admin.AdminLevelAdmin {
+		return ""
+	}
+
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+	if len(parts) < 2 {
+		return loc.T("script.usage")
+	}
+
+	engine := pm.scripts
+	entry := pm.manager.lookup(scriptPluginName)
+	if engine == nil || entry == nil || entry.plugin == nil {
+		return loc.T("script.not_running")
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "reload":
+		loaded, errs := engine.Reload()
+		if len(errs) == 0 {
+			return loc.N("script.reloaded", loaded)
+		}
+		return loc.T("script.reload_errors", loaded, len(errs), strings.Join(errs, " | "))
+
+	case "list":
+		statuses := engine.Statuses()
+		if len(statuses) == 0 {
+			return loc.T("script.none")
+		}
+		items := make([]string, 0, len(statuses))
+		for _, st := range statuses {
+			item := st.Name
+			switch {
+			case st.Error != "":
+				item = loc.T("script.broken", item)
+			case st.Disabled:
+				item = loc.T("script.disabled", item)
+			case len(st.Commands) > 0:
+				item += " [" + strings.Join(st.Commands, " ") + "]"
+			}
+			items = append(items, item)
+		}
+		return loc.T("script.list", strings.Join(items, ", "))
+	}
+	return loc.T("script.usage")
+}
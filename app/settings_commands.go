@@ -0,0 +1,94 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/settings"
+)
+
+func isSettingsCommand(text string) bool {
+	cmd := strings.Fields(text)
+	if len(cmd) == 0 {
+		return false
+	}
+	switch cmd[0] {
+	case "!set", "!unset", "!get":
+		return true
+	}
+	return false
+}
+
+// handleSettingsCommand: !set #csatorna kulcs érték | !unset #csatorna kulcs | !get #csatorna [kulcs]
+func (pm *PluginManager) handleSettingsCommand(msg irc.Message) string {
+	nick := strings.Split(msg.Sender, "!")[0]
+	if pm.adminPlugin == nil || pm.adminPlugin.GetAdminLevel(nick, msg.Sender) < admin.AdminLevelAdmin {
+		return ""
+	}
+
+	loc := pm.ctx.LocaleFor(msg)
+	parts := strings.Fields(msg.Text)
+	cmd := parts[0]
+
+	if len(parts) < 2 || !strings.HasPrefix(parts[1], "#") {
+		switch cmd {
+		case "!set":
+			return loc.T("settings.usage_set_keys", strings.Join(settings.KnownNames(), ", "))
+		case "!unset":
+			return loc.T("settings.usage_unset")
+		default:
+			return loc.T("settings.usage_get")
+		}
+	}
+	channel := parts[1]
+
+	switch cmd {
+	case "!set":
+		if len(parts) < 4 {
+			return loc.T("settings.usage_set")
+		}
+		key := strings.ToLower(parts[2])
+		value := strings.Join(parts[3:], " ")
+		if err := pm.ctx.Set(channel, key, value); err != nil {
+			return loc.T("common.error", err)
+		}
+		return loc.T("settings.set", channel, key, value)
+
+	case "!unset":
+		if len(parts) < 3 {
+			return loc.T("settings.usage_unset")
+		}
+		key := strings.ToLower(parts[2])
+		removed, err := pm.ctx.Unset(channel, key)
+		if err != nil {
+			return loc.T("common.error", err)
+		}
+		if !removed {
+			return loc.T("settings.no_override", channel, key)
+		}
+		value, source := pm.ctx.Lookup(channel, key)
+		return loc.T("settings.unset", channel, key, value, sourceName(loc, source))
+
+	default: // !get
+		if len(parts) >= 3 {
+			key := strings.ToLower(parts[2])
+			if _, ok := settings.Known[key]; !ok {
+				return loc.T("settings.unknown_key", key)
+			}
+			value, source := pm.ctx.Lookup(channel, key)
+			return loc.T("settings.get", channel, key, value, sourceName(loc, source))
+		}
+		overrides := pm.ctx.Overrides(channel)
+		if len(overrides) == 0 {
+			return loc.T("settings.no_overrides", channel)
+		}
+		return loc.T("settings.overrides", channel, strings.Join(overrides, ", "))
+	}
+}
+
+// sourceName a réteg megjelenített neve (pl. "config csatorna", "!set")
+func sourceName(loc *i18n.Locale, src settings.Source) string {
+	return loc.T("settings.source." + string(src))
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/sdnotify"
+)
+
+const (
+	systemdStatusEvery = 30 * time.Second
+	// ennyi ideig nem jött sor a szervertől, holott a félpercenkénti lag PING-re
+	// is jönne válasz: az olvasás elakadt, a watchdog jelzés szünetel
+	readStallTimeout = 3 * time.Minute
+)
+
+// setupSystemd a systemd értesítések (Type=notify): READY=1 az IRC regisztráció
+// után, STATUS= a kapcsolat állapotával, és WATCHDOG=1, amíg az IRC olvasás
+// halad. NOTIFY_SOCKET nélkül nem csinál semmit.
+func (a *App) setupSystemd() {
+	if !sdnotify.Enabled() {
+		return
+	}
+	a.bot.OnRegistered = func() {
+		if err := sdnotify.Ready(a.systemdStatus()); err != nil {
+			log.Printf("⚠️ systemd értesítési hiba: %v", err)
+		}
+	}
+	a.bot.OnDisconnect = func() {
+		sdnotify.Status(a.consoleLocale().T("systemd.reconnecting"))
+	}
+	sdnotify.Status(a.consoleLocale().T("systemd.connecting", a.cfg().Server))
+
+	interval := systemdStatusEvery
+	watchdog := sdnotify.WatchdogInterval()
+	if watchdog > 0 {
+		log.Printf("ℹ️ systemd watchdog: %s", watchdog)
+		if watchdog/2 < interval {
+			interval = watchdog / 2
+		}
+	}
+	go a.systemdLoop(interval, watchdog > 0)
+}
+
+func (a *App) systemdLoop(interval time.Duration, watchdog bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	stalled := false
+	for range ticker.C {
+		alive := a.ircAlive()
+		if alive && watchdog {
+			sdnotify.Watchdog()
+		}
+		if !alive && !stalled {
+			if a.bot.IsConnected() {
+				log.Printf("⚠️ %s óta nem jött sor az IRC szervertől, a watchdog jelzés szünetel",
+					time.Since(a.bot.LastRead()).Round(time.Second))
+			} else {
+				log.Printf("⚠️ Nincs IRC kapcsolat, a watchdog jelzés szünetel")
+			}
+		}
+		stalled = !alive
+		sdnotify.Status(a.systemdStatus())
+	}
+}
+
+// ircAlive: van kapcsolat, és az olvasása halad. Kapcsolat nélkül a watchdog
+// jelzés szünetel, így ha az újracsatlakozás a WatchdogSec alatt sem sikerül, a
+// systemd indítja újra a botot. Ha a folyamat egy zárolásban elakad, a hívás sem
+// tér vissza, így a watchdog akkor is lejár.
+func (a *App) ircAlive() bool {
+	return a.bot.IsConnected() && time.Since(a.bot.LastRead()) < readStallTimeout
+}
+
+func (a *App) systemdStatus() string {
+	loc := a.consoleLocale()
+	if !a.bot.IsConnected() {
+		return loc.T("systemd.offline", a.cfg().Server)
+	}
+	return loc.T("systemd.online", a.bot.GetNick(), a.cfg().Server, len(a.bot.GetJoinedChannels()), a.bot.Lag().Seconds())
+}
+
+// consoleLocale a konzolcsatorna nyelve: a konzolra küldött üzenetek és a
+// systemd állapotsor ezen szól
+func (a *App) consoleLocale() *i18n.Locale {
+	return a.pluginManager.ctx.Locale(a.cfg().ConsoleChannel)
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+func isWebLoginCommand(text string) bool {
+	return strings.TrimSpace(text) == "!weblogin"
+}
+
+// handleWebLoginCommand: !weblogin – egyszer használható belépő link a webes
+// felülethez (filmkérések teljesítése, törlése); csak adminnak, privátban
+func (pm *PluginManager) handleWebLoginCommand(msg irc.Message) string {
+	if !pm.isAdmin(msg.Sender) {
+		return ""
+	}
+	loc := pm.ctx.LocaleFor(msg)
+	if pm.webSessions == nil {
+		return loc.T("weblogin.disabled")
+	}
+	nick := strings.Split(msg.Sender, "!")[0]
+	code := pm.webSessions.NewLoginCode(nick)
+	pm.bot.SendMessage(nick, loc.T("weblogin.link", pm.cfg().HTTPPublicURL(), code))
+
+	if strings.HasPrefix(msg.Channel, "#") || strings.HasPrefix(msg.Channel, "&") {
+		return loc.T("weblogin.sent", nick)
+	}
+	return ""
+}
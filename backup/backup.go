@@ -0,0 +1,307 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package backup a bot adatainak mentése és visszaállítása. A közös adatbázis
+// konzisztens pillanatképe (SQLite online mentés, a bot futása közben is) és az
+// adatkönyvtár JSON állapotfájljai egy időbélyeges tar.gz archívumba kerülnek,
+// az elején egy manifest.json-nal, amely fájlonként a méretet és a SHA-256
+// ellenőrzőösszeget is tartalmazza. A Restore csak hibátlan archívumot állít vissza.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// FormatVersion az archívum formátumának verziója (a manifest format mezője)
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	filePrefix   = "ynm-backup-"
+	fileSuffix   = ".tar.gz"
+)
+
+// a fájlok fajtája a manifestben
+const (
+	KindDatabase = "database" // a közös adatbázis (storage_path)
+	KindJSON     = "json"     // állapotfájl az adatkönyvtárból
+)
+
+// Manifest az archívum tartalomjegyzéke
+type Manifest struct {
+	Format  int       `json:"format"`
+	Created time.Time `json:"created"`
+	Host    string    `json:"host,omitempty"`
+	Schema  int       `json:"schema"` // az adatbázis migrációs verziója
+	Files   []File    `json:"files"`
+}
+
+// File egy mentett fájl
+type File struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Options a mentés forrásai és célja
+type Options struct {
+	Dir         string // az archívumok könyvtára
+	Keep        int    // ennyi archívum marad (0: mind)
+	DataDir     string // a JSON állapotfájlok könyvtára
+	StoragePath string // a közös adatbázis helye (visszaállításkor ide kerül)
+}
+
+// OptionsFromConfig a config backup része és az adatkönyvtár alapján
+func OptionsFromConfig(cfg *config.Config) Options {
+	return Options{
+		Dir:         cfg.BackupDir(),
+		Keep:        cfg.Backup.Keep,
+		DataDir:     cfg.DataPath(""),
+		StoragePath: cfg.StoragePath(),
+	}
+}
+
+// Result egy elkészült mentés
+type Result struct {
+	Path     string
+	Size     int64
+	Manifest Manifest
+}
+
+// Create elkészíti a mentést az opt.Dir könyvtárba, majd törli a keep feletti
+// régi archívumokat. A futó bot mellett is hívható.
+func Create(db *storage.DB, opt Options) (*Result, error) {
+	if err := os.MkdirAll(opt.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(opt.Dir, ".staging-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	m := Manifest{Format: FormatVersion, Created: time.Now()}
+	m.Host, _ = os.Hostname()
+	if m.Schema, err = db.Version(); err != nil {
+		return nil, err
+	}
+
+	dbName := filepath.Base(db.Path())
+	if err := db.Backup(filepath.Join(staging, dbName)); err != nil {
+		return nil, fmt.Errorf("adatbázis mentése: %w", err)
+	}
+	m.Files = append(m.Files, File{Name: dbName, Kind: KindDatabase})
+
+	names, err := stateFiles(opt.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		data, err := readState(filepath.Join(opt.DataDir, name))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue // közben törölték
+		}
+		if err := os.WriteFile(filepath.Join(staging, name), data, 0o644); err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, File{Name: name, Kind: KindJSON})
+	}
+
+	for i := range m.Files {
+		f := &m.Files[i]
+		if f.Size, f.SHA256, err = checksum(filepath.Join(staging, f.Name)); err != nil {
+			return nil, err
+		}
+	}
+
+	target := filepath.Join(opt.Dir, filePrefix+m.Created.Format("20060102-150405")+fileSuffix)
+	if err := writeArchive(target, staging, m); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Path: target, Size: info.Size(), Manifest: m}
+
+	if opt.Keep > 0 {
+		if err := Prune(opt.Dir, opt.Keep); err != nil {
+			return res, fmt.Errorf("régi mentések törlése: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// Prune a dir archívumaiból csak a legújabb keep darabot hagyja meg
+func Prune(dir string, keep int) error {
+	matches, err := List(dir)
+	if err != nil {
+		return err
+	}
+	for len(matches) > keep {
+		if err := os.Remove(matches[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
+
+// List a dir archívumai időrendben (a legrégebbi elöl)
+func List(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches) // az időbélyeg miatt a név szerinti sorrend időrend
+	return matches, nil
+}
+
+// stateFiles az adatkönyvtár JSON fájljai (alkönyvtárak nélkül)
+func stateFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readState beolvassa az állapotfájlt. A bot a fájlokat helyben írja felül, így
+// egy épp íródó fájl csonka lehet: ilyenkor kicsit később újra próbáljuk.
+func readState(path string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if validJSON(data) {
+			return data, nil
+		}
+		if attempt == 4 {
+			return nil, fmt.Errorf("%s: hibás JSON", path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// validJSON: a még üres (soha nem mentett) fájl is elfogadható
+func validJSON(data []byte) bool {
+	return len(bytes.TrimSpace(data)) == 0 || json.Valid(data)
+}
+
+func checksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeArchive a manifestet és a fájlokat a target.tmp-be írja, majd átnevezi,
+// így félkész archívum nem marad a könyvtárban
+func writeArchive(target, staging string, m Manifest) (err error) {
+	tmp := target + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestName, int64(len(manifest)), m.Created, bytes.NewReader(manifest)); err != nil {
+		return err
+	}
+	for _, file := range m.Files {
+		src, err := os.Open(filepath.Join(staging, file.Name))
+		if err != nil {
+			return err
+		}
+		err = writeEntry(tw, file.Name, file.Size, m.Created, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s már létezik", target)
+	}
+	return os.Rename(tmp, target)
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
@@ -0,0 +1,206 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// newBackup egy adatbázissal és egy állapotfájllal elkészített mentés
+func newBackup(t *testing.T) (Options, *Result) {
+	t.Helper()
+	dir := t.TempDir()
+	opt := Options{
+		Dir:         filepath.Join(dir, "backups"),
+		DataDir:     filepath.Join(dir, "data"),
+		StoragePath: filepath.Join(dir, "data", "ynm.db"),
+	}
+	db, err := storage.Open(opt.StoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := os.WriteFile(filepath.Join(opt.DataDir, "settings.json"), []byte(`{"#ynm": {"language": "en"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Create(db, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opt, res
+}
+
+// readArchive a manifest és a fájlok tartalma
+func readArchive(t *testing.T, path string) (Manifest, map[string][]byte) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var m Manifest
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name == manifestName {
+			if err := json.Unmarshal(data, &m); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		files[hdr.Name] = data
+	}
+	return m, files
+}
+
+// writeTarGz a manifestet, majd a fájlokat a megadott sorrendben írja ki
+func writeTarGz(t *testing.T, path string, m Manifest, names []string, files map[string][]byte) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(manifestName, manifest)
+	for _, name := range names {
+		write(name, files[name])
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	_, res := newBackup(t)
+
+	m, err := Verify(res.Path, t.TempDir())
+	if err != nil {
+		t.Fatalf("ép archívum: %v", err)
+	}
+	if len(m.Files) != 2 || m.Files[0].Kind != KindDatabase || m.Files[1].Name != "settings.json" {
+		t.Errorf("manifest fájljai: %+v", m.Files)
+	}
+
+	orig, files := readArchive(t, res.Path)
+	names := []string{orig.Files[0].Name, "settings.json"}
+	tests := []struct {
+		name string
+		edit func(m *Manifest, names []string, files map[string][]byte) []string
+		want string
+	}{
+		{"eltérő tartalom", func(m *Manifest, names []string, files map[string][]byte) []string {
+			files["settings.json"] = []byte(`{"#ynm": {"language": "ro"}}`)
+			return names
+		}, "ellenőrzőösszeg"},
+		{"eltérő méret", func(m *Manifest, names []string, files map[string][]byte) []string {
+			files["settings.json"] = append(files["settings.json"], ' ')
+			return names
+		}, "méret"},
+		{"hiányzó fájl", func(m *Manifest, names []string, files map[string][]byte) []string {
+			return names[:1]
+		}, "hiányzó fájl"},
+		{"ismeretlen fájl", func(m *Manifest, names []string, files map[string][]byte) []string {
+			files["extra.json"] = []byte("{}")
+			return append(names, "extra.json")
+		}, "nem szereplő"},
+		{"ismétlődő fájl", func(m *Manifest, names []string, files map[string][]byte) []string {
+			return append(names, "settings.json")
+		}, "ismétlődő"},
+		{"kiútvonal", func(m *Manifest, names []string, files map[string][]byte) []string {
+			m.Files[1].Name = "../settings.json"
+			return names
+		}, "hibás fájlnév"},
+		{"újabb formátum", func(m *Manifest, names []string, files map[string][]byte) []string {
+			m.Format = FormatVersion + 1
+			return names
+		}, "formátum"},
+		{"adatbázis nélkül", func(m *Manifest, names []string, files map[string][]byte) []string {
+			m.Files = m.Files[1:]
+			return names[1:]
+		}, "adatbázis"},
+	}
+	for _, tt := range tests {
+		m := orig
+		m.Files = append([]File(nil), orig.Files...)
+		copied := make(map[string][]byte, len(files))
+		for name, data := range files {
+			copied[name] = append([]byte(nil), data...)
+		}
+		path := filepath.Join(t.TempDir(), "hibas.tar.gz")
+		writeTarGz(t, path, m, tt.edit(&m, append([]string(nil), names...), copied), copied)
+
+		_, err := Verify(path, t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: %v, várt hiba: %q", tt.name, err, tt.want)
+		}
+	}
+
+	notGzip := filepath.Join(t.TempDir(), "nem.tar.gz")
+	if err := os.WriteFile(notGzip, []byte("nem archívum"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(notGzip, t.TempDir()); err == nil {
+		t.Error("a nem gzip fájl átment az ellenőrzésen")
+	}
+}
+
+// A visszaállítás a régi fájlokat .pre-restore néven megtartja
+func TestRestore(t *testing.T) {
+	opt, res := newBackup(t)
+	state := filepath.Join(opt.DataDir, "settings.json")
+	if err := os.WriteFile(state, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(res.Path, opt); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(state); string(data) != `{"#ynm": {"language": "en"}}` {
+		t.Errorf("visszaállított állapot: %s", data)
+	}
+	if data, _ := os.ReadFile(state + preRestoreSuffix); string(data) != `{}` {
+		t.Errorf("félretett állapot: %s", data)
+	}
+	if _, err := storage.CheckFile(opt.StoragePath); err != nil {
+		t.Errorf("visszaállított adatbázis: %v", err)
+	}
+}
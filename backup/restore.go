@@ -0,0 +1,236 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// a felülírt fájlok ezzel a végződéssel megmaradnak a helyükön
+const preRestoreSuffix = ".pre-restore"
+
+// Restore ellenőrzi az archívumot, és csak hibátlan archívum esetén cseréli le
+// az adatbázist és a JSON állapotfájlokat. A bot legyen leállítva. Ellenőrzés:
+// a manifest formátuma, minden fájl megvan és nincs más, a méret és a SHA-256
+// egyezik, a JSON érvényes, az adatbázis ép és nem újabb sémájú a bot ismertnél.
+// A felülírt fájlok <név>.pre-restore néven megmaradnak.
+func Restore(archive string, opt Options) (*Manifest, error) {
+	if err := os.MkdirAll(opt.DataDir, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(opt.DataDir, ".restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	m, err := Verify(archive, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range m.Files {
+		target := filepath.Join(opt.DataDir, f.Name)
+		if f.Kind == KindDatabase {
+			target = opt.StoragePath
+		}
+		if err := install(filepath.Join(staging, f.Name), target, f.Kind == KindDatabase); err != nil {
+			return m, fmt.Errorf("%s visszaállítása: %w", f.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// Verify kicsomagolja és ellenőrzi az archívumot a dir könyvtárba (a helyén nem
+// módosít semmit); hibás archívum esetén a dir tartalma nem használható
+func Verify(archive, dir string) (*Manifest, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("nem tar.gz archívum: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, fmt.Errorf("az archívum elején nincs %s", manifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(tr, 1<<20)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("hibás manifest: %w", err)
+	}
+	if err := checkManifest(&m); err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]File, len(m.Files))
+	for _, file := range m.Files {
+		expected[file.Name] = file
+	}
+	seen := make(map[string]bool)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sérült archívum: %w", err)
+		}
+		file, ok := expected[hdr.Name]
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("a manifestben nem szereplő fájl: %s", hdr.Name)
+		}
+		if seen[hdr.Name] {
+			return nil, fmt.Errorf("ismétlődő fájl: %s", hdr.Name)
+		}
+		seen[hdr.Name] = true
+		if err := extract(tr, filepath.Join(dir, file.Name), file); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range m.Files {
+		if !seen[file.Name] {
+			return nil, fmt.Errorf("hiányzó fájl: %s", file.Name)
+		}
+	}
+
+	for _, file := range m.Files {
+		path := filepath.Join(dir, file.Name)
+		switch file.Kind {
+		case KindDatabase:
+			if _, err := storage.CheckFile(path); err != nil {
+				return nil, fmt.Errorf("%s: %w", file.Name, err)
+			}
+		case KindJSON:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if !validJSON(data) {
+				return nil, fmt.Errorf("%s: hibás JSON", file.Name)
+			}
+		}
+	}
+	return &m, nil
+}
+
+// checkManifest a formátum, a fájlnevek és a fajták ellenőrzése
+func checkManifest(m *Manifest) error {
+	if m.Format != FormatVersion {
+		return fmt.Errorf("ismeretlen archívum formátum: %d (ez a verzió: %d)", m.Format, FormatVersion)
+	}
+	databases := 0
+	names := make(map[string]bool)
+	for _, f := range m.Files {
+		// csak sima fájlnév: az archívum nem írhat az adatkönyvtáron kívülre
+		if f.Name == "" || f.Name != filepath.Base(f.Name) || f.Name == "." || f.Name == ".." || f.Name == manifestName {
+			return fmt.Errorf("hibás fájlnév a manifestben: %q", f.Name)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("ismétlődő fájl a manifestben: %s", f.Name)
+		}
+		names[f.Name] = true
+		switch f.Kind {
+		case KindDatabase:
+			databases++
+		case KindJSON:
+		default:
+			return fmt.Errorf("%s: ismeretlen fajta: %q", f.Name, f.Kind)
+		}
+		if len(f.SHA256) != sha256.Size*2 {
+			return fmt.Errorf("%s: hiányzó ellenőrzőösszeg", f.Name)
+		}
+	}
+	if databases != 1 {
+		return errors.New("a manifestben nincs (vagy több is van) adatbázis")
+	}
+	return nil
+}
+
+// extract a fájlt kiírja, közben ellenőrzi a méretét és az ellenőrzőösszegét
+func extract(r io.Reader, path string, file File) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), io.LimitReader(r, file.Size+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != file.Size {
+		return fmt.Errorf("%s: a méret eltér (%d, a manifest szerint %d)", file.Name, n, file.Size)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != file.SHA256 {
+		return fmt.Errorf("%s: az ellenőrzőösszeg eltér", file.Name)
+	}
+	return nil
+}
+
+// install a célkönyvtárba másolja, majd a helyére nevezi a fájlt; a régi
+// <név>.pre-restore néven marad meg. Az adatbázis -wal és -shm fájljai a
+// régivel együtt mozognak, különben az SQLite a régi naplót az újra alkalmazná.
+func install(src, target string, database bool) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp := target + ".restore-tmp"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	names := []string{""}
+	if database {
+		names = append(names, "-wal", "-shm")
+	}
+	for _, suffix := range names {
+		// egy korábbi visszaállítás maradéka ne keveredjen a most félretett fájllal
+		os.Remove(target + preRestoreSuffix + suffix)
+	}
+	for _, suffix := range names {
+		err := os.Rename(target+suffix, target+preRestoreSuffix+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, target)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,524 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package chanlog a csatornák és privát beszélgetések naplója (LogDir):
+// üzenetek, a bot saját sorai és a JOIN/PART/QUIT/NICK/KICK/TOPIC események,
+// irssi, weechat vagy JSON Lines formátumban. Célonként és naponta egy fájl
+// (#csatorna_2025-06-01.log), amely használat közben nyitva marad (legfeljebb
+// maxOpenFiles egyszerre); a lezárt napok gzip-be kerülnek.
+package chanlog
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+// timeNow a bejegyzések és a napváltás órája
+var timeNow = time.Now
+
+// Nyitott fájlok: privát üzenetenként is nyílik egy, ezért a számuk korlátos
+// (a legrégebben írt záródik be), és a tétlenek óránként (Rotate) bezáródnak.
+const (
+	maxOpenFiles = 64
+	idleTimeout  = 30 * time.Minute
+)
+
+// Options a napló beállításai
+type Options struct {
+	Dir     string
+	Config  config.ChannelLogConfig
+	Nick    func() string             // a bot aktuális nickje (a saját sorokhoz)
+	Enabled func(channel string) bool // csatornánkénti kikapcsolás (chanlog.enabled); nil: mind naplózva
+}
+
+// Logger a csatornanapló. A metódusai több goroutine-ból is hívhatók.
+type Logger struct {
+	opts   Options
+	format Format
+
+	mu      sync.Mutex
+	files   map[string]*logFile // fájlnév-előtag → az aznapi nyitott fájl
+	members map[string]*members // kisbetűs csatornanév → a bent lévők (a QUIT/NICK szétosztásához)
+	closed  bool
+	subs    map[chan Entry]bool // élő feliratkozók (webes naplónéző)
+	queued  map[string]bool     // a tömörítésre váró vagy éppen tömörülő fájlok
+
+	compressing sync.WaitGroup
+}
+
+type logFile struct {
+	f    *os.File
+	path string
+	day  string
+	used time.Time // az utolsó írás ideje (LRU és tétlenség)
+}
+
+type members struct {
+	name  string
+	nicks map[string]bool // kisbetűs nick
+}
+
+// New létrehozza a naplókönyvtárat, és a háttérben tömöríti a korábbi napok
+// még nyers naplóit
+func New(opts Options) (*Logger, error) {
+	format, err := FormatByName(opts.Config.Format)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	l := &Logger{
+		opts:    opts,
+		format:  format,
+		files:   make(map[string]*logFile),
+		members: make(map[string]*members),
+		subs:    make(map[chan Entry]bool),
+		queued:  make(map[string]bool),
+	}
+	l.sweep()
+	return l, nil
+}
+
+// Message egy bejövő PRIVMSG; privát üzenetnél a cél a küldő nickje
+func (l *Logger) Message(msg irc.Message) {
+	typ, text, ok := ctcp(msg.Text)
+	if !ok {
+		return
+	}
+	target := msg.Channel
+	if !isChannel(target) {
+		target = msg.Nick
+	}
+	e := Entry{Time: timeNow(), Target: target, Type: typ, Nick: msg.Nick, Host: hostOf(msg.Sender), Text: text}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if isChannel(target) {
+		l.channel(target).nicks[strings.ToLower(msg.Nick)] = true
+	}
+	l.write(e)
+}
+
+// Sent a bot egy elküldött sora (irc.Client.OnSend); csak a PRIVMSG és a NOTICE
+// kerül naplóba, a jelszavak kitakarva
+func (l *Logger) Sent(line string) {
+	cmd, rest, _ := strings.Cut(irc.Redact(line), " ")
+	target, text, _ := strings.Cut(rest, " ")
+	text = strings.TrimPrefix(text, ":")
+	if target == "" {
+		return
+	}
+
+	var typ string
+	switch strings.ToUpper(cmd) {
+	case "PRIVMSG":
+		var ok bool
+		if typ, text, ok = ctcp(text); !ok {
+			return
+		}
+	case "NOTICE":
+		if strings.HasPrefix(text, "\x01") {
+			return // CTCP válasz
+		}
+		typ = TypeNotice
+	default:
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.write(Entry{Time: timeNow(), Target: target, Type: typ, Nick: l.self(), Text: text, Self: true})
+}
+
+// Event egy csatorna esemény. Quiet esetén csak a tagságot követi, nem naplóz
+// (a figyelmen kívül hagyott küldők, ha a config így kéri).
+func (l *Logger) Event(ev irc.Event, quiet bool) {
+	e := Entry{Time: timeNow(), Target: ev.Channel, Nick: ev.Nick, Host: hostOf(ev.Sender), Text: ev.Text, Other: ev.Target}
+	nick := strings.ToLower(ev.Nick)
+	self := strings.EqualFold(ev.Nick, l.self())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	write := func(e Entry) {
+		if !quiet {
+			e.Self = self
+			l.write(e)
+		}
+	}
+
+	switch ev.Type {
+	case "JOIN":
+		e.Type = TypeJoin
+		if self {
+			delete(l.members, strings.ToLower(ev.Channel)) // a NAMES lista újra feltölti
+		}
+		l.channel(ev.Channel).nicks[nick] = true
+		write(e)
+	case "PART":
+		e.Type = TypePart
+		write(e)
+		l.leave(ev.Channel, nick)
+	case "KICK":
+		e.Type, e.Host = TypeKick, ""
+		write(e)
+		l.leave(ev.Channel, strings.ToLower(ev.Target))
+	case "TOPIC":
+		e.Type = TypeTopic
+		write(e)
+	case "QUIT", "NICK":
+		e.Type = TypeQuit
+		if ev.Type == "NICK" {
+			e.Type, e.Host = TypeNick, ""
+		}
+		for _, target := range l.whereIs(ev.Nick) {
+			e.Target = target
+			write(e)
+		}
+		for _, m := range l.members {
+			if m.nicks[nick] {
+				delete(m.nicks, nick)
+				if ev.Type == "NICK" {
+					m.nicks[strings.ToLower(ev.Target)] = true
+				}
+			}
+		}
+	}
+}
+
+// Names a csatorna NAMES listája (irc.Client.OnNames): a QUIT és NICK
+// eseményeket ezek alapján írjuk a megfelelő csatornák naplójába
+func (l *Logger) Names(channel string, nicks []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := l.channel(channel)
+	for _, nick := range nicks {
+		m.nicks[strings.ToLower(nick)] = true
+	}
+}
+
+// ChannelsOf a csatornák, ahol a bot és a nick is bent van (a !grep jogosultsághoz)
+func (l *Logger) ChannelsOf(nick string) []string {
+	nick = strings.ToLower(nick)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var channels []string
+	for _, m := range l.members {
+		if m.nicks[nick] {
+			channels = append(channels, m.name)
+		}
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Members a csatornán bent lévők (kisbetűs nickek, ABC sorrendben)
+func (l *Logger) Members(channel string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := l.members[strings.ToLower(channel)]
+	if m == nil {
+		return nil
+	}
+	nicks := make([]string, 0, len(m.nicks))
+	for nick := range m.nicks {
+		nicks = append(nicks, nick)
+	}
+	sort.Strings(nicks)
+	return nicks
+}
+
+// Rotate lezárja az előző napok nyitott fájljait és tömöríti őket, valamint
+// bezárja az idleTimeout óta nem írt fájlokat (óránként fut)
+func (l *Logger) Rotate() {
+	now := timeNow()
+	today := now.Format(dayLayout)
+	l.mu.Lock()
+	for key, lf := range l.files {
+		if lf.day != today || now.Sub(lf.used) > idleTimeout {
+			l.closeFile(key, lf)
+		}
+	}
+	l.mu.Unlock()
+	l.sweep()
+}
+
+// Close lezárja a fájlokat, és megvárja a futó tömörítéseket
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	for key, lf := range l.files {
+		l.closeFile(key, lf)
+	}
+	l.mu.Unlock()
+	l.compressing.Wait()
+	return nil
+}
+
+// write a bejegyzést a cél aznapi fájljába írja; a hívó tartja a zárat
+func (l *Logger) write(e Entry) {
+	if l.closed || e.Target == "" {
+		return
+	}
+	key := safeName(e.Target)
+	if !l.enabled(e.Target) {
+		if lf := l.files[key]; lf != nil {
+			l.closeFile(key, lf)
+		}
+		return
+	}
+
+	day := e.Time.Format(dayLayout)
+	lf := l.files[key]
+	if lf != nil && lf.day != day {
+		l.closeFile(key, lf)
+		lf = nil
+	}
+	if lf == nil {
+		var err error
+		if lf, err = l.openFile(e.Target, day); err != nil {
+			log.Printf("❌ Csatornanapló megnyitási hiba (%s): %v", e.Target, err)
+			return
+		}
+		l.files[key] = lf
+		if len(l.files) > maxOpenFiles {
+			l.closeOldest(key)
+		}
+	}
+	lf.used = timeNow()
+
+	line := l.format.Line(e)
+	if line == "" {
+		return
+	}
+	if _, err := lf.f.WriteString(line + "\n"); err != nil {
+		log.Printf("❌ Csatornanapló írási hiba (%s): %v", lf.path, err)
+	}
+	for ch := range l.subs {
+		select {
+		case ch <- e:
+		default: // a lassú olvasó lemarad, a naplózást nem fogja meg
+		}
+	}
+}
+
+// Subscribe a naplóba kerülő bejegyzések élő folyama (csak az engedélyezett
+// célokéi). A cancel leiratkozik és lezárja a csatornát.
+func (l *Logger) Subscribe(buffer int) (<-chan Entry, func()) {
+	ch := make(chan Entry, buffer)
+	l.mu.Lock()
+	l.subs[ch] = true
+	l.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			delete(l.subs, ch)
+			l.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Days a cél naplózott napjai (2025-06-01), a legújabb elöl
+func (l *Logger) Days(target string) ([]string, error) {
+	files, err := ListFiles(l.opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	name := safeName(target)
+	var days []string
+	for _, f := range files {
+		if f.Target == name && f.Ext == l.format.Ext() {
+			days = append(days, f.Day)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(days)))
+	return days, nil
+}
+
+// Day a cél adott napi naplójának visszaolvasható bejegyzései (lásd ParseLine);
+// a nyitott mai fájlt és a tömörített napokat is olvassa
+func (l *Logger) Day(target, day string) ([]Entry, error) {
+	path, err := logPath(l.opts.Dir, target, day, l.format.Ext())
+	if err != nil {
+		return nil, err
+	}
+	f := LogFile{Path: path, Name: filepath.Base(path), Target: safeName(target), Day: day, Ext: l.format.Ext()}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		f.Path, f.Compressed = path+gzipSuffix, true
+	}
+	return ReadFile(f)
+}
+
+func (l *Logger) enabled(target string) bool {
+	if !isChannel(target) {
+		return !l.opts.Config.SkipPrivate
+	}
+	return l.opts.Enabled == nil || l.opts.Enabled(target)
+}
+
+func (l *Logger) openFile(target, day string) (*logFile, error) {
+	path, err := logPath(l.opts.Dir, target, day, l.format.Ext())
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	if marker := l.format.Opened(timeNow()); marker != "" {
+		f.WriteString(marker + "\n")
+	}
+	return &logFile{f: f, path: path, day: day}, nil
+}
+
+// closeOldest a legrégebben írt fájlt zárja be (a keep kivételével); a hívó tartja a zárat
+func (l *Logger) closeOldest(keep string) {
+	var oldestKey string
+	var oldest *logFile
+	for key, lf := range l.files {
+		if key != keep && (oldest == nil || lf.used.Before(oldest.used)) {
+			oldestKey, oldest = key, lf
+		}
+	}
+	if oldest != nil {
+		l.closeFile(oldestKey, oldest)
+	}
+}
+
+// closeFile lezárja a fájlt, és ha már nem az aznapi, tömöríti; a hívó tartja a zárat
+func (l *Logger) closeFile(key string, lf *logFile) {
+	delete(l.files, key)
+	if marker := l.format.Closed(timeNow()); marker != "" {
+		lf.f.WriteString(marker + "\n")
+	}
+	if err := lf.f.Close(); err != nil {
+		log.Printf("⚠️ Csatornanapló lezárási hiba (%s): %v", lf.path, err)
+	}
+	if lf.day != timeNow().Format(dayLayout) {
+		l.compress(lf.path)
+	}
+}
+
+// sweep a korábbi napok nyers (nem nyitott) naplóit tömöríti a háttérben
+func (l *Logger) sweep() {
+	if l.opts.Config.NoCompress {
+		return
+	}
+	ext := l.format.Ext()
+	paths, err := filepath.Glob(filepath.Join(l.opts.Dir, "*"+ext))
+	if err != nil {
+		return
+	}
+	today := timeNow().Format(dayLayout)
+
+	l.mu.Lock()
+	open := make(map[string]bool, len(l.files))
+	for _, lf := range l.files {
+		open[lf.path] = true
+	}
+	for _, path := range paths {
+		if day := dayOf(path, ext); day != "" && day < today && !open[path] {
+			l.compress(path)
+		}
+	}
+	l.mu.Unlock()
+}
+
+// compress a háttérben tömöríti a fájlt. Egy fájl egyszerre csak egyszer
+// kerül sorra: a lezárás és a söprés ugyanazt az ideiglenes .gz.tmp fájlt
+// írná, és egymás kimenetét rontanák el. A hívó tartja a zárat.
+func (l *Logger) compress(path string) {
+	if l.opts.Config.NoCompress || l.queued[path] {
+		return
+	}
+	l.queued[path] = true
+	l.compressing.Add(1)
+	go func() {
+		defer l.compressing.Done()
+		if err := compressFile(path); err != nil {
+			log.Printf("⚠️ Csatornanapló tömörítési hiba (%s): %v", path, err)
+		}
+		l.mu.Lock()
+		delete(l.queued, path)
+		l.mu.Unlock()
+	}()
+}
+
+// channel a csatorna tagsági bejegyzése (létrehozza, ha még nincs); a hívó tartja a zárat
+func (l *Logger) channel(name string) *members {
+	key := strings.ToLower(name)
+	m := l.members[key]
+	if m == nil {
+		m = &members{name: name, nicks: make(map[string]bool)}
+		l.members[key] = m
+	}
+	return m
+}
+
+// leave a nick kilépett a csatornáról; ha a bot lépett ki, a csatorna tagsága törlődik
+func (l *Logger) leave(channel, nick string) {
+	if nick == strings.ToLower(l.self()) {
+		delete(l.members, strings.ToLower(channel))
+		return
+	}
+	delete(l.channel(channel).nicks, nick)
+}
+
+// whereIs a csatornák, ahol a nick bent van, és a vele folyó privát beszélgetés, ha nyitva van
+func (l *Logger) whereIs(nick string) []string {
+	var targets []string
+	lower := strings.ToLower(nick)
+	for _, m := range l.members {
+		if m.nicks[lower] {
+			targets = append(targets, m.name)
+		}
+	}
+	if _, ok := l.files[safeName(nick)]; ok {
+		targets = append(targets, nick)
+	}
+	return targets
+}
+
+func (l *Logger) self() string {
+	if l.opts.Nick == nil {
+		return ""
+	}
+	return l.opts.Nick()
+}
+
+// ctcp szétválasztja a CTCP ACTION-t (/me) a sima üzenettől; a többi CTCP-t nem naplózzuk
+func ctcp(text string) (typ, body string, ok bool) {
+	if !strings.HasPrefix(text, "\x01") {
+		return TypeMessage, text, true
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(text, "\x01"), "\x01")
+	if action, found := strings.CutPrefix(inner, "ACTION "); found {
+		return TypeAction, action, true
+	}
+	return "", "", false
+}
+
+// hostOf a nick!user@host előtag user@host része
+func hostOf(sender string) string {
+	_, host, _ := strings.Cut(sender, "!")
+	return host
+}
@@ -0,0 +1,95 @@
+package chanlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+// Privát üzenetenként nyílik egy fájl: a számuk korlátos, a tétlenek bezáródnak
+func TestOpenFilesLimited(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	l, err := New(Options{Dir: t.TempDir(), Config: config.ChannelLogConfig{NoCompress: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	say := func(nick, channel string) {
+		l.Message(irc.Message{Sender: nick + "!u@h", Nick: nick, Channel: channel, Text: "szia"})
+		now = now.Add(time.Second)
+	}
+	open := func() int {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.files)
+	}
+
+	say("alice", "#ynm")
+	for i := 0; i < maxOpenFiles+10; i++ {
+		say(fmt.Sprintf("bot%d", i), "YnM")
+		say("alice", "#ynm") // az aktív csatorna nem esik ki
+	}
+	if n := open(); n != maxOpenFiles {
+		t.Errorf("nyitott fájlok: %d, várt %d", n, maxOpenFiles)
+	}
+	l.mu.Lock()
+	_, channelOpen := l.files[safeName("#ynm")]
+	_, firstOpen := l.files[safeName("bot0")]
+	l.mu.Unlock()
+	if !channelOpen || firstOpen {
+		t.Errorf("LRU: #ynm nyitva: %v, bot0 nyitva: %v", channelOpen, firstOpen)
+	}
+
+	now = now.Add(idleTimeout / 2)
+	say("alice", "#ynm")
+	now = now.Add(idleTimeout/2 + time.Minute)
+	l.Rotate()
+	if n := open(); n != 1 {
+		t.Errorf("a tétlen fájlok nyitva maradtak: %d", n)
+	}
+}
+
+// Napváltáskor a lezárt fájl és a söprés ugyanazt a naplót egyszer tömöríti,
+// a .gz visszaolvasható
+func TestRotateCompresses(t *testing.T) {
+	now := time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	dir := t.TempDir()
+	l, err := New(Options{Dir: dir, Config: config.ChannelLogConfig{Format: "json"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		l.Message(irc.Message{Sender: "alice!u@h", Nick: "alice", Channel: "#ynm", Text: fmt.Sprintf("sor %d", i)})
+	}
+	now = now.Add(2 * time.Minute)
+	l.Rotate()
+	l.Rotate()
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path, _ := logPath(dir, "#ynm", "2025-03-01", l.format.Ext())
+	for _, p := range []string{path, path + partialGzip} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s megmaradt: %v", filepath.Base(p), err)
+		}
+	}
+	entries, err := l.Day("#ynm", "2025-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1000 || entries[999].Text != "sor 999" {
+		t.Errorf("visszaolvasva %d bejegyzés", len(entries))
+	}
+}
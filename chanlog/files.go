@@ -0,0 +1,103 @@
+package chanlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	dayLayout   = "2006-01-02"
+	maxNameLen  = 64
+	gzipSuffix  = ".gz"
+	partialGzip = ".gz.tmp"
+)
+
+// isChannel true, ha a cél csatorna (és nem nick)
+func isChannel(target string) bool {
+	return target != "" && strings.ContainsRune("#&+!", rune(target[0]))
+}
+
+// safeName a célból képzett fájlnév-előtag: kisbetűs, csak betű, szám és a
+// csatornanevekben szokásos jelek maradnak, minden más (/, \, szóköz,
+// vezérlőkarakter) "_" lesz, így a név nem vezethet ki a naplókönyvtárból.
+func safeName(target string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range strings.ToLower(target) {
+		if n == maxNameLen {
+			break
+		}
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), strings.ContainsRune("#&+!-_.[]{}^`|", r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+		n++
+	}
+	name := strings.TrimLeft(b.String(), ".")
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// logPath a cél adott napi naplófájlja: <dir>/<név>_<nap><ext>
+func logPath(dir, target, day, ext string) (string, error) {
+	path := filepath.Join(dir, safeName(target)+"_"+day+ext)
+	if filepath.Dir(path) != filepath.Clean(dir) {
+		return "", fmt.Errorf("érvénytelen naplócél: %q", target)
+	}
+	return path, nil
+}
+
+// dayOf a naplófájl nevéből kiolvasott nap ("" ha a név nem ilyen alakú)
+func dayOf(path, ext string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ext)
+	if len(name) < len(dayLayout)+2 || name[len(name)-len(dayLayout)-1] != '_' {
+		return ""
+	}
+	return name[len(name)-len(dayLayout):]
+}
+
+// compressFile gzip-be tömöríti a fájlt (név.log → név.log.gz), majd törli az eredetit.
+// Félbeszakadt tömörítés után az eredeti megmarad, a következő söprés újrapróbálja.
+// A már eltűnt (közben letömörített) fájl nem hiba.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + partialGzip
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+gzipSuffix)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,44 @@
+package chanlog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSafeName(t *testing.T) {
+	tests := []struct{ target, want string }{
+		{"#YnM", "#ynm"},
+		{"&helyi", "&helyi"},
+		{"Bob[away]", "bob[away]"},
+		{"#árvíztűrő", "#árvíztűrő"},
+		{"../../etc/passwd", "_.._etc_passwd"},
+		{"..", "_"},
+		{"...", "_"},
+		{".rejtett", "rejtett"},
+		{"a/b\\c d", "a_b_c_d"},
+		{"#x\x00y\r\n", "#x_y__"},
+		{"", "_"},
+		{strings.Repeat("é", 100), strings.Repeat("é", maxNameLen)},
+	}
+	for _, tt := range tests {
+		if got := safeName(tt.target); got != tt.want {
+			t.Errorf("safeName(%q) = %q, várt %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+// A naplófájl bármilyen célnév esetén a naplókönyvtárban marad
+func TestLogPathStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, target := range []string{"#ynm", "../kint", "..", "/abs/path", "a\\..\\b", "#x/../../y"} {
+		path, err := logPath(dir, target, "2025-03-01", ".log")
+		if err != nil {
+			t.Errorf("logPath(%q): %v", target, err)
+			continue
+		}
+		if filepath.Dir(path) != dir {
+			t.Errorf("logPath(%q) = %s, kívül esik: %s", target, path, dir)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package chanlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry egy naplózott esemény
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Target string    `json:"target"` // csatorna, vagy privát beszélgetésnél a partner nickje
+	Type   string    `json:"type"`   // message, action, notice, join, part, quit, nick, kick, topic
+	Nick   string    `json:"nick"`
+	Host   string    `json:"host,omitempty"`  // user@host, ha ismert
+	Text   string    `json:"text,omitempty"`  // üzenet, indok vagy topic
+	Other  string    `json:"other,omitempty"` // KICK: a kirúgott nick, NICK: az új nick
+	Self   bool      `json:"self,omitempty"`  // a bot saját sora
+}
+
+// Az Entry.Type értékei
+const (
+	TypeMessage = "message"
+	TypeAction  = "action"
+	TypeNotice  = "notice"
+	TypeJoin    = "join"
+	TypePart    = "part"
+	TypeQuit    = "quit"
+	TypeNick    = "nick"
+	TypeKick    = "kick"
+	TypeTopic   = "topic"
+)
+
+// Format egy naplóformátum: a sorok és a fájl megnyitásakor/zárásakor írt jelölés
+type Format interface {
+	Line(e Entry) string
+	Opened(t time.Time) string // "" ha a formátum nem jelöli
+	Closed(t time.Time) string
+	Ext() string
+}
+
+// FormatByName a config channel_log.format értéke alapján ("" → irssi)
+func FormatByName(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "irssi":
+		return irssiFormat{}, nil
+	case "weechat":
+		return weechatFormat{}, nil
+	case "json":
+		return jsonFormat{}, nil
+	}
+	return nil, fmt.Errorf("ismeretlen naplóformátum: %q (irssi, weechat, json)", name)
+}
+
+// irssiFormat: 12:34 <nick> szöveg, 12:34 -!- nick [user@host] has joined #csatorna
+type irssiFormat struct{}
+
+func (irssiFormat) Ext() string { return ".log" }
+
+func (irssiFormat) Opened(t time.Time) string {
+	return "--- Log opened " + t.Format("Mon Jan 02 15:04:05 2006")
+}
+
+func (irssiFormat) Closed(t time.Time) string {
+	return "--- Log closed " + t.Format("Mon Jan 02 15:04:05 2006")
+}
+
+func (irssiFormat) Line(e Entry) string {
+	ts := e.Time.Format("15:04") + " "
+	switch e.Type {
+	case TypeAction:
+		return ts + " * " + e.Nick + " " + e.Text
+	case TypeNotice:
+		if isChannel(e.Target) {
+			return ts + "-" + e.Nick + ":" + e.Target + "- " + e.Text
+		}
+		return ts + "-" + e.Nick + "- " + e.Text
+	case TypeJoin:
+		return ts + "-!- " + e.Nick + hostIn(e.Host, "[", "]") + " has joined " + e.Target
+	case TypePart:
+		return ts + "-!- " + e.Nick + hostIn(e.Host, "[", "]") + " has left " + e.Target + " [" + e.Text + "]"
+	case TypeQuit:
+		return ts + "-!- " + e.Nick + hostIn(e.Host, "[", "]") + " has quit [" + e.Text + "]"
+	case TypeNick:
+		return ts + "-!- " + e.Nick + " is now known as " + e.Other
+	case TypeKick:
+		return ts + "-!- " + e.Other + " was kicked from " + e.Target + " by " + e.Nick + " [" + e.Text + "]"
+	case TypeTopic:
+		return ts + "-!- " + e.Nick + " changed the topic of " + e.Target + " to: " + e.Text
+	}
+	return ts + "<" + e.Nick + "> " + e.Text
+}
+
+// weechatFormat: 2025-06-01 12:34:56<TAB>előtag<TAB>szöveg
+type weechatFormat struct{}
+
+func (weechatFormat) Ext() string             { return ".log" }
+func (weechatFormat) Opened(time.Time) string { return "" }
+func (weechatFormat) Closed(time.Time) string { return "" }
+
+func (weechatFormat) Line(e Entry) string {
+	prefix, text := e.Nick, e.Text
+	host := hostIn(e.Host, "(", ")")
+	switch e.Type {
+	case TypeAction:
+		prefix, text = " *", e.Nick+" "+e.Text
+	case TypeNotice:
+		prefix, text = "--", "Notice("+e.Nick+"): "+e.Text
+	case TypeJoin:
+		prefix, text = "-->", e.Nick+host+" has joined "+e.Target
+	case TypePart:
+		prefix, text = "<--", e.Nick+host+" has left "+e.Target+" ("+e.Text+")"
+	case TypeQuit:
+		prefix, text = "<--", e.Nick+host+" has quit ("+e.Text+")"
+	case TypeNick:
+		prefix, text = "--", e.Nick+" is now known as "+e.Other
+	case TypeKick:
+		prefix, text = "<--", e.Nick+" has kicked "+e.Other+" ("+e.Text+")"
+	case TypeTopic:
+		prefix, text = "--", e.Nick+" has changed topic for "+e.Target+" to \""+e.Text+"\""
+	}
+	return e.Time.Format("2006-01-02 15:04:05") + "\t" + prefix + "\t" + text
+}
+
+// jsonFormat: soronként egy JSON objektum (JSON Lines)
+type jsonFormat struct{}
+
+func (jsonFormat) Ext() string             { return ".jsonl" }
+func (jsonFormat) Opened(time.Time) string { return "" }
+func (jsonFormat) Closed(time.Time) string { return "" }
+
+func (jsonFormat) Line(e Entry) string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func hostIn(host, open, close string) string {
+	if host == "" {
+		return ""
+	}
+	return " " + open + host + close
+}
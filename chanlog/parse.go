@@ -0,0 +1,177 @@
+package chanlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogFile egy naplófájl a LogDir-ben
+type LogFile struct {
+	Path       string
+	Name       string // a tömörítés nélküli fájlnév (a .gz előtti rész)
+	Target     string // a fájlnévből: kisbetűs csatorna vagy nick
+	Day        string // 2025-06-01
+	Ext        string // .log vagy .jsonl
+	Compressed bool
+}
+
+var logFileRe = regexp.MustCompile(`^(.+)_(\d{4}-\d{2}-\d{2})(\.log|\.jsonl)(\.gz)?$`)
+
+// ListFiles a könyvtár naplófájljai (a nyersek és a tömörítettek is), név szerint
+func ListFiles(dir string) ([]LogFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []LogFile
+	for _, e := range entries {
+		m := logFileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		files = append(files, LogFile{
+			Path:       filepath.Join(dir, e.Name()),
+			Name:       strings.TrimSuffix(e.Name(), gzipSuffix),
+			Target:     m[1],
+			Day:        m[2],
+			Ext:        m[3],
+			Compressed: m[4] != "",
+		})
+	}
+	return files, nil
+}
+
+// ReadFile a naplófájl visszaolvasható bejegyzései (a tömörítetté is)
+func ReadFile(f LogFile) ([]Entry, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if f.Compressed {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	var entries []Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if e, ok := ParseLine(f, sc.Text()); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+var (
+	irssiLineRe   = regexp.MustCompile(`^(\d{2}:\d{2}) (.*)$`)
+	legacyLineRe  = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\] <([^>]*)> (.*)$`) // a régi Logger sorai
+	weechatLineRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\t([^\t]*)\t(.*)$`)
+	irssiTopicRe  = regexp.MustCompile(`^-!- (\S+) changed the topic of (\S+) to: (.*)$`)
+	weeTopicRe    = regexp.MustCompile(`^(\S+) has changed topic for (\S+) to "(.*)"$`)
+)
+
+// ParseLine visszaolvas egy naplósort (bármelyik formátumból, a régi
+// "[15:04:05] <nick!user@host> szöveg" sorokból is). Az irssi sorok a napot a
+// fájlnévből kapják. Az üzenetek, /me sorok, notice-ok és topicok olvashatók
+// vissza; a többi sorra (és a "--- Log opened" jelölésekre) ok=false.
+func ParseLine(f LogFile, line string) (Entry, bool) {
+	e := Entry{Target: f.Target}
+	if f.Ext == ".jsonl" {
+		if err := json.Unmarshal([]byte(line), &e); err != nil || e.Type == "" {
+			return Entry{}, false
+		}
+		return e, true
+	}
+
+	if m := weechatLineRe.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", m[1], time.Local)
+		if err != nil {
+			return Entry{}, false
+		}
+		e.Time = t
+		prefix, text := m[2], m[3]
+		switch prefix {
+		case "-->", "<--":
+			return Entry{}, false
+		case " *":
+			e.Type = TypeAction
+			e.Nick, e.Text, _ = strings.Cut(text, " ")
+		case "--":
+			if rest, ok := strings.CutPrefix(text, "Notice("); ok {
+				nick, body, found := strings.Cut(rest, "): ")
+				if !found {
+					return Entry{}, false
+				}
+				e.Type, e.Nick, e.Text = TypeNotice, nick, body
+			} else if t := weeTopicRe.FindStringSubmatch(text); t != nil {
+				e.Type, e.Nick, e.Text = TypeTopic, t[1], t[3]
+			} else {
+				return Entry{}, false
+			}
+		default:
+			e.Type, e.Nick, e.Text = TypeMessage, prefix, text
+		}
+		return e, true
+	}
+
+	if m := legacyLineRe.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", f.Day+" "+m[1], time.Local)
+		if err != nil {
+			return Entry{}, false
+		}
+		nick, host, _ := strings.Cut(m[2], "!")
+		return Entry{Time: t, Target: f.Target, Type: TypeMessage, Nick: nick, Host: host, Text: m[3]}, true
+	}
+
+	m := irssiLineRe.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", f.Day+" "+m[1], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	e.Time = t
+	body := m[2]
+	switch {
+	case strings.HasPrefix(body, "<"):
+		nick, text, found := strings.Cut(body[1:], "> ")
+		if !found {
+			return Entry{}, false
+		}
+		e.Type, e.Nick, e.Text = TypeMessage, nick, text
+	case strings.HasPrefix(body, " * "):
+		e.Type = TypeAction
+		e.Nick, e.Text, _ = strings.Cut(body[3:], " ")
+	case strings.HasPrefix(body, "-!- "):
+		t := irssiTopicRe.FindStringSubmatch(body)
+		if t == nil {
+			return Entry{}, false
+		}
+		e.Type, e.Nick, e.Text = TypeTopic, t[1], t[3]
+	case strings.HasPrefix(body, "-"):
+		head, text, found := strings.Cut(body[1:], "- ")
+		if !found {
+			return Entry{}, false
+		}
+		nick, _, _ := strings.Cut(head, ":")
+		e.Type, e.Nick, e.Text = TypeNotice, nick, text
+	default:
+		return Entry{}, false
+	}
+	return e, true
+}
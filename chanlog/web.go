@@ -0,0 +1,334 @@
+package chanlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxWebClients = 100              // egyszerre nyitott élő (SSE) kapcsolatok
+	webPingEvery  = 25 * time.Second // üres sor, hogy a proxy ne bontsa a kapcsolatot
+	nickColors    = 12
+)
+
+// WebOptions a webes naplónéző beállításai
+type WebOptions struct {
+	Channels func() []string               // a weben olvasható csatornák (weblog.enabled)
+	Redacted func(channel string) []string // a rejtett nickek csatornánként (weblog.redact)
+	Done     <-chan struct{}               // a szerver leállása: az élő kapcsolatok bontásához
+}
+
+// Viewer a csatornanapló csak olvasható webes nézete: a napi napló (a korábbi
+// napok is) és az új sorok élőben (server-sent events). Az élő sorok a Logger
+// feliratkozásából jönnek, nem a fájlok újraolvasásából.
+type Viewer struct {
+	l       *Logger
+	opts    WebOptions
+	clients atomic.Int32
+}
+
+func NewViewer(l *Logger, opts WebOptions) *Viewer {
+	return &Viewer{l: l, opts: opts}
+}
+
+// webLine egy megjelenített sor (a HTML oldalon és az SSE eseményekben is)
+type webLine struct {
+	Time  string `json:"time"`
+	Type  string `json:"type"`
+	Nick  string `json:"nick,omitempty"`
+	Color int    `json:"color"`
+	Text  string `json:"text"`
+}
+
+// webTypes a weben mutatott bejegyzések: minden formátumból visszaolvashatók
+// (lásd ParseLine), így az élő és az újratöltött oldal ugyanazt mutatja
+var webTypes = map[string]bool{TypeMessage: true, TypeAction: true, TypeNotice: true, TypeTopic: true}
+
+// channel a kérésben szereplő csatorna (a "#" elhagyható), ha a weben olvasható
+func (v *Viewer) channel(r *http.Request) (string, bool) {
+	name := r.PathValue("channel")
+	if !isChannel(name) {
+		name = "#" + name
+	}
+	for _, ch := range v.opts.Channels() {
+		if strings.EqualFold(ch, name) {
+			return ch, true
+		}
+	}
+	return "", false
+}
+
+// ServeIndex: GET /logs – a weben olvasható csatornák
+func (v *Viewer) ServeIndex(w http.ResponseWriter, r *http.Request) {
+	channels := v.opts.Channels()
+	sort.Strings(channels)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	logIndexPage.Execute(w, channels)
+}
+
+// ServeChannel: GET /logs/{channel}?day=2025-06-01 – a napi napló (alapból a mai)
+func (v *Viewer) ServeChannel(w http.ResponseWriter, r *http.Request) {
+	channel, ok := v.channel(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	today := timeNow().Format(dayLayout)
+	day := r.URL.Query().Get("day")
+	if _, err := time.Parse(dayLayout, day); err != nil {
+		day = today
+	}
+
+	entries, err := v.l.Day(channel, day)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		http.Error(w, "a napló nem olvasható", http.StatusInternalServerError)
+		return
+	}
+	redact := newRedactor(v.opts.Redacted(channel))
+	lines := make([]webLine, 0, len(entries))
+	for _, e := range entries {
+		if webTypes[e.Type] {
+			lines = append(lines, redact.line(e))
+		}
+	}
+
+	days, _ := v.l.Days(channel)
+	if len(days) == 0 || days[0] != today {
+		days = append([]string{today}, days...)
+	}
+	var prev, next string
+	for i, d := range days {
+		if d == day {
+			if i > 0 {
+				next = days[i-1]
+			}
+			if i+1 < len(days) {
+				prev = days[i+1]
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	logChannelPage.Execute(w, map[string]interface{}{
+		"Channel": channel,
+		"Path":    url.PathEscape(channel),
+		"Day":     day,
+		"Days":    days,
+		"Prev":    prev,
+		"Next":    next,
+		"Live":    day == today,
+		"Lines":   lines,
+	})
+}
+
+// ServeEvents: GET /logs/{channel}/events – az új sorok server-sent eventként
+func (v *Viewer) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	channel, ok := v.channel(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "a streamelés nem támogatott", http.StatusInternalServerError)
+		return
+	}
+	if v.clients.Add(1) > maxWebClients {
+		v.clients.Add(-1)
+		http.Error(w, "túl sok nyitott kapcsolat, próbáld később", http.StatusServiceUnavailable)
+		return
+	}
+	defer v.clients.Add(-1)
+
+	entries, cancel := v.l.Subscribe(64)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: ne pufferelje
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	ping := time.NewTicker(webPingEvery)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-v.opts.Done:
+			return
+		case <-ping.C:
+			if _, ok := v.channel(r); !ok {
+				return // közben kikapcsolták
+			}
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case e, ok := <-entries:
+			if !ok {
+				return
+			}
+			if !strings.EqualFold(e.Target, channel) || !webTypes[e.Type] {
+				continue
+			}
+			data, _ := json.Marshal(newRedactor(v.opts.Redacted(channel)).line(e))
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// redactor a rejtett nickeket "***"-ra cseréli a nick mezőben és a szövegben
+type redactor struct {
+	nicks map[string]bool
+}
+
+// nickWordRe a szövegben a nickként értelmezhető szavak: a nickekben megengedett
+// karakterek leghosszabb sorozatai (egyszer fordul, a rejtett nickek a mapből jönnek)
+var nickWordRe = regexp.MustCompile("[A-Za-z0-9_\\-\\[\\]\\\\^{}|`]+")
+
+func newRedactor(nicks []string) redactor {
+	rd := redactor{nicks: make(map[string]bool)}
+	for _, n := range nicks {
+		if n = strings.TrimSpace(n); n != "" {
+			rd.nicks[strings.ToLower(n)] = true
+		}
+	}
+	return rd
+}
+
+func (rd redactor) nick(n string) string {
+	if rd.nicks[strings.ToLower(n)] {
+		return "***"
+	}
+	return n
+}
+
+func (rd redactor) text(s string) string {
+	if len(rd.nicks) == 0 {
+		return s
+	}
+	return nickWordRe.ReplaceAllStringFunc(s, rd.nick)
+}
+
+func (rd redactor) line(e Entry) webLine {
+	nick := rd.nick(e.Nick)
+	text := rd.text(StripFormatting(e.Text))
+	if e.Type == TypeTopic {
+		text = nick + " új topicot állított be: " + text
+	}
+	return webLine{Time: e.Time.Format("15:04"), Type: e.Type, Nick: nick, Color: nickColor(nick), Text: text}
+}
+
+// nickColor a nickhez rendelt szín sorszáma (mindig ugyanaz a nickhez)
+func nickColor(nick string) int {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(nick)))
+	return int(h.Sum32() % nickColors)
+}
+
+var formattingRe = regexp.MustCompile("\x03(\\d{1,2}(,\\d{1,2})?)?|[\x02\x0f\x11\x16\x1d\x1e\x1f]")
+
+// StripFormatting eltávolítja az IRC színeket és formázást (félkövér, dőlt, ...)
+func StripFormatting(s string) string {
+	return formattingRe.ReplaceAllString(s, "")
+}
+
+const webStyle = `<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 1000px; padding: 0 1em; color: #222; }
+h1 { font-size: 1.4em; }
+#log { font-family: ui-monospace, monospace; font-size: .92em; line-height: 1.45; }
+.line { white-space: pre-wrap; word-break: break-word; }
+.time { color: #999; margin-right: .6em; }
+.event { color: #888; }
+.muted { color: #888; font-size: .9em; }
+nav a { margin-right: 1em; }
+.n0 { color: #c0392b; } .n1 { color: #2471a3; } .n2 { color: #1e8449; } .n3 { color: #b9770e; }
+.n4 { color: #7d3c98; } .n5 { color: #138d75; } .n6 { color: #a04000; } .n7 { color: #2e4053; }
+.n8 { color: #d35400; } .n9 { color: #5b2c6f; } .n10 { color: #117864; } .n11 { color: #922b21; }
+</style>`
+
+var logIndexPage = template.Must(template.New("logs").Funcs(template.FuncMap{"path": url.PathEscape}).Parse(`<!DOCTYPE html>
+<html lang="hu">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>YnM – csatornanaplók</title>` + webStyle + `</head>
+<body>
+<h1>📜 Csatornanaplók</h1>
+{{range .}}<p><a href="logs/{{path .}}">{{.}}</a></p>
+{{else}}<p class="muted">Egyik csatorna naplója sem olvasható a weben.</p>{{end}}
+</body>
+</html>
+`))
+
+var logChannelPage = template.Must(template.New("channel").Parse(`<!DOCTYPE html>
+<html lang="hu">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Channel}} – {{.Day}}</title>` + webStyle + `</head>
+<body>
+<h1>📜 {{.Channel}} – {{.Day}}</h1>
+<nav>
+<a href="../logs">Csatornák</a>
+{{if .Prev}}<a href="?day={{.Prev}}">← {{.Prev}}</a>{{end}}
+{{if .Next}}<a href="?day={{.Next}}">{{.Next}} →</a>{{end}}
+<select onchange="location.search = '?day=' + this.value">
+{{$day := .Day}}{{range .Days}}<option{{if eq . $day}} selected{{end}}>{{.}}</option>{{end}}
+</select>
+{{if .Live}}<span class="muted" id="status">● élő</span>{{end}}
+</nav>
+<div id="log">
+{{range .Lines}}<div class="line"><span class="time">{{.Time}}</span>
+{{- if eq .Type "message"}}<span class="n{{.Color}}">&lt;{{.Nick}}&gt;</span> {{.Text}}
+{{- else if eq .Type "action"}}<span class="n{{.Color}}">* {{.Nick}}</span> {{.Text}}
+{{- else if eq .Type "notice"}}<span class="n{{.Color}}">-{{.Nick}}-</span> {{.Text}}
+{{- else}}<span class="event">{{.Text}}</span>{{end}}</div>
+{{else}}<p class="muted" id="empty">Erre a napra nincs napló.</p>{{end}}
+</div>
+{{if .Live}}
+<script>
+const log = document.getElementById("log");
+function add(l) {
+  const atBottom = window.innerHeight + window.scrollY >= document.body.offsetHeight - 40;
+  const empty = document.getElementById("empty");
+  if (empty) empty.remove();
+  const div = document.createElement("div");
+  div.className = "line";
+  const time = document.createElement("span");
+  time.className = "time";
+  time.textContent = l.time;
+  div.append(time);
+  const prefix = {message: "<" + l.nick + ">", action: "* " + l.nick, notice: "-" + l.nick + "-"}[l.type];
+  if (prefix) {
+    const nick = document.createElement("span");
+    nick.className = "n" + l.color;
+    nick.textContent = prefix;
+    div.append(nick, " " + l.text);
+  } else {
+    const ev = document.createElement("span");
+    ev.className = "event";
+    ev.textContent = l.text;
+    div.append(ev);
+  }
+  log.append(div);
+  if (atBottom) window.scrollTo(0, document.body.scrollHeight);
+}
+const status = document.getElementById("status");
+const es = new EventSource("{{.Path}}/events");
+es.onmessage = e => add(JSON.parse(e.data));
+es.onopen = () => { status.textContent = "● élő"; };
+es.onerror = () => { status.textContent = "○ újracsatlakozás…"; };
+window.scrollTo(0, document.body.scrollHeight);
+</script>
+{{end}}
+</body>
+</html>
+`))
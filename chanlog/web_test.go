@@ -0,0 +1,25 @@
+package chanlog
+
+import "testing"
+
+func TestRedactor(t *testing.T) {
+	rd := newRedactor([]string{"Alice", " bob[m] ", ""})
+	tests := []struct{ in, want string }{
+		{"alice: szia", "***: szia"},
+		{"ALICE és bob[m] itt", "*** és *** itt"},
+		{"alice,alice", "***,***"},
+		{"alice2 és malice nem", "alice2 és malice nem"},
+		{"bob nem, bob[m] igen", "bob nem, *** igen"},
+	}
+	for _, tt := range tests {
+		if got := rd.text(tt.in); got != tt.want {
+			t.Errorf("%q → %q, várt %q", tt.in, got, tt.want)
+		}
+	}
+	if rd.nick("Bob[M]") != "***" || rd.nick("carol") != "carol" {
+		t.Error("a nick mező rejtése hibás")
+	}
+	if got := newRedactor(nil).text("alice"); got != "alice" {
+		t.Errorf("üres lista: %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// admins: list | add <nick> <szint> <maszk> | remove <nick>
+// A lista a közös adatbázisban van, így a futó bot is azonnal látja a változást.
+func (o *options) admins(args []string) error {
+	if len(args) == 0 {
+		return o.usageError()
+	}
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	db, err := storage.Open(cfg.StoragePath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	store := admin.NewMultiAdminStore(db.Admins)
+
+	switch args[0] {
+	case "list":
+		return o.listAdmins(store)
+
+	case "add":
+		fs := o.adminFlags("admins add")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 3 {
+			return o.usageError()
+		}
+		nick, hostmask := fs.Arg(0), fs.Arg(2)
+		level, err := admin.ParseLevel(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("hibás szint: %s (vip, admin, owner)", fs.Arg(1))
+		}
+		if !strings.Contains(hostmask, "@") {
+			return fmt.Errorf("hibás hostmask: %s (pl. *!*@YnM.ynm.hu)", hostmask)
+		}
+
+		// a meglévő bejegyzés (más szinten is) lecserélődik
+		store.RemoveAdmin(nick)
+		info := admin.AdminInfo{Nick: nick, Hostmask: hostmask, Level: level, AddedBy: "cli", AddedAt: time.Now()}
+		if err := store.AddAdmin(info); err != nil {
+			return fmt.Errorf("nem sikerült felvenni: %w", err)
+		}
+		fmt.Fprintf(o.stdout, "✅ %s felvéve: %s (%s)\n", nick, admin.LevelName(level), hostmask)
+		return nil
+
+	case "remove":
+		fs := o.adminFlags("admins remove")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 1 {
+			return o.usageError()
+		}
+		if !store.RemoveAdmin(fs.Arg(0)) {
+			return fmt.Errorf("%s nem szerepel a listában", fs.Arg(0))
+		}
+		fmt.Fprintf(o.stdout, "✅ %s törölve\n", fs.Arg(0))
+		return nil
+	}
+	return o.usageError()
+}
+
+func (o *options) adminFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(o.stderr)
+	fs.Usage = func() {}
+	return fs
+}
+
+func (o *options) listAdmins(store *admin.MultiAdminStore) error {
+	admins := store.ListAll()
+	if len(admins) == 0 {
+		fmt.Fprintln(o.stdout, "Nincs admin.")
+		return nil
+	}
+	sort.Slice(admins, func(i, j int) bool {
+		if admins[i].Level != admins[j].Level {
+			return admins[i].Level > admins[j].Level
+		}
+		return strings.ToLower(admins[i].Nick) < strings.ToLower(admins[j].Nick)
+	})
+
+	w := tabwriter.NewWriter(o.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SZINT\tNICK\tHOSTMASK\tFELVETTE\tIDŐPONT")
+	for _, a := range admins {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", admin.LevelName(a.Level), a.Nick, a.Hostmask, a.AddedBy,
+			a.AddedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
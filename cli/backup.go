@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ynmhu/YnM-Go/backup"
+	"github.com/ynmhu/YnM-Go/control"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// backup [--dir <könyvtár>] [--keep <n>]
+func (o *options) backup(args []string) error {
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	opt := backup.OptionsFromConfig(cfg)
+
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	fs.SetOutput(o.stderr)
+	fs.Usage = func() {}
+	fs.StringVar(&opt.Dir, "dir", opt.Dir, "célkönyvtár")
+	fs.IntVar(&opt.Keep, "keep", opt.Keep, "ennyi mentés marad (0: mind)")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		return o.usageError()
+	}
+
+	db, err := storage.Open(opt.StoragePath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	res, err := backup.Create(db, opt)
+	if res == nil {
+		return err
+	}
+	for _, f := range res.Manifest.Files {
+		fmt.Fprintf(o.stdout, "  %-24s %8d  %s\n", f.Name, f.Size, f.SHA256[:12])
+	}
+	fmt.Fprintf(o.stdout, "✅ %s (%d KB)\n", res.Path, res.Size/1024)
+	if err != nil {
+		fmt.Fprintf(o.stderr, "⚠️ %v\n", err)
+	}
+	return nil
+}
+
+// restore [--check] <archívum>
+func (o *options) restore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(o.stderr)
+	fs.Usage = func() {}
+	check := fs.Bool("check", false, "csak ellenőrzés, a fájlok nem változnak")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return o.usageError()
+	}
+	archive := fs.Arg(0)
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	opt := backup.OptionsFromConfig(cfg)
+
+	if *check {
+		dir, err := os.MkdirTemp("", "ynm-restore-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		m, err := backup.Verify(archive, dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.stdout, "✅ %s rendben: %d fájl, séma %d, készült %s\n",
+			archive, len(m.Files), m.Schema, m.Created.Format("2006-01-02 15:04:05"))
+		return nil
+	}
+
+	// a futó bot nyitva tartja az adatbázist, és a saját állapotát írná vissza
+	if path := cfg.ControlSocketPath(); path != "" && control.Running(path) {
+		return fmt.Errorf("a bot fut; a visszaállítás előtt állítsd le")
+	}
+	m, err := backup.Restore(archive, opt)
+	if err != nil {
+		return err
+	}
+	for _, f := range m.Files {
+		fmt.Fprintf(o.stdout, "  %s\n", f.Name)
+	}
+	fmt.Fprintf(o.stdout, "✅ Visszaállítva: %d fájl (%s), a felülírtak *.pre-restore néven megmaradtak\n",
+		len(m.Files), m.Created.Format("2006-01-02 15:04:05"))
+	return nil
+}
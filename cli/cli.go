@@ -0,0 +1,201 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package cli a ynm-go parancssora: a bot indítása és az egyszeri parancsok
+// (config ellenőrzés, adminok, adatbázis, export, üzenetküldés a futó botnak).
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ynmhu/YnM-Go/app"
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/logging"
+)
+
+// Kilépési kódok (cron és systemd számára)
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage: hibás paraméterezés, a használati útmutató már kiírva
+var errUsage = errors.New("hibás használat")
+
+const usage = `Használat: ynm-go [kapcsolók] [parancs]
+
+Kapcsolók:
+  --config <fájl>        config fájl (alapértelmezés: config/config.yaml)
+  --data-dir <könyvtár>  adatkönyvtár, felülírja a data_dir-t
+  --log-level <szint>    debug, info, warn vagy error (alapértelmezés: info)
+
+Parancsok:
+  run                                         a bot indítása (alapértelmezett)
+  check-config                                a config ellenőrzése, majd kilépés
+  admins list                                 owner/admin/VIP lista
+  admins add <nick> <szint> <maszk>           felvétel (szint: vip, admin, owner)
+  admins remove <nick>                        törlés
+  db migrate                                  séma frissítése, régi adatfájlok átvétele
+  db backup [--dir <könyvtár>] [--keep <n>]   az adatbázis mentése
+  backup [--dir <könyvtár>] [--keep <n>]      teljes mentés tar.gz-be (adatbázis és JSON fájlok)
+  restore [--check] <archívum>                mentés ellenőrzése és visszaállítása (leállított bot mellett)
+  export requests [--format csv|json] [--status all|open|done] [--output <fájl>]
+  send <cél> <szöveg|->                       üzenet a futó boton keresztül ("-": stdin)
+`
+
+// options a globális kapcsolók
+type options struct {
+	configPath string
+	dataDir    string
+	logLevel   string
+	stdout     io.Writer
+	stderr     io.Writer
+	stdin      io.Reader
+}
+
+// Run feldolgozza az argumentumokat (os.Args[1:]) és visszaadja a kilépési kódot
+func Run(args []string) int {
+	opts := &options{stdout: os.Stdout, stderr: os.Stderr, stdin: os.Stdin}
+
+	fs := flag.NewFlagSet("ynm-go", flag.ContinueOnError)
+	fs.SetOutput(opts.stderr)
+	fs.Usage = func() { fmt.Fprint(opts.stderr, usage) }
+	fs.StringVar(&opts.configPath, "config", config.DefaultPath, "config fájl")
+	fs.StringVar(&opts.dataDir, "data-dir", "", "adatkönyvtár")
+	fs.StringVar(&opts.logLevel, "log-level", "info", "naplózási szint")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	// a --log-level felülírja a config logging.level értékét
+	logging.RedirectStdLog()
+	forced := false
+	fs.Visit(func(f *flag.Flag) { forced = forced || f.Name == "log-level" })
+	if forced {
+		if err := logging.Force(opts.logLevel); err != nil {
+			fmt.Fprintf(opts.stderr, "❌ %v\n", err)
+			return exitUsage
+		}
+	}
+
+	rest := fs.Args()
+	command := "run"
+	if len(rest) > 0 {
+		command, rest = rest[0], rest[1:]
+	}
+
+	var err error
+	switch command {
+	case "run":
+		err = opts.run(rest)
+	case "check-config":
+		err = opts.checkConfig(rest)
+	case "admins":
+		err = opts.admins(rest)
+	case "db":
+		err = opts.db(rest)
+	case "backup":
+		err = opts.backup(rest)
+	case "restore":
+		err = opts.restore(rest)
+	case "export":
+		err = opts.export(rest)
+	case "send":
+		err = opts.send(rest)
+	case "help":
+		fmt.Fprint(opts.stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(opts.stderr, "❌ Ismeretlen parancs: %s\n\n", command)
+		err = opts.usageError()
+	}
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	default:
+		fmt.Fprintf(opts.stderr, "❌ %v\n", err)
+		return exitError
+	}
+}
+
+func (o *options) usageError() error {
+	fmt.Fprint(o.stderr, usage)
+	return errUsage
+}
+
+// loadConfig betölti és ellenőrzi a configot. A --data-dir környezeti
+// felülírásként megy át, így a !rehash után is érvényes marad.
+func (o *options) loadConfig() (*config.Config, error) {
+	if o.dataDir != "" {
+		if err := os.Setenv(config.EnvPrefix+"DATA_DIR", o.dataDir); err != nil {
+			return nil, err
+		}
+	}
+	cfg, err := config.Load(o.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("config betöltési hiba: %w", err)
+	}
+	return cfg, nil
+}
+
+func (o *options) run(args []string) error {
+	if len(args) > 0 {
+		return o.usageError()
+	}
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	logFile, err := logging.Setup(cfg.Logging)
+	if err != nil {
+		return fmt.Errorf("naplózás beállítási hiba: %w", err)
+	}
+	defer logFile.Close()
+
+	log.Println("Bot starting up...")
+	if err := app.New(cfg).Run(); err != nil {
+		return fmt.Errorf("alkalmazás hiba: %w", err)
+	}
+	return nil
+}
+
+func (o *options) checkConfig(args []string) error {
+	if len(args) > 0 {
+		return o.usageError()
+	}
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.stdout, "✅ %s rendben (szerver: %s:%s, nick: %s, adatok: %s)\n",
+		cfg.Path(), cfg.Server, serverPort(cfg), cfg.NickName, cfg.DataPath(""))
+	return nil
+}
+
+func serverPort(cfg *config.Config) string {
+	if cfg.UseTLS && cfg.TLSPort != "" {
+		return cfg.TLSPort
+	}
+	return cfg.Port
+}
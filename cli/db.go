@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// db: migrate | backup [--dir <könyvtár>] [--keep <n>]
+func (o *options) db(args []string) error {
+	if len(args) == 0 {
+		return o.usageError()
+	}
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "migrate":
+		if len(args) > 1 {
+			return o.usageError()
+		}
+		return o.migrate(cfg)
+
+	case "backup":
+		fs := flag.NewFlagSet("db backup", flag.ContinueOnError)
+		fs.SetOutput(o.stderr)
+		fs.Usage = func() {}
+		dir := fs.String("dir", cfg.DataPath("backups"), "célkönyvtár")
+		keep := fs.Int("keep", 0, "ennyi mentés marad (0: mind)")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 0 {
+			return o.usageError()
+		}
+		return o.backupDB(cfg, *dir, *keep)
+	}
+	return o.usageError()
+}
+
+// migrate létrehozza/frissíti a közös adatbázist, és átveszi a régi fájlokat
+func (o *options) migrate(cfg *config.Config) error {
+	db, err := storage.Open(cfg.StoragePath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	imported, err := db.Import(storage.LegacySources(cfg))
+	for _, im := range imported {
+		fmt.Fprintf(o.stdout, "✅ %s átvéve: %s (%d sor)\n", im.Source, im.Path, im.Rows)
+	}
+	if err != nil {
+		return err
+	}
+	version, err := db.Version()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.stdout, "✅ %s naprakész (séma: %d)\n", db.Path(), version)
+	return nil
+}
+
+// backupDB az adatbázisról konzisztens másolatot készít (a futó bot mellett is)
+func (o *options) backupDB(cfg *config.Config, dir string, keep int) error {
+	path := cfg.StoragePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintln(o.stdout, "Nincs mentendő adatbázis.")
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	db, err := storage.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	target := filepath.Join(dir, fmt.Sprintf("ynm-%s.db", time.Now().Format("20060102-150405")))
+	if err := db.Backup(target); err != nil {
+		return fmt.Errorf("mentési hiba: %w", err)
+	}
+	fmt.Fprintf(o.stdout, "✅ %s → %s\n", path, target)
+
+	if keep > 0 {
+		if err := pruneBackups(dir, "ynm", keep); err != nil {
+			fmt.Fprintf(o.stderr, "⚠️ régi mentések törlése: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// pruneBackups a name-*.db mentésekből csak a legújabb keep darabot hagyja meg
+func pruneBackups(dir, name string, keep int) error {
+	matches, err := filepath.Glob(filepath.Join(dir, name+"-*.db"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches) // az időbélyeg miatt a név szerinti sorrend időrend
+	for len(matches) > keep {
+		if err := os.Remove(matches[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
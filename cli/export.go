@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// movieRequest egy filmkérés az exportban
+type movieRequest struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Year        int    `json:"year"`
+	PIN         string `json:"pin"`
+	RequestedBy string `json:"requested_by"`
+	Status      string `json:"status"` // "Nem": nyitott, "Igen": teljesítve
+	RequestedAt string `json:"requested_at"`
+	CompletedAt string `json:"completed_at,omitempty"`
+}
+
+// export requests [--format csv|json] [--status all|open|done] [--output <fájl>]
+func (o *options) export(args []string) error {
+	if len(args) == 0 || args[0] != "requests" {
+		return o.usageError()
+	}
+	fs := flag.NewFlagSet("export requests", flag.ContinueOnError)
+	fs.SetOutput(o.stderr)
+	fs.Usage = func() {}
+	format := fs.String("format", "csv", "csv vagy json")
+	status := fs.String("status", "all", "all, open vagy done")
+	output := fs.String("output", "", "kimeneti fájl (alapértelmezés: stdout)")
+	if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 0 {
+		return o.usageError()
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("ismeretlen formátum: %s (csv, json)", *format)
+	}
+	filter, ok := map[string]string{"all": "", "open": "Nem", "done": "Igen"}[*status]
+	if !ok {
+		return fmt.Errorf("ismeretlen státusz: %s (all, open, done)", *status)
+	}
+
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cfg.StoragePath()); err != nil {
+		return err
+	}
+	requests, err := loadRequests(cfg.StoragePath(), filter)
+	if err != nil {
+		return err
+	}
+
+	out := o.stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(requests)
+	} else {
+		err = writeRequestsCSV(out, requests)
+	}
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		fmt.Fprintf(o.stderr, "✅ %d kérés → %s\n", len(requests), *output)
+	}
+	return nil
+}
+
+func loadRequests(path, status string) ([]movieRequest, error) {
+	db, err := storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	movies, err := db.Movies.List(status)
+	if err != nil {
+		return nil, fmt.Errorf("adatbázis hiba: %w", err)
+	}
+	requests := []movieRequest{}
+	for _, m := range movies {
+		r := movieRequest{ID: m.ID, Title: m.Title, Year: m.Year, PIN: m.PIN, RequestedBy: m.RequestedBy,
+			Status: m.Status, RequestedAt: m.UploadDate.Format("2006-01-02 15:04:05")}
+		if m.CompletedDate != nil {
+			r.CompletedAt = m.CompletedDate.Format("2006-01-02 15:04:05")
+		}
+		requests = append(requests, r)
+	}
+	return requests, nil
+}
+
+func writeRequestsCSV(out io.Writer, requests []movieRequest) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"id", "title", "year", "pin", "requested_by", "status", "requested_at", "completed_at"})
+	for _, r := range requests {
+		_ = w.Write([]string{strconv.FormatInt(r.ID, 10), r.Title, strconv.Itoa(r.Year), r.PIN,
+			r.RequestedBy, r.Status, r.RequestedAt, r.CompletedAt})
+	}
+	w.Flush()
+	return w.Error()
+}
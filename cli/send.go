@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/control"
+)
+
+// send <cél> <szöveg...>: a "-" szöveg a standard bemenetet küldi (pl. cron kimenet)
+func (o *options) send(args []string) error {
+	if len(args) < 2 {
+		return o.usageError()
+	}
+	target, text := args[0], strings.Join(args[1:], " ")
+	if text == "-" {
+		data, err := io.ReadAll(io.LimitReader(o.stdin, 32*1024))
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("üres üzenet")
+	}
+
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	path := cfg.ControlSocketPath()
+	if path == "" {
+		return fmt.Errorf("a vezérlő socket ki van kapcsolva (control_socket: \"-\")")
+	}
+	return control.Call(path, control.Request{Command: "send", Target: target, Text: text})
+}
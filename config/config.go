@@ -12,11 +12,13 @@
 package config
 
 import (
-	"io/ioutil"
-	"gopkg.in/yaml.v2"
-	"time"
+	"path/filepath"
+	"strings"
 )
 
+// DefaultPath az alapértelmezett config fájl
+const DefaultPath = "config/config.yaml"
+
 type Config struct {
 	Server               string        `yaml:"Server"`
 	Port                 string        `yaml:"Port"`
@@ -27,8 +29,8 @@ type Config struct {
 	Channels             							[]string      			`yaml:"Channels"`
 	LogDir               							string        			`yaml:"LogDir"`
 	DataDir 										string 					`yaml:"data_dir"`
-	ReconnectOnDisconnect				time.Duration		`yaml:"ReconOnDiscon"`
-	PingCommandCooldown  string        `yaml:"Ping"`
+	ReconnectOnDisconnect				Duration		`yaml:"ReconOnDiscon"`
+	PingCommandCooldown  Duration      `yaml:"Ping"`
 	Admins               []string      `yaml:"admins"`
 
 	// NickServ beállítások
@@ -51,18 +53,18 @@ type Config struct {
 
 	// Névnap plugin
 	NevnapChannels []string `yaml:"NevnapChannels"`
-	NevnapReggel   string   `yaml:"NevnapReggel"`
-	NevnapEste     string   `yaml:"NevnapEste"`
+	NevnapReggel   Clock    `yaml:"NevnapReggel"`
+	NevnapEste     Clock    `yaml:"NevnapEste"`
 
 	// Székelyhon
 	SzekelyhonChannels  []string `yaml:"SzekelyhonChannels"`
-	SzekelyhonInterval  string   `yaml:"SzekelyhonInterval"`
+	SzekelyhonInterval  Duration `yaml:"SzekelyhonInterval"`
 	SzekelyhonStartHour int      `yaml:"SzekelyhonStartHour"`
 	SzekelyhonEndHour   int      `yaml:"SzekelyhonEndHour"`
 
 	// Viccek
 	JokeChannels []string `yaml:"JokeChannels"`
-	JokeSendTime string   `yaml:"JokeSendTime"`
+	JokeSendTime Clock    `yaml:"JokeSendTime"`
 
 	// Movie plugin configuration
 	JellyfinDBPath       string                `yaml:"jellyfin_db_path"`
@@ -84,20 +86,191 @@ type Config struct {
 	OraDatesFile string  `yaml:"ora_dates_file"`
 	OraDBFile    string  `yaml:"ora_db_file"`
 	
-	//Tama
-	 DataDirectory string `json:"data_directory"`
+	// Tama: a régi kisallatok.json helye (data_directory/data), csak az átvételhez;
+	// minden adat a data_dir könyvtárban van
+	 DataDirectory string `yaml:"data_directory"`
+
+	// Rétegzett beállítások (global → hálózat → csatorna)
+	Network  string         `yaml:"Network"` // a hálózat neve a settings.networks-höz (alapértelmezés: Server)
+	Settings SettingsConfig `yaml:"settings"`
+
+	// Külső (külön folyamatban futó) pluginok
+	ExternalPlugins []ExternalPluginConfig `yaml:"external_plugins"`
+
+	// Beágyazott JavaScript scriptek
+	Scripting ScriptingConfig `yaml:"scripting"`
+
+	// Ütemező (időzóna, feladatonkénti pótlási szabály és jitter)
+	Scheduler SchedulerConfig `yaml:"scheduler"`
+
+	// Parancsok közös korlátozása (visszaélés-védelem)
+	RateLimit RateLimitConfig `yaml:"ratelimit"`
+
+	// Figyelmen kívül hagyási lista (!ignore)
+	Ignore IgnoreConfig `yaml:"ignore"`
+
+	// Naplózás (szintek komponensenként, formátum, fájl és forgatás)
+	Logging LoggingConfig `yaml:"logging"`
+
+	// Csatornanaplók (LogDir): formátum, privát beszélgetések, tömörítés
+	ChannelLog ChannelLogConfig `yaml:"channel_log"`
+
+	// Üzenethagyás (!tell): postafiók-korlátok
+	Tell TellConfig `yaml:"tell"`
+
+	// Vezérlő socket (ynm-go send); alapértelmezés: <data_dir>/control.sock, "-" kikapcsolja
+	ControlSocket string `yaml:"control_socket"`
+
+	// !restart systemd alatt: a bot leáll, és 75-ös kóddal kilép, az új folyamatot a
+	// systemd indítja (a unitban RestartForceExitStatus=75); alapból a bot maga indítja
+	SystemdRestart bool `yaml:"systemd_restart"`
+
+	// A bot adatbázisa (adminok, filmkérések, emlékeztetők …); alapértelmezés: <data_dir>/ynm.db
+	StorageFile string `yaml:"storage_path"`
+
+	// Mentések (!backup, ynm-go backup): az adatbázis és a JSON állapotfájlok tar.gz-ben
+	Backup BackupConfig `yaml:"backup"`
+
+	// Fordítások: a beépített üzenetkatalógusok felülírása, új nyelvek
+	I18n I18nConfig `yaml:"i18n"`
+
+	// Beépített HTTP admin API (alapból kikapcsolva)
+	HTTP HTTPConfig `yaml:"http"`
+
+	path string // a fájl, amelyből betöltöttük (az újratöltéshez)
+}
+
+// LoggingConfig a bot naplózása. A komponens a naplózó csomag neve
+// (irc, app, media, ynm, admin, scheduler, scripting …).
+type LoggingConfig struct {
+	Level      string            `yaml:"level"`       // alapszint: debug, info, warn, error (alapértelmezés: info)
+	Format     string            `yaml:"format"`      // console vagy json (alapértelmezés: console)
+	File       string            `yaml:"file"`        // naplófájl, pl. logs/bot.log (üres: csak stderr)
+	AlsoStderr bool              `yaml:"also_stderr"` // fájl mellett a stderr-re is ír
+	Rotation   RotationConfig    `yaml:"rotation"`
+	Components map[string]string `yaml:"components"` // komponens → szint, pl. media: debug
+	RawIRC     bool              `yaml:"raw_irc"`    // a nyers IRC forgalom naplózása (jelszavak kitakarva)
+}
+
+// RotationConfig a naplófájl forgatása és megőrzése
+type RotationConfig struct {
+	MaxSizeMB  int  `yaml:"max_size_mb"`  // ekkora méret felett új fájl (0: nincs méretkorlát)
+	Daily      bool `yaml:"daily"`        // naponta új fájl
+	MaxBackups int  `yaml:"max_backups"`  // ennyi régi fájl marad (0: mind)
+	MaxAgeDays int  `yaml:"max_age_days"` // ennél régebbiek törlődnek (0: nincs korlát)
+}
+
+// ChannelLogConfig a csatornák és privát beszélgetések naplója a LogDir-ben.
+// Csatornánként a chanlog.enabled beállítás kapcsolja ki (!set #csatorna chanlog.enabled off).
+type ChannelLogConfig struct {
+	Format      string `yaml:"format"`       // irssi (alapértelmezés), weechat vagy json
+	SkipPrivate bool   `yaml:"skip_private"` // a privát beszélgetések ne kerüljenek naplóba
+	NoCompress  bool   `yaml:"no_compress"`  // a lezárt napok naplója ne kerüljön gzip-be
+	NoIndex     bool   `yaml:"no_index"`     // a csatornanaplók keresőindexe (!grep) kikapcsolva
+	IndexDB     string `yaml:"index_db"`     // a keresőindex (alapértelmezés: <data_dir>/logindex.db)
+}
+
+// HTTPConfig a beépített HTTP admin API. A /api végpontok tokennel védettek
+// (Authorization: Bearer <token>); a token fájlból is jöhet (token_file).
+type HTTPConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // alapértelmezés: 127.0.0.1:8089
+	Token   string `yaml:"token"`
+
+	PublicMetrics bool `yaml:"public_metrics"` // a /metrics token nélkül is elérhető (helyi Prometheushoz)
+
+	// a filmkérések weboldala (/media) és a !weblogin linkek
+	MediaPage bool   `yaml:"media_page"`
+	PublicURL string `yaml:"public_url"` // a kívülről látható cím, pl. https://bot.ynm.hu
+}
+
+// TellConfig a !tell üzenetek korlátai (0: az alapértelmezés)
+type TellConfig struct {
+	MaxInbox     int `yaml:"max_inbox"`      // ennyi átadatlan üzenet várhat egy címzettre (alapértelmezés: 20)
+	MaxPerSender int `yaml:"max_per_sender"` // ebből ennyi lehet egy feladótól (alapértelmezés: 5)
+	MaxAgeDays   int `yaml:"max_age_days"`   // ennyi nap után az át nem adott üzenet törlődik (alapértelmezés: 90)
+}
+
+// BackupConfig a mentések helye, megőrzése és időzítése
+type BackupConfig struct {
+	Dir      string `yaml:"dir"`      // célkönyvtár (alapértelmezés: <data_dir>/backups)
+	Keep     int    `yaml:"keep"`     // ennyi mentés marad, a régebbiek törlődnek (0: mind)
+	Schedule string `yaml:"schedule"` // időzített mentés, pl. "0 4 * * *" (üres: nincs)
+}
+
+// I18nConfig az üzenetkatalógusok. A válaszok nyelve csatornánként a language
+// beállítás (!set #csatorna language ro), felhasználónként a !lang parancs.
+type I18nConfig struct {
+	Dir string `yaml:"dir"` // <nyelv>.json katalógusok, kulcsonként felülírják a beépítettet (üres: csak a beépített)
+}
+
+// IgnoreConfig az ignore lista beállításai (a bejegyzések a data/ignore.json-ban vannak)
+type IgnoreConfig struct {
+	ApplyToLogging bool `yaml:"apply_to_logging"` // a globálisan ignorált küldők üzenetei a naplóba sem kerülnek
+}
 
+// RateLimitConfig a parancsok korlátozása. A csatornás szabály a parancséra,
+// az a default-ra épül: csak a megadott mezőket írja felül.
+type RateLimitConfig struct {
+	Default  *RateLimitPolicy                      `yaml:"default"`  // minden "!" parancsra (alapértelmezés: nincs)
+	Commands map[string]RateLimitPolicy            `yaml:"commands"` // kulcs: "!ping"
+	Channels map[string]map[string]RateLimitPolicy `yaml:"channels"` // csatorna → parancs → szabály
+}
+
+// RateLimitPolicy egy parancs korlátozása
+type RateLimitPolicy struct {
+	Window    Duration   `yaml:"window"`    // csúszó ablak, pl. "5m"
+	Limit     int        `yaml:"limit"`     // ennyi hívás fér az ablakba, a következő tiltással jár
+	Burst     int        `yaml:"burst"`     // ennyi hívás jöhet a cooldown kivárása nélkül (alapértelmezés: 1)
+	Cooldown  Duration   `yaml:"cooldown"`  // két hívás közti minimális idő, pl. "30s"
+	Penalties []Duration `yaml:"penalties"` // egyre hosszabb tiltások, pl. ["1h", "24h", "168h"]
+}
+
+// SchedulerConfig az ütemező beállításai
+type SchedulerConfig struct {
+	TimeZone string                     `yaml:"timezone"` // pl. "Europe/Budapest" (alapértelmezés: helyi idő)
+	Jobs     map[string]JobPolicyConfig `yaml:"jobs"`     // kulcs: feladatnév vagy előtag, pl. "napivicc"
+}
+
+// JobPolicyConfig egy feladat (vagy feladatcsoport) felülírásai
+type JobPolicyConfig struct {
+	Missed string   `yaml:"missed"` // "skip" vagy "once": a leállás alatt elmaradt futás kezelése
+	Jitter Duration `yaml:"jitter"` // legfeljebb ennyi véletlen késleltetés, pl. "2m"
+}
+
+// ScriptingConfig a scripts/ könyvtár scriptjeinek beállításai
+type ScriptingConfig struct {
+	Dir           string `yaml:"dir"`             // alapértelmezés: scripts
+	CPULimit      Duration `yaml:"cpu_limit"`     // egy hívás max futásideje (alapértelmezés: 200ms)
+	MemoryLimitMB int    `yaml:"memory_limit_mb"` // egy hívás által megtartható memória (alapértelmezés: 32)
+}
+
+// ExternalPluginConfig egy JSON-RPC-n kommunikáló külső plugin indítási adatai
+type ExternalPluginConfig struct {
+	Name    string   `yaml:"name"`    // a !plugin parancsokban használt név
+	Command string   `yaml:"command"` // futtatható fájl, pl. "./ext/kocka"
+	Args    []string `yaml:"args"`
+	Dir     string   `yaml:"dir"`     // munkakönyvtár (alapértelmezés: a bot könyvtára)
+	Env     []string `yaml:"env"`     // extra környezeti változók "KULCS=érték" alakban
+	Timeout Duration `yaml:"timeout"` // parancsonkénti időkorlát (alapértelmezés: 5s)
+}
+
+// SettingsConfig a pluginok rétegzett beállításai; a kulcsok listája a settings csomagban van
+type SettingsConfig struct {
+	Global   map[string]string            `yaml:"global"`
+	Networks map[string]map[string]string `yaml:"networks"`
+	Channels map[string]map[string]string `yaml:"channels"`
 }
 
 type MoviePluginConfig struct {
-	PostTime string `yaml:"post_time"`
+	PostTime Clock  `yaml:"post_time"`
 	PostChan string `yaml:"post_chan"`
 	PostNick string `yaml:"post_nick"`
 }
 
 type MediaAjanlatConfig struct {
 	Channel string `yaml:"channel"`
-	Time    string `yaml:"time"` // formátum: "HH:MM"
+	Time    Clock  `yaml:"time"` // formátum: "HH:MM"
 }
 
 type MediaUploadConfig struct {
@@ -108,17 +281,82 @@ type MediaUploadConfig struct {
 	SentDatesFile   string   `yaml:"sent_dates_file"`
 }
 
-func Load(filename string) (*Config, error) {
-	data, err := ioutil.ReadFile(filename)
-	if err != nil {
-		return nil, err
+// DataPath a bot adatkönyvtárán belüli útvonalat adja vissza (alapértelmezés: "data").
+func (c *Config) DataPath(name string) string {
+	dir := c.DataDir
+	if dir == "" {
+		dir = "data"
 	}
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+	return filepath.Join(dir, name)
+}
+
+// LogIndexPath a csatornanaplók keresőindexe ("" ha ki van kapcsolva)
+func (c *Config) LogIndexPath() string {
+	if c.ChannelLog.NoIndex {
+		return ""
 	}
-	return &cfg, nil
+	if c.ChannelLog.IndexDB != "" {
+		return c.ChannelLog.IndexDB
+	}
+	return c.DataPath("logindex.db")
 }
+
+// HTTPListen a HTTP API címe ("" ha ki van kapcsolva)
+func (c *Config) HTTPListen() string {
+	if !c.HTTP.Enabled {
+		return ""
+	}
+	if c.HTTP.Listen == "" {
+		return "127.0.0.1:8089"
+	}
+	return c.HTTP.Listen
+}
+
+// HTTPPublicURL a HTTP szerver kívülről látható címe, záró "/" nélkül
+// (alapértelmezés: a listen cím, reverse proxy mögött a public_url kell)
+func (c *Config) HTTPPublicURL() string {
+	if c.HTTP.PublicURL != "" {
+		return strings.TrimRight(c.HTTP.PublicURL, "/")
+	}
+	return "http://" + c.HTTPListen()
+}
+
+// MediaListURL a !kell válaszában megadott kéréslista: a bot saját oldala, ha
+// be van kapcsolva, különben a régi, külön karbantartott oldal
+func (c *Config) MediaListURL() string {
+	if c.HTTP.Enabled && c.HTTP.MediaPage {
+		return c.HTTPPublicURL() + "/media"
+	}
+	return "https://bot.ynm.hu/media"
+}
+
+// StoragePath a bot közös adatbázisa (storage_path, alapértelmezés: <data_dir>/ynm.db)
+func (c *Config) StoragePath() string {
+	if c.StorageFile != "" {
+		return c.StorageFile
+	}
+	return c.DataPath("ynm.db")
+}
+
+// BackupDir a mentések könyvtára (backup.dir, alapértelmezés: <data_dir>/backups)
+func (c *Config) BackupDir() string {
+	if c.Backup.Dir != "" {
+		return c.Backup.Dir
+	}
+	return c.DataPath("backups")
+}
+
+// ControlSocketPath a futó példány vezérlő socketje ("" ha ki van kapcsolva)
+func (c *Config) ControlSocketPath() string {
+	switch c.ControlSocket {
+	case "-":
+		return ""
+	case "":
+		return c.DataPath("control.sock")
+	}
+	return c.ControlSocket
+}
+
 type MediaItem struct {
 	Title          string      `json:"title"`
 	Genres         string      `json:"genres"`
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change egy config kulcs, amely az újratöltéskor megváltozott.
+// Az értékeket szándékosan nem tároljuk (jelszavak is lehetnek köztük).
+type Change struct {
+	Key     string // yaml útvonal, pl. "media_upload.interval_minutes"
+	Restart bool   // csak a bot újraindítása után lép életbe
+}
+
+// Changes a megváltozott kulcsok listája
+type Changes []Change
+
+// restartKeys a csak induláskor felhasznált kulcsok (kapcsolat, azonosítás, könyvtárak)
+var restartKeys = []string{
+	"Server", "Port", "NickName", "UserName", "RealName", "ReconOnDiscon",
+	"NickservBotnick", "NickservNick", "NickservPass", "autologin", "AutoJoinWithoutLogin",
+	"SASL", "SASLUser", "SASLPass", "TLS", "TLSCert", "TLSKey", "TLSPort",
+	"LogDir", "data_dir", "data_directory",
+	"external_plugins", "scripting", "scheduler.timezone", "control_socket",
+	"logging.format", "logging.file", "logging.also_stderr", "logging.rotation",
+	"channel_log", "http", "storage_path", "backup.schedule",
+}
+
+// Has true, ha valamelyik kulcs (vagy annak bármely alkulcsa) megváltozott
+func (c Changes) Has(keys ...string) bool {
+	for _, change := range c {
+		for _, key := range keys {
+			if matchKey(change.Key, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Keys a megváltozott kulcsok; restart esetén csak az újraindítást igénylők
+func (c Changes) Keys(restart bool) []string {
+	var keys []string
+	for _, change := range c {
+		if change.Restart == restart {
+			keys = append(keys, change.Key)
+		}
+	}
+	return keys
+}
+
+func matchKey(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+".")
+}
+
+// Diff összehasonlítja a két configot, és visszaadja a megváltozott kulcsokat.
+// A beágyazott struktúrákba belép, a listákat és map-eket egészben hasonlítja.
+func Diff(old, new *Config) Changes {
+	var changes Changes
+	diffValue("", reflect.ValueOf(*old), reflect.ValueOf(*new), &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+func diffValue(path string, a, b reflect.Value, changes *Changes) {
+	if a.Kind() == reflect.Struct {
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := fieldKey(field)
+			if name == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			diffValue(name, a.Field(i), b.Field(i), changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*changes = append(*changes, Change{Key: path, Restart: needsRestart(path)})
+	}
+}
+
+// fieldKey a mező yaml neve (tag híján a kisbetűs Go név, ahogy a yaml csomag is kezeli)
+func fieldKey(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func needsRestart(key string) bool {
+	for _, prefix := range restartKeys {
+		if matchKey(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package config
+
+import "sync/atomic"
+
+// Live a futó bot éppen érvényes configja. A config újratöltés (!rehash) egy
+// új, ellenőrzött példányt tesz közzé; a közzétett példányt senki nem
+// módosítja, így az olvasók zár nélkül, mindig egy teljes configot látnak.
+type Live struct {
+	current atomic.Pointer[Config]
+}
+
+// NewLive a cfg-t közzéteszi első érvényes configként
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.current.Store(cfg)
+	return l
+}
+
+// Get az éppen érvényes config; a mezőit csak olvasni szabad
+func (l *Live) Get() *Config {
+	return l.current.Load()
+}
+
+// Set közzéteszi az új configot; a korábbi Get hívók a régi példányt látják tovább
+func (l *Live) Set(cfg *Config) {
+	l.current.Store(cfg)
+}
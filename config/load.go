@@ -0,0 +1,295 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix a környezeti felülírások előtagja: YNM_SASLPASS, YNM_MEDIA_UPLOAD__ENABLED
+const EnvPrefix = "YNM_"
+
+// secretSuffix a titkos értékek fájlból olvasásának jelölése
+// (config: "SASLPass_file: /run/secrets/sasl", környezet: YNM_SASLPASS_FILE)
+const secretSuffix = "_file"
+
+// Load beolvassa, kiegészíti és ellenőrzi a configot:
+//  1. yaml feldolgozás,
+//  2. YNM_* környezeti változók és *_file titok-hivatkozások,
+//  3. ismeretlen kulcsok keresése (elgépelésnél javaslattal),
+//  4. típusos dekódolás, alapértelmezések, ellenőrzés.
+//
+// Minden hibát egyszerre ad vissza (Errors), hogy egy indítással javítható legyen.
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parse(filename, data, os.Environ())
+}
+
+// Path a fájl, amelyből a config betöltődött
+func (c *Config) Path() string {
+	if c.path == "" {
+		return DefaultPath
+	}
+	return c.path
+}
+
+func parse(filename string, data []byte, environ []string) (*Config, error) {
+	raw := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	var errs Errors
+	root := reflect.TypeOf(Config{})
+	overridden := applyEnv(raw, root, environ, &errs)
+	if resolveSecrets(raw, root, "", &errs) {
+		overridden = true
+	}
+	checkKeys(raw, root, "", &errs)
+
+	// Ha semmit nem írtunk felül, az eredeti szöveget dekódoljuk (pontos sorszámok a hibákban)
+	if overridden {
+		var err error
+		if data, err = yaml.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		if te, ok := err.(*yaml.TypeError); ok {
+			errs = append(errs, te.Errors...)
+		} else {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	cfg.path = filename
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err.(Errors)...)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &cfg, nil
+}
+
+// applyEnv a YNM_* változókat a nyers configba írja. A "__" egy szintet lép
+// lejjebb, a szegmensek kis/nagybetű és aláhúzás nélkül illeszkednek a yaml
+// kulcsokra (YNM_NICKSERVPASS → NickservPass, YNM_MEDIA_UPLOAD__ENABLED →
+// media_upload.enabled). Az érték yaml skalárként értelmeződik, így listák
+// is megadhatók: YNM_CHANNELS='["#a", "#b"]'. A _FILE végű változó értékét
+// a megadott fájlból olvassa.
+func applyEnv(raw map[interface{}]interface{}, root reflect.Type, environ []string, errs *Errors) bool {
+	sort.Strings(environ)
+	changed := false
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || name == EnvPrefix {
+			continue
+		}
+
+		segments := strings.Split(strings.TrimPrefix(name, EnvPrefix), "__")
+		keys, ok := envPath(root, segments)
+		if !ok && strings.HasSuffix(strings.ToLower(name), secretSuffix) {
+			last := len(segments) - 1
+			segments[last] = segments[last][:len(segments[last])-len(secretSuffix)]
+			if keys, ok = envPath(root, segments); ok {
+				content, err := readSecret(value)
+				if err != nil {
+					*errs = append(*errs, fmt.Sprintf("%s: %v", name, err))
+					continue
+				}
+				setPath(raw, keys, content)
+				changed = true
+				continue
+			}
+		}
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: ismeretlen config kulcs", name))
+			continue
+		}
+
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+			parsed = value
+		}
+		setPath(raw, keys, parsed)
+		changed = true
+	}
+	return changed
+}
+
+// envPath a környezeti változó szegmenseit yaml kulcsokká alakítja
+func envPath(t reflect.Type, segments []string) ([]string, bool) {
+	var keys []string
+	for _, segment := range segments {
+		t = indirect(t)
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := fieldByFold(t, segment)
+		if !ok {
+			return nil, false
+		}
+		keys = append(keys, fieldKey(field))
+		t = field.Type
+	}
+	return keys, len(keys) > 0
+}
+
+func setPath(raw map[interface{}]interface{}, keys []string, value interface{}) {
+	m := raw
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[interface{}]interface{})
+		if !ok {
+			next = make(map[interface{}]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// resolveSecrets a "<kulcs>_file: útvonal" bejegyzéseket a fájl tartalmával
+// helyettesíti. Csak szöveges mezőkre működik, és a beágyazott blokkokban is.
+func resolveSecrets(raw map[interface{}]interface{}, t reflect.Type, path string, errs *Errors) bool {
+	changed := false
+	for k, v := range raw {
+		key, _ := k.(string)
+		if field, ok := fieldByKey(t, key); ok {
+			if sub, isMap := v.(map[interface{}]interface{}); isMap && indirect(field.Type).Kind() == reflect.Struct {
+				if resolveSecrets(sub, indirect(field.Type), joinKey(path, key), errs) {
+					changed = true
+				}
+			}
+			continue
+		}
+
+		base := strings.TrimSuffix(key, secretSuffix)
+		if base == key {
+			continue
+		}
+		field, ok := fieldByKey(t, base)
+		if !ok || field.Type.Kind() != reflect.String {
+			continue // checkKeys jelzi ismeretlenként
+		}
+		full := joinKey(path, key)
+		if _, both := raw[base]; both {
+			*errs = append(*errs, fmt.Sprintf("%s: a %s és a %s egyszerre van megadva", full, base, key))
+			continue
+		}
+		file, isString := v.(string)
+		if !isString {
+			*errs = append(*errs, fmt.Sprintf("%s: fájl útvonal kell", full))
+			continue
+		}
+		content, err := readSecret(file)
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s: %v", full, err))
+			continue
+		}
+		delete(raw, k)
+		raw[base] = content
+		changed = true
+	}
+	return changed
+}
+
+// readSecret a titkot tartalmazó fájl, a záró sortörés nélkül
+func readSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// checkKeys megkeresi a struktúrában nem létező kulcsokat
+func checkKeys(value interface{}, t reflect.Type, path string, errs *Errors) {
+	t = indirect(t)
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return // a típushibát a dekódolás jelzi
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, fmt.Sprint(k))
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := fieldByKey(t, key)
+			if !ok {
+				msg := fmt.Sprintf("ismeretlen kulcs: %s", joinKey(path, key))
+				if similar, found := fieldByFold(t, key); found {
+					msg += fmt.Sprintf(" (talán: %s)", fieldKey(similar))
+				}
+				*errs = append(*errs, msg)
+				continue
+			}
+			checkKeys(m[key], field.Type, joinKey(path, key), errs)
+		}
+	case reflect.Map:
+		if m, ok := value.(map[interface{}]interface{}); ok {
+			for k, v := range m {
+				checkKeys(v, t.Elem(), joinKey(path, fmt.Sprint(k)), errs)
+			}
+		}
+	case reflect.Slice:
+		if items, ok := value.([]interface{}); ok {
+			for i, item := range items {
+				checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	}
+}
+
+// fieldByKey a pontos yaml kulcsú mező
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && fieldKey(field) == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldByFold kis/nagybetűtől és aláhúzásoktól függetlenül illeszkedő mező
+func fieldByFold(t reflect.Type, key string) (reflect.StructField, bool) {
+	want := foldKey(key)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && fieldKey(field) != "-" && foldKey(fieldKey(field)) == want {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func foldKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
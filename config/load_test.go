@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want []string // az Errors elemeiben keresett részletek
+	}{
+		{"Server: x\n", []string{"hiányzó kötelező mező(k): Console, NickName"}},
+		{minimalConfig + "Chanels: [\"#a\"]\n", []string{"ismeretlen kulcs: Chanels"}},
+		{minimalConfig + "channels: [\"#a\"]\n", []string{"ismeretlen kulcs: channels (talán: Channels)"}},
+		{minimalConfig + "media_upload:\n  enabled: true\n  intervall: 5\n", []string{
+			"ismeretlen kulcs: media_upload.intervall",
+			"media_upload.channels: legalább egy csatorna kell",
+			"media_upload.interval_minutes: pozitív szám kell",
+			"media_upload.jellyfin_db: kötelező",
+		}},
+		{minimalConfig + "Port: \"70000\"\nChannels: [\"ynm\"]\n", []string{
+			"Port: hibás port",
+			"Channels: hibás csatornanév",
+		}},
+		{minimalConfig + "http:\n  enabled: true\n", []string{"http.token: a HTTP API-hoz kötelező"}},
+	}
+	for _, tt := range tests {
+		_, err := parse("test.yaml", []byte(tt.yaml), nil)
+		var errs Errors
+		if !errors.As(err, &errs) {
+			t.Errorf("%q: %v, Errors-t vártam", tt.yaml, err)
+			continue
+		}
+		if len(errs) != len(tt.want) {
+			t.Errorf("%q: %d hiba (%q), várt %d", tt.yaml, len(errs), errs, len(tt.want))
+		}
+		for _, want := range tt.want {
+			if !slices.ContainsFunc(errs, func(e string) bool { return strings.Contains(e, want) }) {
+				t.Errorf("%q: hiányzó hiba: %q (kaptam: %q)", tt.yaml, want, errs)
+			}
+		}
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "sasl")
+	if err := os.WriteFile(secret, []byte("titok\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	environ := []string{
+		"YNM_NICKNAME=Masik",
+		`YNM_CHANNELS=["#a", "#b"]`,
+		"YNM_MEDIA_UPLOAD__INTERVAL_MINUTES=15",
+		"YNM_SASLPASS_FILE=" + secret,
+		"PATH=/usr/bin",
+	}
+	cfg, err := parse("test.yaml", []byte(minimalConfig), environ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.NickName != "Masik" {
+		t.Errorf("NickName = %q", cfg.NickName)
+	}
+	if !slices.Equal(cfg.Channels, []string{"#a", "#b"}) {
+		t.Errorf("Channels = %q", cfg.Channels)
+	}
+	if cfg.MediaUpload.IntervalMinutes != 15 {
+		t.Errorf("media_upload.interval_minutes = %d", cfg.MediaUpload.IntervalMinutes)
+	}
+	if cfg.SASLPass != "titok" {
+		t.Errorf("SASLPass = %q", cfg.SASLPass)
+	}
+
+	if _, err := parse("test.yaml", []byte(minimalConfig), []string{"YNM_NINCS_ILYEN=1"}); err == nil ||
+		!strings.Contains(err.Error(), "YNM_NINCS_ILYEN: ismeretlen config kulcs") {
+		t.Errorf("ismeretlen környezeti változó: %v", err)
+	}
+}
+
+func TestParseSecretFile(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secret, []byte("abc\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	yaml := minimalConfig + "http:\n  enabled: true\n  token_file: " + secret + "\n"
+	cfg, err := parse("test.yaml", []byte(yaml), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HTTP.Token != "abc" {
+		t.Errorf("http.token = %q", cfg.HTTP.Token)
+	}
+
+	_, err = parse("test.yaml", []byte(yaml+"  token: masik\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "egyszerre van megadva") {
+		t.Errorf("token és token_file együtt: %v", err)
+	}
+	_, err = parse("test.yaml", []byte(minimalConfig+"SASLPass_file: "+filepath.Join(t.TempDir(), "nincs")+"\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "SASLPass_file") {
+		t.Errorf("hiányzó titokfájl: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ynmhu/YnM-Go/scheduler"
+)
+
+// Duration időtartam a configban: "30s", "5m", "1h30m"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return &yaml.TypeError{Errors: []string{"időtartam kell (pl. \"30s\", \"5m\")"}}
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil || v < 0 {
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("hibás időtartam: %q (pl. \"30s\", \"5m\", \"1h\")", s)}}
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	if d == 0 {
+		return "", nil
+	}
+	return d.String(), nil
+}
+
+// Std a time.Duration érték
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Clock napi időpont ("07:30") vagy ütemezés (5 mezős cron, @daily, @every 1h)
+type Clock string
+
+func (c *Clock) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return &yaml.TypeError{Errors: []string{"időpont kell (pl. \"07:30\")"}}
+	}
+	if s != "" {
+		if err := scheduler.Validate(s); err != nil {
+			return &yaml.TypeError{Errors: []string{fmt.Sprintf("hibás időpont: %v", err)}}
+		}
+	}
+	*c = Clock(s)
+	return nil
+}
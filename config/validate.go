@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/scheduler"
+)
+
+// Errors a config összes hibája egyben
+type Errors []string
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return "hibás config: " + e[0]
+	}
+	return fmt.Sprintf("hibás config (%d hiba):\n  - %s", len(e), strings.Join(e, "\n  - "))
+}
+
+// validLogLevel: a logging csomag által ismert szintnevek
+func validLogLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "warning", "error":
+		return true
+	}
+	return false
+}
+
+// Az újracsatlakozás (ReconOnDiscon) alapértéke és alsó korlátja: a hiányzó
+// vagy 0 érték ne pörgesse a kapcsolódási ciklust, és a szerver se tiltson ki.
+const (
+	DefaultReconnectDelay = 60 * time.Second
+	MinReconnectDelay     = 5 * time.Second
+)
+
+// applyDefaults kitölti a hiányzó, ésszerű alapértékkel rendelkező mezőket
+func (c *Config) applyDefaults() {
+	if c.Port == "" {
+		c.Port = "6667"
+	}
+	if c.UseTLS && c.TLSPort == "" {
+		c.TLSPort = "6697"
+	}
+	if c.UserName == "" {
+		c.UserName = c.NickName
+	}
+	if c.RealName == "" {
+		c.RealName = c.NickName
+	}
+	if c.LogDir == "" {
+		c.LogDir = "logs"
+	}
+	if c.DataDir == "" {
+		c.DataDir = "data"
+	}
+	if c.NickservBotnick == "" {
+		c.NickservBotnick = "NickServ"
+	}
+	if c.ReconnectOnDisconnect <= 0 {
+		c.ReconnectOnDisconnect = Duration(DefaultReconnectDelay)
+	} else if c.ReconnectOnDisconnect.Std() < MinReconnectDelay {
+		c.ReconnectOnDisconnect = Duration(MinReconnectDelay)
+	}
+}
+
+// Validate ellenőrzi a kötelező mezőket és az értékek összefüggéseit.
+// A hibákat nem az első előfordulásnál adja vissza, hanem összegyűjtve (Errors).
+func (c *Config) Validate() error {
+	var errs Errors
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	var missing []string
+	for key, value := range map[string]string{
+		"Server": c.Server, "NickName": c.NickName, "Console": c.ConsoleChannel, "LogDir": c.LogDir,
+	} {
+		if value == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		add("hiányzó kötelező mező(k): %s", strings.Join(missing, ", "))
+	}
+
+	for key, port := range map[string]string{"Port": c.Port, "TLSPort": c.TLSPort} {
+		if port == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			add("%s: hibás port: %q (1-65535)", key, port)
+		}
+	}
+
+	// Azonosítás
+	if c.UseSASL && (c.SASLUser == "" || c.SASLPass == "") {
+		add("SASL: a SASLUser és a SASLPass megadása kötelező")
+	}
+	if c.AutoLogin && c.NickservPass == "" {
+		add("autologin: a NickservPass megadása kötelező")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		add("TLSCert és TLSKey csak együtt adható meg")
+	}
+
+	// Csatornák
+	checkChannel := func(key, ch string) {
+		if ch != "" && !strings.HasPrefix(ch, "#") && !strings.HasPrefix(ch, "&") {
+			add("%s: hibás csatornanév: %q (#-tel kezdődik)", key, ch)
+		}
+	}
+	checkChannel("Console", c.ConsoleChannel)
+	for key, list := range map[string][]string{
+		"Channels": c.Channels, "NevnapChannels": c.NevnapChannels, "SzekelyhonChannels": c.SzekelyhonChannels,
+		"JokeChannels": c.JokeChannels, "orachan": c.OraChan, "media_upload.channels": c.MediaUpload.Channels,
+	} {
+		for _, ch := range list {
+			checkChannel(key, ch)
+		}
+	}
+
+	// Székelyhon: az órák csak akkor számítanak, ha a csatornák is a régi mezőkből jönnek
+	if c.SzekelyhonInterval > 0 && len(c.SzekelyhonChannels) > 0 {
+		if c.SzekelyhonStartHour < 0 || c.SzekelyhonStartHour > 23 {
+			add("SzekelyhonStartHour: hibás óra: %d (0-23)", c.SzekelyhonStartHour)
+		}
+		if c.SzekelyhonEndHour < 0 || c.SzekelyhonEndHour > 23 {
+			add("SzekelyhonEndHour: hibás óra: %d (0-23)", c.SzekelyhonEndHour)
+		}
+		if c.SzekelyhonStartHour >= c.SzekelyhonEndHour {
+			add("SzekelyhonStartHour: a kezdő óra nem lehet >= a befejező óránál")
+		}
+	}
+
+	if c.MediaUpload.Enabled {
+		if c.MediaUpload.IntervalMinutes <= 0 {
+			add("media_upload.interval_minutes: pozitív szám kell (most: %d)", c.MediaUpload.IntervalMinutes)
+		}
+		if c.MediaUpload.JellyfinDB == "" {
+			add("media_upload.jellyfin_db: kötelező, ha a media_upload be van kapcsolva")
+		}
+		if len(c.MediaUpload.Channels) == 0 {
+			add("media_upload.channels: legalább egy csatorna kell")
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, ext := range c.ExternalPlugins {
+		key := fmt.Sprintf("external_plugins[%d]", i)
+		if ext.Name == "" {
+			add("%s.name: kötelező", key)
+		} else if names[strings.ToLower(ext.Name)] {
+			add("%s.name: ismétlődő név: %s", key, ext.Name)
+		}
+		names[strings.ToLower(ext.Name)] = true
+		if ext.Command == "" {
+			add("%s.command: kötelező", key)
+		}
+	}
+
+	if c.Scheduler.TimeZone != "" {
+		if _, err := time.LoadLocation(c.Scheduler.TimeZone); err != nil {
+			add("scheduler.timezone: ismeretlen időzóna: %q", c.Scheduler.TimeZone)
+		}
+	}
+	for name, job := range c.Scheduler.Jobs {
+		if job.Missed != "" {
+			if _, err := scheduler.ParseMissedPolicy(job.Missed); err != nil {
+				add("scheduler.jobs.%s.missed: %v", name, err)
+			}
+		}
+	}
+
+	checkPolicy := func(key string, p RateLimitPolicy) {
+		if p.Limit < 0 || p.Burst < 0 {
+			add("%s: a limit és a burst nem lehet negatív", key)
+		}
+	}
+	if c.RateLimit.Default != nil {
+		checkPolicy("ratelimit.default", *c.RateLimit.Default)
+	}
+	for cmd, p := range c.RateLimit.Commands {
+		checkPolicy("ratelimit.commands."+cmd, p)
+	}
+	for channel, cmds := range c.RateLimit.Channels {
+		checkChannel("ratelimit.channels", channel)
+		for cmd, p := range cmds {
+			checkPolicy("ratelimit.channels."+channel+"."+cmd, p)
+		}
+	}
+
+	if c.Logging.Level != "" && !validLogLevel(c.Logging.Level) {
+		add("logging.level: ismeretlen szint: %q (debug, info, warn, error)", c.Logging.Level)
+	}
+	for component, level := range c.Logging.Components {
+		if !validLogLevel(level) {
+			add("logging.components.%s: ismeretlen szint: %q (debug, info, warn, error)", component, level)
+		}
+	}
+	if f := c.Logging.Format; f != "" && f != "console" && f != "json" {
+		add("logging.format: \"console\" vagy \"json\" lehet (most: %q)", f)
+	}
+	if r := c.Logging.Rotation; r.MaxSizeMB < 0 || r.MaxBackups < 0 || r.MaxAgeDays < 0 {
+		add("logging.rotation: az értékek nem lehetnek negatívak")
+	}
+	switch c.ChannelLog.Format {
+	case "", "irssi", "weechat", "json":
+	default:
+		add("channel_log.format: \"irssi\", \"weechat\" vagy \"json\" lehet (most: %q)", c.ChannelLog.Format)
+	}
+
+	if t := c.Tell; t.MaxInbox < 0 || t.MaxPerSender < 0 || t.MaxAgeDays < 0 {
+		add("tell: az értékek nem lehetnek negatívak")
+	}
+
+	if c.Backup.Keep < 0 {
+		add("backup.keep: nem lehet negatív")
+	}
+	if c.Backup.Schedule != "" {
+		if _, err := scheduler.Parse(c.Backup.Schedule, time.Local); err != nil {
+			add("backup.schedule: %v", err)
+		}
+	}
+
+	if dir := c.I18n.Dir; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			add("i18n.dir: nem létező könyvtár: %q", dir)
+		}
+	}
+
+	if c.HTTP.Enabled {
+		if c.HTTP.Token == "" {
+			add("http.token: a HTTP API-hoz kötelező a token (vagy http.token_file)")
+		}
+		if _, _, err := net.SplitHostPort(c.HTTPListen()); err != nil {
+			add("http.listen: hibás cím: %q (pl. 127.0.0.1:8089)", c.HTTP.Listen)
+		}
+		if u := c.HTTP.PublicURL; u != "" && !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			add("http.public_url: http:// vagy https:// címet várok: %q", u)
+		}
+	}
+
+	if c.Scripting.MemoryLimitMB < 0 {
+		add("scripting.memory_limit_mb: nem lehet negatív")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Strings(errs)
+	return errs
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// minimalConfig a kötelező mezők; a teszt a végére fűzi a vizsgált kulcsot
+const minimalConfig = "Server: irc.example.test\nNickName: YnM\nConsole: \"#ynm\"\n"
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want time.Duration
+	}{
+		{"", DefaultReconnectDelay},
+		{`ReconOnDiscon: "0s"`, DefaultReconnectDelay},
+		{`ReconOnDiscon: "100ms"`, MinReconnectDelay},
+		{`ReconOnDiscon: "30s"`, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		cfg, err := parse("test.yaml", []byte(minimalConfig+tt.yaml), nil)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.yaml, err)
+		}
+		if got := cfg.ReconnectOnDisconnect.Std(); got != tt.want {
+			t.Errorf("%q: %v, várt %v", tt.yaml, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package control a futó bot helyi vezérlő socketje (unix socket, soronként egy
+// JSON kérés és válasz). A parancssori "ynm-go send" ezen keresztül küld üzenetet.
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Request egy vezérlő kérés
+type Request struct {
+	Command string `json:"command"` // pl. "send"
+	Target  string `json:"target,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+// Response a kérés eredménye
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Handler egy parancs kiszolgálója
+type Handler func(req Request) error
+
+// ErrNotRunning: nem fut bot a megadott socketen
+var ErrNotRunning = errors.New("a bot nem fut (nincs vezérlő socket)")
+
+const (
+	maxRequestSize = 64 * 1024
+	ioTimeout      = 10 * time.Second
+)
+
+// Server a vezérlő socket kiszolgálója
+type Server struct {
+	path     string
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	listener net.Listener
+	external bool // a listenert a systemd adta (socket aktiválás): a fájl nem a miénk
+}
+
+// NewServer a path unix socketen fog figyelni (Start után)
+func NewServer(path string) *Server {
+	return &Server{path: path, handlers: make(map[string]Handler)}
+}
+
+// Handle regisztrál egy parancsot
+func (s *Server) Handle(command string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[command] = h
+}
+
+// Start megnyitja a socketet. Az előző futásból maradt socketfájlt törli,
+// de ha azon egy másik példány válaszol, hibát ad.
+func (s *Server) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(s.path); err == nil {
+		if Running(s.path) {
+			return fmt.Errorf("már fut egy példány ezen a socketen: %s", s.path)
+		}
+		_ = os.Remove(s.path)
+	}
+
+	l, err := net.Listen("unix", s.path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		l.Close()
+		return err
+	}
+	s.listener = l
+
+	go s.serve(l)
+	log.Printf("✅ Vezérlő socket: %s", s.path)
+	return nil
+}
+
+// StartListener a kapott (systemd socket-aktivált) listeneren szolgál ki; a
+// socketfájlt a systemd kezeli, így Close nem törli
+func (s *Server) StartListener(l net.Listener) {
+	s.listener = l
+	s.external = true
+	go s.serve(l)
+	log.Printf("✅ Vezérlő socket (átvett): %s", l.Addr())
+}
+
+// Handoff a listener fájlleírójának másolatát adja (a socket átadásos
+// újraindításhoz). A kiszolgáló fut tovább, amíg az új folyamat át nem vette a
+// socketet; utána a Release állítja le.
+func (s *Server) Handoff() (*os.File, error) {
+	ul, ok := s.listener.(*net.UnixListener)
+	if !ok {
+		return nil, fmt.Errorf("nincs átadható vezérlő socket")
+	}
+	return ul.File()
+}
+
+// Release a sikeres átadás után leállítja a kiszolgálót; a socket és a
+// socketfájl megmarad az új folyamatnak
+func (s *Server) Release() error {
+	if ul, ok := s.listener.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	s.external = true
+	return s.Close()
+}
+
+// Close leállítja a kiszolgálót és törli a socketfájlt
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	if !s.external {
+		_ = os.Remove(s.path)
+	}
+	return err
+}
+
+func (s *Server) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("⚠️ Vezérlő socket hiba: %v", err)
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		resp := Response{OK: true}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: "hibás kérés: " + err.Error()}
+		} else if err := s.dispatch(req); err != nil {
+			resp = Response{Error: err.Error()}
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(req Request) error {
+	s.mu.RLock()
+	h, ok := s.handlers[req.Command]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("ismeretlen parancs: %q", req.Command)
+	}
+	return h(req)
+}
+
+// Call egy kérést küld a futó példánynak
+func Call(path string, req Request) error {
+	conn, err := net.DialTimeout("unix", path, ioTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotRunning, path)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return err
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return fmt.Errorf("nincs válasz a bottól: %w", err)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// Running true, ha a socketen egy futó példány fogad kapcsolatot
+func Running(path string) bool {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,552 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package extplugin a külön folyamatban futó pluginokat kezeli: elindítja őket,
+// soronkénti JSON-RPC-vel kommunikál velük (lásd pluginsdk), összeomlás után
+// növekvő várakozással újraindítja őket, és időkorlátot érvényesít.
+package extplugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/pluginsdk"
+)
+
+const (
+	defaultCommandTimeout = 5 * time.Second
+	initializeTimeout     = 10 * time.Second
+	shutdownGrace         = 2 * time.Second
+
+	minBackoff = 1 * time.Second
+	maxBackoff = 5 * time.Minute
+	// ennyi hiba nélküli futás után a várakozás visszaáll a minimumra
+	stableRun = time.Minute
+	// ennyi egymás utáni időtúllépés után a folyamatot újraindítjuk
+	maxTimeouts = 3
+
+	// ennyi kimenő sor várhat a plugin stdin-jére; ha megtelik, a plugin nem
+	// olvas, ezért újraindítjuk (az IRC olvasás soha nem vár rá)
+	outboundQueue = 256
+	// egy sor kiírásának időkorlátja; túllépéskor a folyamatot újraindítjuk
+	writeTimeout = 5 * time.Second
+)
+
+var (
+	errNotRunning = errors.New("a plugin nem fut")
+	errTimeout    = errors.New("időtúllépés")
+	errQueueFull  = errors.New("a kimenő sor megtelt")
+)
+
+// BotInfo a pluginnak átadott induló adatokat adja (a nick közben változhat)
+type BotInfo func() pluginsdk.InitializeParams
+
+// Process egy külső plugin folyamat felügyelője. Megvalósítja az app.Plugin
+// interfészt, így a !plugin enable/disable ugyanúgy működik rá.
+type Process struct {
+	spec      config.ExternalPluginConfig
+	timeout   time.Duration
+	sender    pluginapi.Sender
+	registrar pluginapi.Registrar
+	botInfo   BotInfo
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	out      chan []byte // a writeLoop sora; nil, ha a folyamat nem fut
+	running  bool
+	info     pluginsdk.InitializeResult
+	nextID   int64
+	pending  map[int64]chan *pluginsdk.Envelope
+	timeouts int
+
+	stopCh  chan struct{}
+	stopped bool
+	done    chan struct{}
+}
+
+// New létrehozza a felügyelőt; a folyamat a Start hívásakor indul
+func New(spec config.ExternalPluginConfig, sender pluginapi.Sender, registrar pluginapi.Registrar, botInfo BotInfo) (*Process, error) {
+	if spec.Name == "" || spec.Command == "" {
+		return nil, fmt.Errorf("külső pluginhoz név és parancs szükséges")
+	}
+	timeout := defaultCommandTimeout
+	if spec.Timeout > 0 {
+		timeout = spec.Timeout.Std()
+	}
+	return &Process{
+		spec:      spec,
+		timeout:   timeout,
+		sender:    sender,
+		registrar: registrar,
+		botInfo:   botInfo,
+		pending:   make(map[int64]chan *pluginsdk.Envelope),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start elindítja a felügyelő goroutine-t
+func (p *Process) Start() {
+	go p.supervise()
+}
+
+// Stop leállítja a folyamatot, és nem indítja újra
+func (p *Process) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	close(p.stopCh)
+	p.mu.Unlock()
+
+	p.notify(pluginsdk.MethodShutdown, struct{}{})
+	select {
+	case <-p.done:
+	case <-time.After(shutdownGrace + time.Second):
+	}
+}
+
+func (p *Process) supervise() {
+	defer close(p.done)
+	backoff := minBackoff
+
+	for {
+		started := time.Now()
+		err := p.runOnce()
+
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
+
+		if time.Since(started) >= stableRun {
+			backoff = minBackoff
+		}
+		log.Printf("⚠️ %s külső plugin leállt (%v), újraindítás %v múlva", p.spec.Name, err, backoff)
+
+		select {
+		case <-p.stopCh:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// runOnce elindítja a folyamatot, és megvárja a kilépését
+func (p *Process) runOnce() error {
+	cmd := exec.Command(p.spec.Command, p.spec.Args...)
+	cmd.Dir = p.spec.Dir
+	cmd.Env = append(os.Environ(), p.spec.Env...)
+
+	// saját pipe a StdinPipe helyett, mert ennek az írására határidő állítható
+	stdinR, stdin, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	cmd.Stdin = stdinR
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		stdinR.Close()
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		stdinR.Close()
+		return err
+	}
+	err = cmd.Start()
+	stdinR.Close() // a gyerek folyamat már megkapta
+	if err != nil {
+		return fmt.Errorf("indítási hiba: %v", err)
+	}
+
+	out := make(chan []byte, outboundQueue)
+	p.mu.Lock()
+	p.cmd = cmd
+	p.out = out
+	p.running = true
+	p.timeouts = 0
+	p.mu.Unlock()
+
+	writeDone := make(chan struct{})
+	go func() {
+		p.writeLoop(stdin, out, cmd)
+		close(writeDone)
+	}()
+
+	readDone := make(chan struct{})
+	go p.logStderr(stderr)
+	go func() {
+		p.readLoop(stdout)
+		close(readDone)
+	}()
+
+	// Leállításkor kis türelmi idő után kilőjük a folyamatot
+	waitDone := make(chan struct{})
+	go func() {
+		select {
+		case <-p.stopCh:
+			select {
+			case <-waitDone:
+			case <-time.After(shutdownGrace):
+				cmd.Process.Kill()
+			}
+		case <-waitDone:
+		}
+	}()
+
+	if err := p.initialize(); err != nil {
+		log.Printf("❌ %s külső plugin inicializálás hiba: %v", p.spec.Name, err)
+		cmd.Process.Kill()
+	}
+
+	<-readDone
+	waitErr := cmd.Wait()
+	close(waitDone)
+
+	p.mu.Lock()
+	p.running = false
+	p.cmd = nil
+	p.out = nil
+	close(out) // a write csak p.mu alatt, nem nil p.out-ra küld
+	for id, ch := range p.pending {
+		close(ch)
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+	<-writeDone
+	p.registrar.UnregisterCommands(p.spec.Name)
+
+	if waitErr == nil {
+		return errors.New("kilépett")
+	}
+	return waitErr
+}
+
+func (p *Process) initialize() error {
+	resp, err := p.call(pluginsdk.MethodInitialize, p.botInfo(), initializeTimeout)
+	if err != nil {
+		return err
+	}
+	var info pluginsdk.InitializeResult
+	if err := json.Unmarshal(resp, &info); err != nil {
+		return fmt.Errorf("hibás initialize válasz: %v", err)
+	}
+
+	p.mu.Lock()
+	p.info = info
+	p.mu.Unlock()
+
+	p.registrar.UnregisterCommands(p.spec.Name)
+	for _, c := range info.Commands {
+		name := c.Name
+		if err := p.registrar.RegisterCommand(p.spec.Name, name, c.Help, func(msg irc.Message, args []string) string {
+			p.runCommand(name, msg, args)
+			return ""
+		}); err != nil {
+			log.Printf("⚠️ %s külső plugin: %v", p.spec.Name, err)
+		}
+	}
+	log.Printf("✅ %s külső plugin elindult (%d parancs)", p.spec.Name, len(info.Commands))
+	return nil
+}
+
+// runCommand a parancsot a háttérben hívja meg, hogy a lassú plugin ne tartsa fel
+// az IRC olvasást; a választ a plugin csatornájára küldi.
+func (p *Process) runCommand(name string, msg irc.Message, args []string) {
+	params := pluginsdk.CommandParams{
+		Command: name,
+		Args:    args,
+		Message: toSDKMessage(msg),
+	}
+	go func() {
+		resp, err := p.call(pluginsdk.MethodCommand, params, p.timeout)
+		if err != nil {
+			log.Printf("⚠️ %s külső plugin, !%s: %v", p.spec.Name, name, err)
+			if errors.Is(err, errTimeout) {
+				p.noteTimeout()
+			}
+			return
+		}
+		var result pluginsdk.CommandResult
+		if err := json.Unmarshal(resp, &result); err != nil {
+			log.Printf("⚠️ %s külső plugin, hibás válasz: %v", p.spec.Name, err)
+			return
+		}
+		if result.Reply != "" {
+			p.send(msg.Channel, result.Reply)
+		}
+	}()
+}
+
+// noteTimeout túl sok egymás utáni időtúllépés után újraindítja a folyamatot
+func (p *Process) noteTimeout() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timeouts++
+	if p.timeouts >= maxTimeouts && p.cmd != nil {
+		log.Printf("❌ %s külső plugin %d egymás utáni időtúllépés, újraindítás", p.spec.Name, p.timeouts)
+		p.cmd.Process.Kill()
+	}
+}
+
+func (p *Process) readLoop(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var env pluginsdk.Envelope
+		if err := json.Unmarshal([]byte(line), &env); err != nil {
+			log.Printf("⚠️ %s külső plugin érvénytelen sor: %s", p.spec.Name, line)
+			continue
+		}
+		if env.Method != "" {
+			p.handleRequest(&env)
+			continue
+		}
+		if env.ID == nil {
+			if env.Error != nil {
+				log.Printf("⚠️ %s külső plugin hiba: %s", p.spec.Name, env.Error.Message)
+			}
+			continue
+		}
+
+		p.mu.Lock()
+		ch, ok := p.pending[*env.ID]
+		delete(p.pending, *env.ID)
+		if ok {
+			p.timeouts = 0
+		}
+		p.mu.Unlock()
+		if ok {
+			ch <- &env
+		}
+	}
+}
+
+// handleRequest a plugin → bot hívások (send, log)
+func (p *Process) handleRequest(env *pluginsdk.Envelope) {
+	var rpcErr *pluginsdk.RPCError
+
+	switch env.Method {
+	case pluginsdk.MethodSend:
+		var params pluginsdk.SendParams
+		if err := json.Unmarshal(env.Params, &params); err != nil || params.Target == "" || params.Text == "" {
+			rpcErr = &pluginsdk.RPCError{Code: pluginsdk.ErrCodeInvalidParams, Message: "target és text kötelező"}
+			break
+		}
+		p.send(params.Target, params.Text)
+
+	case pluginsdk.MethodLog:
+		var params pluginsdk.LogParams
+		if err := json.Unmarshal(env.Params, &params); err == nil {
+			log.Printf("[%s] %s", p.spec.Name, params.Message)
+		}
+
+	default:
+		rpcErr = &pluginsdk.RPCError{Code: pluginsdk.ErrCodeMethodNotFound, Message: "ismeretlen metódus: " + env.Method}
+	}
+
+	if env.ID != nil {
+		reply := pluginsdk.Envelope{JSONRPC: "2.0", ID: env.ID, Error: rpcErr}
+		if rpcErr == nil {
+			reply.Result = json.RawMessage("{}")
+		}
+		p.write(reply)
+	}
+}
+
+// send a plugin üzenetét küldi ki; sortörést nem engedünk, mert az nyers IRC parancs lenne
+func (p *Process) send(target, text string) {
+	if strings.ContainsAny(target, " \r\n") {
+		log.Printf("⚠️ %s külső plugin érvénytelen cél: %q", p.spec.Name, target)
+		return
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if line != "" {
+			p.sender.SendMessage(target, line)
+		}
+	}
+}
+
+func (p *Process) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		log.Printf("[%s stderr] %s", p.spec.Name, scanner.Text())
+	}
+}
+
+// call kérést küld, és a válaszra vár legfeljebb timeout ideig
+func (p *Process) call(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil, errNotRunning
+	}
+	p.nextID++
+	id := p.nextID
+	ch := make(chan *pluginsdk.Envelope, 1)
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	if err := p.write(pluginsdk.Envelope{JSONRPC: "2.0", ID: &id, Method: method, Params: raw}); err != nil {
+		p.forget(id)
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, errNotRunning
+		}
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	case <-timer.C:
+		p.forget(id)
+		return nil, fmt.Errorf("%s: %w (%v)", method, errTimeout, timeout)
+	case <-p.stopCh:
+		p.forget(id)
+		return nil, errNotRunning
+	}
+}
+
+func (p *Process) forget(id int64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// notify értesítést küld; ha a plugin nem fut, csendben eldobja
+func (p *Process) notify(method string, params interface{}) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	p.write(pluginsdk.Envelope{JSONRPC: "2.0", Method: method, Params: raw})
+}
+
+// write a sort a folyamat kimenő sorába teszi, és soha nem blokkol: az üzeneteket
+// és eseményeket az IRC olvasó goroutine adja át. Ha a sor megtelt, a plugin nem
+// olvassa a stdin-jét, ezért leállítjuk (a felügyelő újraindítja).
+func (p *Process) write(env pluginsdk.Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.out == nil {
+		return errNotRunning
+	}
+	select {
+	case p.out <- append(data, '\n'):
+		return nil
+	default:
+	}
+	log.Printf("❌ %s külső plugin nem olvassa a bemenetét (%d sor vár), újraindítás", p.spec.Name, len(p.out))
+	p.cmd.Process.Kill()
+	p.out = nil // a leállásig minden további írás azonnal hibát ad
+	return errQueueFull
+}
+
+// writeLoop a kimenő sort írja a folyamat stdin-jére, soronként writeTimeout
+// határidővel. Hiba vagy túllépés esetén leállítja a folyamatot, a maradékot eldobja.
+func (p *Process) writeLoop(stdin *os.File, out <-chan []byte, cmd *exec.Cmd) {
+	failed := false
+	for data := range out {
+		if failed {
+			continue
+		}
+		stdin.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := stdin.Write(data); err != nil {
+			log.Printf("❌ %s külső plugin írási hiba: %v, újraindítás", p.spec.Name, err)
+			cmd.Process.Kill()
+			failed = true
+		}
+	}
+}
+
+// ───────────────────── app.Plugin ───────────────────────
+
+// HandleMessage továbbítja az üzenetet, ha a plugin kérte; a parancsok a routeren mennek
+func (p *Process) HandleMessage(msg irc.Message) string {
+	p.mu.Lock()
+	wants := p.running && p.info.Messages
+	p.mu.Unlock()
+	if wants {
+		p.notify(pluginsdk.MethodMessage, toSDKMessage(msg))
+	}
+	return ""
+}
+
+func (p *Process) OnTick() []irc.Message {
+	return nil
+}
+
+// HandleEvent továbbítja a csatorna eseményt, ha a plugin kérte
+func (p *Process) HandleEvent(ev irc.Event) {
+	p.mu.Lock()
+	wants := p.running && p.info.Events
+	p.mu.Unlock()
+	if wants {
+		p.notify(pluginsdk.MethodEvent, pluginsdk.Event{
+			Type:    ev.Type,
+			Sender:  ev.Sender,
+			Nick:    ev.Nick,
+			Channel: ev.Channel,
+			Target:  ev.Target,
+			Text:    ev.Text,
+		})
+	}
+}
+
+func toSDKMessage(msg irc.Message) pluginsdk.Message {
+	return pluginsdk.Message{
+		Sender:  msg.Sender,
+		Nick:    strings.SplitN(msg.Sender, "!", 2)[0],
+		Channel: msg.Channel,
+		Text:    msg.Text,
+	}
+}
@@ -0,0 +1,93 @@
+package extplugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/pluginsdk"
+)
+
+// A teszt bináris maga a külső plugin, ha a környezet kéri: az initialize-ra
+// válaszol (minden üzenetet kér), utána nem olvassa többé a bemenetét.
+func TestMain(m *testing.M) {
+	if os.Getenv("EXTPLUGIN_TEST_HELPER") == "stuck" {
+		in := bufio.NewScanner(os.Stdin)
+		if in.Scan() {
+			var req pluginsdk.Envelope
+			json.Unmarshal(in.Bytes(), &req)
+			result, _ := json.Marshal(pluginsdk.InitializeResult{Protocol: pluginsdk.ProtocolVersion, Name: "lusta", Messages: true})
+			reply, _ := json.Marshal(pluginsdk.Envelope{JSONRPC: "2.0", ID: req.ID, Result: result})
+			os.Stdout.Write(append(reply, '\n'))
+		}
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type nopSender struct{}
+
+func (nopSender) SendMessage(target, text string) {}
+func (nopSender) SendRaw(msg string) error        { return nil }
+
+type nopRegistrar struct{}
+
+func (nopRegistrar) RegisterCommand(owner, name, help string, handler pluginapi.CommandHandler) error {
+	return nil
+}
+func (nopRegistrar) UnregisterCommands(owner string) {}
+
+// Az olvasást abbahagyó plugin nem akaszthatja meg az üzenetek továbbítását
+// (az IRC olvasó goroutine-t): a sor megtelésekor a folyamat újraindul.
+func TestStuckPluginDoesNotBlock(t *testing.T) {
+	spec := config.ExternalPluginConfig{
+		Name:    "lusta",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     []string{"EXTPLUGIN_TEST_HELPER=stuck"},
+	}
+	p, err := New(spec, nopSender{}, nopRegistrar{}, func() pluginsdk.InitializeParams {
+		return pluginsdk.InitializeParams{Protocol: pluginsdk.ProtocolVersion, BotNick: "YnM"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Start()
+	t.Cleanup(p.Stop)
+
+	state := func() (running, messages bool) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.running, p.info.Messages
+	}
+	waitFor(t, "inicializálás", func() bool { running, messages := state(); return running && messages })
+
+	// ~4 MB, jóval több, mint amennyit a pipe és a kimenő sor elbír
+	msg := irc.Message{Sender: "alice!a@h", Channel: "#test", Text: strings.Repeat("x", 4096)}
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		p.HandleMessage(msg)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("a továbbítás %v ideig blokkolt", d)
+	}
+	waitFor(t, "leállítás", func() bool { running, _ := state(); return !running })
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: időtúllépés", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
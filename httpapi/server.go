@@ -0,0 +1,208 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package httpapi a bot beépített HTTP kiszolgálója: tokennel védett JSON
+// végpontok (állapot, csatornák, pluginok, ütemező, hibák) és műveletek
+// (üzenetküldés, join/part, rehash). Alapból csak a localhoston figyel; a
+// végpontokat az app csomag regisztrálja.
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxBodySize     = 64 * 1024
+	shutdownTimeout = 5 * time.Second
+)
+
+// HandlerFunc egy JSON végpont: a visszaadott érték JSON-ként megy ki, a hiba
+// {"error": "..."} alakban (az állapotkódot az Error adja, egyébként 500)
+type HandlerFunc func(r *http.Request) (interface{}, error)
+
+// Error egy HTTP állapotkóddal ellátott hiba
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Errorf állapotkóddal ellátott hibát készít (pl. 400 a hibás kérésre)
+func Errorf(status int, format string, args ...interface{}) error {
+	return &Error{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// Server a HTTP kiszolgáló
+type Server struct {
+	addr  string
+	token string
+	mux   *http.ServeMux
+	srv   *http.Server
+	ln    net.Listener
+	done  chan struct{} // Close-kor záródik (a hosszan élő kérésekhez, pl. SSE)
+	once  sync.Once
+}
+
+// NewServer az addr címen fog figyelni (Start után); a védett végpontokhoz a token kell
+func NewServer(addr, token string) *Server {
+	s := &Server{addr: addr, token: token, mux: http.NewServeMux(), done: make(chan struct{})}
+	s.srv = &http.Server{Handler: s.mux, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Handle tokennel védett JSON végpontot regisztrál, pl. "GET /api/status"
+func (s *Server) Handle(pattern string, h HandlerFunc) {
+	s.mux.Handle(pattern, s.auth(jsonHandler(h)))
+}
+
+// HandlePublic token nélkül elérhető JSON végpontot regisztrál (pl. /health)
+func (s *Server) HandlePublic(pattern string, h HandlerFunc) {
+	s.mux.Handle(pattern, jsonHandler(h))
+}
+
+// HandleHTTP tetszőleges (nem JSON) kezelőt regisztrál; public esetén token nélkül
+func (s *Server) HandleHTTP(pattern string, h http.Handler, public bool) {
+	if !public {
+		h = s.auth(h)
+	}
+	s.mux.Handle(pattern, h)
+}
+
+// Start megnyitja a portot és a háttérben kiszolgál
+func (s *Server) Start() error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.StartListener(l)
+	return nil
+}
+
+// StartListener egy már megnyitott listeneren szolgál ki (socket átadásos
+// újraindításkor az előző folyamattól átvett porton)
+func (s *Server) StartListener(l net.Listener) {
+	s.ln = l
+	go func() {
+		if err := s.srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("❌ HTTP API hiba: %v", err)
+		}
+	}()
+	if host, _, _ := net.SplitHostPort(s.addr); !isLoopback(host) {
+		log.Printf("⚠️ A HTTP API nem csak a localhoston figyel (%s); csak megbízható hálózaton használd", s.addr)
+	}
+	log.Printf("✅ HTTP API: http://%s", l.Addr())
+}
+
+// Handoff a port fájlleírójának másolatát adja (a socket átadásos
+// újraindításhoz). A kiszolgáló fut tovább, amíg az új folyamat át nem vette a
+// portot; utána a Close állítja le, és a még el nem fogadott kapcsolatokat már
+// az új folyamat kapja meg.
+func (s *Server) Handoff() (*os.File, error) {
+	tl, ok := s.ln.(*net.TCPListener)
+	if !ok {
+		return nil, fmt.Errorf("nincs átadható HTTP port")
+	}
+	return tl.File()
+}
+
+// Close leállítja a kiszolgálót (a folyamatban lévő kéréseket rövid ideig megvárja)
+func (s *Server) Close() error {
+	s.once.Do(func() { close(s.done) })
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+// Done a leálláskor záródó csatorna: a folyamatos válaszok (SSE) ebből tudják,
+// hogy be kell fejezniük, különben a leállás kivárná őket
+func (s *Server) Done() <-chan struct{} { return s.done }
+
+// auth a tokent az Authorization: Bearer fejlécből, vagy (böngészőhöz, EventSource-hoz)
+// a ?token= paraméterből fogadja el
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.Authorized(r) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "hiányzó vagy hibás token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Authorized igaz, ha a kérés a helyes tokent hozza (a saját hitelesítést végző,
+// nyilvánosan regisztrált végpontokhoz)
+func (s *Server) Authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func jsonHandler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, err := h(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *Error
+			if errors.As(err, &apiErr) {
+				status = apiErr.Status
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		if v == nil {
+			v = map[string]bool{"ok": true}
+		}
+		writeJSON(w, http.StatusOK, v)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("⚠️ HTTP API válasz írási hiba: %v", err)
+	}
+}
+
+// DecodeJSON a kérés törzsét v-be olvassa; hiba esetén 400-as Error-t ad
+func DecodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return Errorf(http.StatusBadRequest, "hibás kérés: %v", err)
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuth(t *testing.T) {
+	newServer := func(token string) *Server {
+		s := NewServer("127.0.0.1:0", token)
+		s.Handle("GET /api/status", func(r *http.Request) (interface{}, error) { return nil, nil })
+		s.HandlePublic("GET /health", func(r *http.Request) (interface{}, error) { return nil, nil })
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		token  string // a beállított token
+		url    string
+		header string // Authorization
+		want   int
+	}{
+		{"fejléc", "titok", "/api/status", "Bearer titok", http.StatusOK},
+		{"paraméter", "titok", "/api/status?token=titok", "", http.StatusOK},
+		{"hibás fejléc", "titok", "/api/status", "Bearer rossz", http.StatusUnauthorized},
+		{"hibás paraméter", "titok", "/api/status?token=rossz", "", http.StatusUnauthorized},
+		{"hiányzó token", "titok", "/api/status", "", http.StatusUnauthorized},
+		{"előtag", "titok", "/api/status?token=tit", "", http.StatusUnauthorized},
+		{"üres beállított token", "", "/api/status", "", http.StatusUnauthorized},
+		{"üres beállított token, üres fejléc", "", "/api/status?token=", "Bearer ", http.StatusUnauthorized},
+		{"nyilvános végpont", "titok", "/health", "", http.StatusOK},
+		{"nyilvános végpont, üres token", "", "/health", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.url, nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		newServer(tt.token).mux.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: %d, várt %d (%s)", tt.name, rec.Code, tt.want, strings.TrimSpace(rec.Body.String()))
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	type request struct {
+		Target string `json:"target"`
+		Text   string `json:"text"`
+	}
+	tests := []struct {
+		name string
+		body string
+		ok   bool
+	}{
+		{"helyes", `{"target": "#ynm", "text": "szia"}`, true},
+		{"ismeretlen mező", `{"target": "#ynm", "txt": "szia"}`, false},
+		{"hibás JSON", `{"target": `, false},
+		{"üres törzs", ``, false},
+		{"túl nagy törzs", `{"target": "#ynm", "text": "` + strings.Repeat("a", maxBodySize) + `"}`, false},
+	}
+	for _, tt := range tests {
+		var v request
+		err := DecodeJSON(httptest.NewRequest("POST", "/api/say", strings.NewReader(tt.body)), &v)
+		if tt.ok {
+			if err != nil || v.Target != "#ynm" || v.Text != "szia" {
+				t.Errorf("%s: %+v, %v", tt.name, v, err)
+			}
+			continue
+		}
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+			t.Errorf("%s: %v, várt 400-as hiba", tt.name, err)
+		}
+	}
+
+	// a végponton át a hiba 400-as JSON válasz
+	s := NewServer("127.0.0.1:0", "titok")
+	s.Handle("POST /api/say", func(r *http.Request) (interface{}, error) {
+		var v request
+		return nil, DecodeJSON(r, &v)
+	})
+	req := httptest.NewRequest("POST", "/api/say", strings.NewReader(`{"nick": "x"}`))
+	req.Header.Set("Authorization", "Bearer titok")
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"error": "hibás kérés:`) {
+		t.Errorf("végpont: %d %s", rec.Code, rec.Body.String())
+	}
+}
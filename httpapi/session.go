@@ -0,0 +1,117 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	loginCodeTTL  = 5 * time.Minute
+	sessionTTL    = 12 * time.Hour
+	sessionCookie = "ynm_session"
+)
+
+// Sessions a böngészős bejelentkezések: az IRC-n kért (!weblogin), egyszer
+// használható belépőkód cookie-s munkamenetre váltható. A munkamenetek csak a
+// memóriában élnek, újraindítás után újra be kell lépni.
+type Sessions struct {
+	mu       sync.Mutex
+	codes    map[string]session // belépőkód → admin
+	sessions map[string]session // cookie értéke → admin
+}
+
+type session struct {
+	user    string
+	expires time.Time
+}
+
+func NewSessions() *Sessions {
+	return &Sessions{codes: make(map[string]session), sessions: make(map[string]session)}
+}
+
+// NewLoginCode rövid ideig érvényes belépőkódot ad a felhasználónak
+func (s *Sessions) NewLoginCode(user string) string {
+	code := randomToken()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+	s.codes[code] = session{user: user, expires: time.Now().Add(loginCodeTTL)}
+	return code
+}
+
+// Login a ?code= belépőkódot munkamenetre váltja és beállítja a cookie-t
+func (s *Sessions) Login(w http.ResponseWriter, r *http.Request) (string, bool) {
+	code := r.URL.Query().Get("code")
+	s.mu.Lock()
+	login, ok := s.codes[code]
+	delete(s.codes, code)
+	if !ok || time.Now().After(login.expires) {
+		s.mu.Unlock()
+		return "", false
+	}
+	id := randomToken()
+	s.sessions[id] = session{user: login.user, expires: time.Now().Add(sessionTTL)}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name: sessionCookie, Value: id, Path: "/", MaxAge: int(sessionTTL.Seconds()),
+		HttpOnly: true, Secure: isHTTPS(r), SameSite: http.SameSiteLaxMode,
+	})
+	return login.user, true
+}
+
+// User a kéréshez tartozó bejelentkezett felhasználó
+func (s *Sessions) User(r *http.Request) (string, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[c.Value]
+	if !ok || time.Now().After(sess.expires) {
+		return "", false
+	}
+	return sess.user, true
+}
+
+// Logout megszünteti a munkamenetet és törli a cookie-t
+func (s *Sessions) Logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, c.Value)
+		s.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+}
+
+func (s *Sessions) purgeLocked() {
+	now := time.Now()
+	for k, v := range s.codes {
+		if now.After(v.expires) {
+			delete(s.codes, k)
+		}
+	}
+	for k, v := range s.sessions {
+		if now.After(v.expires) {
+			delete(s.sessions, k)
+		}
+	}
+}
+
+// isHTTPS: közvetlenül TLS-en vagy HTTPS-es reverse proxy mögül jött a kérés
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+func randomToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic("httpapi: crypto/rand: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
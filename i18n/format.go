@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"strings"
+	"time"
+)
+
+// PluralRule az n-hez tartozó CLDR kategória ("one", "few", "other")
+type PluralRule func(n int) string
+
+// pluralRule a nyelv szabálya; az ismeretlen nyelveké az angolé (one/other)
+func pluralRule(lang string) PluralRule {
+	switch lang {
+	case "ro":
+		// román (CLDR): 1 → one; 0 és az 1–19-re végződők (100-zal osztva, pl. 101) → few;
+		// a többi → other ("20 de minute")
+		return func(n int) string {
+			if n < 0 {
+				n = -n
+			}
+			switch {
+			case n == 1:
+				return "one"
+			case n == 0 || (n%100 >= 1 && n%100 <= 19):
+				return "few"
+			}
+			return "other"
+		}
+	default:
+		// magyar, angol: 1 → one, minden más → other
+		return func(n int) string {
+			if n == 1 || n == -1 {
+				return "one"
+			}
+			return "other"
+		}
+	}
+}
+
+// Month a hónap neve (pl. "március", "martie")
+func (l *Locale) Month(m time.Month) string {
+	return l.T("month." + m.String()[:3])
+}
+
+// Weekday a nap neve (pl. "hétfő", "luni")
+func (l *Locale) Weekday(d time.Weekday) string {
+	return l.T("weekday." + d.String()[:3])
+}
+
+// Date hosszú dátum (pl. "2025. március 5.", "5 martie 2025", "March 5, 2025")
+func (l *Locale) Date(t time.Time) string {
+	return l.T("format.date", t.Year(), l.Month(t.Month()), t.Day())
+}
+
+// DayMonth nap és hónap évszám nélkül (pl. "március 5.", "5 martie")
+func (l *Locale) DayMonth(m time.Month, day int) string {
+	return l.T("format.day_month", l.Month(m), day)
+}
+
+// DateTime rövid dátum és idő a nyelv szokása szerint (pl. "2025.03.05. 14:30")
+func (l *Locale) DateTime(t time.Time) string {
+	return t.Format(l.T("layout.datetime"))
+}
+
+// ShortDate rövid dátum (pl. "2025.03.05.", "05.03.2025")
+func (l *Locale) ShortDate(t time.Time) string {
+	return t.Format(l.T("layout.date"))
+}
+
+// Clock a napon belüli idő (pl. "14:30:05")
+func (l *Locale) Clock(t time.Time) string {
+	return t.Format(l.T("layout.time"))
+}
+
+// durationUnits a Duration egységei a legnagyobbtól
+var durationUnits = []struct {
+	key  string
+	size time.Duration
+}{
+	{"unit.day", 24 * time.Hour},
+	{"unit.hour", time.Hour},
+	{"unit.minute", time.Minute},
+	{"unit.second", time.Second},
+}
+
+// Duration az időtartam a két legnagyobb nem nulla egységgel, másodpercre
+// kerekítve (pl. "1 óra 30 perc", "2 zile 3 ore", "45 seconds")
+func (l *Locale) Duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < 0 {
+		d = -d
+	}
+	var parts []string
+	for _, u := range durationUnits {
+		n := int(d / u.size)
+		d -= time.Duration(n) * u.size
+		if n > 0 {
+			parts = append(parts, l.N(u.key, n))
+		}
+		// a nagyobb egység után csak a közvetlenül következő jelenik meg ("1 óra", nem "1 óra 5 másodperc")
+		if len(parts) == 2 || (len(parts) == 1 && n == 0) {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return l.N("unit.second", 0)
+	}
+	return strings.Join(parts, " ")
+}
+
+// agoUnits a relatív idő egységei a legnagyobbtól (hónap: 30 nap, év: 365 nap)
+var agoUnits = []struct {
+	key  string
+	size time.Duration
+}{
+	{"ago.years", 365 * 24 * time.Hour},
+	{"ago.months", 30 * 24 * time.Hour},
+	{"ago.days", 24 * time.Hour},
+	{"ago.hours", time.Hour},
+	{"ago.minutes", time.Minute},
+}
+
+// Ago a d idővel ezelőtti időpont (pl. "5 perce", "acum 3 ore", "2 days ago");
+// egy percen belül "épp most"
+func (l *Locale) Ago(d time.Duration) string {
+	for _, u := range agoUnits {
+		if d >= u.size {
+			return l.N(u.key, int(d/u.size))
+		}
+	}
+	return l.T("ago.now")
+}
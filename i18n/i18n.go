@@ -0,0 +1,243 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package i18n a bot válaszainak fordításai. Nyelvenként egy JSON üzenetkatalógus
+// (locales/<nyelv>.json, a binárisba beépítve; a config i18n.dir könyvtárában
+// lévő azonos nevű fájlok kulcsonként felülírják, új nyelvet is hozzáadhatnak).
+// A katalógus értéke egyszerű szöveg, vagy többes számú alakoknál objektum a
+// CLDR kategóriákkal ("one", "few", "other"). A szövegek fmt formátumúak; a
+// paraméterek sorrendje nyelvenként %[2]s alakban cserélhető.
+//
+// A válasz nyelve: a felhasználó saját nyelve (!lang), különben a csatorna
+// language beállítása (lásd pluginapi.Context.LocaleFor). A hiányzó fordítás
+// helyett a magyar szöveg jelenik meg.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default az alapnyelv: minden kulcs megvan benne, a többi nyelv ebből pótol
+const Default = "hu"
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+// message egy katalógusbejegyzés: egyszerű szöveg, vagy többes számú alakok
+type message struct {
+	text  string
+	forms map[string]string // "one", "few", "other"
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &m.forms); err != nil {
+		return fmt.Errorf("a bejegyzés se nem szöveg, se nem többes számú alakok: %s", data)
+	}
+	if _, ok := m.forms["other"]; !ok {
+		return fmt.Errorf("a többes számú bejegyzésből hiányzik az \"other\" alak")
+	}
+	return nil
+}
+
+// Locale egy nyelv katalógusa; a hiányzó kulcsokat a fallback (magyar) adja
+type Locale struct {
+	lang     string
+	messages map[string]message
+	plural   PluralRule
+	fallback *Locale
+}
+
+var (
+	mu      sync.RWMutex
+	locales = loadBuiltin()
+)
+
+func loadBuiltin() map[string]*Locale {
+	entries, err := builtin.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	catalogs := make(map[string]map[string]message)
+	for _, e := range entries {
+		data, err := builtin.ReadFile("locales/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		messages, err := parse(data)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", e.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(e.Name(), ".json")] = messages
+	}
+	return build(catalogs)
+}
+
+func parse(data []byte) (map[string]message, error) {
+	messages := make(map[string]message)
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// build a katalógusokból elkészíti a nyelveket; mind a magyarra esik vissza
+func build(catalogs map[string]map[string]message) map[string]*Locale {
+	base := &Locale{lang: Default, messages: catalogs[Default], plural: pluralRule(Default)}
+	result := map[string]*Locale{Default: base}
+	for lang, messages := range catalogs {
+		if lang != Default {
+			result[lang] = &Locale{lang: lang, messages: messages, plural: pluralRule(lang), fallback: base}
+		}
+	}
+	return result
+}
+
+// LoadDir a dir könyvtár <nyelv>.json fájljaival kulcsonként felülírja (vagy
+// új nyelvvel bővíti) a beépített katalógusokat. Üres dir esetén a beépítettek
+// maradnak. Hibás fájl esetén semmi sem változik.
+func LoadDir(dir string) error {
+	fresh := loadBuiltin()
+	if dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return err
+		}
+		catalogs := make(map[string]map[string]message)
+		for lang, l := range fresh {
+			catalogs[lang] = l.messages
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			overrides, err := parse(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			lang := strings.ToLower(strings.TrimSuffix(filepath.Base(path), ".json"))
+			if catalogs[lang] == nil {
+				catalogs[lang] = make(map[string]message)
+			}
+			for key, m := range overrides {
+				catalogs[lang][key] = m
+			}
+		}
+		fresh = build(catalogs)
+	}
+
+	mu.Lock()
+	locales = fresh
+	mu.Unlock()
+	return nil
+}
+
+// Get a nyelv katalógusa; ismeretlen nyelv esetén a magyar
+func Get(lang string) *Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	if l, ok := locales[strings.ToLower(strings.TrimSpace(lang))]; ok {
+		return l
+	}
+	return locales[Default]
+}
+
+// Supported igaz, ha a nyelvhez van katalógus
+func Supported(lang string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := locales[strings.ToLower(strings.TrimSpace(lang))]
+	return ok
+}
+
+// Languages az ismert nyelvek kódjai ábécérendben
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(locales))
+	for lang := range locales {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Missing a nyelv katalógusából hiányzó (a magyarban meglévő) kulcsok
+func Missing(lang string) []string {
+	l := Get(lang)
+	if l.fallback == nil {
+		return nil
+	}
+	var keys []string
+	for key := range l.fallback.messages {
+		if _, ok := l.messages[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lang a nyelv kódja (pl. "hu")
+func (l *Locale) Lang() string {
+	return l.lang
+}
+
+// lookup a kulcs bejegyzése, szükség esetén a magyar katalógusból
+func (l *Locale) lookup(key string) (*Locale, message, bool) {
+	for loc := l; loc != nil; loc = loc.fallback {
+		if m, ok := loc.messages[key]; ok {
+			return loc, m, true
+		}
+	}
+	return nil, message{}, false
+}
+
+// T a kulcs szövege a paraméterekkel; ismeretlen kulcs esetén maga a kulcs
+func (l *Locale) T(key string, args ...interface{}) string {
+	_, m, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.forms != nil {
+		text = m.forms["other"]
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// N a kulcs n-hez illő többes számú alakja; a formátum első paramétere maga az
+// n, utána jönnek az args-ok (pl. "%d perce")
+func (l *Locale) N(key string, n int, args ...interface{}) string {
+	loc, m, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := m.text
+	if m.forms != nil {
+		text = m.forms[loc.plural(n)]
+		if text == "" {
+			text = m.forms["other"]
+		}
+	}
+	return fmt.Sprintf(text, append([]interface{}{n}, args...)...)
+}
@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"hu", 0, "other"},
+		{"hu", 1, "one"},
+		{"hu", 2, "other"},
+		{"en", 1, "one"},
+		{"en", -1, "one"},
+		{"en", 21, "other"},
+		{"ro", 0, "few"},
+		{"ro", 1, "one"},
+		{"ro", 2, "few"},
+		{"ro", 19, "few"},
+		{"ro", 20, "other"},
+		{"ro", 100, "other"},
+		{"ro", 101, "few"},
+		{"ro", 119, "few"},
+		{"ro", 120, "other"},
+		{"ro", -5, "few"},
+		{"xx", 1, "one"},
+		{"xx", 5, "other"},
+	}
+	for _, tt := range tests {
+		if got := pluralRule(tt.lang)(tt.n); got != tt.want {
+			t.Errorf("pluralRule(%q)(%d) = %q, várt %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range Languages() {
+		if missing := Missing(lang); len(missing) > 0 {
+			t.Errorf("%s: hiányzó kulcsok: %v", lang, missing)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	ro := Get("ro")
+	if got, want := ro.N("unit.minute", 1), "1 minut"; got != want {
+		t.Errorf("ro one = %q, várt %q", got, want)
+	}
+	if got, want := ro.N("unit.minute", 101), "101 minute"; got != want {
+		t.Errorf("ro few = %q, várt %q", got, want)
+	}
+	if got, want := ro.N("unit.minute", 20), "20 de minute"; got != want {
+		t.Errorf("ro other = %q, várt %q", got, want)
+	}
+
+	if got := Get("xx").Lang(); got != Default {
+		t.Errorf("ismeretlen nyelv = %q, várt %q", got, Default)
+	}
+	if got := Get(" EN ").Lang(); got != "en" {
+		t.Errorf("Get(\" EN \") = %q", got)
+	}
+	if got := Get("en").T("nincs.ilyen.kulcs"); got != "nincs.ilyen.kulcs" {
+		t.Errorf("ismeretlen kulcs = %q", got)
+	}
+	if got, want := Get("en").T("grep.none", "x"), "🔎 No matches for: x"; got != want {
+		t.Errorf("T = %q, várt %q", got, want)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Cleanup(func() { LoadDir("") })
+
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("en.json", `{"grep.none": "nothing: %s"}`)
+	write("de.json", `{"grep.none": "Keine Treffer: %s"}`)
+	if err := LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := Get("en").T("grep.none", "x"); got != "nothing: x" {
+		t.Errorf("felülírt en = %q", got)
+	}
+	if got := Get("en").T("pager.none"); got != "There are no more lines to show." {
+		t.Errorf("a felül nem írt kulcs = %q", got)
+	}
+	if !Supported("de") {
+		t.Fatal("a de nyelv nem töltődött be")
+	}
+	// a de katalógusból hiányzó kulcsok a magyarból jönnek
+	if got := Get("de").T("pager.none"); got != "Nincs több megjeleníthető sor." {
+		t.Errorf("de fallback = %q", got)
+	}
+
+	// hibás fájl esetén semmi sem változik
+	write("ro.json", `{"grep.none": {"one": "%d"}}`)
+	if err := LoadDir(dir); err == nil {
+		t.Fatal("a hibás katalógus nem adott hibát")
+	}
+	if got := Get("en").T("grep.none", "x"); got != "nothing: x" {
+		t.Errorf("hibás betöltés után en = %q", got)
+	}
+
+	if err := LoadDir(""); err != nil {
+		t.Fatal(err)
+	}
+	if Supported("de") {
+		t.Error("az üres dir nem állította vissza a beépített katalógusokat")
+	}
+}
+
+func TestDurationAndAgo(t *testing.T) {
+	tests := []struct {
+		lang string
+		d    time.Duration
+		want string
+	}{
+		{"en", 90 * time.Minute, "1 hour 30 minutes"},
+		{"en", time.Hour + 5*time.Second, "1 hour"},
+		{"en", 0, "0 seconds"},
+		{"ro", 2*24*time.Hour + 3*time.Hour, "2 zile 3 ore"},
+		{"ro", 20 * time.Second, "20 de secunde"},
+	}
+	for _, tt := range tests {
+		if got := Get(tt.lang).Duration(tt.d); got != tt.want {
+			t.Errorf("%s Duration(%s) = %q, várt %q", tt.lang, tt.d, got, tt.want)
+		}
+	}
+
+	ago := []struct {
+		lang string
+		d    time.Duration
+		want string
+	}{
+		{"en", 30 * time.Second, Get("en").T("ago.now")},
+		{"en", 5 * time.Minute, "5 minutes ago"},
+		{"ro", 3 * time.Hour, "acum 3 ore"},
+		{"ro", 24 * time.Hour, "acum 1 zi"},
+		{"hu", 40 * 24 * time.Hour, "1 hónapja"},
+	}
+	for _, tt := range ago {
+		if got := Get(tt.lang).Ago(tt.d); got != tt.want {
+			t.Errorf("%s Ago(%s) = %q, várt %q", tt.lang, tt.d, got, tt.want)
+		}
+	}
+}
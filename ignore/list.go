@@ -0,0 +1,243 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package ignore a bot globális figyelmen kívül hagyási listája. A bejegyzések
+// nick!user@host maszkra (* és ? helyettesítővel) vagy services fiókra ($a:fiók)
+// illeszkednek, lejárhatnak, és vonatkozhatnak minden pluginra vagy csak egyre.
+package ignore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountPrefix a fiókra illeszkedő bejegyzések előtagja (a szerverek extban jelölése)
+const AccountPrefix = "$a:"
+
+// Entry egy figyelmen kívül hagyott maszk vagy fiók
+type Entry struct {
+	Mask    string    `json:"mask"`             // "nick!user@host" maszk vagy "$a:fiók"
+	Plugin  string    `json:"plugin,omitempty"` // üres: minden plugin
+	Until   time.Time `json:"until,omitempty"`  // nulla: végleges
+	Reason  string    `json:"reason,omitempty"`
+	AddedBy string    `json:"added_by"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Expired true, ha a bejegyzés lejárt
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.Until.IsZero() && !now.Before(e.Until)
+}
+
+// Matches true, ha a küldő (nick!user@host) vagy a fiókja illeszkedik
+func (e *Entry) Matches(sender, account string) bool {
+	if strings.HasPrefix(e.Mask, AccountPrefix) {
+		return account != "" && account != "*" && strings.EqualFold(e.Mask[len(AccountPrefix):], account)
+	}
+	return Wildcard(e.Mask, strings.ToLower(sender))
+}
+
+// List a bejegyzések tárolója, a data könyvtár JSON fájljába mentve
+type List struct {
+	mu      sync.RWMutex
+	path    string
+	entries []*Entry
+	now     func() time.Time
+}
+
+func New(path string) *List {
+	return &List{path: path, now: time.Now}
+}
+
+// Load betölti a mentett bejegyzéseket
+func (l *List) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &l.entries)
+}
+
+func (l *List) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0644)
+}
+
+// NormalizeMask teljes maszkká egészíti ki a megadott értéket:
+// "nick" → "nick!*@*", "user@host" → "*!user@host", "$a:Fiók" → "$a:fiók"
+func NormalizeMask(mask string) (string, error) {
+	mask = strings.ToLower(strings.TrimSpace(mask))
+	if strings.HasPrefix(mask, AccountPrefix) {
+		if len(mask) == len(AccountPrefix) {
+			return "", fmt.Errorf("hiányzó fióknév: %q", mask)
+		}
+		return mask, nil
+	}
+	if mask == "" || strings.ContainsAny(mask, " ,") {
+		return "", fmt.Errorf("érvénytelen maszk: %q", mask)
+	}
+	switch {
+	case !strings.Contains(mask, "!") && !strings.Contains(mask, "@"):
+		mask += "!*@*"
+	case !strings.Contains(mask, "!"):
+		mask = "*!" + mask
+	case !strings.Contains(mask, "@"):
+		mask += "@*"
+	}
+	if mask == "*!*@*" {
+		return "", fmt.Errorf("a %q maszk mindenkire illeszkedne", mask)
+	}
+	return mask, nil
+}
+
+// Add felveszi (vagy frissíti) a bejegyzést; a maszk + plugin páros egyedi
+func (l *List) Add(e Entry) error {
+	mask, err := NormalizeMask(e.Mask)
+	if err != nil {
+		return err
+	}
+	e.Mask = mask
+	e.Plugin = strings.ToLower(e.Plugin)
+	if e.AddedAt.IsZero() {
+		e.AddedAt = l.now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, existing := range l.entries {
+		if existing.Mask == e.Mask && existing.Plugin == e.Plugin {
+			l.entries[i] = &e
+			return l.saveLocked()
+		}
+	}
+	l.entries = append(l.entries, &e)
+	return l.saveLocked()
+}
+
+// Remove törli a maszk bejegyzéseit; üres plugin esetén minden hatókörből
+func (l *List) Remove(mask, plugin string) (int, error) {
+	mask, err := NormalizeMask(mask)
+	if err != nil {
+		return 0, err
+	}
+	plugin = strings.ToLower(plugin)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := l.entries[:0]
+	removed := 0
+	for _, e := range l.entries {
+		if e.Mask == mask && (plugin == "" || e.Plugin == plugin) {
+			removed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	l.entries = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, l.saveLocked()
+}
+
+// Entries az érvényes bejegyzések maszk szerint rendezve; a lejártakat törli
+func (l *List) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	kept := l.entries[:0]
+	var active []Entry
+	for _, e := range l.entries {
+		if e.Expired(now) {
+			continue
+		}
+		kept = append(kept, e)
+		active = append(active, *e)
+	}
+	if len(kept) != len(l.entries) {
+		l.entries = kept
+		l.saveLocked()
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Mask != active[j].Mask {
+			return active[i].Mask < active[j].Mask
+		}
+		return active[i].Plugin < active[j].Plugin
+	})
+	return active
+}
+
+// Ignored true, ha a küldőt figyelmen kívül kell hagyni. Üres plugin esetén
+// csak a globális bejegyzések számítanak, egyébként a pluginra szólók is.
+func (l *List) Ignored(sender, account, plugin string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	now := l.now()
+	for _, e := range l.entries {
+		if e.Expired(now) || (e.Plugin != "" && e.Plugin != strings.ToLower(plugin)) {
+			continue
+		}
+		if e.Matches(sender, account) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wildcard kis- és nagybetűtől függetlenül illeszti a * és ? helyettesítős mintát
+func Wildcard(pattern, s string) bool {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+
+	p, i := 0, 0
+	star, mark := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, i
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
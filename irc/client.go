@@ -13,18 +13,24 @@
 package irc
 
 import (
+	"log"
 	"bufio"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"crypto/tls"
 	"encoding/base64"
 
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/logging"
 )
 
+// rawLog a nyers forgalom (logging.raw_irc); a szintje külön állítható: !loglevel irc.raw warn
+var rawLog = logging.For("irc.raw")
+
 // ──────────────────────── Típusok ────────────────────────────
 
 // bejövő PRIVMSG
@@ -33,17 +39,33 @@ type Message struct {
 	Nick    string // ⬅️ ez az új mező
 	Channel string
 	Text    string
+	Account string // a küldő bejelentkezett fiókja (IRCv3 account-tag), ha a szerver küldi
+}
+
+// nem PRIVMSG jellegű csatorna/felhasználó esemény (JOIN, PART, QUIT, NICK, KICK, TOPIC)
+type Event struct {
+	Type    string // "JOIN", "PART", "QUIT", "NICK", "KICK", "TOPIC"
+	Sender  string // nick!user@host
+	Nick    string
+	Channel string // QUIT és NICK esetén üres
+	Target  string // KICK: a kirúgott nick, NICK: az új nick
+	Text    string // indok / topic szövege
 }
 
 // fő kliens‑struktúra
 type Client struct {
 	conn            net.Conn
-	config          *config.Config
+	config          atomic.Pointer[config.Config] // SetConfig cseréli (config újratöltés)
 	OnConnect       func()
 	OnMessage       func(Message)
-	OnPong          func(pongID string)
+	OnEvent         func(Event)
+	onPong          func(pongID string) // a saját PING-ekre jött PONG (SetPongHandler)
 	OnLoginFailed   func(reason string)
 	OnLoginSuccess  func()
+	OnSend          func(line string)                    // minden sikeresen elküldött sor (a csatornanaplóhoz)
+	OnNames         func(channel string, nicks []string) // NAMES lista (353), előtagok nélkül
+	OnRegistered    func()                               // megjött a 001: a szerver regisztrált
+	OnDisconnect    func()                               // a kapcsolat megszakadt (vagy lezártuk)
 	mu              sync.RWMutex
 	connected       bool
 	disconnectChan  chan struct{}
@@ -54,6 +76,7 @@ type Client struct {
 	loggedUsers    map[string]struct{}
 	joinedChannels map[string]struct{}
 	nick           string
+	caps           map[string]struct{} // a szerver által elfogadott képességek (CAP ACK)
 	
 	// SASL beállítások
 	useSASL  bool
@@ -61,28 +84,49 @@ type Client struct {
 	saslPass string
 	
 	// üzenet küldés queue (optimalizálás)
-	sendQueue chan string
-	sendDone  chan struct{}
+	sendQueue    chan string
+	sendDone     chan struct{}
+	dropWarn     time.Time     // az utolsó "küldési sor megtelt" figyelmeztetés
+	sendInterval atomic.Int64 // szünet két elküldött sor között (ns)
+
+	// socket átadás (Detach / Reattach)
+	sendPause  chan chan struct{} // a küldő ciklus megállítása két sor között
+	sendResume chan struct{}
+	detaching  chan []byte // az olvasó ciklus ide adja a fel nem dolgozott bájtokat
+	detached   net.Conn    // a lekapcsolt, de le nem zárt kapcsolat
+
+	// késleltetés mérése (saját PING)
+	registered bool // megjött a 001 (a lag mérése csak ezután)
+	lagSent    time.Time
+	lag        time.Duration
+
+	lastRead atomic.Int64 // az utolsó beolvasott sor ideje (UnixNano), a watchdoghoz
 }
 
 // ─────────────────────── Konstruktor ─────────────────────────
 
 func NewClient(cfg *config.Config) *Client {
 	c := &Client{
-		config:         cfg,
 		disconnectChan: make(chan struct{}, 1),
 		useSASL:        cfg.UseSASL,
 		saslUser:       cfg.SASLUser,
 		saslPass:       cfg.SASLPass,
 		loggedUsers:    make(map[string]struct{}),
 		joinedChannels: make(map[string]struct{}),
+		caps:           make(map[string]struct{}),
 		nick:           cfg.NickName,
 		sendQueue:      make(chan string, 100),
 		sendDone:       make(chan struct{}),
+		sendPause:      make(chan chan struct{}),
+		sendResume:     make(chan struct{}),
 	}
 	
+	c.config.Store(cfg)
+	c.sendInterval.Store(int64(defaultSendInterval))
+
 	// indítjuk a send queue kezelőt
 	go c.sendQueueHandler()
+	go c.lagLoop()
 	
 	// reconnect figyelő goroutine
 	if cfg.ReconnectOnDisconnect > 0 {
@@ -91,6 +135,17 @@ func NewClient(cfg *config.Config) *Client {
 	return c
 }
 
+// cfg az éppen érvényes config
+func (c *Client) cfg() *config.Config {
+	return c.config.Load()
+}
+
+// SetConfig közzéteszi az újratöltött configot. A kapcsolódási adatok (szerver,
+// nick, azonosítás) csak a következő kapcsolódáskor számítanak.
+func (c *Client) SetConfig(cfg *config.Config) {
+	c.config.Store(cfg)
+}
+
 // ─────────────────────── Getter metódusok ─────────────────────────
 
 func (c *Client) GetLoggedUsers() []string {
@@ -131,6 +186,21 @@ func (c *Client) IsConnected() bool {
 	return c.connected
 }
 
+// Lag a legutóbbi mért késleltetés (0, ha még nincs mérés)
+func (c *Client) Lag() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lag
+}
+
+// LastRead az utolsó, a szervertől beolvasott sor ideje (nulla, ha még nem jött)
+func (c *Client) LastRead() time.Time {
+	if ns := c.lastRead.Load(); ns != 0 {
+		return time.Unix(0, ns)
+	}
+	return time.Time{}
+}
+
 // ─────────────────────── Kapcsolódás ─────────────────────────
 
 func (c *Client) IsTLS() bool {
@@ -148,26 +218,26 @@ func (c *Client) Connect() error {
 	var err error
 	var tlsConfig *tls.Config
 
-	server := c.config.Server
-	port := c.config.Port
-	if c.config.UseTLS && c.config.TLSPort != "" {
-		port = c.config.TLSPort
+	server := c.cfg().Server
+	port := c.cfg().Port
+	if c.cfg().UseTLS && c.cfg().TLSPort != "" {
+		port = c.cfg().TLSPort
 	}
 
 	addr := server + ":" + port
 
-	if c.config.UseTLS {
+	if c.cfg().UseTLS {
 		tlsConfig = &tls.Config{
 			InsecureSkipVerify: true,
 			ServerName:         server,
 		}
 
-		if c.config.TLSCert != "" && c.config.TLSKey != "" {
-			cert, certErr := tls.LoadX509KeyPair(c.config.TLSCert, c.config.TLSKey)
+		if c.cfg().TLSCert != "" && c.cfg().TLSKey != "" {
+			cert, certErr := tls.LoadX509KeyPair(c.cfg().TLSCert, c.cfg().TLSKey)
 			if certErr == nil {
 				tlsConfig.Certificates = []tls.Certificate{cert}
 			} else {
-				fmt.Printf("⚠️ TLS cert/key betöltési hiba: %v\n", certErr)
+				log.Printf("⚠️ TLS cert/key betöltési hiba: %v", certErr)
 			}
 		}
 
@@ -184,16 +254,23 @@ func (c *Client) Connect() error {
 	c.connected = true
 	c.loggedIn = false
 	c.reconnecting = false
+	c.registered = false
+	c.lagSent = time.Time{}
+	c.caps = make(map[string]struct{})
 	c.mu.Unlock()
+	c.lastRead.Store(time.Now().UnixNano())
+	connectedGauge.Set(1)
 
-	go c.readLoop()
+	go c.readLoop(bufio.NewReader(conn))
 
 	// Kezdeti parancsok küldése
 	if c.useSASL {
 		c.SendRaw("CAP REQ :sasl")
+		// külön kérés, hogy egy NAK ne akassza meg a SASL-t
+		c.SendRaw("CAP REQ :account-tag")
 	} else {
-		c.SendRaw(fmt.Sprintf("NICK %s", c.config.NickName))
-		c.SendRaw(fmt.Sprintf("USER %s 0 * :%s", c.config.UserName, c.config.RealName))
+		c.SendRaw(fmt.Sprintf("NICK %s", c.cfg().NickName))
+		c.SendRaw(fmt.Sprintf("USER %s 0 * :%s", c.cfg().UserName, c.cfg().RealName))
 	}
 
 	if c.OnConnect != nil {
@@ -211,9 +288,14 @@ func (c *Client) Disconnect() {
 		c.conn.Close()
 		c.conn = nil
 	}
+	wasConnected := c.connected
 	c.connected = false
 	c.loggedIn = false
 	c.mu.Unlock()
+	connectedGauge.Set(0)
+	if wasConnected && c.OnDisconnect != nil {
+		c.OnDisconnect()
+	}
 
 	// jelezzük a reconnect‑ciklusnak
 	select {
@@ -224,6 +306,14 @@ func (c *Client) Disconnect() {
 
 // ──────────────────── Üzenet küldés optimalizálva ─────────────────
 
+// defaultSendInterval a sorok közti szünet, hogy a szerver ne dobjon ki floodért
+const defaultSendInterval = 100 * time.Millisecond
+
+// SetSendInterval beállítja a két elküldött sor közti szünetet (a tesztekben 0)
+func (c *Client) SetSendInterval(d time.Duration) {
+	c.sendInterval.Store(int64(d))
+}
+
 func (c *Client) sendQueueHandler() {
 	for {
 		select {
@@ -231,9 +321,22 @@ func (c *Client) sendQueueHandler() {
 			if !ok {
 				return
 			}
-			c.sendRawDirect(msg)
+			sendQueueLen.Set(float64(len(c.sendQueue)))
+			if err := c.sendRawDirect(msg); err != nil {
+				sendDropped.Inc("disconnected")
+			}
 			// kis késleltetés az IRC szerver túlterhelésének elkerülése miatt
-			time.Sleep(100 * time.Millisecond)
+			if d := time.Duration(c.sendInterval.Load()); d > 0 {
+				time.Sleep(d)
+			}
+		case ack := <-c.sendPause:
+			// socket átadás: a sorban maradt üzeneteket a Detach viszi tovább
+			close(ack)
+			select {
+			case <-c.sendResume:
+			case <-c.sendDone:
+				return
+			}
 		case <-c.sendDone:
 			return
 		}
@@ -252,7 +355,13 @@ func (c *Client) sendRawDirect(msg string) error {
 
 	_, err := conn.Write([]byte(msg + "\r\n"))
 	if err == nil {
-		fmt.Println(">>", msg)
+		linesSent.Inc()
+	}
+	if err == nil && c.cfg().Logging.RawIRC {
+		rawLog.Info(">> " + Redact(msg))
+	}
+	if err == nil && c.OnSend != nil {
+		c.OnSend(msg)
 	}
 	return err
 }
@@ -273,39 +382,63 @@ func (c *Client) SendRaw(msg string) error {
 	c.mu.RUnlock()
 
 	if !connected {
+		sendDropped.Inc("disconnected")
 		return fmt.Errorf("not connected")
 	}
 
 	select {
 	case c.sendQueue <- msg:
+		sendQueueLen.Set(float64(len(c.sendQueue)))
 		return nil
 	default:
+		sendDropped.Inc("queue_full")
+		c.mu.Lock()
+		warn := time.Since(c.dropWarn) > time.Minute
+		if warn {
+			c.dropWarn = time.Now()
+		}
+		c.mu.Unlock()
+		if warn {
+			log.Printf("⚠️ A küldési sor megtelt (%d sor), kimenő üzenetek vesznek el", cap(c.sendQueue))
+		}
 		return fmt.Errorf("send queue full")
 	}
 }
 
 // ─────────────────────── Olvasó‑ciklus ───────────────────────
 
-func (c *Client) readLoop() {
+func (c *Client) readLoop(reader *bufio.Reader) {
+	detached := false
 	defer func() {
-		c.Disconnect()
+		if !detached {
+			c.Disconnect()
+		}
 	}()
 
-	reader := bufio.NewReader(c.conn)
-
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
-			fmt.Printf("Olvasási hiba: %v\n", err)
+			if detached = c.detachRead(reader, line, err); detached {
+				return
+			}
+			log.Printf("❌ Olvasási hiba: %v", err)
 			return
 		}
 
+		c.lastRead.Store(time.Now().UnixNano())
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
+		linesReceived.Inc()
 		
-		fmt.Println("<<", line)
+		if c.cfg().Logging.RawIRC {
+			rawLog.Info("<< " + Redact(line))
+		}
+
+		// IRCv3 üzenet tagek leválasztása (@account=...;time=... :prefix PARANCS ...)
+		var tags map[string]string
+		tags, line = splitTags(line)
 
 		// PING → PONG response
 		if strings.HasPrefix(line, "PING ") {
@@ -319,6 +452,14 @@ func (c *Client) readLoop() {
 			continue
 		}
 
+		// Csatorna események továbbítása a pluginoknak
+		if ev := parseEvent(line); ev != nil {
+			c.trackEvent(ev)
+			if c.OnEvent != nil {
+				c.OnEvent(*ev)
+			}
+		}
+
 		// JOIN események kezelése
 		if strings.Contains(line, " JOIN ") {
 			c.handleJoin(line)
@@ -331,6 +472,9 @@ func (c *Client) readLoop() {
 			continue
 		}
 
+		// elfogadott képességek (CAP ACK) követése a socket átadáshoz
+		c.trackCaps(line)
+
 		// SASL autentikáció kezelése
 		if c.handleSASL(line) {
 			continue
@@ -355,6 +499,7 @@ func (c *Client) readLoop() {
 
 		// PRIVMSG feldolgozás
 		if msg := parseMessage(line); msg != nil && c.OnMessage != nil {
+			msg.Account = tags["account"]
 			c.OnMessage(*msg)
 		}
 	}
@@ -365,11 +510,11 @@ func (c *Client) readLoop() {
 func (c *Client) handleNickInUse() {
 	c.mu.Lock()
 	oldNick := c.nick
-	newNick := fmt.Sprintf("%s_%d", c.config.NickName, time.Now().Unix()%10000)
+	newNick := fmt.Sprintf("%s_%d", c.cfg().NickName, time.Now().Unix()%10000)
 	c.nick = newNick
 	c.mu.Unlock()
 	
-	fmt.Printf("Nick %s foglalt/rezervált, új nick: %s\n", oldNick, newNick)
+	log.Printf("⚠️ Nick %s foglalt/rezervált, új nick: %s", oldNick, newNick)
 	c.SendRaw("NICK " + newNick)
 }
 
@@ -417,15 +562,20 @@ func (c *Client) handleNames(line string) {
 
 	c.mu.Lock()
 	c.joinedChannels[channel] = struct{}{}
-	nicks := strings.Fields(usersPart)
-	for _, nick := range nicks {
+	var nicks []string
+	for _, nick := range strings.Fields(usersPart) {
 		// Csatorna operator/voice előtagok eltávolítása
 		nick = strings.TrimLeft(nick, "+%@&~!")
 		if nick != "" {
 			c.loggedUsers[nick] = struct{}{}
+			nicks = append(nicks, nick)
 		}
 	}
 	c.mu.Unlock()
+
+	if c.OnNames != nil {
+		c.OnNames(channel, nicks)
+	}
 }
 
 func (c *Client) handleSASL(line string) bool {
@@ -464,14 +614,14 @@ func (c *Client) handleSASL(line string) bool {
 
 	// SASL sikeres (903)
 	if strings.Contains(line, " 903 ") {
-		fmt.Println("✔️ SASL autentikáció sikeres")
+		log.Println("✔️ SASL autentikáció sikeres")
 		c.mu.Lock()
 		c.loggedIn = true
 		c.mu.Unlock()
 
 		c.SendRaw("CAP END")
-		c.SendRaw(fmt.Sprintf("NICK %s", c.config.NickName))
-		c.SendRaw(fmt.Sprintf("USER %s 0 * :%s", c.config.UserName, c.config.RealName))
+		c.SendRaw(fmt.Sprintf("NICK %s", c.cfg().NickName))
+		c.SendRaw(fmt.Sprintf("USER %s 0 * :%s", c.cfg().UserName, c.cfg().RealName))
 
 		if c.OnLoginSuccess != nil {
 			c.OnLoginSuccess()
@@ -481,7 +631,7 @@ func (c *Client) handleSASL(line string) bool {
 
 	// SASL sikertelen (904 vagy 905)
 	if strings.Contains(line, " 904 ") || strings.Contains(line, " 905 ") {
-		fmt.Println("❌ SASL autentikáció sikertelen")
+		log.Println("❌ SASL autentikáció sikertelen")
 		c.SendRaw("CAP END")
 
 		if c.OnLoginFailed != nil {
@@ -510,7 +660,7 @@ func (c *Client) handleNickServ(line string) bool {
 		c.loggedIn = true
 		c.mu.Unlock()
 
-		fmt.Println("✔️ NickServ autentikáció sikeres")
+		log.Println("✔️ NickServ autentikáció sikeres")
 		if !wasLoggedIn && c.OnLoginSuccess != nil {
 			c.OnLoginSuccess()
 		}
@@ -523,21 +673,25 @@ func (c *Client) handleNickServ(line string) bool {
 func (c *Client) handleWelcome() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.registered = true
+	if c.OnRegistered != nil {
+		go c.OnRegistered()
+	}
 
 	// Javított logika a config alapján
-	if c.config.UseSASL {
+	if c.cfg().UseSASL {
 		// SASL esetén már történt az autentikáció
 		return
 	}
 
-	if c.config.AutoLogin {
+	if c.cfg().AutoLogin {
 		// AutoLogin be van kapcsolva, várjuk a NickServ választ
 		// A loggedIn flag-et a NickServ válasz fogja beállítani
 		return
 	}
 
 	// AutoLogin kikapcsolva
-	if c.config.AutoJoinWithoutLogin {
+	if c.cfg().AutoJoinWithoutLogin {
 		// Engedélyezett a csatlakozás login nélkül
 		c.loggedIn = true
 		if c.OnLoginSuccess != nil {
@@ -549,11 +703,35 @@ func (c *Client) handleWelcome() {
 
 func (c *Client) handlePong(line string) {
 	parts := strings.Split(line, " ")
-	if len(parts) >= 4 && c.OnPong != nil {
-		c.OnPong(strings.TrimPrefix(parts[3], ":"))
+	if len(parts) >= 4 && strings.TrimPrefix(parts[3], ":") == lagToken {
+		c.mu.Lock()
+		if !c.lagSent.IsZero() {
+			c.lag = time.Since(c.lagSent)
+			c.lagSent = time.Time{}
+		}
+		lag := c.lag
+		c.mu.Unlock()
+		lagGauge.Set(lag.Seconds())
+		lagHistogram.Observe(lag.Seconds())
+		return
+	}
+	c.mu.RLock()
+	onPong := c.onPong
+	c.mu.RUnlock()
+	if len(parts) >= 4 && onPong != nil {
+		onPong(strings.TrimPrefix(parts[3], ":"))
 	}
 }
 
+// SetPongHandler beállítja (nil esetén törli) a nem a késleltetésméréshez
+// tartozó PONG-ok kezelőjét. A ping plugin engedélyezéskor beállítja,
+// leállításkor törli; futás közben is hívható.
+func (c *Client) SetPongHandler(fn func(pongID string)) {
+	c.mu.Lock()
+	c.onPong = fn
+	c.mu.Unlock()
+}
+
 // ────────────────────── Reconnect‑ciklus ─────────────────────
 
 func (c *Client) reconnectLoop() {
@@ -566,23 +744,118 @@ func (c *Client) reconnectLoop() {
 		c.reconnecting = true
 		c.mu.Unlock()
 
-		fmt.Println("🔄 Újracsatlakozás...")
-		time.Sleep(c.config.ReconnectOnDisconnect)
+		log.Println("🔄 Újracsatlakozás...")
+		time.Sleep(c.reconnectDelay())
 
 		for {
 			if err := c.Connect(); err == nil {
-				fmt.Println("✔️ Újracsatlakozás sikeres")
+				reconnects.Inc("ok")
+				log.Println("✔️ Újracsatlakozás sikeres")
 				break
 			} else {
-				fmt.Printf("❌ Újracsatlakozás sikertelen: %v\n", err)
+				reconnects.Inc("failed")
+				log.Printf("❌ Újracsatlakozás sikertelen: %v", err)
 			}
-			time.Sleep(c.config.ReconnectOnDisconnect)
+			time.Sleep(c.reconnectDelay())
+		}
+	}
+}
+
+// reconnectDelay a kísérletek közti szünet; a kézzel összerakott (nem
+// config.Load-dal betöltött) config esetén is legalább config.MinReconnectDelay
+func (c *Client) reconnectDelay() time.Duration {
+	if d := c.cfg().ReconnectOnDisconnect.Std(); d >= config.MinReconnectDelay {
+		return d
+	}
+	return config.MinReconnectDelay
+}
+
+// ───────────────────── Késleltetés mérése ───────────────────────
+
+const (
+	lagToken    = "ynm-lag" // a saját PING azonosítója (a PONG-ját nem adjuk tovább)
+	lagInterval = 30 * time.Second
+)
+
+// lagLoop félpercenként PING-et küld; a PONG-ig eltelt idő a késleltetés.
+// Ha a válasz nem jön meg, a mért érték a következő PING-ig tovább nő. A PING
+// a küldési sort megkerülve megy ki, így a mérésbe a sorban várakozás nem számít bele.
+func (c *Client) lagLoop() {
+	ticker := time.NewTicker(lagInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.sendDone:
+			return
+		}
+		c.mu.Lock()
+		ready := c.connected && c.registered
+		if ready && !c.lagSent.IsZero() {
+			c.lag = time.Since(c.lagSent) // a válasz még nem jött meg
+			lagGauge.Set(c.lag.Seconds())
+		}
+		measure := ready && c.lagSent.IsZero()
+		if measure {
+			c.lagSent = time.Now()
+		}
+		c.mu.Unlock()
+		if !ready {
+			continue
+		}
+		if err := c.sendRawDirect("PING :" + lagToken); err != nil && measure {
+			c.mu.Lock()
+			c.lagSent = time.Time{}
+			c.mu.Unlock()
 		}
 	}
 }
 
 // ───────────────────── PRIVMSG parser ───────────────────────
 
+// splitTags leválasztja a sor elejéről az IRCv3 tageket
+func splitTags(line string) (map[string]string, string) {
+	if !strings.HasPrefix(line, "@") {
+		return nil, line
+	}
+	raw, rest, _ := strings.Cut(line[1:], " ")
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(raw, ";") {
+		key, value, _ := strings.Cut(tag, "=")
+		tags[key] = unescapeTagValue(value)
+	}
+	return tags, strings.TrimLeft(rest, " ")
+}
+
+func unescapeTagValue(v string) string {
+	if !strings.Contains(v, `\`) {
+		return v
+	}
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i+1 >= len(v) {
+			if v[i] != '\\' {
+				b.WriteByte(v[i])
+			}
+			continue
+		}
+		i++
+		switch v[i] {
+		case ':':
+			b.WriteByte(';')
+		case 's':
+			b.WriteByte(' ')
+		case 'r':
+			b.WriteByte('\r')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+	return b.String()
+}
+
 func parseMessage(line string) *Message {
 	parts := strings.Split(line, " ")
 	if len(parts) < 4 || parts[1] != "PRIVMSG" {
@@ -608,14 +881,126 @@ func parseMessage(line string) *Message {
 	}
 }
 
+// ───────────────────── Esemény parser ───────────────────────
+
+// parseEvent a JOIN/PART/QUIT/NICK/KICK/TOPIC sorokat alakítja Event-té
+func parseEvent(line string) *Event {
+	if !strings.HasPrefix(line, ":") {
+		return nil
+	}
+	prefix, rest, ok := strings.Cut(line[1:], " ")
+	if !ok {
+		return nil
+	}
+
+	// a trailing (":"-tal kezdődő) paraméter levágása
+	params, trailing, hasTrailing := strings.Cut(rest, " :")
+	if !hasTrailing && strings.HasPrefix(rest, ":") {
+		params, trailing = "", rest[1:]
+	}
+	fields := strings.Fields(params)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ev := &Event{
+		Type:   strings.ToUpper(fields[0]),
+		Sender: prefix,
+		Nick:   strings.SplitN(prefix, "!", 2)[0],
+	}
+	args := fields[1:]
+
+	switch ev.Type {
+	case "JOIN":
+		if len(args) > 0 {
+			ev.Channel = args[0]
+		} else {
+			ev.Channel = trailing
+		}
+	case "PART":
+		if len(args) == 0 {
+			return nil
+		}
+		ev.Channel = args[0]
+		ev.Text = trailing
+	case "QUIT":
+		ev.Text = trailing
+	case "NICK":
+		if len(args) > 0 {
+			ev.Target = args[0]
+		} else {
+			ev.Target = trailing
+		}
+	case "KICK":
+		if len(args) < 2 {
+			return nil
+		}
+		ev.Channel = args[0]
+		ev.Target = args[1]
+		ev.Text = trailing
+	case "TOPIC":
+		if len(args) == 0 {
+			return nil
+		}
+		ev.Channel = args[0]
+		ev.Text = trailing
+	default:
+		return nil
+	}
+	return ev
+}
+
+// trackEvent frissíti a saját nicket és az ismert felhasználók listáját
+func (c *Client) trackEvent(ev *Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev.Type {
+	case "NICK":
+		if ev.Nick == c.nick {
+			c.nick = ev.Target
+		}
+		delete(c.loggedUsers, ev.Nick)
+		c.loggedUsers[ev.Target] = struct{}{}
+	case "QUIT":
+		delete(c.loggedUsers, ev.Nick)
+	case "PART":
+		if ev.Nick == c.nick {
+			delete(c.joinedChannels, ev.Channel)
+		}
+	case "KICK":
+		if ev.Target == c.nick {
+			delete(c.joinedChannels, ev.Channel)
+		}
+	}
+}
+
+// trackCaps a CAP ACK / CAP DEL sorokból frissíti az elfogadott képességeket
+func (c *Client) trackCaps(line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[1] != "CAP" || (fields[3] != "ACK" && fields[3] != "DEL") {
+		return
+	}
+	_, list, _ := strings.Cut(line, " "+fields[3]+" ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range strings.Fields(strings.TrimPrefix(list, ":")) {
+		if fields[3] == "DEL" || strings.HasPrefix(name, "-") {
+			delete(c.caps, strings.TrimPrefix(name, "-"))
+		} else {
+			c.caps[name] = struct{}{}
+		}
+	}
+}
+
 // ───────────────────── NickServ azonosítás ───────────────────────
 
 func (c *Client) IdentifyNickServ() error {
-	if !c.config.AutoLogin {
+	if !c.cfg().AutoLogin {
 		return nil
 	}
 
-	if c.config.NickservBotnick == "" || c.config.NickservNick == "" || c.config.NickservPass == "" {
+	if c.cfg().NickservBotnick == "" || c.cfg().NickservNick == "" || c.cfg().NickservPass == "" {
 		return fmt.Errorf("NickServ adatok hiányoznak a konfigurációból")
 	}
 
@@ -624,18 +1009,18 @@ func (c *Client) IdentifyNickServ() error {
 		time.Sleep(3 * time.Second)
 
 		// Nick váltás a regisztrált nickre
-		if c.GetNick() != c.config.NickservNick {
-			nickChangeCmd := fmt.Sprintf("NICK %s", c.config.NickservNick)
+		if c.GetNick() != c.cfg().NickservNick {
+			nickChangeCmd := fmt.Sprintf("NICK %s", c.cfg().NickservNick)
 			if err := c.SendRaw(nickChangeCmd); err != nil {
-				fmt.Printf("❌ Nem sikerült nicket váltani: %v\n", err)
+				log.Printf("❌ Nem sikerült nicket váltani: %v", err)
 			}
 		}
 
 		// Azonosítás
 		identifyCmd := fmt.Sprintf("PRIVMSG %s :IDENTIFY %s %s", 
-			c.config.NickservBotnick, c.config.NickservNick, c.config.NickservPass)
+			c.cfg().NickservBotnick, c.cfg().NickservNick, c.cfg().NickservPass)
 		if err := c.SendRaw(identifyCmd); err != nil {
-			fmt.Printf("❌ Nem sikerült azonosítani: %v\n", err)
+			log.Printf("❌ Nem sikerült azonosítani: %v", err)
 			if c.OnLoginFailed != nil {
 				c.OnLoginFailed("Nem sikerült azonosítani: " + err.Error())
 			}
@@ -0,0 +1,25 @@
+package irc
+
+import "time"
+
+// Sender az üzenetküldés. A pluginok ettől függenek, nem a konkrét klienstől,
+// így a tesztekben bármi (pl. az irctest szerverhez kapcsolt kliens) állhat mögötte.
+type Sender interface {
+	SendMessage(target, text string)
+	SendRaw(msg string) error
+}
+
+// Conn a küldésen túl a kapcsolat állapota: saját nick, csatornák, ismert
+// felhasználók. Az *irc.Client megvalósítja.
+type Conn interface {
+	Sender
+	GetNick() string
+	GetJoinedChannels() []string
+	GetLoggedUsers() []string
+	IsConnected() bool
+	IsLoggedIn() bool
+	IsTLS() bool
+	Lag() time.Duration
+}
+
+var _ Conn = (*Client)(nil)
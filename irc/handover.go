@@ -0,0 +1,205 @@
+package irc
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"time"
+)
+
+// Session az élő IRC munkamenet állapota, amellyel egy új folyamat újracsatlakozás
+// nélkül folytathatja a kapcsolatot (socket átadás, !restart)
+type Session struct {
+	Server     string   `json:"server"`
+	Nick       string   `json:"nick"`
+	Channels   []string `json:"channels"`
+	Users      []string `json:"users"`
+	Caps       []string `json:"caps"` // a szerver által elfogadott IRCv3 képességek
+	LoggedIn   bool     `json:"logged_in"`
+	Registered bool     `json:"registered"`
+	Input      []byte   `json:"input,omitempty"` // beolvasott, de fel nem dolgozott bájtok
+	Queue      []string `json:"queue,omitempty"` // el nem küldött kimenő sorok
+}
+
+var (
+	// ErrHandoverTLS: a TLS munkamenet állapota nem adható át, csak újracsatlakozás lehet
+	ErrHandoverTLS = errors.New("TLS kapcsolat nem adható át")
+	// ErrHandoverNotReady: nincs kapcsolat, vagy a szerver még nem regisztrált
+	ErrHandoverNotReady = errors.New("nincs átadható, regisztrált IRC kapcsolat")
+)
+
+// detachTimeout ennyit vár az olvasó és a küldő ciklus megállására
+const detachTimeout = 5 * time.Second
+
+// Detach megállítja a küldést és az olvasást, és visszaadja a munkamenet
+// állapotát a kapcsolat fájlleírójának másolatával. A kapcsolat nyitva marad, a
+// kliens pedig nem csatlakozik újra; hiba esetén változatlanul fut tovább.
+// Sikertelen átadás után a Reattach folytatja a munkamenetet.
+func (c *Client) Detach() (*Session, *os.File, error) {
+	c.mu.RLock()
+	conn, ready := c.conn, c.connected && c.registered
+	c.mu.RUnlock()
+	if conn == nil || !ready {
+		return nil, nil, ErrHandoverNotReady
+	}
+	if _, ok := conn.(*tls.Conn); ok {
+		return nil, nil, ErrHandoverTLS
+	}
+	tcp, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil, nil, fmt.Errorf("nem TCP kapcsolat: %T", conn)
+	}
+
+	// a küldő ciklus két sor között áll meg, így a sorrend nem sérül
+	ack := make(chan struct{})
+	select {
+	case c.sendPause <- ack:
+		<-ack
+	case <-time.After(detachTimeout):
+		return nil, nil, fmt.Errorf("a küldési sor nem állt meg")
+	}
+
+	// az olvasó ciklust a lejárt határidő állítja meg; a már beolvasott bájtokat visszaadja
+	input := make(chan []byte, 1)
+	c.mu.Lock()
+	c.detaching = input
+	c.mu.Unlock()
+	tcp.SetReadDeadline(time.Now())
+	var pending []byte
+	select {
+	case pending = <-input:
+	case <-time.After(detachTimeout):
+		c.mu.Lock()
+		c.detaching = nil
+		c.mu.Unlock()
+		tcp.SetReadDeadline(time.Time{})
+		c.sendResume <- struct{}{}
+		return nil, nil, fmt.Errorf("az olvasás nem állt meg")
+	}
+
+	c.mu.Lock()
+	s := &Session{
+		Server:     c.cfg().Server,
+		Nick:       c.nick,
+		Channels:   keys(c.joinedChannels),
+		Users:      keys(c.loggedUsers),
+		Caps:       keys(c.caps),
+		LoggedIn:   c.loggedIn,
+		Registered: c.registered,
+		Input:      pending,
+	}
+	// a kapcsolatot lekapcsoljuk, de nem zárjuk le (és nem jelzünk a reconnectnek)
+	c.detached = conn
+	c.conn = nil
+	c.connected = false
+	c.mu.Unlock()
+	connectedGauge.Set(0)
+
+	for len(c.sendQueue) > 0 {
+		s.Queue = append(s.Queue, <-c.sendQueue)
+	}
+	sendQueueLen.Set(0)
+
+	f, err := tcp.File()
+	if err != nil {
+		c.Reattach(s)
+		return nil, nil, err
+	}
+	return s, f, nil
+}
+
+// Reattach a Detach után (ha az átadás nem sikerült) folytatja a munkamenetet
+// a saját kapcsolaton
+func (c *Client) Reattach(s *Session) error {
+	c.mu.Lock()
+	conn := c.detached
+	c.detached = nil
+	c.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("nincs lekapcsolt kapcsolat")
+	}
+	c.attach(conn, s)
+	c.sendResume <- struct{}{}
+	return nil
+}
+
+// Adopt egy másik folyamattól átvett kapcsolaton folytatja a munkamenetet:
+// nem küld NICK/USER-t és nem lép be újra a csatornákba, csak NAMES-szel
+// frissíti a csatornák tagjait
+func (c *Client) Adopt(f *os.File, s *Session) error {
+	conn, err := net.FileConn(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("az átvett kapcsolat nem használható: %v", err)
+	}
+	c.attach(conn, s)
+	for _, channel := range s.Channels {
+		c.SendRaw("NAMES " + channel)
+	}
+	return nil
+}
+
+func (c *Client) attach(conn net.Conn, s *Session) {
+	conn.SetReadDeadline(time.Time{})
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.reconnecting = false
+	c.registered = s.Registered
+	c.loggedIn = s.LoggedIn
+	c.lagSent = time.Time{}
+	if s.Nick != "" {
+		c.nick = s.Nick
+	}
+	c.joinedChannels = toSet(s.Channels)
+	c.loggedUsers = toSet(s.Users)
+	c.caps = toSet(s.Caps)
+	c.mu.Unlock()
+	c.lastRead.Store(time.Now().UnixNano())
+	connectedGauge.Set(1)
+
+	go c.readLoop(bufio.NewReader(io.MultiReader(bytes.NewReader(s.Input), conn)))
+
+	for _, line := range s.Queue {
+		c.SendRaw(line)
+	}
+}
+
+// detachRead az olvasó ciklus hibájakor: ha a Detach állította meg, átadja a
+// már beolvasott bájtokat, és igazat ad (ilyenkor nincs Disconnect)
+func (c *Client) detachRead(reader *bufio.Reader, partial string, err error) bool {
+	c.mu.Lock()
+	input := c.detaching
+	c.detaching = nil
+	c.mu.Unlock()
+	if input == nil || !errors.Is(err, os.ErrDeadlineExceeded) {
+		return false
+	}
+	rest, _ := reader.Peek(reader.Buffered())
+	input <- append([]byte(partial), rest...)
+	return true
+}
+
+func keys(set map[string]struct{}) []string {
+	list := make([]string, 0, len(set))
+	for key := range set {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func toSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+	for _, item := range list {
+		set[item] = struct{}{}
+	}
+	return set
+}
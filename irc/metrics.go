@@ -0,0 +1,17 @@
+package irc
+
+import "github.com/ynmhu/YnM-Go/metrics"
+
+var (
+	linesReceived = metrics.NewCounter("ynm_irc_lines_received_total", "A szervertől kapott IRC sorok.")
+	linesSent     = metrics.NewCounter("ynm_irc_lines_sent_total", "A szervernek elküldött IRC sorok.")
+	sendQueueLen  = metrics.NewGauge("ynm_irc_send_queue_length", "A küldési sorban várakozó sorok száma.")
+	sendDropped   = metrics.NewCounter("ynm_irc_send_dropped_total",
+		"Elveszett kimenő sorok: a küldési sor megtelt, vagy nincs kapcsolat.", "reason")
+	reconnects = metrics.NewCounter("ynm_irc_reconnects_total",
+		"Újracsatlakozási kísérletek eredmény szerint.", "result")
+	connectedGauge = metrics.NewGauge("ynm_irc_connected", "1, ha a bot csatlakozva van.")
+	lagGauge       = metrics.NewGauge("ynm_irc_lag_seconds", "A legutóbbi mért késleltetés (PING → PONG).")
+	lagHistogram   = metrics.NewHistogram("ynm_irc_lag_distribution_seconds",
+		"A mért késleltetések eloszlása.", []float64{.01, .05, .1, .25, .5, 1, 2, 5, 10, 30})
+)
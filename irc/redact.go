@@ -0,0 +1,60 @@
+package irc
+
+import "strings"
+
+// secretCommands a NickServ parancsok, amelyek utolsó paramétere jelszó
+var secretCommands = map[string]bool{
+	"IDENTIFY": true, "REGISTER": true, "GHOST": true, "RECOVER": true,
+	"RELEASE": true, "REGAIN": true, "SETPASS": true,
+}
+
+// Redact a naplózáshoz kitakarja a jelszavakat egy nyers IRC sorból:
+// PASS, OPER, AUTHENTICATE adat (SASL) és a NickServ IDENTIFY/GHOST/... parancsok.
+func Redact(line string) string {
+	prefix, rest := "", line
+	if strings.HasPrefix(rest, ":") {
+		if i := strings.IndexByte(rest, ' '); i > 0 {
+			prefix, rest = rest[:i+1], rest[i+1:]
+		}
+	}
+	command, params, _ := strings.Cut(rest, " ")
+	switch strings.ToUpper(command) {
+	case "PASS":
+		return prefix + command + " ***"
+	case "OPER":
+		if name, _, ok := strings.Cut(params, " "); ok {
+			return prefix + command + " " + name + " ***"
+		}
+	case "AUTHENTICATE":
+		// a mechanizmus neve és a "+" (üres / folytatás) nem titok
+		if params != "+" && !strings.EqualFold(params, "PLAIN") && !strings.EqualFold(params, "EXTERNAL") {
+			return prefix + command + " ***"
+		}
+	case "PRIVMSG", "NOTICE":
+		target, text, ok := strings.Cut(params, " :")
+		if !ok || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "&") {
+			break // csatornaüzenet: nem szolgáltatásnak szól
+		}
+		if redacted, changed := redactServiceCommand(text); changed {
+			return prefix + command + " " + target + " :" + redacted
+		}
+	case "NS", "NICKSERV":
+		if redacted, changed := redactServiceCommand(params); changed {
+			return prefix + command + " " + redacted
+		}
+	}
+	return line
+}
+
+// redactServiceCommand: "IDENTIFY nick jelszó" → "IDENTIFY nick ***"
+func redactServiceCommand(text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 || !secretCommands[strings.ToUpper(fields[0])] {
+		return text, false
+	}
+	if strings.EqualFold(fields[0], "REGISTER") && len(fields) >= 3 {
+		return fields[0] + " *** " + strings.Join(fields[2:], " "), true // REGISTER jelszó email
+	}
+	last := len(fields) - 1
+	return strings.Join(append(fields[:last:last], "***"), " "), true
+}
@@ -0,0 +1,32 @@
+package irc
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct{ line, want string }{
+		{"PASS titok", "PASS ***"},
+		{"pass titok", "pass ***"},
+		{"OPER admin titok", "OPER admin ***"},
+		{"PRIVMSG NickServ :IDENTIFY titok", "PRIVMSG NickServ :IDENTIFY ***"},
+		{"PRIVMSG NickServ :IDENTIFY YnM titok", "PRIVMSG NickServ :IDENTIFY YnM ***"},
+		{"PRIVMSG nickserv :identify YnM titok", "PRIVMSG nickserv :identify YnM ***"},
+		{"PRIVMSG NickServ :REGISTER titok a@b.hu", "PRIVMSG NickServ :REGISTER *** a@b.hu"},
+		{"PRIVMSG NickServ :GHOST YnM titok", "PRIVMSG NickServ :GHOST YnM ***"},
+		{"NS IDENTIFY titok", "NS IDENTIFY ***"},
+		{"AUTHENTICATE PLAIN", "AUTHENTICATE PLAIN"},
+		{"AUTHENTICATE +", "AUTHENTICATE +"},
+		{"AUTHENTICATE WW5NAFluTQB0aXRvaw==", "AUTHENTICATE ***"},
+		{":szerver AUTHENTICATE +", ":szerver AUTHENTICATE +"},
+		{":YnM!u@h PASS titok", ":YnM!u@h PASS ***"},
+		// csatornaüzenet és ártalmatlan parancs érintetlen
+		{"PRIVMSG #ynm :IDENTIFY titok", "PRIVMSG #ynm :IDENTIFY titok"},
+		{"PRIVMSG NickServ :INFO YnM", "PRIVMSG NickServ :INFO YnM"},
+		{"PRIVMSG NickServ :IDENTIFY", "PRIVMSG NickServ :IDENTIFY"},
+		{"NICK YnM", "NICK YnM"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.line); got != tt.want {
+			t.Errorf("Redact(%q) = %q, várt %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package irctest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+)
+
+// Bot egy valódi irc.Client a teszt szerverhez kapcsolva. A pluginok
+// irc.Sender-ként / irc.Conn-ként kapják meg.
+type Bot struct {
+	*irc.Client
+	Server *Server
+	Config *config.Config
+
+	registered chan struct{}
+}
+
+// Dial kapcsolódik a szerverhez cfg-vel (nil: s.Config()), de nem vár a
+// regisztrációra (pl. a sikertelen SASL teszteléséhez). A küldési sor nem késleltet.
+func Dial(t testing.TB, s *Server, cfg *config.Config) *Bot {
+	t.Helper()
+	if cfg == nil {
+		cfg = s.Config()
+	}
+	b := &Bot{Client: irc.NewClient(cfg), Server: s, Config: cfg, registered: make(chan struct{})}
+	b.SetSendInterval(0)
+
+	var once sync.Once
+	b.OnRegistered = func() { once.Do(func() { close(b.registered) }) }
+	if err := b.Connect(); err != nil {
+		t.Fatalf("irctest: %v", err)
+	}
+	t.Cleanup(b.Close)
+	return b
+}
+
+// NewBot mint a Dial, de megvárja a regisztrációt (001)
+func NewBot(t testing.TB, s *Server, cfg *config.Config) *Bot {
+	t.Helper()
+	b := Dial(t, s, cfg)
+	if err := b.WaitRegistered(); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// WaitRegistered megvárja, hogy a kliens megkapja és feldolgozza a 001-et
+func (b *Bot) WaitRegistered() error {
+	select {
+	case <-b.registered:
+	case <-time.After(b.Server.Timeout):
+		return fmt.Errorf("irctest: a bot %v alatt nem regisztrált", b.Server.Timeout)
+	}
+	return b.Server.Sync()
+}
+
+// Enter belép a csatornára, és megvárja, hogy a bot feldolgozza a NAMES listát
+func (b *Bot) Enter(t testing.TB, channel string) {
+	t.Helper()
+	from := b.Server.Len()
+	b.Join(channel)
+	if _, err := b.Server.WaitCommand(from, "JOIN"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Handle a bejövő PRIVMSG-eket h-nak adja; a nem üres választ – mint az app –
+// az üzenet csatornájára küldi
+func (b *Bot) Handle(h func(msg irc.Message) string) {
+	b.OnMessage = func(msg irc.Message) {
+		if reply := h(msg); reply != "" {
+			b.SendMessage(msg.Channel, reply)
+		}
+	}
+}
+
+// Plugin amit a plugin manager is hív; a megfigyelő (pluginapi.MessageObserver)
+// és eseménykezelő (pluginapi.EventHandler) pluginokat az Attach felismeri
+type Plugin interface {
+	HandleMessage(msg irc.Message) string
+}
+
+// Attach a pluginokat úgy köti a bothoz, ahogy a plugin manager: minden üzenetet
+// megkapnak a megfigyelők, a válasz az első nem üres HandleMessage eredménye
+// (az üzenet csatornájára), az eseményeket az eseménykezelők kapják
+func (b *Bot) Attach(plugins ...Plugin) {
+	b.Handle(func(msg irc.Message) string {
+		for _, p := range plugins {
+			if observer, ok := p.(pluginapi.MessageObserver); ok {
+				observer.ObserveMessage(msg)
+			}
+		}
+		for _, p := range plugins {
+			if reply := p.HandleMessage(msg); reply != "" {
+				return reply
+			}
+		}
+		return ""
+	})
+	b.HandleEvents(func(ev irc.Event) {
+		for _, p := range plugins {
+			if handler, ok := p.(pluginapi.EventHandler); ok {
+				handler.HandleEvent(ev)
+			}
+		}
+	})
+}
+
+// HandleEvents a JOIN/PART/QUIT/NICK/KICK/TOPIC eseményeket h-nak adja
+func (b *Bot) HandleEvents(h func(ev irc.Event)) {
+	b.OnEvent = h
+}
+
+// Say from nevében target-re írja a szöveget, és a bot közben küldött
+// PRIVMSG-jeinek szövegét adja vissza (bármely címzettnek)
+func (b *Bot) Say(t testing.TB, from, target, text string) []string {
+	t.Helper()
+	return b.exchange(t, func() { b.Server.Privmsg(from, target, text) })
+}
+
+// SayAs mint a Say, de az account-tag jelzi a küldő fiókját
+func (b *Bot) SayAs(t testing.TB, from, account, target, text string) []string {
+	t.Helper()
+	return b.exchange(t, func() { b.Server.PrivmsgAs(from, account, target, text) })
+}
+
+// Sent lefuttatja fn-t, és a bot közben küldött PRIVMSG-jeit adja vissza
+// "célpont: szöveg" alakban
+func (b *Bot) Sent(t testing.TB, fn func()) []string {
+	t.Helper()
+	lines, err := b.Server.Exchange(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, l := range lines {
+		if l.Command == "PRIVMSG" {
+			out = append(out, l.Target()+": "+l.Text())
+		}
+	}
+	return out
+}
+
+func (b *Bot) exchange(t testing.TB, fn func()) []string {
+	t.Helper()
+	lines, err := b.Server.Exchange(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Texts(lines, "PRIVMSG")
+}
+
+// Texts a command parancsú sorok szövege
+func Texts(lines []Line, command string) []string {
+	var texts []string
+	for _, l := range lines {
+		if l.Command == command {
+			texts = append(texts, l.Text())
+		}
+	}
+	return texts
+}
@@ -0,0 +1,40 @@
+package irctest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock léptethető óra; az Env az ütemezőnek adja, így az időzített feladatok
+// valós várakozás nélkül futtathatók
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock t-ről induló óra
+func NewClock(t time.Time) *Clock {
+	return &Clock{now: t}
+}
+
+// Now az óra szerinti idő
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set az órát t-re állítja
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Advance d-vel előre lépteti az órát, és az új időt adja vissza
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
@@ -0,0 +1,78 @@
+package irctest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
+	"github.com/ynmhu/YnM-Go/settings"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// Start az Env órájának kezdőideje: egy hétfő reggel
+var Start = time.Date(2025, time.June, 2, 8, 0, 0, 0, time.Local)
+
+// Env egy plugin teszt környezete: szerver, hozzá kapcsolt bot, ideiglenes
+// adatkönyvtár a közös adatbázissal, beállítások, és léptethető órájú ütemező.
+// Az ütemező nem fut magától; az Advance lépteti.
+type Env struct {
+	*Bot
+	Clock *Clock
+	Ctx   *pluginapi.Context
+	DB    *storage.DB
+}
+
+// NewEnv felépíti a környezetet; configure a bot configját módosíthatja a
+// kapcsolódás előtt (alapból egy csatorna: #test)
+func NewEnv(t testing.TB, configure func(cfg *config.Config)) *Env {
+	t.Helper()
+	s := NewServer(t)
+	cfg := s.Config()
+	cfg.DataDir = t.TempDir()
+	cfg.Channels = []string{"#test"}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	db, err := storage.Open(cfg.StoragePath())
+	if err != nil {
+		t.Fatalf("irctest: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	clock := NewClock(Start)
+	sched := scheduler.New(time.Local, cfg.DataPath("scheduler.json"))
+	sched.SetClock(clock.Now)
+	t.Cleanup(sched.Stop)
+
+	store := settings.NewStore(cfg, cfg.DataPath("settings.json"))
+	ctx := pluginapi.NewContext(store, sched, db, cfg.Scheduler.Jobs)
+
+	return &Env{Bot: NewBot(t, s, cfg), Clock: clock, Ctx: ctx, DB: db}
+}
+
+// Advance d-vel lépteti az órát, lefuttatja az esedékes feladatokat, és megvárja,
+// hogy a küldéseik a szerverhez érjenek
+func (e *Env) Advance(t testing.TB, d time.Duration) {
+	t.Helper()
+	e.Clock.Advance(d)
+	e.RunDue(t)
+}
+
+// Tick mint az Advance, de visszaadja az esedékes feladatok küldéseit
+// "célpont: szöveg" alakban (lásd Sent)
+func (e *Env) Tick(t testing.TB, d time.Duration) []string {
+	t.Helper()
+	return e.Sent(t, func() { e.Advance(t, d) })
+}
+
+// RunDue lefuttatja az óra szerint esedékes feladatokat, és szinkronizál
+func (e *Env) RunDue(t testing.TB) {
+	t.Helper()
+	e.Ctx.Scheduler.RunDue()
+	if err := e.Server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
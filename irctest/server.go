@@ -0,0 +1,816 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package irctest egy folyamaton belüli, szkriptelhető IRC szerver a tesztekhez.
+// Lejátssza a regisztrációt (CAP, SASL PLAIN, NICK/USER, 001), a NickServ
+// azonosítást és a JOIN-t; rögzíti, amit a bot küld, és üzeneteket, eseményeket
+// juttat a bothoz. A Bot és az Env erre épülve egy valódi irc.Client-et kapcsol
+// hozzá, így a pluginok a teljes úton (socket, parser, küldési sor) tesztelhetők.
+package irctest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// DefaultTimeout ennyit várnak a Wait jellegű hívások
+const DefaultTimeout = 5 * time.Second
+
+// Line egy nyers IRC sor részekre bontva
+type Line struct {
+	Raw     string
+	Prefix  string
+	Command string
+	Params  []string
+}
+
+// ParseLine felbontja a sort (az IRCv3 tageket eldobja)
+func ParseLine(raw string) Line {
+	l := Line{Raw: raw}
+	rest := raw
+	if strings.HasPrefix(rest, "@") {
+		_, rest, _ = strings.Cut(rest, " ")
+	}
+	if strings.HasPrefix(rest, ":") {
+		l.Prefix, rest, _ = strings.Cut(rest[1:], " ")
+	}
+	params, trailing, hasTrailing := strings.Cut(rest, " :")
+	if !hasTrailing && strings.HasPrefix(params, ":") {
+		params, trailing, hasTrailing = "", params[1:], true
+	}
+	fields := strings.Fields(params)
+	if len(fields) > 0 {
+		l.Command = strings.ToUpper(fields[0])
+		l.Params = fields[1:]
+	}
+	if hasTrailing {
+		l.Params = append(l.Params, trailing)
+	}
+	return l
+}
+
+// Param az i. paraméter ("" ha nincs)
+func (l Line) Param(i int) string {
+	if i < len(l.Params) {
+		return l.Params[i]
+	}
+	return ""
+}
+
+// Target a PRIVMSG / NOTICE címzettje
+func (l Line) Target() string { return l.Param(0) }
+
+// Text a PRIVMSG / NOTICE szövege (az utolsó paraméter)
+func (l Line) Text() string {
+	if len(l.Params) == 0 {
+		return ""
+	}
+	return l.Params[len(l.Params)-1]
+}
+
+// Handler egy parancs szkriptelt kezelője; a beépített viselkedés a
+// Server.Default hívással kérhető
+type Handler func(c *Conn, l Line)
+
+// Server a teszt IRC szerver
+type Server struct {
+	// Name a szerver neve a válaszok előtagjában
+	Name string
+	// Accounts fiók → jelszó a SASL és a NickServ azonosításhoz
+	Accounts map[string]string
+	// Caps a CAP REQ-re elfogadott képességek
+	Caps []string
+	// Timeout a Wait jellegű hívások határideje
+	Timeout time.Duration
+
+	ln net.Listener
+
+	mu       sync.Mutex
+	conns    []*Conn
+	lines    []Line              // a bottól kapott sorok, érkezési sorrendben
+	cursors  map[string]int      // NextMessage: címzettenként a már visszaadott sorok vége
+	handlers map[string]Handler  // parancs → szkriptelt kezelő
+	members  map[string][]string // kisbetűs csatorna → nickek (a NAMES válaszhoz)
+	topics   map[string]string   // kisbetűs csatorna → topic
+	changed  chan struct{}       // minden új sornál lezárul és újra létrejön
+	syncID   int
+	closed   bool
+}
+
+// NewServer elindítja a szervert a 127.0.0.1 egy szabad portján; a teszt végén leáll
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("irctest: %v", err)
+	}
+	s := &Server{
+		Name:     "irc.test",
+		Accounts: make(map[string]string),
+		Caps:     []string{"sasl", "account-tag"},
+		Timeout:  DefaultTimeout,
+		ln:       ln,
+		cursors:  make(map[string]int),
+		handlers: make(map[string]Handler),
+		members:  make(map[string][]string),
+		topics:   make(map[string]string),
+		changed:  make(chan struct{}),
+	}
+	go s.accept()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Addr a szerver címe (host:port)
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Config egy erre a szerverre kapcsolódó alap bot config: SASL és NickServ
+// nélkül, azonnali csatlakozással, újracsatlakozás nélkül
+func (s *Server) Config() *config.Config {
+	host, port, _ := net.SplitHostPort(s.Addr())
+	return &config.Config{
+		Server:               host,
+		Port:                 port,
+		NickName:             "YnM",
+		UserName:             "ynm",
+		RealName:             "YnM Go Bot",
+		AutoJoinWithoutLogin: true,
+		NickservBotnick:      "NickServ",
+	}
+}
+
+// Handle szkriptelt kezelőt ad egy parancshoz (pl. "JOIN"); felülírja a beépítettet
+func (s *Server) Handle(command string, h Handler) {
+	s.mu.Lock()
+	s.handlers[strings.ToUpper(command)] = h
+	s.mu.Unlock()
+}
+
+// Close leállítja a szervert és bontja a kapcsolatokat
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	conns := s.conns
+	s.mu.Unlock()
+
+	s.ln.Close()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+// Disconnect bontja a bot kapcsolatait (a szerver tovább fut)
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		c := &Conn{server: s, nc: nc, host: "bot.test"}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		go c.serve()
+	}
+}
+
+// ─────────────────────── Küldés a botnak ───────────────────────
+
+// Send nyers sort küld minden regisztrált kapcsolatnak
+func (s *Server) Send(line string) {
+	s.mu.Lock()
+	conns := append([]*Conn(nil), s.conns...)
+	s.mu.Unlock()
+	for _, c := range conns {
+		if c.Registered() {
+			c.Send(line)
+		}
+	}
+}
+
+// Sendf formázott nyers sort küld
+func (s *Server) Sendf(format string, args ...interface{}) {
+	s.Send(fmt.Sprintf(format, args...))
+}
+
+// Mask a nick teljes maszkja ("nick" → "nick!nick@user.test"); a már teljes maszk marad
+func Mask(who string) string {
+	if strings.Contains(who, "!") {
+		return who
+	}
+	return who + "!" + strings.ToLower(who) + "@user.test"
+}
+
+func nickOf(who string) string {
+	return strings.SplitN(who, "!", 2)[0]
+}
+
+// Privmsg üzenetet küld a bothoz from nevében (from lehet nick vagy teljes maszk)
+func (s *Server) Privmsg(from, target, text string) {
+	s.Sendf(":%s PRIVMSG %s :%s", Mask(from), target, text)
+}
+
+// PrivmsgAs mint a Privmsg, de az account-tag jelzi a küldő fiókját
+func (s *Server) PrivmsgAs(from, account, target, text string) {
+	s.Sendf("@account=%s :%s PRIVMSG %s :%s", account, Mask(from), target, text)
+}
+
+// Notice NOTICE-t küld a bothoz
+func (s *Server) Notice(from, target, text string) {
+	s.Sendf(":%s NOTICE %s :%s", Mask(from), target, text)
+}
+
+// Join: who belép a csatornára
+func (s *Server) Join(who, channel string) {
+	s.addMember(channel, nickOf(who))
+	s.Sendf(":%s JOIN %s", Mask(who), channel)
+}
+
+// Part: who kilép a csatornáról
+func (s *Server) Part(who, channel, reason string) {
+	s.removeMember(channel, nickOf(who))
+	s.Sendf(":%s PART %s :%s", Mask(who), channel, reason)
+}
+
+// Quit: who kilép a szerverről
+func (s *Server) Quit(who, reason string) {
+	s.mu.Lock()
+	for ch := range s.members {
+		s.removeMemberLocked(ch, nickOf(who))
+	}
+	s.mu.Unlock()
+	s.Sendf(":%s QUIT :%s", Mask(who), reason)
+}
+
+// Nick: who új nicket vesz fel
+func (s *Server) Nick(who, newNick string) {
+	s.mu.Lock()
+	for ch, nicks := range s.members {
+		for i, n := range nicks {
+			if strings.EqualFold(n, nickOf(who)) {
+				s.members[ch][i] = newNick
+			}
+		}
+	}
+	s.mu.Unlock()
+	s.Sendf(":%s NICK :%s", Mask(who), newNick)
+}
+
+// Kick: by kirúgja target-et a csatornáról
+func (s *Server) Kick(by, channel, target, reason string) {
+	s.removeMember(channel, target)
+	s.Sendf(":%s KICK %s %s :%s", Mask(by), channel, target, reason)
+}
+
+// Topic: who átírja a csatorna topicját
+func (s *Server) Topic(who, channel, topic string) {
+	s.mu.Lock()
+	s.topics[strings.ToLower(channel)] = topic
+	s.mu.Unlock()
+	s.Sendf(":%s TOPIC %s :%s", Mask(who), channel, topic)
+}
+
+func (s *Server) addMember(channel, nick string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lc := strings.ToLower(channel)
+	for _, n := range s.members[lc] {
+		if strings.EqualFold(n, nick) {
+			return
+		}
+	}
+	s.members[lc] = append(s.members[lc], nick)
+}
+
+func (s *Server) removeMember(channel, nick string) {
+	s.mu.Lock()
+	s.removeMemberLocked(strings.ToLower(channel), nick)
+	s.mu.Unlock()
+}
+
+func (s *Server) removeMemberLocked(lc, nick string) {
+	nicks := s.members[lc]
+	for i, n := range nicks {
+		if strings.EqualFold(n, nick) {
+			s.members[lc] = append(nicks[:i:i], nicks[i+1:]...)
+			return
+		}
+	}
+}
+
+// ─────────────────────── A bot sorai ───────────────────────
+
+func (s *Server) record(l Line) {
+	s.mu.Lock()
+	s.lines = append(s.lines, l)
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// Lines a bottól eddig kapott összes sor
+func (s *Server) Lines() []Line {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Line(nil), s.lines...)
+}
+
+// Len az eddig kapott sorok száma (a Wait from paraméteréhez)
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.lines)
+}
+
+// Wait megvárja az első olyan sort a from indextől, amelyre match igaz, és az
+// indexével együtt adja vissza
+func (s *Server) Wait(from int, match func(Line) bool) (int, Line, error) {
+	deadline := time.NewTimer(s.Timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		for i := from; i < len(s.lines); i++ {
+			if match(s.lines[i]) {
+				l := s.lines[i]
+				s.mu.Unlock()
+				return i, l, nil
+			}
+		}
+		from = len(s.lines)
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return -1, Line{}, fmt.Errorf("irctest: %v alatt nem jött a várt sor", s.Timeout)
+		}
+	}
+}
+
+// WaitCommand megvárja a bot következő command parancsát a from indextől
+func (s *Server) WaitCommand(from int, command string) (Line, error) {
+	_, l, err := s.Wait(from, func(l Line) bool { return l.Command == command })
+	return l, err
+}
+
+// Sync megvárja, hogy a bot mindent feldolgozzon és elküldjön, amit eddig kapott:
+// PING-et küld, és a PONG-ra vár. A kliens a sorokat sorban dolgozza fel és
+// egyetlen küldési soron válaszol, így a PONG előtt minden válasz megérkezik.
+func (s *Server) Sync() error {
+	s.mu.Lock()
+	s.syncID++
+	token := fmt.Sprintf("irctest-sync-%d", s.syncID)
+	from := len(s.lines)
+	s.mu.Unlock()
+
+	s.Send("PING :" + token)
+	_, _, err := s.Wait(from, func(l Line) bool { return l.Command == "PONG" && l.Text() == token })
+	return err
+}
+
+// Exchange lefuttatja fn-t (pl. egy Privmsg-et), szinkronizál, és visszaadja a
+// bot közben küldött sorait (a szinkron PONG nélkül)
+func (s *Server) Exchange(fn func()) ([]Line, error) {
+	from := s.Len()
+	fn()
+	if err := s.Sync(); err != nil {
+		return nil, err
+	}
+	var result []Line
+	for _, l := range s.Lines()[from:] {
+		if l.Command == "PONG" && strings.HasPrefix(l.Text(), "irctest-sync-") {
+			continue
+		}
+		result = append(result, l)
+	}
+	return result, nil
+}
+
+// Messages a bot által target-nek küldött PRIVMSG-ek szövege
+func (s *Server) Messages(target string) []string {
+	var texts []string
+	for _, l := range s.Lines() {
+		if l.Command == "PRIVMSG" && strings.EqualFold(l.Target(), target) {
+			texts = append(texts, l.Text())
+		}
+	}
+	return texts
+}
+
+// NextMessage a bot target-nek küldött következő PRIVMSG szövege: az előzőleg
+// visszaadottak utáni első, vagy ha még nincs ilyen, a következő érkező
+func (s *Server) NextMessage(target string) (string, error) {
+	key := strings.ToLower(target)
+	s.mu.Lock()
+	from := s.cursors[key]
+	s.mu.Unlock()
+
+	i, l, err := s.Wait(from, func(l Line) bool {
+		return l.Command == "PRIVMSG" && strings.EqualFold(l.Target(), target)
+	})
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.cursors[key] = i + 1
+	s.mu.Unlock()
+	return l.Text(), nil
+}
+
+// ─────────────────────── Kapcsolat ───────────────────────
+
+// Conn a bot egy kapcsolata a szerver oldalán
+type Conn struct {
+	server *Server
+	nc     net.Conn
+	host   string
+
+	wmu sync.Mutex // a socket írása
+
+	mu         sync.Mutex
+	nick       string
+	user       string
+	account    string
+	capPending bool // CAP tárgyalás alatt a regisztráció vár a CAP END-re
+	registered bool
+	sasl       string // a darabokban érkező AUTHENTICATE adat
+	channels   map[string]bool
+}
+
+// Nick a bot nickje ezen a kapcsolaton
+func (c *Conn) Nick() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nick
+}
+
+// Account a SASL-lal vagy NickServ-vel azonosított fiók ("" ha nincs)
+func (c *Conn) Account() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.account
+}
+
+// Registered megjött-e már a 001
+func (c *Conn) Registered() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.registered
+}
+
+// Channels a csatornák, ahol a bot bent van
+func (c *Conn) Channels() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []string
+	for ch := range c.channels {
+		result = append(result, ch)
+	}
+	return result
+}
+
+// Send nyers sort küld a botnak
+func (c *Conn) Send(line string) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	c.nc.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	c.nc.Write([]byte(line + "\r\n"))
+}
+
+// Sendf formázott nyers sort küld a botnak
+func (c *Conn) Sendf(format string, args ...interface{}) {
+	c.Send(fmt.Sprintf(format, args...))
+}
+
+// Numeric szerver választ küld (":irc.test 001 <nick> ...")
+func (c *Conn) Numeric(code, text string) {
+	nick := c.Nick()
+	if nick == "" {
+		nick = "*"
+	}
+	c.Sendf(":%s %s %s %s", c.server.Name, code, nick, text)
+}
+
+// Close bontja a kapcsolatot
+func (c *Conn) Close() {
+	c.nc.Close()
+}
+
+func (c *Conn) mask() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nick + "!" + c.user + "@" + c.host
+}
+
+func (c *Conn) serve() {
+	defer c.nc.Close()
+	reader := bufio.NewReader(c.nc)
+	for {
+		raw, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		raw = strings.TrimRight(raw, "\r\n")
+		if raw == "" {
+			continue
+		}
+		l := ParseLine(raw)
+		c.server.mu.Lock()
+		h := c.server.handlers[l.Command]
+		c.server.mu.Unlock()
+		if h != nil {
+			h(c, l)
+		} else {
+			c.server.Default(c, l)
+		}
+		// a feldolgozás után: aki a sorra vár, a szerver válaszát is láthatja már
+		c.server.record(l)
+	}
+}
+
+// Default a parancs beépített kezelése (a szkriptelt kezelőkből is hívható)
+func (s *Server) Default(c *Conn, l Line) {
+	switch l.Command {
+	case "CAP":
+		c.handleCap(l)
+	case "AUTHENTICATE":
+		c.handleAuthenticate(l)
+	case "NICK":
+		c.handleNick(l)
+	case "USER":
+		c.mu.Lock()
+		c.user = l.Param(0)
+		c.mu.Unlock()
+		c.maybeWelcome()
+	case "PING":
+		c.Sendf(":%s PONG %s :%s", s.Name, s.Name, l.Text())
+	case "JOIN":
+		for _, ch := range strings.Split(l.Param(0), ",") {
+			c.join(ch)
+		}
+	case "PART":
+		for _, ch := range strings.Split(l.Param(0), ",") {
+			c.mu.Lock()
+			delete(c.channels, strings.ToLower(ch))
+			c.mu.Unlock()
+			s.removeMember(ch, c.Nick())
+			c.Sendf(":%s PART %s", c.mask(), ch)
+		}
+	case "PRIVMSG":
+		if strings.EqualFold(l.Target(), "NickServ") {
+			c.handleNickServ(l.Text())
+		}
+	case "QUIT":
+		c.Close()
+	}
+}
+
+func (c *Conn) handleCap(l Line) {
+	switch strings.ToUpper(l.Param(0)) {
+	case "LS":
+		c.mu.Lock()
+		c.capPending = true
+		c.mu.Unlock()
+		c.Sendf(":%s CAP * LS :%s", c.server.Name, strings.Join(c.server.Caps, " "))
+	case "REQ":
+		c.mu.Lock()
+		c.capPending = true
+		c.mu.Unlock()
+		requested := l.Text()
+		for _, name := range strings.Fields(requested) {
+			if !c.server.supports(strings.TrimPrefix(name, "-")) {
+				c.Sendf(":%s CAP * NAK :%s", c.server.Name, requested)
+				return
+			}
+		}
+		c.Sendf(":%s CAP * ACK :%s", c.server.Name, requested)
+	case "END":
+		c.mu.Lock()
+		c.capPending = false
+		c.mu.Unlock()
+		c.maybeWelcome()
+	}
+}
+
+func (s *Server) supports(capability string) bool {
+	for _, name := range s.Caps {
+		if name == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAuthenticate a SASL PLAIN: "AUTHENTICATE PLAIN" → "+", majd a base64
+// adat legfeljebb 400 bájtos darabokban ("+" zárja, ha pont 400 volt)
+func (c *Conn) handleAuthenticate(l Line) {
+	arg := l.Param(0)
+	if strings.EqualFold(arg, "PLAIN") {
+		c.Send("AUTHENTICATE +")
+		return
+	}
+	if strings.EqualFold(arg, "*") {
+		c.Numeric("906", ":SASL authentication aborted")
+		return
+	}
+
+	c.mu.Lock()
+	if arg != "+" {
+		c.sasl += arg
+	}
+	data := c.sasl
+	complete := arg == "+" || len(arg) < 400
+	if complete {
+		c.sasl = ""
+	}
+	c.mu.Unlock()
+	if !complete {
+		return
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	parts := strings.Split(string(decoded), "\x00")
+	if err != nil || len(parts) != 3 {
+		c.Numeric("904", ":SASL authentication failed")
+		return
+	}
+	account, password := parts[1], parts[2]
+	if !c.server.checkPassword(account, password) {
+		c.Numeric("904", ":SASL authentication failed")
+		return
+	}
+	c.mu.Lock()
+	c.account = account
+	c.mu.Unlock()
+	c.Numeric("900", fmt.Sprintf("%s %s :You are now logged in as %s", c.mask(), account, account))
+	c.Numeric("903", ":SASL authentication successful")
+}
+
+func (s *Server) checkPassword(account, password string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, pass := range s.Accounts {
+		if strings.EqualFold(name, account) {
+			return pass == password
+		}
+	}
+	return false
+}
+
+func (c *Conn) handleNick(l Line) {
+	nick := l.Text()
+	if nick == "" {
+		nick = l.Param(0)
+	}
+	if c.server.nickTaken(c, nick) {
+		c.Numeric("433", nick+" :Nickname is already in use")
+		return
+	}
+	c.mu.Lock()
+	old := c.nick
+	oldMask := c.nick + "!" + c.user + "@" + c.host
+	c.nick = nick
+	registered := c.registered
+	c.mu.Unlock()
+
+	if registered && old != nick {
+		c.Sendf(":%s NICK :%s", oldMask, nick)
+	}
+	c.maybeWelcome()
+}
+
+// nickTaken foglalt-e a nick: egy csatornán lévő (injektált) felhasználó vagy
+// egy másik kapcsolat használja
+func (s *Server) nickTaken(self *Conn, nick string) bool {
+	own := self.Nick()
+	s.mu.Lock()
+	conns := append([]*Conn(nil), s.conns...)
+	for _, nicks := range s.members {
+		for _, n := range nicks {
+			if strings.EqualFold(n, nick) && !strings.EqualFold(n, own) {
+				s.mu.Unlock()
+				return true
+			}
+		}
+	}
+	s.mu.Unlock()
+	for _, c := range conns {
+		if c != self && strings.EqualFold(c.Nick(), nick) {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeWelcome a NICK, USER és a CAP END után regisztrál (001-004, MOTD)
+func (c *Conn) maybeWelcome() {
+	c.mu.Lock()
+	ready := !c.registered && !c.capPending && c.nick != "" && c.user != ""
+	if ready {
+		c.registered = true
+	}
+	nick := c.nick
+	c.mu.Unlock()
+	if !ready {
+		return
+	}
+	name := c.server.Name
+	c.Numeric("001", ":Welcome to the irctest network "+nick)
+	c.Numeric("002", ":Your host is "+name)
+	c.Numeric("003", ":This server was created for tests")
+	c.Numeric("004", name+" irctest o o")
+	c.Numeric("422", ":MOTD File is missing")
+}
+
+func (c *Conn) join(channel string) {
+	if channel == "" {
+		return
+	}
+	lc := strings.ToLower(channel)
+	nick := c.Nick()
+
+	c.mu.Lock()
+	if c.channels == nil {
+		c.channels = make(map[string]bool)
+	}
+	c.channels[lc] = true
+	c.mu.Unlock()
+	c.server.addMember(channel, nick)
+
+	c.server.mu.Lock()
+	topic := c.server.topics[lc]
+	names := make([]string, 0, len(c.server.members[lc]))
+	for _, n := range c.server.members[lc] {
+		if n == nick {
+			n = "@" + n
+		}
+		names = append(names, n)
+	}
+	c.server.mu.Unlock()
+
+	c.Sendf(":%s JOIN %s", c.mask(), channel)
+	if topic != "" {
+		c.Numeric("332", channel+" :"+topic)
+	}
+	c.Numeric("353", "= "+channel+" :"+strings.Join(names, " "))
+	c.Numeric("366", channel+" :End of /NAMES list.")
+}
+
+// handleNickServ: "IDENTIFY [fiók] jelszó"
+func (c *Conn) handleNickServ(text string) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "IDENTIFY") {
+		c.nickServNotice("Unknown command.")
+		return
+	}
+	account, password := c.Nick(), fields[1]
+	if len(fields) >= 3 {
+		account, password = fields[1], fields[2]
+	}
+	if !c.server.checkPassword(account, password) {
+		c.nickServNotice("Authentication failed. Invalid password for " + account + ".")
+		return
+	}
+	c.mu.Lock()
+	c.account = account
+	c.mu.Unlock()
+	c.nickServNotice("You are now identified for " + account + ".")
+}
+
+func (c *Conn) nickServNotice(text string) {
+	c.Sendf(":NickServ!NickServ@services.test NOTICE %s :%s", c.Nick(), text)
+}
@@ -0,0 +1,222 @@
+package irctest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		raw     string
+		prefix  string
+		command string
+		params  []string
+	}{
+		{"PING :abc", "", "PING", []string{"abc"}},
+		{"NICK YnM", "", "NICK", []string{"YnM"}},
+		{"USER ynm 0 * :YnM Go Bot", "", "USER", []string{"ynm", "0", "*", "YnM Go Bot"}},
+		{":a!b@c PRIVMSG #test :hello there", "a!b@c", "PRIVMSG", []string{"#test", "hello there"}},
+		{"@account=bob :bob!b@h PRIVMSG #x :hi", "bob!b@h", "PRIVMSG", []string{"#x", "hi"}},
+		{"privmsg #x ::)", "", "PRIVMSG", []string{"#x", ":)"}},
+	}
+	for _, tt := range tests {
+		l := ParseLine(tt.raw)
+		if l.Prefix != tt.prefix || l.Command != tt.command || strings.Join(l.Params, "|") != strings.Join(tt.params, "|") {
+			t.Errorf("ParseLine(%q) = %q %q %q", tt.raw, l.Prefix, l.Command, l.Params)
+		}
+	}
+}
+
+func TestRegistration(t *testing.T) {
+	s := NewServer(t)
+	b := NewBot(t, s, nil)
+
+	if _, err := s.WaitCommand(0, "USER"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.GetNick(); got != "YnM" {
+		t.Errorf("nick = %q", got)
+	}
+	if !b.IsConnected() || !b.IsLoggedIn() {
+		t.Errorf("connected=%v loggedIn=%v", b.IsConnected(), b.IsLoggedIn())
+	}
+}
+
+func TestNickInUse(t *testing.T) {
+	s := NewServer(t)
+	s.addMember("#foglalt", "YnM")
+	b := NewBot(t, s, nil)
+
+	if nick := b.GetNick(); !strings.HasPrefix(nick, "YnM_") {
+		t.Errorf("nick = %q, YnM_ előtagú kellett", nick)
+	}
+}
+
+func TestSASL(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		ok       bool
+	}{
+		{"helyes jelszó", "titok", true},
+		{"rossz jelszó", "rossz", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(t)
+			s.Accounts["ynm"] = "titok"
+			cfg := s.Config()
+			cfg.UseSASL, cfg.SASLUser, cfg.SASLPass = true, "ynm", tt.password
+
+			var mu sync.Mutex
+			var failed string
+			b := Dial(t, s, cfg)
+			b.OnLoginFailed = func(reason string) {
+				mu.Lock()
+				failed = reason
+				mu.Unlock()
+			}
+
+			if _, err := s.WaitCommand(0, "CAP"); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.ok {
+				if _, _, err := s.Wait(0, func(l Line) bool { return l.Command == "CAP" && l.Param(0) == "END" }); err != nil {
+					t.Fatal(err)
+				}
+				if b.IsLoggedIn() {
+					t.Error("rossz jelszóval is bejelentkezett")
+				}
+				return
+			}
+			if err := b.WaitRegistered(); err != nil {
+				t.Fatal(err)
+			}
+			if !b.IsLoggedIn() {
+				t.Error("nincs bejelentkezve")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if failed != "" {
+				t.Errorf("OnLoginFailed: %s", failed)
+			}
+			if _, l, _ := s.Wait(0, func(l Line) bool { return l.Command == "AUTHENTICATE" && l.Param(0) != "PLAIN" }); !strings.Contains(l.Raw, "AUTHENTICATE ") {
+				t.Errorf("hiányzó AUTHENTICATE adat: %q", l.Raw)
+			}
+		})
+	}
+}
+
+func TestNickServ(t *testing.T) {
+	s := NewServer(t)
+	s.Accounts["YnM"] = "titok"
+	cfg := s.Config()
+	cfg.AutoLogin, cfg.AutoJoinWithoutLogin = true, false
+	cfg.NickservNick, cfg.NickservPass = "YnM", "titok"
+
+	success := make(chan struct{})
+	b := NewBot(t, s, cfg)
+	b.OnLoginSuccess = func() { close(success) }
+	if err := b.IdentifyNickServ(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, l, err := s.Wait(0, func(l Line) bool { return l.Command == "PRIVMSG" && l.Target() == "NickServ" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Text() != "IDENTIFY YnM titok" {
+		t.Errorf("IDENTIFY sor: %q", l.Text())
+	}
+	<-success
+	if !b.IsLoggedIn() {
+		t.Error("nincs bejelentkezve")
+	}
+}
+
+func TestJoinAndEvents(t *testing.T) {
+	s := NewServer(t)
+	s.Join("alice", "#test")
+	b := NewBot(t, s, nil)
+
+	var mu sync.Mutex
+	var events []string
+	b.HandleEvents(func(ev irc.Event) {
+		mu.Lock()
+		events = append(events, ev.Type+" "+ev.Nick+" "+ev.Channel+" "+ev.Target+" "+ev.Text)
+		mu.Unlock()
+	})
+
+	b.Enter(t, "#test")
+	if got := b.GetJoinedChannels(); len(got) != 1 || got[0] != "#test" {
+		t.Errorf("csatornák: %v", got)
+	}
+
+	if _, err := s.Exchange(func() {
+		s.Join("bob", "#test")
+		s.Topic("alice", "#test", "új topic")
+		s.Nick("bob", "bobby")
+		s.Kick("alice", "#test", "bobby", "viszlát")
+		s.Part("alice", "#test", "megyek")
+		s.Quit("carol", "bye")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"JOIN YnM #test  ",
+		"JOIN bob #test  ",
+		"TOPIC alice #test  új topic",
+		"NICK bob  bobby ",
+		"KICK alice #test bobby viszlát",
+		"PART alice #test  megyek",
+		"QUIT carol   bye",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("események:\n%s\nvárt:\n%s", strings.Join(events, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHandleAndScript(t *testing.T) {
+	s := NewServer(t)
+	b := NewBot(t, s, nil)
+	b.Handle(func(msg irc.Message) string {
+		if msg.Text == "!hello" {
+			return "szia " + msg.Nick + " (" + msg.Account + ")"
+		}
+		return ""
+	})
+
+	if got := b.Say(t, "alice", "#test", "!hello"); len(got) != 1 || got[0] != "szia alice ()" {
+		t.Errorf("válasz: %q", got)
+	}
+	if got := b.SayAs(t, "alice", "alice_acc", "#test", "!hello"); len(got) != 1 || got[0] != "szia alice (alice_acc)" {
+		t.Errorf("válasz fiókkal: %q", got)
+	}
+	if got := b.Say(t, "alice", "#test", "más"); len(got) != 0 {
+		t.Errorf("nem várt válasz: %q", got)
+	}
+
+	// szkriptelt JOIN: a szerver nem engedi be a botot
+	s.Handle("JOIN", func(c *Conn, l Line) {
+		c.Numeric("474", l.Param(0)+" :Cannot join channel (+b)")
+	})
+	b.Enter(t, "#tiltott")
+	if got := b.GetJoinedChannels(); len(got) != 0 {
+		t.Errorf("tiltott csatornára is belépett: %v", got)
+	}
+
+	b.SendMessage("#test", "egy")
+	b.SendMessage("#test", "kettő")
+	for _, want := range []string{"szia alice ()", "szia alice (alice_acc)", "egy", "kettő"} {
+		got, err := s.NextMessage("#test")
+		if err != nil || got != want {
+			t.Errorf("NextMessage = %q, %v; várt %q", got, err, want)
+		}
+	}
+}
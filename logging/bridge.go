@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// bridge a szabványos log csomag kimenete: minden sort a hívó csomagjának
+// komponens-loggerével, a sor jelöléséből kikövetkeztetett szinten ír ki
+type bridge struct{}
+
+func (bridge) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	component := callerComponent()
+	level := LineLevel(msg)
+	if level < table.level(component) {
+		return len(p), nil
+	}
+	table.remember(component)
+
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:]) // bridge.Write → log.Output → log.Printf → hívó
+	r := slog.NewRecord(timeNow(), level, msg, pcs[0])
+	_ = (&handler{component: component}).Handle(context.Background(), r)
+	return len(p), nil
+}
+
+// LineLevel a régi stílusú naplósor szintje: ❌ hiba, ⚠️ figyelmeztetés, DEBUG, egyébként info
+func LineLevel(line string) slog.Level {
+	switch {
+	case strings.Contains(line, "❌"), strings.HasPrefix(line, "Error"), strings.HasPrefix(line, "Hiba"):
+		return slog.LevelError
+	case strings.Contains(line, "⚠️"), strings.HasPrefix(line, "Warning"):
+		return slog.LevelWarn
+	case strings.HasPrefix(line, "DEBUG"), strings.HasPrefix(line, "[DEBUG]"):
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+var (
+	componentCacheMu sync.RWMutex
+	componentCache   = make(map[uintptr]string)
+)
+
+// callerComponent a log.Printf-et hívó csomag neve (pl. ".../plugins/media" → "media")
+func callerComponent() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !skipFrame(frame.Function) {
+			return componentOf(frame.PC, frame.Function)
+		}
+		if !more {
+			return "app"
+		}
+	}
+}
+
+func skipFrame(fn string) bool {
+	return strings.HasPrefix(fn, "log.") || strings.HasPrefix(fn, "runtime.") ||
+		strings.HasPrefix(fn, "fmt.") || strings.Contains(fn, "/logging.")
+}
+
+func componentOf(pc uintptr, fn string) string {
+	componentCacheMu.RLock()
+	c, ok := componentCache[pc]
+	componentCacheMu.RUnlock()
+	if ok {
+		return c
+	}
+
+	pkg := fn
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if i := strings.Index(pkg, "."); i >= 0 {
+		pkg = pkg[:i]
+	}
+	if pkg == "main" || pkg == "" {
+		pkg = "app"
+	}
+
+	componentCacheMu.Lock()
+	componentCache[pc] = pkg
+	componentCacheMu.Unlock()
+	return pkg
+}
@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// handler a komponens loggerek kezelője: a szintet a levels táblából, a
+// kimenetet hívásonként az aktuális sink-ből veszi
+type handler struct {
+	component string
+	ops       []func(slog.Handler) slog.Handler // WithAttrs / WithGroup, a sink-re alkalmazva
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= table.level(h.component)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	rememberRecord(h.component, r)
+	out := currentSink()
+	if h.component != "" {
+		out = out.WithAttrs([]slog.Attr{slog.String("component", h.component)})
+	}
+	for _, op := range h.ops {
+		out = op(out)
+	}
+	return out.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for _, a := range attrs {
+		if a.Key == "component" {
+			return (&handler{component: a.Value.String(), ops: h.ops}).WithAttrs(without(attrs, "component"))
+		}
+	}
+	return h.with(func(s slog.Handler) slog.Handler { return s.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.with(func(s slog.Handler) slog.Handler { return s.WithGroup(name) })
+}
+
+func (h *handler) with(op func(slog.Handler) slog.Handler) *handler {
+	ops := append(append([]func(slog.Handler) slog.Handler(nil), h.ops...), op)
+	return &handler{component: h.component, ops: ops}
+}
+
+func without(attrs []slog.Attr, key string) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Key != key {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// consoleHandler ember által olvasható sorok:
+// 2025-06-01 12:00:00 INFO  [media] üzenet kulcs=érték
+type consoleHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	attrs  []slog.Attr
+	groups []string
+}
+
+func newConsoleHandler(w io.Writer) *consoleHandler {
+	return &consoleHandler{mu: &sync.Mutex{}, w: w}
+}
+
+func (h *consoleHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	b.WriteString(t.Format("2006-01-02 15:04:05 "))
+	fmt.Fprintf(&b, "%-5s ", r.Level.String())
+
+	var rest []slog.Attr
+	component := ""
+	collect := func(a slog.Attr) bool {
+		if a.Key == "component" && len(h.groups) == 0 {
+			component = a.Value.String()
+		} else {
+			rest = append(rest, a)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		collect(a)
+	}
+	r.Attrs(collect)
+
+	if component != "" {
+		b.WriteString("[" + component + "] ")
+	}
+	b.WriteString(r.Message)
+	prefix := ""
+	if len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+	for _, a := range rest {
+		writeAttr(&b, prefix, a)
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		for _, sub := range a.Value.Group() {
+			writeAttr(b, prefix+a.Key+".", sub)
+		}
+		return
+	}
+	value := a.Value.String()
+	if strings.ContainsAny(value, " \t\"=") || value == "" {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, value)
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &c
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.groups = append(append([]string(nil), h.groups...), name)
+	return &c
+}
+
+// fanout több kimenetre írja ugyanazt a rekordot (fájl és stderr)
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, l slog.Level) bool { return true }
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,267 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package logging a bot naplózása log/slog alapon: komponensenkénti szintek
+// (configból és futás közben, !loglevel), konzolos vagy JSON kimenet, forgatott
+// naplófájl. A régi log.Printf hívások is ide futnak be: a komponens a hívó
+// csomag neve, a szint a sor jelölése (❌ hiba, ⚠️ figyelmeztetés, DEBUG).
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// levels a komponensenkénti szintek. Elsőbbség: futás közbeni (!loglevel) →
+// config → a szülő komponens ("irc.raw" → "irc") → alapszint.
+type levels struct {
+	mu        sync.RWMutex
+	base      slog.Level
+	forced    *slog.Level // --log-level: felülírja a config alapszintjét
+	config    map[string]slog.Level
+	overrides map[string]slog.Level
+	seen      map[string]bool // a naplózott komponensek (a !loglevel listához)
+}
+
+var table = &levels{
+	base:      slog.LevelInfo,
+	config:    make(map[string]slog.Level),
+	overrides: make(map[string]slog.Level),
+	seen:      make(map[string]bool),
+}
+
+// sink az aktuális kimenet; a loggerek hívásonként ezt használják, így a
+// csomag szintű (Setup előtt létrehozott) loggerek is az új kimenetre írnak
+var sink atomic.Value // sinkBox
+
+// sinkBox: az atomic.Value mindig azonos típust vár
+type sinkBox struct{ h slog.Handler }
+
+func init() {
+	setSink(newConsoleHandler(os.Stderr))
+}
+
+func setSink(h slog.Handler) { sink.Store(sinkBox{h}) }
+
+func currentSink() slog.Handler { return sink.Load().(sinkBox).h }
+
+func (t *levels) level(component string) slog.Level {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for c := component; c != ""; {
+		if l, ok := t.overrides[c]; ok {
+			return l
+		}
+		if l, ok := t.config[c]; ok {
+			return l
+		}
+		i := strings.LastIndex(c, ".")
+		if i < 0 {
+			break
+		}
+		c = c[:i]
+	}
+	if t.forced != nil {
+		return *t.forced
+	}
+	return t.base
+}
+
+func (t *levels) remember(component string) {
+	if component == "" {
+		return
+	}
+	t.mu.RLock()
+	known := t.seen[component]
+	t.mu.RUnlock()
+	if !known {
+		t.mu.Lock()
+		t.seen[component] = true
+		t.mu.Unlock()
+	}
+}
+
+// For a komponens loggere, pl. logging.For("media")
+func For(component string) *slog.Logger {
+	table.remember(component)
+	return slog.New(&handler{component: component})
+}
+
+// ParseLevel a szint neve: debug, info, warn (warning), error
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("ismeretlen naplózási szint: %q (debug, info, warn, error)", s)
+}
+
+// LevelName a szint kisbetűs neve
+func LevelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
+
+// Force a parancssori --log-level: az alapszintet a config helyett ez adja
+func Force(level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	table.mu.Lock()
+	table.forced = &l
+	table.mu.Unlock()
+	return nil
+}
+
+// ApplyLevels beállítja a config szintjeit (induláskor és újratöltéskor).
+// A futás közben (!loglevel) állított szintek megmaradnak.
+func ApplyLevels(cfg config.LoggingConfig) error {
+	base, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	components := make(map[string]slog.Level, len(cfg.Components))
+	for name, level := range cfg.Components {
+		l, err := ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		components[strings.ToLower(name)] = l
+	}
+
+	table.mu.Lock()
+	table.base = base
+	table.config = components
+	table.mu.Unlock()
+	return nil
+}
+
+// SetLevel futás közben állítja egy komponens szintjét; a "reset" visszaáll a configra
+func SetLevel(component, level string) error {
+	component = strings.ToLower(component)
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if strings.EqualFold(level, "reset") {
+		delete(table.overrides, component)
+		return nil
+	}
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	table.overrides[component] = l
+	table.seen[component] = true
+	return nil
+}
+
+// ComponentLevel egy komponens érvényes szintje és annak forrása
+type ComponentLevel struct {
+	Component string
+	Level     slog.Level
+	Source    string // "runtime", "config" vagy "default"
+}
+
+// Levels a naplózott és a beállított komponensek szintjei, név szerint
+func Levels() []ComponentLevel {
+	table.mu.RLock()
+	sources := make(map[string]string)
+	for c := range table.seen {
+		sources[c] = "default"
+	}
+	for c := range table.config {
+		sources[c] = "config"
+	}
+	for c := range table.overrides {
+		sources[c] = "runtime"
+	}
+	table.mu.RUnlock()
+
+	out := make([]ComponentLevel, 0, len(sources))
+	for c, source := range sources {
+		out = append(out, ComponentLevel{Component: c, Level: table.level(c), Source: source})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Component < out[j].Component })
+	return out
+}
+
+// BaseLevel az alapszint (a külön nem állított komponenseké)
+func BaseLevel() slog.Level {
+	return table.level("")
+}
+
+// Setup beállítja a kimenetet és a szinteket, és a szabványos log csomagot is
+// átirányítja. A visszaadott Closer a naplófájlt zárja.
+func Setup(cfg config.LoggingConfig) (io.Closer, error) {
+	if err := ApplyLevels(cfg); err != nil {
+		return nil, err
+	}
+
+	var closer io.Closer = nopCloser{}
+	var outputs []io.Writer
+	if cfg.File != "" {
+		f, err := OpenRotating(cfg.File, cfg.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		closer = f
+		outputs = append(outputs, f)
+		if cfg.AlsoStderr {
+			outputs = append(outputs, os.Stderr)
+		}
+	} else {
+		outputs = append(outputs, os.Stderr)
+	}
+
+	var handlers []slog.Handler
+	for _, w := range outputs {
+		if cfg.Format == "json" {
+			handlers = append(handlers, slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+		} else {
+			handlers = append(handlers, newConsoleHandler(w))
+		}
+	}
+	if len(handlers) == 1 {
+		setSink(handlers[0])
+	} else {
+		setSink(fanout(handlers))
+	}
+
+	RedirectStdLog()
+	return closer, nil
+}
+
+// RedirectStdLog a log.Printf hívásokat a komponens loggerekre tereli
+func RedirectStdLog() {
+	slog.SetDefault(For("app"))
+	// a SetDefault a log csomagot a saját írójára állítja; ezt cseréljük
+	log.SetFlags(0)
+	log.SetOutput(bridge{})
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// recentSize ennyi legutóbbi figyelmeztetést és hibát őrzünk meg (a HTTP API-hoz)
+const recentSize = 100
+
+// Record egy megőrzött naplóbejegyzés
+type Record struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Component string    `json:"component,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// recentRing a legutóbbi figyelmeztetések és hibák körpuffere
+type recentRing struct {
+	mu      sync.Mutex
+	records [recentSize]Record
+	next    int
+	full    bool
+}
+
+var recent = &recentRing{}
+
+func (r *recentRing) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % recentSize
+	r.full = r.full || r.next == 0
+}
+
+// rememberRecord a figyelmeztetés és hiba szintű bejegyzéseket megőrzi
+func rememberRecord(component string, r slog.Record) {
+	if r.Level < slog.LevelWarn {
+		return
+	}
+	recent.add(Record{Time: r.Time, Level: LevelName(r.Level), Component: component, Message: r.Message})
+}
+
+// RecentErrors a legutóbbi (legfeljebb 100) figyelmeztetés és hiba, a legújabb elöl
+func RecentErrors() []Record {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	n := recent.next
+	if recent.full {
+		n = recentSize
+	}
+	out := make([]Record, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, recent.records[(recent.next-i+recentSize)%recentSize])
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// timeNow a forgatás és a rekordok órája
+var timeNow = time.Now
+
+// RotatingFile méret és/vagy nap szerint forgatott naplófájl. A régi fájlok
+// neve időbélyeget kap (bot.log → bot-20250601-120000.log), a megőrzést a
+// MaxBackups és a MaxAgeDays szabja meg.
+type RotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	policy config.RotationConfig
+	file   *os.File
+	size   int64
+	day    string
+}
+
+// OpenRotating megnyitja (vagy létrehozza) a naplófájlt
+func OpenRotating(path string, policy config.RotationConfig) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &RotatingFile{path: path, policy: policy}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	r.day = info.ModTime().Format("2006-01-02")
+	if info.Size() == 0 {
+		r.day = timeNow().Format("2006-01-02")
+	}
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.due(len(p)) {
+		if err := r.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ Naplófájl forgatási hiba: %v\n", err)
+		}
+		if r.file == nil {
+			return 0, os.ErrClosed
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) due(next int) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.policy.MaxSizeMB > 0 && r.size+int64(next) > int64(r.policy.MaxSizeMB)<<20 {
+		return true
+	}
+	return r.policy.Daily && timeNow().Format("2006-01-02") != r.day
+}
+
+// rotate átnevezi a fájlt és újat nyit. Hiba esetén az eredeti útvonalra
+// nyit vissza, így a naplózás forgatás nélkül folytatódik.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return r.reopen(err)
+	}
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext)
+	target := fmt.Sprintf("%s-%s%s", base, timeNow().Format("20060102-150405"), ext)
+	if err := os.Rename(r.path, target); err != nil {
+		return r.reopen(err)
+	}
+	if err := r.open(); err != nil {
+		// az új fájl nem nyílt meg: a régit visszanevezzük, és abba írunk tovább
+		_ = os.Rename(target, r.path)
+		return r.reopen(err)
+	}
+	r.prune(base, ext)
+	return nil
+}
+
+// reopen a sikertelen forgatás után újra megnyitja az eredeti útvonalat;
+// ha ez sem sikerül, a fájl zárva marad (a Write hibát ad)
+func (r *RotatingFile) reopen(cause error) error {
+	if err := r.open(); err != nil {
+		r.file = nil
+		return fmt.Errorf("%v (újranyitás: %v)", cause, err)
+	}
+	return cause
+}
+
+// prune a megőrzési szabályon kívül eső régi fájlokat törli
+func (r *RotatingFile) prune(base, ext string) {
+	old, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(old))) // legújabb elöl
+	cutoff := timeNow().AddDate(0, 0, -r.policy.MaxAgeDays)
+	for i, name := range old {
+		expired := r.policy.MaxBackups > 0 && i >= r.policy.MaxBackups
+		if r.policy.MaxAgeDays > 0 {
+			if info, err := os.Stat(name); err == nil && info.ModTime().Before(cutoff) {
+				expired = true
+			}
+		}
+		if expired {
+			_ = os.Remove(name)
+		}
+	}
+}
+
+// Close lezárja a fájlt
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// Sikertelen átnevezés után a napló az eredeti fájlban folytatódik
+func TestRotateFailureReopens(t *testing.T) {
+	day := time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)
+	timeNow = func() time.Time { return day }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	path := filepath.Join(t.TempDir(), "bot.log")
+	r, err := OpenRotating(path, config.RotationConfig{Daily: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := r.Write([]byte("első\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a forgatás célja egy nem üres könyvtár, így az átnevezés nem sikerülhet
+	day = day.Add(2 * time.Minute)
+	target := filepath.Join(filepath.Dir(path), "bot-"+day.Format("20060102-150405")+".log")
+	if err := os.MkdirAll(filepath.Join(target, "foglalt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("második\n")); err != nil {
+		t.Fatalf("írás a sikertelen forgatás után: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "első\nmásodik\n" {
+		t.Errorf("naplófájl: %q", data)
+	}
+}
@@ -0,0 +1,282 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package logindex a csatornanaplók teljes szöveges keresőindexe (SQLite FTS5).
+// Az indexelő a LogDir fájljait olvassa fájlonként nyilvántartott pozíciótól,
+// így a meglévő (akár tömörített) naplókat is feldolgozza, és percenként csak
+// az új sorokat veszi fel. Privát beszélgetések nem kerülnek az indexbe.
+//
+// Az FTS5 a go-sqlite3-ban build taggel kapcsolható be (go build -tags sqlite_fts5);
+// nélküle a keresés egyszerű LIKE szűréssel, idő szerint rendezve működik.
+package logindex
+
+import (
+	"bufio"
+	"compress/gzip"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/ynmhu/YnM-Go/chanlog"
+)
+
+// Index a keresőindex adatbázisa
+type Index struct {
+	db  *sql.DB
+	fts bool // van FTS5 (különben LIKE keresés)
+
+	syncMu sync.Mutex // egyszerre egy indexelés fut
+}
+
+// Open megnyitja (létrehozza) az indexet
+func Open(path string) (*Index, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	ix := &Index{db: db}
+	if err := ix.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("naplóindex (%s): %w", path, err)
+	}
+	return ix, nil
+}
+
+func (ix *Index) migrate() error {
+	_, err := ix.db.Exec(`
+		CREATE TABLE IF NOT EXISTS entries (
+			id     INTEGER PRIMARY KEY,
+			ts     INTEGER NOT NULL,
+			target TEXT NOT NULL,
+			nick   TEXT NOT NULL COLLATE NOCASE,
+			type   TEXT NOT NULL,
+			text   TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS entries_target_ts ON entries(target, ts);
+		CREATE TABLE IF NOT EXISTS indexed_files (
+			name   TEXT PRIMARY KEY,
+			offset INTEGER NOT NULL,
+			done   INTEGER NOT NULL DEFAULT 0 -- tömörített, teljesen feldolgozva
+		);
+		CREATE TABLE IF NOT EXISTS meta (
+			key   TEXT PRIMARY KEY,
+			value INTEGER NOT NULL
+		);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ix.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS entries_fts USING fts5(
+		text, content='entries', content_rowid='id', tokenize='unicode61 remove_diacritics 2')`)
+	if err == nil {
+		// FTS5 nélküli build esetén a már létező tábla megnyitása is itt bukik el
+		_, err = ix.db.Exec(`SELECT rowid FROM entries_fts LIMIT 0`)
+	}
+	if err != nil {
+		if !strings.Contains(err.Error(), "no such module") {
+			return err
+		}
+		log.Printf("⚠️ Az SQLite FTS5 nélkül készült (go build -tags sqlite_fts5), a !grep egyszerű szövegkereséssel működik")
+		return nil
+	}
+	ix.fts = true
+
+	// a FTS5 nélküli futások alatt felvett sorok pótlása
+	tx, err := ix.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := catchUpFTS(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// catchUpFTS a még nem indexelt sorokat a szövegindexbe is felveszi
+func catchUpFTS(tx *sql.Tx) error {
+	var last int64
+	if err := tx.QueryRow(`SELECT COALESCE((SELECT value FROM meta WHERE key = 'fts_rowid'), 0)`).Scan(&last); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO entries_fts(rowid, text) SELECT id, text FROM entries WHERE id > ?`, last); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`INSERT INTO meta(key, value) VALUES ('fts_rowid', (SELECT COALESCE(MAX(id), 0) FROM entries))
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`)
+	return err
+}
+
+// FTS true, ha a keresés az FTS5 rangsorolását használja
+func (ix *Index) FTS() bool { return ix.fts }
+
+// Close lezárja az adatbázist
+func (ix *Index) Close() error {
+	return ix.db.Close()
+}
+
+// Sync a könyvtár csatornanaplóinak új sorait veszi fel; visszaadja a felvett sorok számát
+func (ix *Index) Sync(dir string) (int, error) {
+	ix.syncMu.Lock()
+	defer ix.syncMu.Unlock()
+
+	files, err := chanlog.ListFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+	states, err := ix.fileStates()
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	var errs []error
+	for _, f := range files {
+		if !strings.ContainsRune("#&+!", rune(f.Target[0])) {
+			continue // privát beszélgetés
+		}
+		st := states[f.Name]
+		if st.done {
+			continue
+		}
+		n, offset, err := ix.syncFile(f, st.offset)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Path, err)) // a többi fájl ettől még feldolgozható
+			continue
+		}
+		added += n
+		states[f.Name] = fileState{offset: offset, done: f.Compressed}
+	}
+	return added, errors.Join(errs...)
+}
+
+// fileState egy naplófájl feldolgozottsága
+type fileState struct {
+	offset int64 // a tömörítetlen tartalomban eddig feldolgozott bájtok
+	done   bool  // tömörített (lezárt nap), teljesen feldolgozva
+}
+
+func (ix *Index) fileStates() (map[string]fileState, error) {
+	rows, err := ix.db.Query(`SELECT name, offset, done FROM indexed_files`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	states := make(map[string]fileState)
+	for rows.Next() {
+		var name string
+		var st fileState
+		if err := rows.Scan(&name, &st.offset, &st.done); err != nil {
+			return nil, err
+		}
+		states[name] = st
+	}
+	return states, rows.Err()
+}
+
+// syncFile a fájl offset utáni teljes sorait veszi fel egy tranzakcióban.
+// A tömörített fájl ugyanazon a néven fut tovább, így nem indexelődik újra;
+// a feldolgozása után lezártnak számít.
+func (ix *Index) syncFile(f chanlog.LogFile, start int64) (int, int64, error) {
+	offset := start
+	if !f.Compressed {
+		info, err := os.Stat(f.Path)
+		if err != nil || info.Size() <= offset {
+			return 0, offset, err
+		}
+	}
+
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return 0, offset, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if f.Compressed {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, offset, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	if _, err := io.CopyN(io.Discard, r, offset); err != nil && err != io.EOF {
+		return 0, start, err
+	}
+
+	tx, err := ix.db.Begin()
+	if err != nil {
+		return 0, start, err
+	}
+	defer tx.Rollback()
+	insert, err := tx.Prepare(`INSERT INTO entries(ts, target, nick, type, text) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, start, err
+	}
+	defer insert.Close()
+
+	added := 0
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			break // a félig kiírt utolsó sort a következő körben olvassuk
+		}
+		offset += int64(len(line))
+		e, ok := chanlog.ParseLine(f, strings.TrimRight(line, "\r\n"))
+		if !ok || !searchable(e) {
+			continue
+		}
+		target := strings.ToLower(e.Target)
+		if _, err := insert.Exec(e.Time.Unix(), target, e.Nick, e.Type, e.Text); err != nil {
+			return 0, start, err
+		}
+		added++
+	}
+
+	if _, err := tx.Exec(`INSERT INTO indexed_files(name, offset, done) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET offset = excluded.offset, done = excluded.done`,
+		f.Name, offset, f.Compressed); err != nil {
+		return 0, start, err
+	}
+	if ix.fts {
+		if err := catchUpFTS(tx); err != nil {
+			return 0, start, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, start, err
+	}
+	return added, offset, nil
+}
+
+// searchable: csak a szöveges sorok kerülnek az indexbe (a JOIN/PART … nem)
+func searchable(e chanlog.Entry) bool {
+	switch e.Type {
+	case chanlog.TypeMessage, chanlog.TypeAction, chanlog.TypeNotice, chanlog.TypeTopic:
+		return e.Text != ""
+	}
+	return false
+}
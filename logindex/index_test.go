@@ -0,0 +1,110 @@
+package logindex
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTest(t *testing.T) *Index {
+	t.Helper()
+	ix, err := Open(filepath.Join(t.TempDir(), "logindex.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ix.Close() })
+	return ix
+}
+
+func appendFile(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// gzipFile a chanlog napváltásához hasonlóan tömörít: név.log → név.log.gz
+func gzipFile(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countEntries(t *testing.T, ix *Index) int {
+	t.Helper()
+	var n int
+	if err := ix.db.QueryRow(`SELECT COUNT(*) FROM entries`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// A napváltáskor tömörített fájlt a tárolt pozíciótól folytatja: a már
+// felvett sorok nem duplázódnak, a félig kiírt sor a befejezése után kerül be
+func TestSyncResumesAcrossCompression(t *testing.T) {
+	dir := t.TempDir()
+	raw := filepath.Join(dir, "#ynm_2025-03-01.log")
+	appendFile(t, raw, "--- Log opened Sat Mar 01 12:00:00 2025\n"+
+		"12:00 <alice> első sor\n"+
+		"12:01 -!- bob [u@h] has joined #ynm\n"+
+		"12:02 <bob> második sor\n"+
+		"12:03 <alice> harm")
+	appendFile(t, filepath.Join(dir, "bob_2025-03-01.log"), "12:00 <bob> privát üzenet\n")
+
+	ix := openTest(t)
+	sync := func(want int) {
+		t.Helper()
+		n, err := ix.Sync(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("Sync: %d új sor, várt %d", n, want)
+		}
+	}
+
+	sync(2)
+	sync(0)
+	appendFile(t, raw, "adik sor\n12:04 <bob> negyedik sor\n")
+	gzipFile(t, raw)
+	sync(2)
+	sync(0)
+
+	if n := countEntries(t, ix); n != 4 {
+		t.Errorf("%d sor az indexben, várt 4", n)
+	}
+	hits, err := ix.Search(Query{Text: "harmadik", Channels: []string{"#YnM"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Nick != "alice" || hits[0].Channel != "#ynm" {
+		t.Errorf("harmadik: %+v", hits)
+	}
+}
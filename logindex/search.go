@@ -0,0 +1,120 @@
+package logindex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query egy keresés feltételei
+type Query struct {
+	Text     string    // a keresett szavak (mind szerepeljen); "szó*" előtagra keres
+	Channels []string  // csak ezekben a csatornákban (üres: nincs találat)
+	Nick     string    // csak ennek a nicknek a sorai
+	Since    time.Time // csak ennél újabb sorok
+	Limit    int
+}
+
+// Hit egy találat
+type Hit struct {
+	Time    time.Time
+	Channel string // kisbetűs
+	Nick    string
+	Type    string // chanlog.Type…
+	Text    string
+}
+
+// Search a feltételeknek megfelelő sorok: FTS5 esetén relevancia, egyébként idő szerint
+func (ix *Index) Search(q Query) ([]Hit, error) {
+	terms := strings.Fields(q.Text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("üres keresés")
+	}
+	if len(q.Channels) == 0 {
+		return nil, nil
+	}
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+
+	var (
+		from, order string
+		where       []string
+		args        []interface{}
+	)
+	if ix.fts {
+		expr := matchExpr(terms)
+		if expr == "" {
+			return nil, fmt.Errorf("üres keresés")
+		}
+		from = "entries_fts JOIN entries e ON e.id = entries_fts.rowid"
+		where = append(where, "entries_fts MATCH ?")
+		args = append(args, expr)
+		order = "bm25(entries_fts), e.ts DESC"
+	} else {
+		from = "entries e"
+		for _, term := range terms {
+			where = append(where, `e.text LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+likeEscape(strings.TrimSuffix(term, "*"))+"%")
+		}
+		order = "e.ts DESC"
+	}
+
+	placeholders := make([]string, len(q.Channels))
+	for i, ch := range q.Channels {
+		placeholders[i] = "?"
+		args = append(args, strings.ToLower(ch))
+	}
+	where = append(where, "e.target IN ("+strings.Join(placeholders, ", ")+")")
+	if q.Nick != "" {
+		where = append(where, "e.nick = ?")
+		args = append(args, q.Nick)
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "e.ts >= ?")
+		args = append(args, q.Since.Unix())
+	}
+	args = append(args, q.Limit)
+
+	rows, err := ix.db.Query(`SELECT e.ts, e.target, e.nick, e.type, e.text FROM `+from+
+		` WHERE `+strings.Join(where, " AND ")+` ORDER BY `+order+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []Hit
+	for rows.Next() {
+		var h Hit
+		var ts int64
+		if err := rows.Scan(&ts, &h.Channel, &h.Nick, &h.Type, &h.Text); err != nil {
+			return nil, err
+		}
+		h.Time = time.Unix(ts, 0)
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// matchExpr a szavakból FTS5 kifejezést készít: minden szó idézőjelbe kerül
+// (így a felhasználó nem írhat FTS szintaxist), a "szó*" előtagkeresés marad
+func matchExpr(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefix := strings.HasSuffix(term, "*")
+		term = strings.Trim(term, `*"`)
+		if term == "" {
+			continue
+		}
+		part := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if prefix {
+			part += "*"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,90 @@
+package logindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/chanlog"
+)
+
+func TestMatchExpr(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{[]string{"alma"}, `"alma"`},
+		{[]string{"alm*"}, `"alm"*`},
+		{[]string{"alma", "körte"}, `"alma" "körte"`},
+		{[]string{`a"b`}, `"a""b"`},
+		{[]string{`"idézet"`}, `"idézet"`},
+		{[]string{"NOT", "alma", "OR", "NEAR(x"}, `"NOT" "alma" "OR" "NEAR(x"`},
+		{[]string{"text:alma", "^kezd", "-ki"}, `"text:alma" "^kezd" "-ki"`},
+		{[]string{"*", `""`, `"*"`}, ""},
+	}
+	for _, tt := range tests {
+		if got := matchExpr(tt.terms); got != tt.want {
+			t.Errorf("matchExpr(%q) = %s, várt %s", tt.terms, got, tt.want)
+		}
+	}
+}
+
+// FTS5 nélkül a keresés LIKE szűrés: minden szónak szerepelnie kell, a % és
+// a _ szó szerint értendő, a "szó*" előtag-csillag elhagyható
+func TestSearchLike(t *testing.T) {
+	ix := openTest(t)
+	ix.fts = false
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	rows := []struct{ target, nick, text string }{
+		{"#ynm", "alice", "almafa a kertben"},
+		{"#ynm", "bob", "100% biztos"},
+		{"#ynm", "bob", "100 biztos"},
+		{"#ynm", "alice", "a_b változó"},
+		{"#ynm", "alice", "axb változó"},
+		{"#mas", "alice", "almafa máshol"},
+	}
+	for i, r := range rows {
+		if _, err := ix.db.Exec(`INSERT INTO entries(ts, target, nick, type, text) VALUES (?, ?, ?, ?, ?)`,
+			base.Add(time.Duration(i)*time.Minute).Unix(), r.target, r.nick, chanlog.TypeMessage, r.text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string // a találatok szövege, újabb elöl
+	}{
+		{"egy szó", Query{Text: "almafa", Channels: []string{"#YNM"}}, []string{"almafa a kertben"}},
+		{"előtag", Query{Text: "alma*", Channels: []string{"#ynm", "#mas"}}, []string{"almafa máshol", "almafa a kertben"}},
+		{"minden szó", Query{Text: "biztos 100%", Channels: []string{"#ynm"}}, []string{"100% biztos"}},
+		{"aláhúzás", Query{Text: "a_b", Channels: []string{"#ynm"}}, []string{"a_b változó"}},
+		{"nick", Query{Text: "változó", Channels: []string{"#ynm"}, Nick: "ALICE"}, []string{"axb változó", "a_b változó"}},
+		{"időszak", Query{Text: "biztos", Channels: []string{"#ynm"}, Since: base.Add(2 * time.Minute)}, []string{"100 biztos"}},
+		{"limit", Query{Text: "a", Channels: []string{"#ynm"}, Limit: 1}, []string{"axb változó"}},
+		{"csatorna nélkül", Query{Text: "almafa"}, nil},
+	}
+	for _, tt := range tests {
+		hits, err := ix.Search(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, h := range hits {
+			got = append(got, h.Text)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %q, várt %q", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: %q, várt %q", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := ix.Search(Query{Text: "  ", Channels: []string{"#ynm"}}); err == nil {
+		t.Error("üres keresés: nincs hiba")
+	}
+}
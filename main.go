@@ -1,22 +1,12 @@
 package main
 
 import (
-	"log"
+	"os"
 
-	"github.com/ynmhu/YnM-Go/app"
-	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/cli"
 )
 
 func main() {
-	// Konfiguráció betöltése
-	cfg, err := config.Load("config/config.yaml")
-	if err != nil {
-		log.Fatalf("Config betöltési hiba: %v", err)
-	}
-
-	// Alkalmazás létrehozása és indítása
-	application := app.New(cfg)
-	if err := application.Run(); err != nil {
-		log.Fatalf("Alkalmazás hiba: %v", err)
-	}
-}
\ No newline at end of file
+	// Parancssor feldolgozása (alapértelmezés: a bot indítása)
+	os.Exit(cli.Run(os.Args[1:]))
+}
@@ -0,0 +1,328 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package metrics a bot Prometheus mérőszámai: számlálók, mérők és hisztogramok
+// címkékkel, a Prometheus szöveges formátumában kiírva (a HTTP API /metrics
+// végpontja). Külső függőség nélkül működik; a mérőszámokat a csomagok
+// csomagszintű változóként hozzák létre, az alapértelmezett regiszterben.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefBuckets az időtartam-hisztogramok alapértelmezett határai (másodperc)
+var DefBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector egy kiírható mérőszám
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry a regisztrált mérőszámok
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// NewRegistry üres regiszter
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Default az alapértelmezett regiszter; a New* függvények ebbe regisztrálnak
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[c.name()] {
+		panic("metrics: kétszer regisztrált mérőszám: " + c.name())
+	}
+	r.names[c.name()] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText kiírja a mérőszámokat a Prometheus szöveges formátumában, név szerint
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler a /metrics kiszolgálója
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// Handler az alapértelmezett regiszter /metrics kiszolgálója
+func Handler() http.Handler { return Default.Handler() }
+
+// desc a mérőszámok közös leírása
+type desc struct {
+	fqName string
+	help   string
+	kind   string // counter, gauge, histogram
+	labels []string
+}
+
+func (d *desc) name() string { return d.fqName }
+
+func (d *desc) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.fqName, escapeHelp(d.help), d.fqName, d.kind)
+}
+
+// key a címkeértékekből képzett kulcs (ellenőrzi a darabszámot)
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s: %d címkeérték kell, %d érkezett", d.fqName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelText a {a="x",b="y"} rész; extra a hisztogram "le" címkéje
+func (d *desc) labelText(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+escapeLabel(v)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+extra[i+1]+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ───────────────────────────── Számláló ─────────────────────────────
+
+// Counter csak növekvő érték, címkénként
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter számlálót regisztrál (a név végződése _total)
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{fqName: name, help: help, kind: "counter", labels: labels}, values: make(map[string]float64)}
+	if len(labels) == 0 {
+		c.values[""] = 0 // címke nélkül nulláról is látszik
+	}
+	Default.register(c)
+	return c
+}
+
+// Inc eggyel növel
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add v-vel növel (negatív érték nem megengedett)
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := c.key(labelValues)
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+// Value az aktuális érték
+func (c *Counter) Value(labelValues ...string) float64 {
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.fqName, c.labelText(key), formatFloat(c.values[key]))
+	}
+}
+
+// ───────────────────────────── Mérő ─────────────────────────────
+
+// Gauge tetszőlegesen változó érték, címkénként
+type Gauge struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+	fn     func() float64 // GaugeFunc esetén a lekérdezéskor számolt érték
+}
+
+// NewGauge mérőt regisztrál
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{desc: desc{fqName: name, help: help, kind: "gauge", labels: labels}, values: make(map[string]float64)}
+	if len(labels) == 0 {
+		g.values[""] = 0
+	}
+	Default.register(g)
+	return g
+}
+
+// NewGaugeFunc címke nélküli mérő, amelynek értékét fn adja a kiíráskor
+func NewGaugeFunc(name, help string, fn func() float64) *Gauge {
+	g := &Gauge{desc: desc{fqName: name, help: help, kind: "gauge"}, fn: fn}
+	Default.register(g)
+	return g
+}
+
+// Set beállítja az értéket
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	g.values[key] = v
+	g.mu.Unlock()
+}
+
+// Add v-vel módosítja az értéket
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	g.values[key] += v
+	g.mu.Unlock()
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.header(w)
+	if g.fn != nil {
+		fmt.Fprintf(w, "%s %s\n", g.fqName, formatFloat(g.fn()))
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %s\n", g.fqName, g.labelText(key), formatFloat(g.values[key]))
+	}
+}
+
+// ───────────────────────────── Hisztogram ─────────────────────────────
+
+// Histogram értékek eloszlása (pl. időtartamok), címkénként
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histSeries
+}
+
+type histSeries struct {
+	counts []uint64 // határonként (nem kumulatív)
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram hisztogramot regisztrál; üres buckets esetén DefBuckets
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{desc: desc{fqName: name, help: help, kind: "histogram", labels: labels}, buckets: buckets, series: make(map[string]*histSeries)}
+	Default.register(h)
+	return h
+}
+
+// Observe egy mért érték
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.series[key]
+	if s == nil {
+		s = &histSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// ObserveSince a start óta eltelt időt (másodpercben) rögzíti
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.fqName, h.labelText(key, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.fqName, h.labelText(key, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.fqName, h.labelText(key), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.fqName, h.labelText(key), s.count)
+	}
+}
+
+// ───────────────────────────── Segédek ─────────────────────────────
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "a testdata fájlok újraírása")
+
+// A kiírás a Prometheus szöveges formátuma: név szerinti sorrend, kumulatív
+// (a határt is tartalmazó) vödrök +Inf-fel, escape-elt címkék és súgó
+func TestWriteTextGolden(t *testing.T) {
+	saved := Default
+	Default = NewRegistry()
+	t.Cleanup(func() { Default = saved })
+
+	msgs := NewCounter("ynm_messages_total", "Feldolgozott üzenetek.\nTöbbsoros \\ súgó.", "network", "channel")
+	msgs.Inc("libera", "#ynm")
+	msgs.Add(2.5, "libera", "#ynm")
+	msgs.Inc("libera", `#"idéző"\jel`)
+	msgs.Inc("efnet", "#sor\ntörés")
+	msgs.Add(-1, "efnet", "#sor\ntörés") // negatív érték nem számít
+
+	NewCounter("ynm_reconnects_total", "Újrakapcsolódások.")
+
+	users := NewGauge("ynm_channel_users", "Felhasználók csatornánként.", "channel")
+	users.Set(12, "#ynm")
+	users.Add(-2, "#ynm")
+	users.Set(math.Inf(1), "#vegtelen")
+	NewGaugeFunc("ynm_goroutines", "Goroutine-ok.", func() float64 { return 7 })
+
+	lat := NewHistogram("ynm_command_seconds", "Parancsok futásideje.", []float64{1, 0.1, 0.5}, "command")
+	for _, v := range []float64{0.05, 0.1, 0.3, 0.5, 0.7, 2} {
+		lat.Observe(v, "help")
+	}
+	lat.Observe(0.25, `a"b`)
+
+	var buf bytes.Buffer
+	Default.WriteText(&buf)
+
+	golden := filepath.Join("testdata", "exposition.prom")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("eltérő kiírás (go test ./metrics -update után a diffet érdemes átnézni):\n%s\nvárt:\n%s", buf.Bytes(), want)
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+var startTime = float64(time.Now().Unix())
+
+var (
+	_ = NewGaugeFunc("go_goroutines", "A futó goroutine-ok száma.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	_ = NewGaugeFunc("go_memstats_alloc_bytes", "A heapen lefoglalt, használatban lévő bájtok.", func() float64 {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		return float64(m.Alloc)
+	})
+	_ = NewGaugeFunc("process_start_time_seconds", "A folyamat indulási ideje (Unix másodperc).", func() float64 {
+		return startTime
+	})
+)
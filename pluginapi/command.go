@@ -0,0 +1,26 @@
+package pluginapi
+
+import "github.com/ynmhu/YnM-Go/irc"
+
+// CommandHandler egy routerben regisztrált parancs kezelője; args a parancsnév utáni szavak.
+type CommandHandler func(msg irc.Message, args []string) string
+
+// Registrar – ezen keresztül regisztrálnak parancsot a pluginok a közös routerbe.
+type Registrar interface {
+	RegisterCommand(owner, name, help string, handler CommandHandler) error
+	UnregisterCommands(owner string)
+}
+
+// Sender – üzenetküldés a botnak (irc.Sender); az *irc.Client megvalósítja.
+type Sender = irc.Sender
+
+// EventHandler – a nem PRIVMSG eseményeket (JOIN, PART, ...) fogadó pluginok.
+type EventHandler interface {
+	HandleEvent(ev irc.Event)
+}
+
+// MessageObserver – minden bejövő PRIVMSG-t megkapó pluginok (pl. seen), attól
+// függetlenül, hogy végül melyik plugin vagy parancs válaszol rá.
+type MessageObserver interface {
+	ObserveMessage(msg irc.Message)
+}
@@ -0,0 +1,100 @@
+package pluginapi
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/scheduler"
+	"github.com/ynmhu/YnM-Go/settings"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// Context a pluginok által elérhető közös szolgáltatások.
+// A beállításokat rétegzetten kérdezhetik le, pl. ctx.Setting("#Magyar", "joke.time");
+// a saját adataikat a Storage repository-jaiban tárolják (pl. ctx.Storage.Movies).
+type Context struct {
+	*settings.Store
+	Scheduler *scheduler.Scheduler
+	Storage   *storage.DB
+
+	// feladatnév vagy előtag → config felülírás (missed, jitter); config újratöltéskor cserélődik
+	policyMu    sync.RWMutex
+	jobPolicies map[string]config.JobPolicyConfig
+}
+
+func NewContext(store *settings.Store, sched *scheduler.Scheduler, db *storage.DB, policies map[string]config.JobPolicyConfig) *Context {
+	return &Context{Store: store, Scheduler: sched, Storage: db, jobPolicies: policies}
+}
+
+// Now a pillanatnyi idő az ütemező órája szerint; az időzítést számoló pluginok
+// ezt használják a time.Now helyett, hogy a tesztekben az óra léptethető legyen
+func (c *Context) Now() time.Time {
+	return c.Scheduler.Now()
+}
+
+// SetJobPolicies lecseréli a feladatonkénti felülírásokat (config újratöltéskor)
+func (c *Context) SetJobPolicies(policies map[string]config.JobPolicyConfig) {
+	c.policyMu.Lock()
+	c.jobPolicies = policies
+	c.policyMu.Unlock()
+}
+
+// Schedule felveszi a feladatot az ütemezőbe. A config scheduler.jobs részében
+// a teljes névre vagy a ":" előtti előtagra adott missed/jitter felülírja a
+// plugin alapértelmezését.
+func (c *Context) Schedule(job scheduler.Job) error {
+	c.policyMu.RLock()
+	policy, ok := c.jobPolicies[job.Name]
+	if !ok {
+		prefix, _, _ := strings.Cut(job.Name, ":")
+		policy, ok = c.jobPolicies[prefix]
+	}
+	c.policyMu.RUnlock()
+	if ok {
+		if policy.Missed != "" {
+			if missed, err := scheduler.ParseMissedPolicy(policy.Missed); err == nil {
+				job.Missed = missed
+			}
+		}
+		if policy.Jitter > 0 {
+			job.Jitter = policy.Jitter.Std()
+		}
+	}
+	return c.Scheduler.Add(job)
+}
+
+// ChannelJobs csatornánként egy "prefix:#csatorna" feladatot tart fenn minden
+// csatornán, ahol az enabledKey igaz, a specKey időzítésével. A beállítások
+// változásakor (!set) újrahangolja őket. A visszaadott függvény leállítja mindet.
+func (c *Context) ChannelJobs(prefix, enabledKey, specKey string, template scheduler.Job, run func(channel string) error) (cancel func()) {
+	sync := func() {
+		wanted := make(map[string]bool)
+		for _, ch := range c.Channels(enabledKey) {
+			channel := ch
+			job := template
+			job.Name = prefix + ":" + strings.ToLower(channel)
+			job.Spec = c.SettingSchedule(channel, specKey)
+			job.Run = func() error { return run(channel) }
+			if err := c.Schedule(job); err != nil {
+				log.Printf("❌ Ütemezési hiba: %v", err)
+				continue
+			}
+			wanted[job.Name] = true
+		}
+		for _, name := range c.Scheduler.Names(prefix + ":") {
+			if !wanted[name] {
+				c.Scheduler.Remove(name)
+			}
+		}
+	}
+
+	sync()
+	unsubscribe := c.OnChange(sync)
+	return func() {
+		unsubscribe()
+		c.Scheduler.RemovePrefix(prefix + ":")
+	}
+}
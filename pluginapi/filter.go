@@ -0,0 +1,29 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package pluginapi a pluginok és a pluginkezelő közös típusait tartalmazza.
+package pluginapi
+
+// ChannelFilter megmondja, hogy egy plugin küldhet-e az adott csatornára.
+// A nil szűrő mindent enged.
+type ChannelFilter func(channel string) bool
+
+// Allows true, ha a csatornára szabad küldeni.
+func (f ChannelFilter) Allows(channel string) bool {
+	return f == nil || f(channel)
+}
+
+// ChannelFilterable – az időzítetten, saját goroutine-ból küldő pluginok
+// ezen keresztül kapják meg a csatornánkénti engedélyezési szűrőt.
+type ChannelFilterable interface {
+	SetChannelFilter(f ChannelFilter)
+}
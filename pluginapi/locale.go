@@ -0,0 +1,44 @@
+package pluginapi
+
+import (
+	"log"
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+// Localizer a válaszok nyelve; a *Context megvalósítja. A pluginok minden
+// felhasználónak szóló szöveget ezen át, az i18n katalógusból állítanak elő.
+type Localizer interface {
+	// Locale a csatorna nyelve (language beállítás), pl. az időzített bejelentésekhez
+	Locale(channel string) *i18n.Locale
+	// LocaleFor a parancsra adott válasz nyelve: a küldő saját nyelve, különben a csatornáé
+	LocaleFor(msg irc.Message) *i18n.Locale
+	// UserLocale egy adott felhasználónak szóló üzenet nyelve (pl. privát értesítés)
+	UserLocale(nick, account, channel string) *i18n.Locale
+}
+
+func (c *Context) Locale(channel string) *i18n.Locale {
+	return i18n.Get(c.Setting(channel, "language"))
+}
+
+func (c *Context) LocaleFor(msg irc.Message) *i18n.Locale {
+	nick := msg.Nick
+	if nick == "" {
+		nick = strings.SplitN(msg.Sender, "!", 2)[0]
+	}
+	return c.UserLocale(nick, msg.Account, msg.Channel)
+}
+
+func (c *Context) UserLocale(nick, account, channel string) *i18n.Locale {
+	if c.Storage != nil {
+		lang, err := c.Storage.Languages.Get(nick, account)
+		if err != nil {
+			log.Printf("❌ Nyelv lekérdezési hiba: %v", err)
+		} else if lang != "" && i18n.Supported(lang) {
+			return i18n.Get(lang)
+		}
+	}
+	return c.Locale(channel)
+}
@@ -0,0 +1,55 @@
+package pluginapi
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/settings"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// A language beállítás rétegei (config, !set) és a felhasználó saját nyelve (!lang)
+func TestLocale(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Settings: config.SettingsConfig{
+		Channels: map[string]map[string]string{"#English": {"language": "en"}},
+	}}
+	store := settings.NewStore(cfg, filepath.Join(dir, "settings.json"))
+	if err := store.Set("#romana", "language", "ro"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("#romana", "language", "xx"); err == nil {
+		t.Error("ismeretlen nyelv beállítható")
+	}
+	db, err := storage.Open(filepath.Join(dir, "ynm.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Languages.Set("bob", "", "en", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(store, nil, db, nil)
+
+	tests := []struct {
+		nick, channel string
+		want          string
+	}{
+		{"alice", "#magyar", "hu"},
+		{"alice", "#english", "en"},
+		{"alice", "#Romana", "ro"},
+		{"bob", "#romana", "en"},
+	}
+	for _, tt := range tests {
+		msg := irc.Message{Sender: tt.nick + "!u@h", Nick: tt.nick, Channel: tt.channel}
+		if got := ctx.LocaleFor(msg).Lang(); got != tt.want {
+			t.Errorf("%s a %s csatornán: %s, várt %s", tt.nick, tt.channel, got, tt.want)
+		}
+	}
+	if got := ctx.Locale("#romana").Lang(); got != "ro" {
+		t.Errorf("csatorna nyelve: %s, várt ro", got)
+	}
+}
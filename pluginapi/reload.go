@@ -0,0 +1,19 @@
+package pluginapi
+
+import "github.com/ynmhu/YnM-Go/config"
+
+// ReloadEvent a config újratöltése (!rehash): a régi és az új config, valamint
+// a megváltozott kulcsok. Az új config már érvényes, amikor a pluginok megkapják.
+// Mindkét példány csak olvasható; a configot eltároló plugin az New mutatót
+// tegye el (szinkronizáltan, pl. atomic.Pointer), ne a régi tartalmát írja felül.
+type ReloadEvent struct {
+	Old     *config.Config
+	New     *config.Config
+	Changes config.Changes
+}
+
+// ConfigReloader az újratöltésre feliratkozó plugin: élőben alkalmazza, amit tud
+// (időzítés, útvonal). Ha hibát ad vissza, a manager újraindítja a plugint.
+type ConfigReloader interface {
+	ReloadConfig(ev ReloadEvent) error
+}
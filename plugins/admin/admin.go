@@ -13,17 +13,22 @@
 package admin
 
 import (
+	"log"
 	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-	"log"
 
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/sdnotify"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
 // Admin levels using numeric values
@@ -36,48 +41,48 @@ const (
 
 // AdminPlugin handles administrative commands
 type AdminPlugin struct {
-	bot              *irc.Client
-	cfg              *config.Config
+	bot              irc.Sender
+	cfg              atomic.Pointer[config.Config] // ReloadConfig cseréli
 	mu               sync.RWMutex
-	userBanUntil     map[string]time.Time
-	userRequestTimes map[string][]time.Time
-	userBanNotified  map[string]bool
 	store            *MultiAdminStore
 	currentUsers     map[string]string 
 	hasInitialOwner bool
+	tr               pluginapi.Localizer // a válaszok nyelve
+
+	// OnRehash a config újratöltését végzi (az app állítja be), a válasz a változások listája
+	OnRehash func(loc *i18n.Locale) string
+	// OnRestart socket átadással indítja újra a botot (az app állítja be); siker
+	// esetén nem tér vissza, hiba esetén normál újraindítás következik
+	OnRestart func() error
+	// OnShutdown a normál leállítás (az app állítja be): a socket átadás nélküli
+	// újraindítás előtt lezárja a pluginokat, az adatbázist és a kapcsolatot
+	OnShutdown func()
+	// OnBackup elkészíti az adatok mentését (az app állítja be), a válasz a mentés neve
+	OnBackup func(loc *i18n.Locale) (string, error)
 }
 
-func NewAdminPlugin(cfg *config.Config) *AdminPlugin {
-	return &AdminPlugin{
-		cfg:              cfg,
-		userBanUntil:     make(map[string]time.Time),
-		userRequestTimes: make(map[string][]time.Time),
-		userBanNotified:  make(map[string]bool),
+func NewAdminPlugin(cfg *config.Config, admins *storage.AdminRepo, tr pluginapi.Localizer) *AdminPlugin {
+	p := &AdminPlugin{
+		store:            NewMultiAdminStore(admins),
 		currentUsers:     make(map[string]string),
+		tr:               tr,
 	}
+	p.cfg.Store(cfg)
+	return p
 }
 
-func (p *AdminPlugin) Initialize(bot *irc.Client) {
-    p.bot = bot
-	
-	// Create data directory if it doesn't exist
-	if _, err := os.Stat("data"); os.IsNotExist(err) {
-		if err := os.Mkdir("data", 0755); err != nil {
-			fmt.Printf("Error creating data directory: %v\n", err)
-		}
+// ReloadConfig átveszi az új configot, és a configból felvett adminokat az új listához igazítja
+func (p *AdminPlugin) ReloadConfig(ev pluginapi.ReloadEvent) error {
+	p.cfg.Store(ev.New)
+	if ev.Changes.Has("admins") {
+		p.SetConfigAdmins(ev.New.Admins)
 	}
-	
-	// Initialize the admin store
-    p.store = NewMultiAdminStore()
-    if err := p.store.Load(); err != nil {
-        fmt.Printf("Error loading admin store: %v\n", err)
-    }
-
-    p.hasInitialOwner = p.store.HasOwner()
+	return nil
 }
 
-func init() {
-    log.Println("Bot starting up...")
+func (p *AdminPlugin) Initialize(bot irc.Sender) {
+    p.bot = bot
+    p.hasInitialOwner = p.store.HasOwner()
 }
 
 func (p *AdminPlugin) GetAdminLevel(nick, hostmask string) int {
@@ -104,6 +109,7 @@ func (p *AdminPlugin) HandleMessage(msg irc.Message) string {
 	cmd := parts[0]
 	fullHostmask := msg.Sender 
 	nick := strings.Split(fullHostmask, "!")[0]
+	loc := p.tr.LocaleFor(msg)
 
 	// Handle !hello command (first-time setup)
 	if cmd == "!hello" {
@@ -121,9 +127,9 @@ func (p *AdminPlugin) HandleMessage(msg irc.Message) string {
 			AddedAt:  time.Now(),
 		}
 		if err := p.store.AddAdmin(info); err != nil {
-			return "Error saving admin data"
+			return loc.T("admin.save_error")
 		}
-		return fmt.Sprintf("%s registered as bot owner (level 3)", nick)
+		return loc.T("admin.hello", nick)
 	}
 
 	// Check admin status
@@ -136,79 +142,66 @@ func (p *AdminPlugin) HandleMessage(msg irc.Message) string {
 	switch cmd {
 	case "!die":
 		if adminLevel >= AdminLevelOwner {
-			p.bot.SendMessage(p.cfg.ConsoleChannel, "Shutting down by admin command...")
+			console := p.cfg.Load().ConsoleChannel
+			p.bot.SendMessage(console, p.tr.Locale(console).T("admin.die_console", nick))
 			go func() {
 				time.Sleep(1 * time.Second)
 				os.Exit(0)
 			}()
-			return "Shutting down..."
+			return loc.T("admin.die")
 		}
-		return "Insufficient privileges (requires level 3)"
+		return loc.T("admin.no_privileges", AdminLevelOwner)
 		
 	case "!restart":
 		if adminLevel >= AdminLevelAdmin {
-			p.bot.SendMessage(p.cfg.ConsoleChannel, "Restarting...")
-			go p.restartBot()
-			return "Restarting..."
+			console := p.cfg.Load().ConsoleChannel
+			p.bot.SendMessage(console, p.tr.Locale(console).T("admin.restarting"))
+			// "!restart cold": socket átadás nélkül, újracsatlakozással
+			go p.restartBot(len(parts) > 1 && strings.EqualFold(parts[1], "cold"))
+			return loc.T("admin.restarting")
 		}
-		return "Insufficient privileges (requires level 2)"
+		return loc.T("admin.no_privileges", AdminLevelAdmin)
 		
-	case "!rehash":
-		if adminLevel >= AdminLevelAdmin {
-			newCfg, err := config.Load("config/config.yaml")
-			if err != nil {
-				return fmt.Sprintf("Config reload error: %v", err)
-			}
-
-			oldChannels := make(map[string]struct{})
-			for _, ch := range p.cfg.Channels {
-				oldChannels[ch] = struct{}{}
-			}
-
-			newChannels := make(map[string]struct{})
-			for _, ch := range newCfg.Channels {
-				newChannels[ch] = struct{}{}
+	case "!backup":
+		if adminLevel >= AdminLevelOwner {
+			if p.OnBackup == nil {
+				return loc.T("admin.backup_unavailable")
 			}
-
-			// Kilépés azokról a csatornákról, amik törlődtek
-			for ch := range oldChannels {
-				if _, ok := newChannels[ch]; !ok {
-					p.bot.SendRaw("PART " + ch)
-					// vagy p.bot.Part(ch), ha van ilyen metódusod (ha nincs, implementáld)
-				}
+			reply, err := p.OnBackup(loc)
+			if err != nil {
+				return loc.T("admin.backup_failed", err)
 			}
+			return reply
+		}
+		return loc.T("admin.no_privileges", AdminLevelOwner)
 
-			// Belépés az új csatornákba
-			for ch := range newChannels {
-				if _, ok := oldChannels[ch]; !ok {
-					p.bot.Join(ch)
-				}
+	case "!rehash":
+		if adminLevel >= AdminLevelAdmin {
+			if p.OnRehash == nil {
+				return loc.T("admin.rehash_unavailable")
 			}
-
-			p.cfg = newCfg
-
-			return "Configuration reloaded, channels updated"
+			return p.OnRehash(loc)
 		}
-		return "Insufficient privileges (requires level 2)"
+		return loc.T("admin.no_privileges", AdminLevelAdmin)
 
 		
 	case "!addadmin":
 		if adminLevel >= AdminLevelAdmin && len(parts) >= 2 {
-			return p.handleAddAdmin(parts[1:], nick, adminLevel)
+			return p.handleAddAdmin(loc, parts[1:], nick, adminLevel)
 		}
-		return "Usage: !addadmin <nick> [level] [hostmask] (requires level 2)"
+		return loc.T("admin.usage_addadmin")
 		
 	case "!deladmin":
 		if adminLevel >= AdminLevelAdmin && len(parts) >= 2 {
-			return p.handleDelAdmin(parts[1], nick, adminLevel)
+			return p.handleDelAdmin(loc, parts[1], nick, adminLevel)
 		}
-		return "Usage: !deladmin <nick> (requires level 2)"
+		return loc.T("admin.usage_deladmin")
 		
 	case "!listadmins":
 		if adminLevel >= AdminLevelVIP {
-			return p.handleListAdmins()
+			return p.handleListAdmins(loc)
 		}
-		return "Insufficient privileges (requires level 1)"
+		return loc.T("admin.no_privileges", AdminLevelVIP)
 		
 	case "!admininfo":
 		if adminLevel >= AdminLevelVIP {
@@ -216,21 +209,20 @@ func (p *AdminPlugin) HandleMessage(msg irc.Message) string {
 			if len(parts) >= 2 {
 				target = parts[1]
 			}
-			return p.handleAdminInfo(target)
+			return p.handleAdminInfo(loc, target)
 		}
-		return "Insufficient privileges (requires level 1)"
+		return loc.T("admin.no_privileges", AdminLevelVIP)
 		
 	case "!help":
-		return p.handleHelp(adminLevel)
+		return p.handleHelp(loc, adminLevel)
 		
 	case "!whoami":
 		// Debug command to show user's admin status
 		if adminLevel > AdminLevelNone {
 			levelStr := p.getLevelString(adminLevel)
-			return fmt.Sprintf("You are %s (%s - level %d). Hostmask: %s", 
-				nick, levelStr, adminLevel, fullHostmask)
+			return loc.T("admin.whoami", nick, levelStr, adminLevel, fullHostmask)
 		}
-		return fmt.Sprintf("You are %s with no admin privileges (level 0). Hostmask: %s", nick, fullHostmask)
+		return loc.T("admin.whoami_none", nick, fullHostmask)
 	}
 
 	return ""
@@ -239,21 +231,12 @@ func (p *AdminPlugin) HandleMessage(msg irc.Message) string {
 
 
 func (p *AdminPlugin) getLevelString(level int) string {
-	switch level {
-	case AdminLevelVIP:
-		return "VIP"
-	case AdminLevelAdmin:
-		return "Admin"
-	case AdminLevelOwner:
-		return "Owner"
-	default:
-		return "Unknown"
-	}
+	return LevelName(level)
 }
 
-func (p *AdminPlugin) handleAddAdmin(args []string, requester string, requesterLevel int) string {
+func (p *AdminPlugin) handleAddAdmin(loc *i18n.Locale, args []string, requester string, requesterLevel int) string {
 	if len(args) < 1 {
-		return "Usage: !addadmin <nick> [level] [hostmask] - Levels: 1=VIP, 2=Admin, 3=Owner"
+		return loc.T("admin.usage_addadmin")
 	}
 	
 	nick := args[0]
@@ -273,7 +256,7 @@ func (p *AdminPlugin) handleAddAdmin(args []string, requester string, requesterL
 			case "owner", "3":
 				level = AdminLevelOwner
 			default:
-				return "Invalid level. Use: 1=VIP, 2=Admin, 3=Owner"
+				return loc.T("admin.invalid_level")
 			}
 		} else {
 			switch parsedLevel {
@@ -284,23 +267,23 @@ func (p *AdminPlugin) handleAddAdmin(args []string, requester string, requesterL
 			case 3:
 				level = AdminLevelOwner
 			default:
-				return "Invalid level. Use: 1=VIP, 2=Admin, 3=Owner"
+				return loc.T("admin.invalid_level")
 			}
 		}
 	}
 	
 	// Check permissions for adding users at this level
 	if requesterLevel == AdminLevelAdmin && level > AdminLevelVIP {
-		return "As an admin, you can only add VIPs (level 1)"
+		return loc.T("admin.add_vip_only")
 	}
 	
 	if requesterLevel < AdminLevelOwner && level >= AdminLevelAdmin {
-		return "Only owners can add admins (level 2) or other owners (level 3)"
+		return loc.T("admin.add_owner_only")
 	}
 	
 	// Prevent adding users with equal or higher level (except owners adding owners)
 	if level >= requesterLevel && !(requesterLevel == AdminLevelOwner && level == AdminLevelOwner) {
-		return "You cannot add users with equal or higher privileges"
+		return loc.T("admin.add_higher")
 	}
 	
 	// Parse hostmask if provided
@@ -313,7 +296,7 @@ func (p *AdminPlugin) handleAddAdmin(args []string, requester string, requesterL
 			} else {
 				// Ha nincs hostmask, inkább jelezd, hogy adják meg explicit módon
 				p.mu.RUnlock()
-				return "Hostmask missing. ex !addadmin YnM vip *!*@YnM.ynm.hu."
+				return loc.T("admin.hostmask_missing")
 			}
 			p.mu.RUnlock()
 		}
@@ -327,45 +310,39 @@ func (p *AdminPlugin) handleAddAdmin(args []string, requester string, requesterL
 		AddedAt:  time.Now(),
 	}
 	if err := p.store.AddAdmin(info); err != nil {
-		return "Error saving admin data"
-	}
-	if err := p.store.Save(); err != nil {
-		return "Error saving admin data"
+		return loc.T("admin.save_error")
 	}
 	
 	levelStr := p.getLevelString(level)
-	return fmt.Sprintf("Added %s as %s (level %d)", nick, levelStr, level)
+	return loc.T("admin.added", nick, levelStr, level)
 }
 
-func (p *AdminPlugin) handleDelAdmin(nick, requester string, requesterLevel int) string {
+func (p *AdminPlugin) handleDelAdmin(loc *i18n.Locale, nick, requester string, requesterLevel int) string {
 	info, exists := p.store.GetAdmin(nick)
 	if !exists {
-		return "User is not an admin"
+		return loc.T("admin.not_admin", nick)
 	}
 	
 	// Check if requester can remove this admin
 	if info.Level >= requesterLevel {
-		return "You cannot remove admins with equal or higher privileges"
+		return loc.T("admin.del_higher")
 	}
 	
 	if nick == requester {
-		return "You cannot remove yourself"
+		return loc.T("admin.del_self")
 	}
 	
 	if p.store.RemoveAdmin(nick) {
-		if err := p.store.Save(); err != nil {
-			return "Error saving admin data"
-		}
-		return fmt.Sprintf("Removed %s from admin list", nick)
+		return loc.T("admin.removed", nick)
 	}
 	
-	return "Failed to remove admin"
+	return loc.T("admin.remove_failed")
 }
 
-func (p *AdminPlugin) handleListAdmins() string {
+func (p *AdminPlugin) handleListAdmins(loc *i18n.Locale) string {
 	admins := p.store.ListAll()
 	if len(admins) == 0 {
-		return "No admins configured"
+		return loc.T("admin.list_empty")
 	}
 	
 	var result []string
@@ -375,23 +352,22 @@ func (p *AdminPlugin) handleListAdmins() string {
 		result = append(result, fmt.Sprintf("%s (%s-%d)", admin.Nick, levelStr, admin.Level))
 	}
 	
-	return "Admins: " + strings.Join(result, ", ")
+	return loc.T("admin.list", strings.Join(result, ", "))
 }
 
-func (p *AdminPlugin) handleAdminInfo(nick string) string {
+func (p *AdminPlugin) handleAdminInfo(loc *i18n.Locale, nick string) string {
 	info, exists := p.store.GetAdmin(nick)
 	if !exists {
-		return fmt.Sprintf("%s is not an admin", nick)
+		return loc.T("admin.not_admin", nick)
 	}
 	
 	levelStr := p.getLevelString(info.Level)
 	
-	return fmt.Sprintf("%s: %s (level %d), added by %s on %s, hostmask: %s",
-		info.Nick, levelStr, info.Level, info.AddedBy,
-		info.AddedAt.Format("2006-01-02 15:04:05"), info.Hostmask)
+	return loc.T("admin.info", info.Nick, levelStr, info.Level, info.AddedBy,
+		loc.DateTime(info.AddedAt), info.Hostmask)
 }
 
-func (p *AdminPlugin) handleHelp(adminLevel int) string {
+func (p *AdminPlugin) handleHelp(loc *i18n.Locale, adminLevel int) string {
 	var commands []string
 	
 	if adminLevel >= AdminLevelVIP {
@@ -399,43 +375,68 @@ func (p *AdminPlugin) handleHelp(adminLevel int) string {
 	}
 	
 	if adminLevel >= AdminLevelAdmin {
-		commands = append(commands, "!addadmin", "!deladmin", "!rehash", "!restart")
+		commands = append(commands, "!addadmin", "!deladmin", "!rehash", "!restart", "!plugin", "!set", "!script", "!schedule", "!limits", "!unban", "!banlist", "!ignore", "!loglevel")
 	}
 	
 	if adminLevel >= AdminLevelOwner {
-		commands = append(commands, "!die")
+		commands = append(commands, "!backup", "!die")
 	}
 	
 	if len(commands) == 0 {
-		return "No admin commands available"
+		return loc.T("admin.help_empty")
 	}
 	
-	helpText := "Available admin commands: " + strings.Join(commands, ", ")
-	helpText += fmt.Sprintf(" | Your level: %d (%s)", adminLevel, p.getLevelString(adminLevel))
+	helpText := loc.T("admin.help", strings.Join(commands, ", "))
+	helpText += " | " + loc.T("admin.help_level", adminLevel, p.getLevelString(adminLevel))
 	
 	// Add hierarchy information based on user level
 	if adminLevel == AdminLevelAdmin {
-		helpText += " | You can add: VIPs (level 1)"
+		helpText += " | " + loc.T("admin.help_can_add_vip")
 	} else if adminLevel == AdminLevelOwner {
-		helpText += " | You can add: VIPs (level 1), Admins (level 2), Owners (level 3)"
+		helpText += " | " + loc.T("admin.help_can_add_all")
 	}
 	
-	helpText += " | Levels: 1=VIP, 2=Admin, 3=Owner"
+	helpText += " | " + loc.T("admin.levels")
 	
 	return helpText
 }
 
-func (p *AdminPlugin) restartBot() {
+func (p *AdminPlugin) restartBot(cold bool) {
+	// Az IRC kapcsolat átadása az új folyamatnak (TLS esetén nem lehetséges)
+	if p.OnRestart != nil && !cold {
+		// a parancs válasza még a küldési sorba kerüljön (azt is átadjuk)
+		time.Sleep(500 * time.Millisecond)
+		err := p.OnRestart()
+		log.Printf("ℹ️ Újraindítás socket átadás nélkül: %v", err)
+	}
+
+	// systemd alatt, ha a config kéri, az újraindítást a systemd végzi
+	// (RestartForceExitStatus=75), így a szolgáltatás állapota és a watchdog is követi
+	viaSystemd := p.cfg.Load().SystemdRestart && sdnotify.UnderSystemd()
+
+	channel := p.cfg.Load().ConsoleChannel
+	console := p.tr.Locale(channel)
+	executable, err := os.Executable()
+	if err != nil && !viaSystemd {
+		p.bot.SendMessage(channel, console.T("admin.restart_failed", err))
+		return
+	}
+
 	// Send quit message to IRC
 	p.bot.SendRaw("QUIT :Restarting...")
 	
 	// Give time for message to be sent
 	time.Sleep(1 * time.Second)
-	
-	executable, err := os.Executable()
-	if err != nil {
-		p.bot.SendMessage(p.cfg.ConsoleChannel, "Restart error: "+err.Error())
-		return
+
+	// az új folyamat indulása előtt a régi rendben leáll (pluginok, adatbázis, naplók)
+	sdnotify.Stopping("Újraindítás (!restart)...")
+	if p.OnShutdown != nil {
+		p.OnShutdown()
+	}
+
+	if viaSystemd {
+		log.Printf("🔄 Újraindítás a systemd-n keresztül (kilépési kód: %d)", sdnotify.RestartExitCode)
+		os.Exit(sdnotify.RestartExitCode)
 	}
 
 	cmd := exec.Command(executable, os.Args[1:]...)
@@ -444,8 +445,9 @@ func (p *AdminPlugin) restartBot() {
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Start(); err != nil {
-		p.bot.SendMessage(p.cfg.ConsoleChannel, "Restart failed: "+err.Error())
-		return
+		// a kapcsolat már bontva: a hibát csak a napló őrzi, a felügyelő indíthat újra
+		log.Printf("❌ Az új folyamat nem indult el: %v", err)
+		os.Exit(1)
 	}
 
 	os.Exit(0)
@@ -472,20 +474,43 @@ func (p *AdminPlugin) AddAdmin(nick string) {
 
 	hostmask := "*!*@*"
 	if fullHostmask, ok := p.currentUsers[nick]; ok {
-		fmt.Println("DEBUG: fullHostmask before simplify:", fullHostmask)
+		log.Println("DEBUG fullHostmask before simplify:", fullHostmask)
 		hostmask = simplifyHostmask(fullHostmask)
-		fmt.Println("DEBUG: hostmask after simplify:", hostmask)
+		log.Println("DEBUG hostmask after simplify:", hostmask)
 	}
 
 	info := AdminInfo{
 		Nick:     nick,
 		Hostmask: hostmask,
 		Level:    AdminLevelOwner,
-		AddedBy:  "system",
+		AddedBy:  configAdminSource,
 		AddedAt:  time.Now(),
 	}
 
 	_ = p.store.AddAdmin(info)
-	_ = p.store.Save()
+}
+
+// configAdminSource a config admins listájából felvett bejegyzések AddedBy értéke
+const configAdminSource = "config"
+
+// SetConfigAdmins a configból felvett adminokat a nicks listára cseréli: a
+// listából kikerült nickek törlődnek, az újak bekerülnek. A !hello, az
+// !addadmin és a CLI által felvett bejegyzéseket nem érinti.
+func (p *AdminPlugin) SetConfigAdmins(nicks []string) {
+	wanted := make(map[string]bool)
+	for _, nick := range nicks {
+		wanted[strings.ToLower(nick)] = true
+	}
+	for _, info := range p.store.ListAll() {
+		if info.AddedBy == configAdminSource && !wanted[strings.ToLower(info.Nick)] {
+			p.store.RemoveAdmin(info.Nick)
+			log.Printf("ℹ️ %s admin törölve (kikerült a configból)", info.Nick)
+		}
+	}
+	for _, nick := range nicks {
+		if _, ok := p.store.GetAdmin(nick); !ok {
+			p.AddAdmin(nick)
+		}
+	}
 }
 
@@ -1,169 +1,101 @@
 // ============================================================================
-//  YnM‑Go – több­fájlos admin tároló (Owner / Admin / VIP)
+//  YnM‑Go – admin tároló (Owner / Admin / VIP)
 // ============================================================================
 
 package admin
 
 import (
-    "encoding/json"
     "fmt"
-    "os"
-    "path/filepath"
+    "log"
     "strings"
-    "sync"
-    "time"
+
+    "github.com/ynmhu/YnM-Go/storage"
 )
 
 // ────────────────────── Típusok ──────────────────────────────
 
-type AdminInfo struct {
-	Nick     string    `json:"nick"`
-	Hostmask string    `json:"hostmask"`
-	Level    int       `json:"level"`
-	AddedBy  string    `json:"added_by"`
-	AddedAt  time.Time `json:"added_at"`
-}
-
-// egyszerű fájl‑alapú store (egy szint / fájl)
-type fileStore struct {
-	mu       sync.RWMutex
-	filePath string
-	Admins   map[string]AdminInfo // key = nick
-}
-
-func newFileStore(path string) *fileStore {
-	return &fileStore{
-		filePath: path,
-		Admins:   make(map[string]AdminInfo),
-	}
-}
-
-func (f *fileStore) load() error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	if err := os.MkdirAll(filepath.Dir(f.filePath), 0755); err != nil {
-		return err
-	}
-	data, err := os.ReadFile(f.filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // első futás
-		}
-		return err
-	}
-	return json.Unmarshal(data, &f.Admins)
-}
-
-func (f *fileStore) save() error {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	data, err := json.MarshalIndent(f.Admins, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(f.filePath, data, 0600)
-}
+// AdminInfo egy owner/admin/VIP bejegyzés
+type AdminInfo = storage.Admin
 
 // ───────────────── Multi‑store (Owner / Admin / VIP) ─────────
 
+// MultiAdminStore a szintek a közös adatbázisban (storage.AdminRepo). Minden
+// hívás az adatbázist olvassa, így a CLI módosítása a futó botnál is azonnal él.
 type MultiAdminStore struct {
-	owners *fileStore
-	admins *fileStore
-	vips   *fileStore
+	repo *storage.AdminRepo
 }
 
-func NewMultiAdminStore() *MultiAdminStore {
-	return &MultiAdminStore{
-		owners: newFileStore("data/owners.json"),
-		admins: newFileStore("data/admins.json"),
-		vips:   newFileStore("data/vips.json"),
-	}
+// NewMultiAdminStore az adatbázis admins tábláján dolgozik
+func NewMultiAdminStore(repo *storage.AdminRepo) *MultiAdminStore {
+	return &MultiAdminStore{repo: repo}
 }
 
-// betölt minden fájlt
-func (m *MultiAdminStore) Load() error {
-	if err := m.owners.load(); err != nil {
-		return err
-	}
-	if err := m.admins.load(); err != nil {
-		return err
+// ParseLevel a szint számként (1-3) vagy névként (vip, admin, owner)
+func ParseLevel(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "vip", "1":
+		return AdminLevelVIP, nil
+	case "admin", "2":
+		return AdminLevelAdmin, nil
+	case "owner", "3":
+		return AdminLevelOwner, nil
 	}
-	return m.vips.load()
+	return AdminLevelNone, fmt.Errorf("invalid level: %s", s)
 }
 
-// ment minden fájlt
-func (m *MultiAdminStore) Save() error {
-	if err := m.owners.save(); err != nil {
-		return err
-	}
-	if err := m.admins.save(); err != nil {
-		return err
+// LevelName a szint neve (VIP, Admin, Owner)
+func LevelName(level int) string {
+	switch level {
+	case AdminLevelVIP:
+		return "VIP"
+	case AdminLevelAdmin:
+		return "Admin"
+	case AdminLevelOwner:
+		return "Owner"
+	default:
+		return "Unknown"
 	}
-	return m.vips.save()
 }
 
 // van‑e már owner?
 func (m *MultiAdminStore) HasOwner() bool {
-	m.owners.mu.RLock()
-	defer m.owners.mu.RUnlock()
-	return len(m.owners.Admins) > 0
+	n, err := m.repo.Count(AdminLevelOwner)
+	if err != nil {
+		log.Printf("❌ Admin tároló hiba: %v", err)
+	}
+	return n > 0
 }
 
-
-// hozzáadás szint alapján
+// hozzáadás szint alapján (szintváltáskor a régi szint helyére)
 func (m *MultiAdminStore) AddAdmin(info AdminInfo) error {
 	switch info.Level {
 	case AdminLevelOwner:
 		if m.HasOwner() {
 			return fmt.Errorf("owner already exists")
 		}
-		m.owners.mu.Lock()
-		m.owners.Admins[info.Nick] = info
-		m.owners.mu.Unlock()
-	case AdminLevelAdmin:
-		m.admins.mu.Lock()
-		m.admins.Admins[info.Nick] = info
-		m.admins.mu.Unlock()
-	case AdminLevelVIP:
-		m.vips.mu.Lock()
-		m.vips.Admins[info.Nick] = info
-		m.vips.mu.Unlock()
+	case AdminLevelAdmin, AdminLevelVIP:
 	default:
 		return fmt.Errorf("invalid level")
 	}
-	return m.Save()
+	return m.repo.Put(info)
 }
 
 // törlés (nick alapján, bármely szint)
 func (m *MultiAdminStore) RemoveAdmin(nick string) bool {
-	removed := false
-	for _, fs := range []*fileStore{m.owners, m.admins, m.vips} {
-		fs.mu.Lock()
-		if _, ok := fs.Admins[nick]; ok {
-			delete(fs.Admins, nick)
-			removed = true
-		}
-		fs.mu.Unlock()
-	}
-	if removed {
-		_ = m.Save()
+	removed, err := m.repo.Delete(nick)
+	if err != nil {
+		log.Printf("❌ Admin tároló hiba: %v", err)
 	}
 	return removed
 }
 
 // lekér egy admin info‑t
 func (m *MultiAdminStore) GetAdmin(nick string) (AdminInfo, bool) {
-	for _, fs := range []*fileStore{m.owners, m.admins, m.vips} {
-		fs.mu.RLock()
-		if info, ok := fs.Admins[nick]; ok {
-			fs.mu.RUnlock()
-			return info, true
-		}
-		fs.mu.RUnlock()
+	info, ok, err := m.repo.Get(nick)
+	if err != nil {
+		log.Printf("❌ Admin tároló hiba: %v", err)
 	}
-	return AdminInfo{}, false
+	return info, ok
 }
 
 // szint meghatározása hostmask alapján
@@ -180,13 +112,9 @@ func (m *MultiAdminStore) GetAdminLevel(nick, hostmask string) int {
 
 // listázás szintenként
 func (m *MultiAdminStore) ListAll() []AdminInfo {
-	var res []AdminInfo
-	for _, fs := range []*fileStore{m.owners, m.admins, m.vips} {
-		fs.mu.RLock()
-		for _, a := range fs.Admins {
-			res = append(res, a)
-		}
-		fs.mu.RUnlock()
+	list, err := m.repo.All()
+	if err != nil {
+		log.Printf("❌ Admin tároló hiba: %v", err)
 	}
-	return res
+	return list
 }
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irctest"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+)
+
+func newTestAdmin(t *testing.T) (*irctest.Env, *AdminPlugin) {
+	t.Helper()
+	env := irctest.NewEnv(t, nil)
+	p := NewAdminPlugin(env.Config, env.DB.Admins, env.Ctx)
+	p.Initialize(env.Bot)
+	env.Attach(p)
+	return env, p
+}
+
+func TestAdminCommands(t *testing.T) {
+	env, p := newTestAdmin(t)
+	hu := i18n.Get("hu")
+
+	ownerMask := irctest.Mask("owner")
+	bobMask := irctest.Mask("bob")
+
+	steps := []struct {
+		name  string
+		setup func()
+		from  string
+		text  string
+		want  []string
+	}{
+		{"idegen nem kap választ", nil, "alice", "!whoami", nil},
+		{"első owner", nil, "owner", "!hello", []string{hu.T("admin.hello", "owner")}},
+		{"második !hello néma", nil, "bob", "!hello", nil},
+		{"whoami", nil, "owner", "!whoami", []string{hu.T("admin.whoami", "owner", "Owner", AdminLevelOwner, ownerMask)}},
+		{"hostmask nélkül", nil, "owner", "!addadmin carol 2", []string{hu.T("admin.hostmask_missing")}},
+		{"bob megszólal", nil, "bob", "szia", nil},
+		{"admin felvétele", nil, "owner", "!addadmin bob 2", []string{hu.T("admin.added", "bob", "Admin", AdminLevelAdmin)}},
+		{"érvénytelen szint", nil, "owner", "!addadmin dave 7 *!*@x", []string{hu.T("admin.invalid_level")}},
+		{"admin csak VIP-et vehet fel", nil, "bob", "!addadmin dave 2 *!*@x", []string{hu.T("admin.add_vip_only")}},
+		{"admin VIP-et vesz fel", nil, "bob", "!addadmin dave 1 *!*@dave.test", []string{hu.T("admin.added", "dave", "VIP", AdminLevelVIP)}},
+		{"lista", nil, "bob", "!listadmins", []string{hu.T("admin.list", "owner (Owner-3), bob (Admin-2), dave (VIP-1)")}},
+		{"magasabb szint nem törölhető", nil, "bob", "!deladmin owner", []string{hu.T("admin.del_higher")}},
+		{"nem admin törlése", nil, "bob", "!deladmin senki", []string{hu.T("admin.not_admin", "senki")}},
+		{"rehash nélkül", nil, "bob", "!rehash", []string{hu.T("admin.rehash_unavailable")}},
+		{"rehash", func() { p.OnRehash = func(loc *i18n.Locale) string { return "újratöltve" } }, "bob", "!rehash", []string{"újratöltve"}},
+		{"backup adminnak tilos", nil, "bob", "!backup", []string{hu.T("admin.no_privileges", AdminLevelOwner)}},
+		{"backup hiba", func() {
+			p.OnBackup = func(loc *i18n.Locale) (string, error) { return "", errors.New("tele a lemez") }
+		}, "owner", "!backup", []string{hu.T("admin.backup_failed", errors.New("tele a lemez"))}},
+		{"admin törlése", nil, "owner", "!deladmin bob", []string{hu.T("admin.removed", "bob")}},
+		{"a törölt admin néma", nil, "bob", "!listadmins", nil},
+		{"idegen hoszt nem owner", nil, "owner!x@mashol.test", "!whoami", nil},
+	}
+	for _, st := range steps {
+		if st.setup != nil {
+			st.setup()
+		}
+		got := env.Say(t, st.from, "#test", st.text)
+		if !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, st.want)
+		}
+	}
+
+	if level := p.GetAdminLevel("owner", ownerMask); level != AdminLevelOwner {
+		t.Errorf("owner szintje: %d", level)
+	}
+	if level := p.GetAdminLevel("bob", bobMask); level != AdminLevelNone {
+		t.Errorf("bob szintje törlés után: %d", level)
+	}
+}
+
+func TestAdminLanguage(t *testing.T) {
+	env, _ := newTestAdmin(t)
+	if err := env.Ctx.Set("#en", "language", "en"); err != nil {
+		t.Fatal(err)
+	}
+	env.Say(t, "owner", "#test", "!hello")
+
+	en := i18n.Get("en")
+	got := env.Say(t, "owner", "#en", "!deladmin senki")
+	if want := en.T("admin.not_admin", "senki"); len(got) != 1 || got[0] != want {
+		t.Errorf("angol csatorna: %q, várt %q", got, want)
+	}
+}
+
+// A configból törölt admin újratöltéskor kikerül, a kézzel felvettek maradnak
+func TestReloadConfigAdmins(t *testing.T) {
+	env, p := newTestAdmin(t)
+	p.SetConfigAdmins([]string{"alice"})
+	vip := AdminInfo{Nick: "dave", Hostmask: "*!*@dave.test", Level: AdminLevelVIP, AddedBy: "cli", AddedAt: time.Now()}
+	if err := p.store.AddAdmin(vip); err != nil {
+		t.Fatal(err)
+	}
+
+	newCfg := *env.Config
+	newCfg.Admins = []string{"bob"}
+	ev := pluginapi.ReloadEvent{Old: env.Config, New: &newCfg, Changes: config.Changes{{Key: "admins"}}}
+	if err := p.ReloadConfig(ev); err != nil {
+		t.Fatal(err)
+	}
+
+	for nick, want := range map[string]bool{"alice": false, "bob": true, "dave": true} {
+		if _, ok := p.store.GetAdmin(nick); ok != want {
+			t.Errorf("%s admin: %v, várt %v", nick, ok, want)
+		}
+	}
+	if p.cfg.Load() != &newCfg {
+		t.Error("az új config nem került át")
+	}
+}
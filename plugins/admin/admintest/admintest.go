@@ -0,0 +1,33 @@
+// Package admintest a pluginok tesztjeinek közös környezete: irctest.Env egy
+// admin pluginnal, amelyben "owner" a tulajdonos. Azért nem az irctest része,
+// mert az admin plugin saját tesztje is irctest-et használ (import kör lenne).
+package admintest
+
+import (
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/irctest"
+	"github.com/ynmhu/YnM-Go/plugins/admin"
+)
+
+// NewEnv teszt környezet egy admin pluginnal, amelyben "owner" az owner; az
+// admins további felhasználók a !addadmin paramétereivel (pl. "mod 2 *!mod@user.test")
+func NewEnv(t testing.TB, configure func(cfg *config.Config), admins ...string) (*irctest.Env, *admin.AdminPlugin) {
+	t.Helper()
+	env := irctest.NewEnv(t, configure)
+	adm := admin.NewAdminPlugin(env.Config, env.DB.Admins, env.Ctx)
+	adm.Initialize(env.Bot)
+	commands := []string{"!hello"}
+	for _, a := range admins {
+		commands = append(commands, "!addadmin "+a)
+	}
+	for _, text := range commands {
+		adm.HandleMessage(irc.Message{Sender: irctest.Mask("owner"), Nick: "owner", Channel: "#test", Text: text})
+	}
+	if level := adm.GetAdminLevel("owner", irctest.Mask("owner")); level != admin.AdminLevelOwner {
+		t.Fatalf("admintest: owner szintje %d", level)
+	}
+	return env, adm
+}
@@ -0,0 +1,44 @@
+package media
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	movieType  = "MediaBrowser.Controller.Entities.Movies.Movie"
+	seriesType = "MediaBrowser.Controller.Entities.TV.Series"
+)
+
+// staff a tesztek adminjai az owner mellett: "mod" admin, "vip" VIP
+var staff = []string{"mod 2 *!mod@user.test", "vip 1 *!vip@user.test"}
+
+// jellyfinItem a Jellyfin TypedBaseItems táblájának egy sora
+type jellyfinItem struct {
+	typ, name, original, overview, path, created, genres string
+	ticks                                                int64
+	year                                                 int
+}
+
+// jellyfinDB a tesztben használt oszlopokkal létrehoz egy Jellyfin adatbázist
+func jellyfinDB(t *testing.T, items ...jellyfinItem) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "library.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE TypedBaseItems (Type TEXT, Name TEXT, CleanName TEXT, OriginalTitle TEXT,
+		RunTimeTicks INTEGER, Overview TEXT, Path TEXT, DateCreated TEXT, Genres TEXT, ProductionYear INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range items {
+		if _, err := db.Exec(`INSERT INTO TypedBaseItems VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			it.typ, it.name, it.name, it.original, it.ticks, it.overview, it.path, it.created, it.genres, it.year); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
@@ -9,28 +9,41 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type MediaAjanlatPlugin struct {
-	bot           *irc.Client
+	bot           irc.Sender
+	ctx           *pluginapi.Context // film.enabled / film.time csatornánként
 	dbPath        string
-	channel       string
-	dailyTime     string
 	mutex         sync.Mutex
-	dailySchedule *time.Timer
+	filter        pluginapi.ChannelFilter
+	cancelJobs    func()
+	pause         time.Duration // szünet az ajánló sorai között
 }
 
-func NewMediaAjanlatPlugin(bot *irc.Client, dbPath, channel, dailyTime string) *MediaAjanlatPlugin {
+func NewMediaAjanlatPlugin(bot irc.Sender, ctx *pluginapi.Context, dbPath string) *MediaAjanlatPlugin {
 	p := &MediaAjanlatPlugin{
-		bot:       bot,
-		dbPath:    dbPath,
-		channel:   channel,
-		dailyTime: dailyTime,
+		bot:    bot,
+		ctx:    ctx,
+		dbPath: dbPath,
+		pause:  1 * time.Second,
 	}
 
-	go p.scheduleDailyRecommendation()
+	// Napi ajánló csatornánként a film.time szerint (film:#csatorna feladatok)
+	log.Printf("[MediaAjanlatPlugin] Napi ajánló elindult, alap időpont: %s", ctx.Setting("", "film.time"))
+	p.cancelJobs = ctx.ChannelJobs("film", "film.enabled", "film.time",
+		scheduler.Job{Missed: scheduler.MissedSkip},
+		func(channel string) error {
+			if p.filter.Allows(channel) {
+				p.sendRecommendation(p.ctx.Locale(channel), channel)
+			}
+			return nil
+		})
 	return p
 }
 
@@ -48,45 +61,31 @@ func (p *MediaAjanlatPlugin) Help() string {
 
 func (p *MediaAjanlatPlugin) HandleMessage(msg irc.Message) string {
 	if strings.TrimSpace(msg.Text) == "!film" {
-		return p.sendRecommendation(msg.Channel)
+		return p.sendRecommendation(p.ctx.LocaleFor(msg), msg.Channel)
 	}
 	return ""
 }
 
-func (p *MediaAjanlatPlugin) scheduleDailyRecommendation() {
-	for {
-		now := time.Now()
-		targetTime, err := time.ParseInLocation("15:04", p.dailyTime, now.Location())
-		if err != nil {
-			log.Printf("[MediaAjanlatPlugin] Hibás időformátum a konfigurációban: %v", err)
-			return
-		}
-
-		nextRun := time.Date(now.Year(), now.Month(), now.Day(), targetTime.Hour(), targetTime.Minute(), 0, 0, now.Location())
-		if nextRun.Before(now) {
-			nextRun = nextRun.Add(24 * time.Hour)
-		}
-
-		duration := nextRun.Sub(now)
-		log.Printf("[MediaAjanlatPlugin] Következő napi ajánlás időzítve: %v", nextRun)
-
-		time.Sleep(duration)
+func (p *MediaAjanlatPlugin) Stop() {
+	p.cancelJobs()
+}
 
-		p.sendRecommendation(p.channel)
-	}
+func (p *MediaAjanlatPlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
 }
 
-func (p *MediaAjanlatPlugin) sendRecommendation(channel string) string {
+func (p *MediaAjanlatPlugin) sendRecommendation(loc *i18n.Locale, channel string) string {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	db, err := sql.Open("sqlite3", p.dbPath)
 	if err != nil {
 		log.Printf("[MediaAjanlatPlugin] DB megnyitási hiba: %v", err)
-		return "Adatbázis hiba!"
+		return loc.T("media.db_error")
 	}
 	defer db.Close()
 
+	start := time.Now()
 	rows, err := db.Query(`
 		SELECT Name, CleanName, OriginalTitle, RunTimeTicks, Overview, Path 
 		FROM TypedBaseItems 
@@ -95,7 +94,7 @@ func (p *MediaAjanlatPlugin) sendRecommendation(channel string) string {
 	`)
 	if err != nil {
 		log.Printf("[MediaAjanlatPlugin] SQL hiba: %v", err)
-		return "Lekérdezési hiba!"
+		return loc.T("media.db_error")
 	}
 	defer rows.Close()
 
@@ -106,20 +105,21 @@ func (p *MediaAjanlatPlugin) sendRecommendation(channel string) string {
 			movies = append(movies, m)
 		}
 	}
+	observeQuery("ajanlo", "movies", start)
 
 	if len(movies) == 0 {
-		p.bot.SendMessage(channel, "Nincs elérhető film az adatbázisban!")
+		p.bot.SendMessage(channel, loc.T("film.none"))
 		return ""
 	}
 
 	movie := movies[rand.Intn(len(movies))]
 	runtimeStr := convertTicksToTime(movie.RunTimeTicks)
 
-	p.bot.SendMessage(channel, fmt.Sprintf("🎬 Napi film ajánlat: %s", movie.OriginalTitle))
-	time.Sleep(1 * time.Second)
-	p.bot.SendMessage(channel, fmt.Sprintf("*Lejátszási idő*: %s", runtimeStr))
-	time.Sleep(1 * time.Second)
-	p.bot.SendMessage(channel, fmt.Sprintf("*Áttekintés*: %s", movie.Overview))
+	p.bot.SendMessage(channel, loc.T("film.title", movie.OriginalTitle))
+	time.Sleep(p.pause)
+	p.bot.SendMessage(channel, loc.T("media.runtime", runtimeStr))
+	time.Sleep(p.pause)
+	p.bot.SendMessage(channel, loc.T("media.overview", movie.Overview))
 
 	return ""
 }
@@ -0,0 +1,70 @@
+package media
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestMediaAjanlat(t *testing.T) {
+	hu := i18n.Get("hu")
+	movie := jellyfinItem{typ: movieType, name: "Alien", original: "Alien", overview: "Űrhajó, idegen.",
+		path: "/media/f1/r/alien.mkv", ticks: int64(117*time.Minute) / 100}
+	recommendation := []string{
+		"#test: " + hu.T("film.title", "Alien"),
+		"#test: " + hu.T("media.runtime", "01:57:00"),
+		"#test: " + hu.T("media.overview", "Űrhajó, idegen."),
+	}
+
+	tests := []struct {
+		name string
+		db   func(t *testing.T) string
+		want []string
+	}{
+		{"egy film", func(t *testing.T) string {
+			return jellyfinDB(t, movie,
+				jellyfinItem{typ: movieType, name: "Tiltott", original: "Tiltott", path: "/media/x/tiltott.mkv"},
+				jellyfinItem{typ: seriesType, name: "Sorozat", original: "Sorozat", path: "/media/f/Series/s"})
+		}, recommendation},
+		{"üres könyvtár", func(t *testing.T) string { return jellyfinDB(t) }, []string{"#test: " + hu.T("film.none")}},
+		{"hiányzó adatbázis", func(t *testing.T) string {
+			return filepath.Join(t.TempDir(), "nincs", "library.db")
+		}, []string{"#test: " + hu.T("media.db_error")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, _ := admintest.NewEnv(t, nil, staff...)
+			p := NewMediaAjanlatPlugin(env.Bot, env.Ctx, tt.db(t))
+			p.pause = 0
+			t.Cleanup(p.Stop)
+			env.Attach(p)
+			if got := env.Sent(t, func() { env.Server.Privmsg("alice", "#test", "!film") }); !slices.Equal(got, tt.want) {
+				t.Errorf("!film: %q, várt %q", got, tt.want)
+			}
+		})
+	}
+
+	// napi ajánló a film.time (alapból 21:35) szerint
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) { cfg.MediaAjanlat.Channel = "#test" }, staff...)
+	p := NewMediaAjanlatPlugin(env.Bot, env.Ctx, jellyfinDB(t, movie))
+	p.pause = 0
+	t.Cleanup(p.Stop)
+	for _, st := range []struct {
+		advance time.Duration
+		want    []string
+	}{
+		{13 * time.Hour, nil},
+		{35 * time.Minute, recommendation},
+		{time.Hour, nil},
+	} {
+		got := env.Tick(t, st.advance)
+		if !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", env.Clock.Now().Format("15:04"), got, st.want)
+		}
+	}
+}
@@ -1,36 +1,33 @@
 package media
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
-	_ "github.com/mattn/go-sqlite3"
+	"time"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	 "github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/storage"
 
 )
 
 type MovieDeletionPlugin struct {
-	bot         *irc.Client
+	bot         irc.Sender
 	adminPlugin *admin.AdminPlugin
-	db          *sql.DB
+	movies      *storage.MovieRepo
+	tr          pluginapi.Localizer
 	mutex       sync.RWMutex
-	movieDBPath string
 }
 
-func NewMovieDeletionPlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin, movieDBPath string) *MovieDeletionPlugin {
+func NewMovieDeletionPlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, movies *storage.MovieRepo, tr pluginapi.Localizer) *MovieDeletionPlugin {
 	plugin := &MovieDeletionPlugin{
 		bot:         bot,
 		adminPlugin: adminPlugin,
-		movieDBPath: movieDBPath,
-	}
-
-	// Initialize database
-	if err := plugin.initializeDatabase(); err != nil {
-		////log.Printf("Failed to initialize movie deletion plugin database: %v", err)
-		return nil
+		movies:      movies,
+		tr:          tr,
 	}
 
 	log.Printf("MovieDeletionPlugin initialized successfully")
@@ -64,105 +61,62 @@ func (p *MovieDeletionPlugin) HandleMessage(msg irc.Message) string {
     }
 
     // Naplózzuk csak a tényleges !del parancsokat
-    //log.Printf("[MovieDeletionPlugin] Command received: '%s' from '%s'", msg.Text, msg.Sender)
+	mediaLog.Debug("Command received", "text", msg.Text, "sender", msg.Sender)
 
     // Feldolgozzuk a parancsot
     text := strings.TrimSpace(msg.Text)
     parts := strings.Fields(text)
+    loc := p.tr.LocaleFor(msg)
     
     if len(parts) == 1 { // Csak !del
-        return "Használat: !del <PIN>"
+        return loc.T("del.usage")
     }
     
     if len(parts) == 2 { // !del <PIN>
         pin := parts[1]
         if !isValidPIN(pin) {
-            return "Helytelen PIN formátum. 4-6 számjegy szükséges."
+            return loc.T("media.bad_pin")
         }
-        return p.handleMovieDeletion(pin)
+        return p.handleMovieDeletion(loc, pin)
     }
     
-    return "Túl sok paraméter. Használat: !del <PIN>"
+    return loc.T("media.too_many_args", loc.T("del.usage"))
 }
 
 
-func (p *MovieDeletionPlugin) handleMovieDeletion(pin string) string {
+func (p *MovieDeletionPlugin) handleMovieDeletion(loc *i18n.Locale, pin string) string {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	//log.Printf("[MovieDeletionPlugin] Processing deletion for PIN: %s", pin)
+	mediaLog.Debug("Processing deletion", "pin", pin)
 
 	// Delete movie by PIN
-	deleted, err := p.deleteMovieByPIN(pin)
+	deleted, err := DeleteRequest(p.movies, pin)
 	if err != nil {
-		//log.Printf("[MovieDeletionPlugin] Database error: %v", err)
-		return fmt.Sprintf("Adatbázis hiba: %v", err)
+		mediaLog.Error("Database error", "err", err)
+		return loc.T("media.db_error")
 	}
 
 	if deleted {
-		//log.Printf("[MovieDeletionPlugin] Successfully deleted movie with PIN: %s", pin)
-		return fmt.Sprintf("%s sikeresen törölve.", pin)
+		mediaLog.Debug("Movie deleted", "pin", pin)
+		return loc.T("del.done", pin)
 	} else {
-		//log.Printf("[MovieDeletionPlugin] Movie not found for PIN: %s", pin)
-		return fmt.Sprintf("Nem tudom törölni %s. Lehetséges nem létezik.", pin)
+		mediaLog.Debug("Movie not found", "pin", pin)
+		return loc.T("del.not_found", pin)
 	}
 }
 
-func (p *MovieDeletionPlugin) initializeDatabase() error {
-	var err error
-	
-	//log.Printf("[MovieDeletionPlugin] Opening database: %s", p.movieDBPath)
-	
-	p.db, err = sql.Open("sqlite3", p.movieDBPath)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
-	}
-
-	// Test database connection
-	if err := p.db.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %v", err)
-	}
+// DeleteRequest törli a PIN-hez tartozó kérést (a !del és a webes kéréslista is ezt hívja)
+func DeleteRequest(movies *storage.MovieRepo, pin string) (bool, error) {
+	mediaLog.Debug("Deleting movie", "pin", pin)
 
-	// Check if movies table exists
-	var count int
-	err = p.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='movies'").Scan(&count)
-	if err != nil {
-		return fmt.Errorf("failed to check for movies table: %v", err)
-	}
-	
-	if count == 0 {
-		return fmt.Errorf("movies table does not exist")
-	}
-
-	//log.Printf("[MovieDeletionPlugin] Database initialized successfully")
-	return nil
-}
-
-func (p *MovieDeletionPlugin) deleteMovieByPIN(pin string) (bool, error) {
-	query := `DELETE FROM movies WHERE pin = ?`
-	
-	//log.Printf("[MovieDeletionPlugin] Deleting movie with PIN: %s", pin)
-	
-	result, err := p.db.Exec(query, pin)
+	start := time.Now()
+	deleted, err := movies.Delete(pin)
+	observeQuery("del", "delete", start)
 	if err != nil {
 		return false, fmt.Errorf("failed to delete movie: %v", err)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return false, fmt.Errorf("failed to get rows affected: %v", err)
-	}
-
-	//log.Printf("[MovieDeletionPlugin] Deleted %d row(s)", rowsAffected)
-	return rowsAffected > 0, nil
-}
-
-func (p *MovieDeletionPlugin) Close() error {
-	//log.Printf("[MovieDeletionPlugin] Closing database connection")
-	if p.db != nil {
-		return p.db.Close()
-	}
-	return nil
+	return deleted, nil
 }
 
 func (p *MovieDeletionPlugin) OnTick() []irc.Message {
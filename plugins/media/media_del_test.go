@@ -0,0 +1,40 @@
+package media
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestMovieDeletion(t *testing.T) {
+	env, adm := admintest.NewEnv(t, nil, staff...)
+	env.Attach(NewMovieDeletionPlugin(env.Bot, adm, env.DB.Movies, env.Ctx))
+	hu := i18n.Get("hu")
+	if err := env.DB.Movies.Add("Dűne", "12345", "alice", 2021); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name string
+		from string
+		text string
+		want []string
+	}{
+		{"VIP nem törölhet", "vip", "!del 12345", nil},
+		{"PIN nélkül", "mod", "!del", []string{hu.T("del.usage")}},
+		{"hibás PIN", "mod", "!del 1234567", []string{hu.T("media.bad_pin")}},
+		{"túl sok paraméter", "mod", "!del 12345 67890", []string{hu.T("media.too_many_args", hu.T("del.usage"))}},
+		{"törlés", "mod", "!del 12345", []string{hu.T("del.done", "12345")}},
+		{"már nincs meg", "owner", "!del 12345", []string{hu.T("del.not_found", "12345")}},
+	}
+	for _, st := range steps {
+		if got := env.Say(t, st.from, "#test", st.text); !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, st.want)
+		}
+	}
+	if m, err := env.DB.Movies.ByPIN("12345"); err != nil || m != nil {
+		t.Errorf("törölt kérés: %+v, %v", m, err)
+	}
+}
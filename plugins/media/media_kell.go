@@ -13,26 +13,46 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/logging"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
-
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
+// mediaLog a media pluginok naplója (a részletes sorok debug szinten: !loglevel media debug)
+var mediaLog = logging.For("media")
+
 type MoviePlugin struct {
-	bot             *irc.Client
+	bot             irc.Sender
 	adminPlugin     *admin.AdminPlugin
-	db              *sql.DB
+	movies          *storage.MovieRepo
 	jellyfinDB      *sql.DB
 	lastHeckTime    map[string]time.Time
-	movieRequests   []string
+	movieRequests   []movieRequest
 	usedPins        map[string]bool
 	mutex           sync.RWMutex
 	requestsChannel string
 	jellyfinDBPath  string
-	movieDBPath     string
 	postTime        string
 	postChan        string
 	postNick        string
+	listURL         string        // a kéréslista címe a !kell válaszában
+	pause           time.Duration // szünet a több soros válaszok sorai között
+	filter          pluginapi.ChannelFilter
+	ctx             *pluginapi.Context
+}
+
+// a napi kérés-összesítő feladat neve az ütemezőben
+const requestPostingJob = "kell:posting"
+
+// movieRequest egy még ki nem küldött kérés; a szövege a kiküldéskor, a
+// posting csatorna nyelvén készül
+type movieRequest struct {
+	requester, title, pin string
+	year                  int
 }
 
 type JellyfinMovie struct {
@@ -45,27 +65,27 @@ type JellyfinMovie struct {
 	Type          string
 }
 
-func NewMoviePlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin, jellyfinDBPath, movieDBPath, requestsChannel, postTime, postChan, postNick string) *MoviePlugin {
+func NewMoviePlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, ctx *pluginapi.Context, jellyfinDBPath, requestsChannel, postTime, postChan, postNick, listURL string) *MoviePlugin {
 	plugin := &MoviePlugin{
 		bot:             bot,
 		adminPlugin:     adminPlugin,
+		movies:          ctx.Storage.Movies,
 		lastHeckTime:    make(map[string]time.Time),
-		movieRequests:   make([]string, 0),
+		movieRequests:   make([]movieRequest, 0),
 		usedPins:        make(map[string]bool),
 		requestsChannel: requestsChannel,
 		jellyfinDBPath:  jellyfinDBPath,
-		movieDBPath:     movieDBPath,
 		postTime:        postTime,
 		postChan:        postChan,
 		postNick:        postNick,
+		listURL:         listURL,
+		pause:           1 * time.Second,
+		ctx:             ctx,
 	}
 
-	if err := plugin.initializeDatabases(); err != nil {
-		log.Fatalf("Failed to initialize movie plugin databases: %v", err)
-	}
-
+	plugin.openJellyfinDB()
 	plugin.loadExistingPINs()
-	go plugin.startRequestPosting()
+	plugin.startRequestPosting()
 
 	return plugin
 }
@@ -94,102 +114,80 @@ func (p *MoviePlugin) handleMovieRequest(msg irc.Message) string {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	loc := p.ctx.LocaleFor(msg)
 	movieRequestRegex := regexp.MustCompile(`^!kell\s+(.+)$`)
 	matches := movieRequestRegex.FindStringSubmatch(msg.Text)
 	if len(matches) < 2 {
-		return "Usage: !kell Film címe és dátum!"
+		return loc.T("kell.usage")
 	}
 
 	requester := strings.Split(msg.Sender, "!")[0]
 	details := strings.TrimSpace(matches[1])
 	parts := strings.Split(details, " ")
 	if len(parts) < 2 {
-		return "Usage: !kell Film címe és dátum!"
+		return loc.T("kell.usage")
 	}
 
 	yearStr := parts[len(parts)-1]
 	year, err := strconv.Atoi(yearStr)
 	if err != nil || len(yearStr) != 4 {
-		return "Évjárat pl 1995 vagy 2024."
+		return loc.T("kell.bad_year")
 	}
 	title := strings.Join(parts[:len(parts)-1], " ")
 	if title == "" {
-		return "Usage: !kell Film címe és dátum!"
+		return loc.T("kell.usage")
 	}
 
 	if exists, info := p.checkJellyfinMovie(title); exists {
-		p.bot.SendMessage(msg.Channel, fmt.Sprintf("'*%s*' már fel van töltve *YnM* *Media* -ra.", title))
-		time.Sleep(1 * time.Second)
-		p.bot.SendMessage(msg.Channel, fmt.Sprintf("*Cím*: %s", info.Name))
-		time.Sleep(1 * time.Second)
-		p.bot.SendMessage(msg.Channel, fmt.Sprintf("*Feltöltés dátuma*: %s *Lejátszási idő*: %s", p.parseDate(info.DateCreated), p.formatRuntime(info.RunTimeTicks)))
-		time.Sleep(1 * time.Second)
-		p.bot.SendMessage(msg.Channel, fmt.Sprintf("*Áttekintés*: %s", info.Overview))
+		p.bot.SendMessage(msg.Channel, loc.T("kell.already_uploaded", title))
+		time.Sleep(p.pause)
+		p.bot.SendMessage(msg.Channel, loc.T("kell.title", info.Name))
+		time.Sleep(p.pause)
+		p.bot.SendMessage(msg.Channel, loc.T("kell.uploaded", p.parseDate(loc, info.DateCreated), p.formatRuntime(loc, info.RunTimeTicks)))
+		time.Sleep(p.pause)
+		p.bot.SendMessage(msg.Channel, loc.T("media.overview", info.Overview))
 		return ""
 	}
 
 	if requested, requester, date := p.isMovieRequestedWithDetails(title); requested {
-		return fmt.Sprintf("'%s' Filmet már kérte @%s %s-án/én.", title, requester, date)
+		when := loc.T("media.unknown_date")
+		if !date.IsZero() {
+			when = loc.ShortDate(date)
+		}
+		return loc.T("kell.already_requested", title, requester, when)
 	}
 
 	pin := p.generatePIN()
 	if err := p.addMovieToDatabase(title, pin, requester, year); err != nil {
-		return fmt.Sprintf("Adatbázis hiba: %v", err)
+		log.Printf("Error adding movie request: %v", err)
+		return loc.T("media.db_error")
 	}
 
-	request := fmt.Sprintf("🎬 @%s új filmet kért: *%s* (📅 %d) – PIN: 🔑 %s", requester, title, year, pin)
-	p.movieRequests = append(p.movieRequests, request)
+	p.movieRequests = append(p.movieRequests, movieRequest{requester: requester, title: title, pin: pin, year: year})
 	nick := strings.Split(msg.Sender, "!")[0]
-	p.bot.SendMessage(msg.Channel, fmt.Sprintf("@%s Cim: '%s' (Évjárat: %d) hozzáadva, PIN: %s.", nick, title, year, pin))
-	time.Sleep(1 * time.Second)
-	return "Kérések Listája: https://bot.ynm.hu/media"
+	p.bot.SendMessage(msg.Channel, loc.T("kell.added", nick, title, year, pin))
+	time.Sleep(p.pause)
+	return loc.T("kell.list", p.listURL)
 }
 
-func (p *MoviePlugin) initializeDatabases() error {
-	var err error
-	p.db, err = sql.Open("sqlite3", p.movieDBPath)
-	if err != nil {
-		return fmt.Errorf("failed to open movie database: %v", err)
-	}
-
-	createTableQuery := `CREATE TABLE IF NOT EXISTS movies (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		title TEXT NOT NULL,
-		pin TEXT NOT NULL UNIQUE,
-		upload_date DATETIME DEFAULT CURRENT_TIMESTAMP,
-		requested_by TEXT NOT NULL,
-		year INTEGER NOT NULL,
-		status TEXT DEFAULT 'Nem'
-	);`
-
-	if _, err := p.db.Exec(createTableQuery); err != nil {
-		return fmt.Errorf("failed to create movies table: %v", err)
-	}
-
+// openJellyfinDB a Jellyfin adatbázisa csak olvasásra (a már feltöltött filmek ellenőrzéséhez)
+func (p *MoviePlugin) openJellyfinDB() {
 	if _, err := os.Stat(p.jellyfinDBPath); err == nil {
 		p.jellyfinDB, err = sql.Open("sqlite3", p.jellyfinDBPath+"?mode=ro")
 		if err != nil {
 			log.Printf("Warning: Failed to open Jellyfin database: %v", err)
 		}
 	}
-
-	return nil
 }
 
 func (p *MoviePlugin) loadExistingPINs() {
-	rows, err := p.db.Query("SELECT pin FROM movies")
+	defer observeQuery("kell", "pins", time.Now())
+	pins, err := p.movies.PINs()
 	if err != nil {
 		log.Printf("Error loading existing PINs: %v", err)
 		return
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var pin string
-		if err := rows.Scan(&pin); err != nil {
-			log.Printf("Error scanning PIN: %v", err)
-			continue
-		}
+	for _, pin := range pins {
 		p.usedPins[pin] = true
 	}
 }
@@ -215,6 +213,7 @@ func (p *MoviePlugin) checkJellyfinMovie(title string) (bool, JellyfinMovie) {
 		AND (Name = ? COLLATE NOCASE OR CleanName = ? COLLATE NOCASE OR OriginalTitle = ? COLLATE NOCASE)`
 
 	var movie JellyfinMovie
+	defer observeQuery("kell", "jellyfin_lookup", time.Now())
 	err := p.jellyfinDB.QueryRow(query, title, title, title).Scan(&movie.Name, &movie.CleanName, &movie.OriginalTitle, &movie.RunTimeTicks, &movie.DateCreated, &movie.Overview, &movie.Type)
 	if err != nil {
 		if err != sql.ErrNoRows {
@@ -226,41 +225,42 @@ func (p *MoviePlugin) checkJellyfinMovie(title string) (bool, JellyfinMovie) {
 	return true, movie
 }
 
-func (p *MoviePlugin) isMovieRequestedWithDetails(title string) (bool, string, string) {
-	var requester, uploadDate string
-	err := p.db.QueryRow("SELECT requested_by, upload_date FROM movies WHERE title = ? LIMIT 1", title).Scan(&requester, &uploadDate)
+// isMovieRequestedWithDetails a kérő és a kérés dátuma (ismeretlen dátum esetén nulla)
+func (p *MoviePlugin) isMovieRequestedWithDetails(title string) (bool, string, time.Time) {
+	defer observeQuery("kell", "requested", time.Now())
+	movie, err := p.movies.ByTitle(title)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, "", ""
-		}
 		log.Printf("Error checking if movie is requested: %v", err)
-		return false, "", ""
+		return false, "", time.Time{}
+	}
+	if movie == nil {
+		return false, "", time.Time{}
 	}
-	return true, requester, p.parseDate(uploadDate)
+	return true, movie.RequestedBy, movie.UploadDate
 }
 
 func (p *MoviePlugin) addMovieToDatabase(title, pin, requester string, year int) error {
-	_, err := p.db.Exec("INSERT INTO movies (title, pin, requested_by, year, status) VALUES (?, ?, ?, ?, 'Nem')", title, pin, requester, year)
-	return err
+	defer observeQuery("kell", "insert", time.Now())
+	return p.movies.Add(title, pin, requester, year)
 }
 
-func (p *MoviePlugin) parseDate(dateString string) string {
+func (p *MoviePlugin) parseDate(loc *i18n.Locale, dateString string) string {
 	if dateString == "" {
-		return "Ismeretlen dátum"
+		return loc.T("media.unknown_date")
 	}
 	dateString = strings.ReplaceAll(dateString, "Z", "+00:00")
 	formats := []string{"2006-01-02T15:04:05+00:00", "2006-01-02T15:04:05.000+00:00", "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
 	for _, format := range formats {
 		if t, err := time.Parse(format, dateString); err == nil {
-			return t.Format("2006-01-02")
+			return loc.ShortDate(t)
 		}
 	}
-	return "Ismeretlen dátum"
+	return loc.T("media.unknown_date")
 }
 
-func (p *MoviePlugin) formatRuntime(runtimeTicks *int64) string {
+func (p *MoviePlugin) formatRuntime(loc *i18n.Locale, runtimeTicks *int64) string {
 	if runtimeTicks == nil || *runtimeTicks == 0 {
-		return "N/A"
+		return loc.T("media.unknown_runtime")
 	}
 	totalSeconds := *runtimeTicks / 10_000_000
 	hours := totalSeconds / 3600
@@ -269,40 +269,45 @@ func (p *MoviePlugin) formatRuntime(runtimeTicks *int64) string {
 	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
 }
 
+// startRequestPosting a post_time (ÓÓ:PP vagy cron) szerint küldi ki az összegyűlt kéréseket
 func (p *MoviePlugin) startRequestPosting() {
-	for {
-		now := time.Now()
-		scheduledTime, err := time.Parse("15:04", p.postTime)
-		if err != nil {
-			log.Printf("❌ Hibás post_time formátum: %v", err)
-			return
-		}
-		next := time.Date(now.Year(), now.Month(), now.Day(), scheduledTime.Hour(), scheduledTime.Minute(), 0, 0, now.Location())
-		if now.After(next) {
-			next = next.Add(24 * time.Hour)
-		}
-		time.Sleep(time.Until(next))
-		p.mutex.Lock()
-		p.postMovieRequests()
-		p.mutex.Unlock()
+	if p.postTime == "" {
+		return
+	}
+	err := p.ctx.Schedule(scheduler.Job{
+		Name:   requestPostingJob,
+		Spec:   p.postTime,
+		Missed: scheduler.MissedOnce,
+		Run: func() error {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			p.postMovieRequests()
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Hibás post_time formátum: %v", err)
 	}
 }
 
 func (p *MoviePlugin) postMovieRequests() {
-	if len(p.movieRequests) == 0 {
+	if len(p.movieRequests) == 0 || !p.filter.Allows(p.postChan) {
 		return
 	}
-	for _, request := range p.movieRequests {
-		message := fmt.Sprintf("🚨 @%s 🚨: %s", p.postNick, request)
-		p.bot.SendMessage(p.postChan, message)
+	loc := p.ctx.Locale(p.postChan)
+	for _, r := range p.movieRequests {
+		request := loc.T("kell.request", r.requester, r.title, r.year, r.pin)
+		p.bot.SendMessage(p.postChan, loc.T("kell.post", p.postNick, request))
 	}
-	p.movieRequests = make([]string, 0)
+	p.movieRequests = make([]movieRequest, 0)
+}
+
+func (p *MoviePlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
 }
 
 func (p *MoviePlugin) Close() error {
-	if p.db != nil {
-		p.db.Close()
-	}
+	p.ctx.Scheduler.Remove(requestPostingJob)
 	if p.jellyfinDB != nil {
 		p.jellyfinDB.Close()
 	}
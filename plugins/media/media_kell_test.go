@@ -0,0 +1,81 @@
+package media
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestMovieRequest(t *testing.T) {
+	env, adm := admintest.NewEnv(t, nil, staff...)
+	ticks := int64(2*time.Hour+28*time.Minute) / 100 // a Jellyfin 100 ns-os egységben tárol
+	jellyfin := jellyfinDB(t, jellyfinItem{typ: movieType, name: "Inception", original: "Inception",
+		overview: "Álmok az álmokban.", created: "2024-03-05 10:00:00", ticks: ticks})
+	p := NewMoviePlugin(env.Bot, adm, env.Ctx, jellyfin, "", "21:00", "#test", "admin", "https://media.test/kell")
+	p.pause = 0
+	t.Cleanup(func() { p.Close() })
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	// a PIN véletlenszerű, ezért a mentett kérésből olvassuk vissza
+	pin := func(title string) string {
+		m, err := env.DB.Movies.ByTitle(title)
+		if err != nil || m == nil {
+			t.Fatalf("%s kérés: %v, %v", title, m, err)
+		}
+		return m.PIN
+	}
+
+	steps := []struct {
+		name string
+		text string
+		want func() []string
+	}{
+		{"cím nélkül", "!kell", func() []string { return nil }},
+		{"évjárat nélkül", "!kell Dűne", func() []string { return []string{"#test: " + hu.T("kell.usage")} }},
+		{"hibás évjárat", "!kell Dűne 21", func() []string { return []string{"#test: " + hu.T("kell.bad_year")} }},
+		{"már feltöltve", "!kell inception 2010", func() []string {
+			return []string{
+				"#test: " + hu.T("kell.already_uploaded", "inception"),
+				"#test: " + hu.T("kell.title", "Inception"),
+				"#test: " + hu.T("kell.uploaded", hu.ShortDate(time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)), "02:28:00"),
+				"#test: " + hu.T("media.overview", "Álmok az álmokban."),
+			}
+		}},
+		{"új kérés", "!kell Dűne 2021", func() []string {
+			return []string{
+				"#test: " + hu.T("kell.added", "alice", "Dűne", 2021, pin("Dűne")),
+				"#test: " + hu.T("kell.list", "https://media.test/kell"),
+			}
+		}},
+		{"már kérték", "!kell Dűne 2021", func() []string {
+			m, _ := env.DB.Movies.ByTitle("Dűne")
+			return []string{"#test: " + hu.T("kell.already_requested", "Dűne", "alice", hu.ShortDate(m.UploadDate))}
+		}},
+	}
+	for _, st := range steps {
+		got := env.Sent(t, func() { env.Server.Privmsg("alice", "#test", st.text) })
+		if want := st.want(); !slices.Equal(got, want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, want)
+		}
+	}
+
+	// az összegyűlt kérések 21:00-kor mennek ki, egyszer
+	request := hu.T("kell.request", "alice", "Dűne", 2021, pin("Dűne"))
+	for _, tt := range []struct {
+		advance time.Duration
+		want    []string
+	}{
+		{12 * time.Hour, nil},
+		{time.Hour, []string{"#test: " + hu.T("kell.post", "admin", request)}},
+		{24 * time.Hour, nil},
+	} {
+		got := env.Tick(t, tt.advance)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, várt %q", env.Clock.Now().Format("01-02 15:04"), got, tt.want)
+		}
+	}
+}
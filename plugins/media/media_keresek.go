@@ -1,47 +1,43 @@
 package media
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 	"sync"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
-type MovieRequest struct {
-	ID           int
-	Title        string
-	PIN          string
-	RequestedBy  string
-	Year         int
-	Status       string
-	UploadDate   time.Time
-	CompletedDate *time.Time
-}
+// a movies.status értékei (az Elutasítva állapotot kézzel vagy külső eszköz állítja)
+const (
+	StatusPending   = storage.MoviePending
+	StatusCompleted = storage.MovieCompleted
+	StatusRejected  = storage.MovieRejected
+)
+
+// MovieRequest egy filmkérés a közös adatbázisból
+type MovieRequest = storage.Movie
 
 // MovieRequestPlugin - A plugin fő struktúrája
 type MovieRequestPlugin struct {
-	bot         *irc.Client
+	bot         irc.Sender
 	adminPlugin *admin.AdminPlugin
-	db          *sql.DB
+	movies      *storage.MovieRepo
+	tr          pluginapi.Localizer
 	mutex       sync.RWMutex
-	movieDBPath string
 }
 
 
-func NewMovieRequestPlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin, movieDBPath string) *MovieRequestPlugin {
+func NewMovieRequestPlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, movies *storage.MovieRepo, tr pluginapi.Localizer) *MovieRequestPlugin {
 	plugin := &MovieRequestPlugin{
 		bot:         bot,
 		adminPlugin: adminPlugin,
-		movieDBPath: movieDBPath,
-	}
-
-	if err := plugin.initializeDatabase(); err != nil {
-		log.Printf("Failed to initialize movie request plugin database: %v", err)
-		return nil
+		movies:      movies,
+		tr:          tr,
 	}
 
 	log.Printf("MovieRequestPlugin initialized successfully")
@@ -72,21 +68,21 @@ func (p *MovieRequestPlugin) HandleMessage(msg irc.Message) string {
         return ""
     }
 
+    loc := p.tr.LocaleFor(msg)
     requests, err := p.getPendingRequests()
     if err != nil {
         log.Printf("[MovieRequestPlugin] Database error: %v", err)
-        return fmt.Sprintf("Adatbázis hiba: %v", err)
+        return loc.T("media.db_error")
     }
 
     if len(requests) == 0 {
-        return "Nincs függőben lévő kérés."
+        return loc.T("keresek.none")
     }
 
     // Küldjük külön üzenetként, hogy minden kérés új sorban legyen
-    p.bot.SendMessage(msg.Channel, "Függőben lévő kérések:")
+    p.bot.SendMessage(msg.Channel, loc.T("keresek.title"))
     for _, req := range requests {
-        p.bot.SendMessage(msg.Channel, fmt.Sprintf(
-            "Kérő: @%s  | Film: %s (%d) - PIN: %s ",
+        p.bot.SendMessage(msg.Channel, loc.T("keresek.line",
             req.RequestedBy, req.Title, req.Year, req.PIN,
         ))
         time.Sleep(500 * time.Millisecond) // Kis késleltetés, hogy ne floodoljon
@@ -98,64 +94,18 @@ func (p *MovieRequestPlugin) getPendingRequests() ([]MovieRequest, error) {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
-	query := `SELECT pin, requested_by, title, year, upload_date FROM movies WHERE status = 'Nem' ORDER BY upload_date DESC`
-	rows, err := p.db.Query(query)
+	defer observeQuery("keresek", "pending", time.Now())
+	pending, err := p.movies.List(StatusPending)
 	if err != nil {
 		return nil, fmt.Errorf("query error: %v", err)
 	}
-	defer rows.Close()
-
-	var requests []MovieRequest
-	for rows.Next() {
-		var req MovieRequest
-		var uploadDateStr string
-		
-		err := rows.Scan(&req.PIN, &req.RequestedBy, &req.Title, &req.Year, &uploadDateStr)
-		if err != nil {
-			log.Printf("[MovieRequestPlugin] Row scan error: %v", err)
-			continue
-		}
-
-		if uploadDate, err := time.Parse("2006-01-02 15:04:05", uploadDateStr); err == nil {
-			req.UploadDate = uploadDate
-		}
-
-		requests = append(requests, req)
-	}
-
-	return requests, nil
-}
-
-func (p *MovieRequestPlugin) initializeDatabase() error {
-	var err error
-	
-	p.db, err = sql.Open("sqlite3", p.movieDBPath)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
-	}
 
-	if err := p.db.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %v", err)
+	// a legújabb kérés elöl
+	requests := make([]MovieRequest, 0, len(pending))
+	for i := len(pending) - 1; i >= 0; i-- {
+		requests = append(requests, pending[i])
 	}
-
-	var count int
-	err = p.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='movies'").Scan(&count)
-	if err != nil {
-		return fmt.Errorf("failed to check for movies table: %v", err)
-	}
-	
-	if count == 0 {
-		return fmt.Errorf("movies table does not exist")
-	}
-
-	return nil
-}
-
-func (p *MovieRequestPlugin) Close() error {
-	if p.db != nil {
-		return p.db.Close()
-	}
-	return nil
+	return requests, nil
 }
 
 func (p *MovieRequestPlugin) OnTick() []irc.Message {
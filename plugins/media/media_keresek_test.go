@@ -0,0 +1,55 @@
+package media
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestPendingRequests(t *testing.T) {
+	env, adm := admintest.NewEnv(t, nil, staff...)
+	env.Attach(NewMovieRequestPlugin(env.Bot, adm, env.DB.Movies, env.Ctx))
+	hu := i18n.Get("hu")
+
+	steps := []struct {
+		name  string
+		setup func()
+		from  string
+		want  []string
+	}{
+		{"VIP nem láthatja", nil, "vip", nil},
+		{"üres lista", nil, "mod", []string{hu.T("keresek.none")}},
+		{"a legújabb elöl", func() {
+			for _, r := range []struct {
+				title, pin, by string
+				year           int
+			}{{"Dűne", "11111", "alice", 2021}, {"Alien", "22222", "bob", 1979}} {
+				if err := env.DB.Movies.Add(r.title, r.pin, r.by, r.year); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}, "mod", []string{
+			hu.T("keresek.title"),
+			hu.T("keresek.line", "bob", "Alien", 1979, "22222"),
+			hu.T("keresek.line", "alice", "Dűne", 2021, "11111"),
+		}},
+		{"a teljesített nem függő", func() {
+			if _, err := CompleteRequest(env.DB.Movies, "22222"); err != nil {
+				t.Fatal(err)
+			}
+		}, "owner", []string{
+			hu.T("keresek.title"),
+			hu.T("keresek.line", "alice", "Dűne", 2021, "11111"),
+		}},
+	}
+	for _, st := range steps {
+		if st.setup != nil {
+			st.setup()
+		}
+		if got := env.Say(t, st.from, "#test", "!keresek"); !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", st.name, got, st.want)
+		}
+	}
+}
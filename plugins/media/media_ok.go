@@ -1,40 +1,37 @@
 package media
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
 type MovieCompletionPlugin struct {
-	bot         *irc.Client
+	bot         irc.Sender
 	adminPlugin *admin.AdminPlugin
-	db          *sql.DB
+	movies      *storage.MovieRepo
+	tr          pluginapi.Localizer
 	mutex       sync.RWMutex
-	movieDBPath string
 }
 
-func NewMovieCompletionPlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin, movieDBPath string) *MovieCompletionPlugin {
+func NewMovieCompletionPlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, movies *storage.MovieRepo, tr pluginapi.Localizer) *MovieCompletionPlugin {
 	plugin := &MovieCompletionPlugin{
 		bot:         bot,
 		adminPlugin: adminPlugin,
-		movieDBPath: movieDBPath,
+		movies:      movies,
+		tr:          tr,
 	}
 
-	// Initialize database
-	if err := plugin.initializeDatabase(); err != nil {
-		//log.Printf("Failed to initialize movie completion plugin database: %v", err)
-		return nil
-	}
-
-	//log.Printf("MovieCompletionPlugin initialized successfully")
+	mediaLog.Debug("MovieCompletionPlugin initialized successfully")
 	return plugin
 }
 
@@ -65,223 +62,91 @@ func (p *MovieCompletionPlugin) HandleMessage(msg irc.Message) string {
     }
 
     // Naplózzuk csak a tényleges !ok parancsokat
-   // log.Printf("[MovieCompletionPlugin] Command received: '%s' from '%s'", msg.Text, msg.Sender)
+	mediaLog.Debug("Command received", "text", msg.Text, "sender", msg.Sender)
 
     // Feldolgozzuk a parancsot
     text := strings.TrimSpace(msg.Text)
     parts := strings.Fields(text)
+    loc := p.tr.LocaleFor(msg)
     
     if len(parts) == 1 { // Csak !ok
-        return "Használat: !ok <PIN>"
+        return loc.T("ok.usage")
     }
     
     if len(parts) == 2 { // !ok <PIN>
         pin := parts[1]
         if !isValidPIN(pin) {
-            return "Helytelen PIN formátum. 4-6 számjegy szükséges."
+            return loc.T("media.bad_pin")
         }
-        return p.handleMovieCompletion(pin, msg)
+        return p.handleMovieCompletion(loc, pin, msg)
     }
     
-    return "Túl sok paraméter. Használat: !ok <PIN>"
+    return loc.T("media.too_many_args", loc.T("ok.usage"))
 }
 
-func (p *MovieCompletionPlugin) handleMovieCompletion(pin string, msg irc.Message) string {
+func (p *MovieCompletionPlugin) handleMovieCompletion(loc *i18n.Locale, pin string, msg irc.Message) string {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	//log.Printf("[MovieCompletionPlugin] Processing completion for PIN: %s", pin)
-
-	// Get movie details
-	movie, err := p.getMovieByPIN(pin)
-	if err != nil {
-		//log.Printf("[MovieCompletionPlugin] Database error: %v", err)
-		return fmt.Sprintf("Adatbázis hiba: %v", err)
-	}
-
-	if movie == nil {
-		//log.Printf("[MovieCompletionPlugin] Movie not found for PIN: %s", pin)
-		return fmt.Sprintf("Nincs film a(z) %s PIN-hez.", pin)
-	}
-
-		log.Printf("[MovieCompletionPlugin] Found movie: '%s' by %s, status: %s", 
-		movie.Title, movie.RequestedBy, movie.Status)
+	mediaLog.Debug("Processing completion", "pin", pin)
 
-	// Check if already completed
-	if movie.Status == "Igen" {
-		return fmt.Sprintf("A(z) %s PIN már teljesítve lett korábban.", pin)
+	movie, err := CompleteRequest(p.movies, pin)
+	switch {
+	case errors.Is(err, ErrRequestNotFound):
+		mediaLog.Debug("Movie not found", "pin", pin)
+		return loc.T("ok.not_found", pin)
+	case errors.Is(err, ErrAlreadyCompleted):
+		return loc.T("ok.already_done", pin)
+	case err != nil:
+		mediaLog.Error("Error marking as completed", "err", err)
+		return loc.T("media.db_error")
 	}
 
-	// Mark as completed
-	err = p.markMovieAsCompleted(pin)
-	if err != nil {
-		//log.Printf("[MovieCompletionPlugin] Error marking as completed: %v", err)
-		return fmt.Sprintf("Hiba a teljesítés során: %v", err)
-	}
-
-	//log.Printf("[MovieCompletionPlugin] Successfully marked PIN %s as completed", pin)
-	
-	response := fmt.Sprintf("✅ PIN %s teljesítve! Film: '%s' (%d) - Kérő: @%s - Teljesítve: %s", 
-		pin, movie.Title, movie.Year, movie.RequestedBy, time.Now().Format("2006-01-02 15:04:05"))
+	response := loc.T("ok.done", 
+		pin, movie.Title, movie.Year, movie.RequestedBy, loc.DateTime(*movie.CompletedDate))
 	
 	return response
 }
 
-func (p *MovieCompletionPlugin) initializeDatabase() error {
-	var err error
-	
-	//log.Printf("[MovieCompletionPlugin] Opening database: %s", p.movieDBPath)
-	
-	p.db, err = sql.Open("sqlite3", p.movieDBPath)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
-	}
-
-	// Test database connection
-	if err := p.db.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %v", err)
-	}
-
-	// Check if movies table exists
-	var count int
-	err = p.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='movies'").Scan(&count)
-	if err != nil {
-		return fmt.Errorf("failed to check for movies table: %v", err)
-	}
-	
-	if count == 0 {
-		return fmt.Errorf("movies table does not exist")
-	}
-
-	// Try to add completed_date column if it doesn't exist
-	_, err = p.db.Exec("ALTER TABLE movies ADD COLUMN completed_date DATETIME")
-	if err != nil {
-		// Column might already exist, check if it's there
-		rows, err := p.db.Query("PRAGMA table_info(movies)")
-		if err != nil {
-			return fmt.Errorf("failed to get table info: %v", err)
-		}
-		defer rows.Close()
-		
-		hasCompletedDate := false
-		for rows.Next() {
-			var cid int
-			var name, dataType string
-			var notNull, pk int
-			var defaultValue sql.NullString
-			
-			err = rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk)
-			if err != nil {
-				continue
-			}
-			
-			if name == "completed_date" {
-				hasCompletedDate = true
-				break
-			}
-		}
-		
-		if !hasCompletedDate {
-			return fmt.Errorf("failed to add completed_date column: %v", err)
-		}
-	}
-
-	//log.Printf("[MovieCompletionPlugin] Database initialized successfully")
-	return nil
-}
+var (
+	ErrRequestNotFound  = errors.New("nincs ilyen PIN")
+	ErrAlreadyCompleted = errors.New("a kérés már teljesítve van")
+)
 
-func (p *MovieCompletionPlugin) getMovieByPIN(pin string) (*MovieRequest, error) {
-	query := `SELECT id, title, pin, requested_by, year, status, upload_date, completed_date FROM movies WHERE pin = ?`
-	
-	//log.Printf("[MovieCompletionPlugin] Querying database for PIN: %s", pin)
-	
-	row := p.db.QueryRow(query, pin)
-	
-	var movie MovieRequest
-	var uploadDateStr string
-	var completedDateStr sql.NullString
-	
-	err := row.Scan(&movie.ID, &movie.Title, &movie.PIN, &movie.RequestedBy, 
-		&movie.Year, &movie.Status, &uploadDateStr, &completedDateStr)
-	
+// CompleteRequest teljesítettnek jelöli a PIN-hez tartozó kérést. A !ok és a
+// webes kéréslista is ezt hívja; ErrRequestNotFound / ErrAlreadyCompleted a
+// két nem adatbázis eredetű hiba.
+func CompleteRequest(movies *storage.MovieRepo, pin string) (*MovieRequest, error) {
+	mediaLog.Debug("Querying database", "pin", pin)
+	start := time.Now()
+	movie, err := movies.ByPIN(pin)
+	observeQuery("ok", "select", start)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Movie not found
-		}
 		return nil, fmt.Errorf("database scan error: %v", err)
 	}
-
-	// Parse upload date
-	if uploadDate, err := time.Parse("2006-01-02 15:04:05", uploadDateStr); err == nil {
-		movie.UploadDate = uploadDate
+	if movie == nil {
+		return nil, ErrRequestNotFound
 	}
-
-	// Parse completed date
-	if completedDateStr.Valid && completedDateStr.String != "N/A" {
-		if completedDate, err := time.Parse("2006-01-02 15:04:05", completedDateStr.String); err == nil {
-			movie.CompletedDate = &completedDate
-		}
+	log.Printf("[MovieCompletionPlugin] Found movie: '%s' by %s, status: %s",
+		movie.Title, movie.RequestedBy, movie.Status)
+	if movie.Status == StatusCompleted {
+		return movie, ErrAlreadyCompleted
 	}
-
-	return &movie, nil
-}
-
-func (p *MovieCompletionPlugin) markMovieAsCompleted(pin string) error {
-	// Use Go's time formatting to ensure consistent format
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	query := `UPDATE movies SET status = 'Igen', completed_date = ? WHERE pin = ?`
-	
-	//log.Printf("[MovieCompletionPlugin] Updating movie status for PIN: %s with completion date: %s", pin, currentTime)
-	
-	result, err := p.db.Exec(query, currentTime, pin)
+	now := time.Now()
+	start = now
+	updated, err := movies.Complete(pin, now)
+	observeQuery("ok", "update", start)
 	if err != nil {
-		return fmt.Errorf("failed to update movie: %v", err)
+		return nil, fmt.Errorf("failed to update movie: %v", err)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %v", err)
+	if !updated {
+		return nil, fmt.Errorf("no movie found with PIN %s", pin)
 	}
+	mediaLog.Debug("Marked as completed", "pin", pin)
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("no movie found with PIN %s", pin)
-	}
-
-	//log.Printf("[MovieCompletionPlugin] Successfully updated %d row(s)", rowsAffected)
-	return nil
-}
-
-// Add this function to debug what's in the database
-func (p *MovieCompletionPlugin) debugMovieRecord(pin string) {
-	query := `SELECT id, title, pin, requested_by, year, status, upload_date, completed_date FROM movies WHERE pin = ?`
-	
-	row := p.db.QueryRow(query, pin)
-	
-	var id, year int
-	var title, pinDB, requestedBy, status, uploadDate string
-	var completedDate sql.NullString
-	
-	err := row.Scan(&id, &title, &pinDB, &requestedBy, &year, &status, &uploadDate, &completedDate)
-	if err != nil {
-		//log.Printf("[DEBUG] Error scanning row: %v", err)
-		return
-	}
-	
-	//log.Printf("[DEBUG] Movie record - ID: %d, Title: %s, PIN: %s, Status: %s", id, title, pinDB, status)
-	//log.Printf("[DEBUG] Upload Date: %s", uploadDate)
-	//log.Printf("[DEBUG] Completed Date Valid: %v, Value: '%s'", completedDate.Valid, completedDate.String)
-}
-
-// Call this in your handleMovieCompletion function after marking as completed:
-// p.debugMovieRecord(pin)
-
-
-func (p *MovieCompletionPlugin) Close() error {
-	//log.Printf("[MovieCompletionPlugin] Closing database connection")
-	if p.db != nil {
-		return p.db.Close()
-	}
-	return nil
+	movie.Status = StatusCompleted
+	movie.CompletedDate = &now
+	return movie, nil
 }
 
 func (p *MovieCompletionPlugin) OnTick() []irc.Message {
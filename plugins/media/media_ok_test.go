@@ -0,0 +1,50 @@
+package media
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestMovieCompletion(t *testing.T) {
+	env, adm := admintest.NewEnv(t, nil, staff...)
+	env.Attach(NewMovieCompletionPlugin(env.Bot, adm, env.DB.Movies, env.Ctx))
+	hu := i18n.Get("hu")
+	if err := env.DB.Movies.Add("Dűne", "12345", "alice", 2021); err != nil {
+		t.Fatal(err)
+	}
+
+	// a teljesítés ideje a mentett kérésből
+	done := func() string {
+		m, err := env.DB.Movies.ByPIN("12345")
+		if err != nil || m == nil || m.CompletedDate == nil {
+			t.Fatalf("teljesített kérés: %+v, %v", m, err)
+		}
+		return hu.T("ok.done", "12345", "Dűne", 2021, "alice", hu.DateTime(*m.CompletedDate))
+	}
+
+	steps := []struct {
+		name string
+		from string
+		text string
+		want func() []string
+	}{
+		{"VIP nem teljesíthet", "vip", "!ok 12345", func() []string { return nil }},
+		{"PIN nélkül", "mod", "!ok", func() []string { return []string{hu.T("ok.usage")} }},
+		{"hibás PIN", "mod", "!ok 12a", func() []string { return []string{hu.T("media.bad_pin")} }},
+		{"túl sok paraméter", "mod", "!ok 12345 most", func() []string {
+			return []string{hu.T("media.too_many_args", hu.T("ok.usage"))}
+		}},
+		{"ismeretlen PIN", "mod", "!ok 99999", func() []string { return []string{hu.T("ok.not_found", "99999")} }},
+		{"teljesítés", "mod", "!ok 12345", func() []string { return []string{done()} }},
+		{"már teljesítve", "owner", "!ok 12345", func() []string { return []string{hu.T("ok.already_done", "12345")} }},
+	}
+	for _, st := range steps {
+		got := env.Say(t, st.from, "#test", st.text)
+		if want := st.want(); !slices.Equal(got, want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, want)
+		}
+	}
+}
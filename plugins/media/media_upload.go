@@ -4,14 +4,16 @@ package media
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -97,20 +99,25 @@ var (
 )
 
 type MediaUploadPlugin struct {
-	bot        *irc.Client
-	cfg        *config.Config
-	sentDates  []string
+	bot        irc.Sender
+	cfg        atomic.Pointer[config.Config] // ReloadConfig cseréli
+	ctx        *pluginapi.Context // upload.enabled csatornánként
 	lastDate   string
-	ticker     *time.Ticker
-	stopChan   chan struct{}
+	filter     pluginapi.ChannelFilter
+	pause      time.Duration // szünet a bejelentés sorai között
 }
 
-func NewMediaUploadPlugin(bot *irc.Client, cfg *config.Config) *MediaUploadPlugin {
-	return &MediaUploadPlugin{
+// a feltöltés-ellenőrzés feladatneve az ütemezőben
+const mediaUploadJob = "upload"
+
+func NewMediaUploadPlugin(bot irc.Sender, cfg *config.Config, ctx *pluginapi.Context) *MediaUploadPlugin {
+	p := &MediaUploadPlugin{
 		bot:      bot,
-		cfg:      cfg,
-		stopChan: make(chan struct{}),
+		ctx:      ctx,
+		pause:    1 * time.Second,
 	}
+	p.cfg.Store(cfg)
+	return p
 }
 
 func (p *MediaUploadPlugin) Name() string {
@@ -123,62 +130,43 @@ func (p *MediaUploadPlugin) HandleMessage(msg irc.Message) string {
 }
 
 func (p *MediaUploadPlugin) Start() error {
-	if !p.cfg.MediaUpload.Enabled {
+	cfg := p.cfg.Load().MediaUpload
+	if !cfg.Enabled {
 		return nil
 	}
 
-	// Betöltjük a már elküldött dátumokat
-	var err error
-	p.sentDates, err = p.loadSentDates()
-	if err != nil {
-		return err
+	// Ellenőrzés interval_minutes percenként
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return fmt.Errorf("hibás interval_minutes: %d", cfg.IntervalMinutes)
 	}
-
-	// Indítjuk a ticker-t
-	p.ticker = time.NewTicker(time.Duration(p.cfg.MediaUpload.IntervalMinutes) * time.Minute)
-	
-	go func() {
-		for {
-			select {
-			case <-p.ticker.C:
-				p.checkAndSendMedia()
-			case <-p.stopChan:
-				return
-			}
-		}
-	}()
-
-	return nil
+	return p.ctx.Schedule(scheduler.Job{
+		Name:   mediaUploadJob,
+		Spec:   "@every " + interval.String(),
+		Missed: scheduler.MissedSkip,
+		Run: func() error {
+			p.checkAndSendMedia()
+			return nil
+		},
+	})
 }
 
 func (p *MediaUploadPlugin) Stop() {
-	if p.ticker != nil {
-		p.ticker.Stop()
-	}
-	close(p.stopChan)
+	p.ctx.Scheduler.Remove(mediaUploadJob)
 }
 
-func (p *MediaUploadPlugin) loadSentDates() ([]string, error) {
-	data, err := os.ReadFile(p.cfg.MediaUpload.SentDatesFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// Ha a fájl nem létezik, létrehozzuk üres listával
-			_ = p.saveSentDates([]string{})
-			return []string{}, nil
-		}
-		return nil, err
+// ReloadConfig az új media_upload beállításokkal (időköz, fájlok, be/ki) újraindítja az ellenőrzést
+func (p *MediaUploadPlugin) ReloadConfig(ev pluginapi.ReloadEvent) error {
+	if !ev.Changes.Has("media_upload") {
+		return nil
 	}
-	var dates []string
-	err = json.Unmarshal(data, &dates)
-	return dates, err
+	p.Stop()
+	p.cfg.Store(ev.New)
+	return p.Start()
 }
 
-func (p *MediaUploadPlugin) saveSentDates(dates []string) error {
-	data, err := json.Marshal(dates)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(p.cfg.MediaUpload.SentDatesFile, data, 0644)
+func (p *MediaUploadPlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
 }
 
 func (p *MediaUploadPlugin) checkAndSendMedia() {
@@ -187,27 +175,33 @@ func (p *MediaUploadPlugin) checkAndSendMedia() {
 		return
 	}
 
+	// a már bejelentett feltöltések a DateCreated értékük szerint
 	created := strings.Split(m.DateCreated, ".")[0]
-	if p.contains(p.sentDates, created) {
+	if sent, err := p.ctx.Storage.Uploads.Sent(created); err != nil || sent {
 		return
 	}
 
-	// Üzenetek küldése
-	for _, msg := range p.FormatMediaMessage(m) {
-		for _, ch := range p.cfg.MediaUpload.Channels {
+	// Üzenetek küldése, csatornánként annak nyelvén
+	for _, ch := range p.ctx.Channels("upload.enabled") {
+		if !p.filter.Allows(ch) {
+			continue
+		}
+		for _, msg := range p.FormatMediaMessage(p.ctx.Locale(ch), m) {
 			p.bot.SendMessage(ch, msg)
-			time.Sleep(1 * time.Second)
+			time.Sleep(p.pause)
 		}
 	}
+	uploadAnnouncements.Inc(m.MediaType)
 
 	// Dátum hozzáadása a küldött listához
-	p.sentDates = append(p.sentDates, created)
-	_ = p.saveSentDates(p.sentDates)
+	if err := p.ctx.Storage.Uploads.MarkSent(created); err != nil {
+		mediaLog.Error("Upload mentési hiba", "err", err)
+	}
 	p.lastDate = m.DateCreated
 }
 
 func (p *MediaUploadPlugin) getLatestMedia() (*MediaItem, error) {
-	db, err := sql.Open("sqlite3", p.cfg.MediaUpload.JellyfinDB)
+	db, err := sql.Open("sqlite3", p.cfg.Load().MediaUpload.JellyfinDB)
 	if err != nil {
 		return nil, err
 	}
@@ -226,6 +220,7 @@ func (p *MediaUploadPlugin) getLatestMedia() (*MediaItem, error) {
 		ORDER BY i.DateCreated DESC
 		LIMIT 1`
 
+	defer observeQuery("upload", "latest", time.Now())
 	row := db.QueryRow(query)
 	var m MediaItem
 	if err := row.Scan(&m.Title, &m.Genres, &m.Overview, &m.RuntimeTicks, &m.ProductionYear, &m.DateCreated, &m.Path, &m.MediaType); err != nil {
@@ -234,7 +229,7 @@ func (p *MediaUploadPlugin) getLatestMedia() (*MediaItem, error) {
 	return &m, nil
 }
 
-func (p *MediaUploadPlugin) FormatMediaMessage(m *MediaItem) []string {
+func (p *MediaUploadPlugin) FormatMediaMessage(loc *i18n.Locale, m *MediaItem) []string {
 	parts := strings.Split(m.Path, "/")
 	basePath := m.Path
 	if len(parts) >= 4 {
@@ -256,13 +251,19 @@ func (p *MediaUploadPlugin) FormatMediaMessage(m *MediaItem) []string {
 	}
 
 	created := strings.Split(m.DateCreated, ".")[0]
-	mediaLabel := map[string]string{"Movie": "Film", "Series": "Sorozat"}[m.MediaType]
+	if t, err := time.Parse("2006-01-02 15:04:05", created); err == nil {
+		created = loc.DateTime(t)
+	}
+	mediaLabel := ""
+	if m.MediaType == "Movie" || m.MediaType == "Series" {
+		mediaLabel = loc.T("upload.type." + m.MediaType)
+	}
 
 	return []string{
-		fmt.Sprintf(" 「 ✦ %s ✦ 」 | 🎭: %s", m.Title, m.Genres),
-		fmt.Sprintf("👆: %s | 📂: %s %s", created, custom, mediaLabel),
-		fmt.Sprintf("⏰: %s | 📅: %d 🎥", runtime, m.ProductionYear),
-		fmt.Sprintf("📝: %s", overview),
+		loc.T("upload.title", m.Title, m.Genres),
+		loc.T("upload.created", created, custom, mediaLabel),
+		loc.T("upload.runtime", runtime, m.ProductionYear),
+		loc.T("upload.overview", overview),
 	}
 }
 
@@ -278,16 +279,6 @@ func (p *MediaUploadPlugin) parseRuntimeTicks(ticks any) (string, error) {
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s), nil
 }
 
-func (p *MediaUploadPlugin) contains(list []string, item string) bool {
-	for _, v := range list {
-		if v == item {
-			return true
-		}
-	}
-	return false
-}
-
-
 func (p *MediaUploadPlugin) OnTick() []irc.Message {
     return nil
 }
@@ -0,0 +1,74 @@
+package media
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestMediaUpload(t *testing.T) {
+	jellyfin := jellyfinDB(t, jellyfinItem{typ: movieType, name: "Alien", overview: "Űrhajó, idegen.",
+		path: "/media/f1/r/alien.mkv", created: "2025-06-02 07:00:00.0000000", genres: "Sci-Fi",
+		ticks: int64(117*time.Minute) / 100, year: 1979})
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) {
+		cfg.MediaUpload.Enabled, cfg.MediaUpload.Channels = true, []string{"#test"}
+		cfg.MediaUpload.IntervalMinutes, cfg.MediaUpload.JellyfinDB = 5, jellyfin
+	}, staff...)
+	p := NewMediaUploadPlugin(env.Bot, env.Config, env.Ctx)
+	p.pause = 0
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Stop)
+	hu := i18n.Get("hu")
+
+	announce := func(title, genres, created, custom, kind, runtime string, year int, overview string) []string {
+		return []string{
+			"#test: " + hu.T("upload.title", title, genres),
+			"#test: " + hu.T("upload.created", created, custom, kind),
+			"#test: " + hu.T("upload.runtime", runtime, year),
+			"#test: " + hu.T("upload.overview", overview),
+		}
+	}
+	addSeries := func() {
+		db, err := sql.Open("sqlite3", jellyfin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(`INSERT INTO TypedBaseItems VALUES (?, 'Dűne', 'Dűne', '', NULL, ?, '/media/f/Series/dune', '2025-06-02 08:07:00', 'Dráma', 2024)`,
+			seriesType, strings.Repeat("Homok. ", 60)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name    string
+		setup   func()
+		advance time.Duration
+		want    []string
+	}{
+		{"intervallum előtt", nil, 4 * time.Minute, nil},
+		{"új film", nil, time.Minute, announce("Alien", "Sci-Fi", hu.DateTime(time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)),
+			"✅ 2022-ig 📼 📺", hu.T("upload.type.Movie"), "01:57:00", 1979, "Űrhajó, idegen.")},
+		{"már bejelentve", nil, 5 * time.Minute, nil},
+		{"új sorozat, rövidített leírással", addSeries, 5 * time.Minute, announce("Dűne", "Dráma",
+			hu.DateTime(time.Date(2025, 6, 2, 8, 7, 0, 0, time.UTC)), "✅ Sorozatok 🍿 📺", hu.T("upload.type.Series"), "", 2024,
+			strings.TrimSpace(strings.Repeat("Homok. ", 50)))},
+	}
+	for _, st := range steps {
+		if st.setup != nil {
+			st.setup()
+		}
+		got := env.Tick(t, st.advance)
+		if !slices.Equal(got, st.want) {
+			t.Errorf("%s:\n%q\nvárt:\n%q", st.name, got, st.want)
+		}
+	}
+}
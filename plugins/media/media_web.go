@@ -0,0 +1,432 @@
+package media
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/httpapi"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+// a kérések állapota a weboldalon és az exportban
+const (
+	statePending   = "pending"
+	stateCompleted = "completed"
+	stateRejected  = "rejected"
+)
+
+// RequestsWeb a filmkérések listája a bot HTTP szerverén: HTML oldal (/media),
+// JSON és CSV export, az adminoknak teljesítés (!ok) és törlés (!del)
+type RequestsWeb struct {
+	movies *storage.MovieRepo
+
+	// admin a kérést küldő admin neve (munkamenet vagy API token alapján)
+	admin func(r *http.Request) (string, bool)
+	// announce a webes műveletek IRC bejelentése a csatorna nyelvén (nil: nincs)
+	announce func(text func(loc *i18n.Locale) string)
+}
+
+// NewRequestsWeb a közös adatbázis filmkéréseit mutatja; admin dönti el, ki módosíthat
+func NewRequestsWeb(movies *storage.MovieRepo, admin func(*http.Request) (string, bool), announce func(func(*i18n.Locale) string)) *RequestsWeb {
+	return &RequestsWeb{movies: movies, admin: admin, announce: announce}
+}
+
+// WebRequest egy kérés az exportban
+type WebRequest struct {
+	PIN         string     `json:"pin"`
+	Title       string     `json:"title"`
+	Year        int        `json:"year"`
+	RequestedBy string     `json:"requested_by"`
+	State       string     `json:"state"` // pending, completed, rejected
+	Requested   *time.Time `json:"requested,omitempty"`
+	Completed   *time.Time `json:"completed,omitempty"`
+}
+
+// requestFilter a lista szűrése és rendezése (?q=, ?state=, ?sort=, ?order=)
+type requestFilter struct {
+	Query string
+	State string
+	Sort  string // date, title, year, requester, pin, completed
+	Desc  bool
+}
+
+func parseFilter(q url.Values) requestFilter {
+	f := requestFilter{Query: strings.TrimSpace(q.Get("q")), State: q.Get("state"), Sort: q.Get("sort")}
+	switch f.State {
+	case statePending, stateCompleted, stateRejected:
+	default:
+		f.State = ""
+	}
+	switch f.Sort {
+	case "title", "year", "requester", "pin", "completed":
+		f.Desc = q.Get("order") == "desc"
+	default:
+		f.Sort = "date"
+		f.Desc = q.Get("order") != "asc" // a legújabb elöl
+	}
+	return f
+}
+
+// values a szűrő URL paraméterei (az export linkekhez és a rendezéshez)
+func (f requestFilter) values(sortBy string, desc bool) template.URL {
+	v := url.Values{}
+	if f.Query != "" {
+		v.Set("q", f.Query)
+	}
+	if f.State != "" {
+		v.Set("state", f.State)
+	}
+	v.Set("sort", sortBy)
+	if desc {
+		v.Set("order", "desc")
+	} else {
+		v.Set("order", "asc")
+	}
+	return template.URL(v.Encode())
+}
+
+// ListRequests az összes kérés
+func ListRequests(movies *storage.MovieRepo) ([]WebRequest, error) {
+	defer observeQuery("web", "list", time.Now())
+	all, err := movies.List("")
+	if err != nil {
+		return nil, fmt.Errorf("query error: %v", err)
+	}
+
+	list := make([]WebRequest, 0, len(all))
+	for _, m := range all {
+		r := WebRequest{
+			PIN:         m.PIN,
+			Title:       m.Title,
+			Year:        m.Year,
+			RequestedBy: m.RequestedBy,
+			State:       requestState(m.Status),
+			Completed:   m.CompletedDate,
+		}
+		if !m.UploadDate.IsZero() {
+			requested := m.UploadDate
+			r.Requested = &requested
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+func requestState(status string) string {
+	switch status {
+	case StatusCompleted:
+		return stateCompleted
+	case StatusRejected:
+		return stateRejected
+	}
+	return statePending
+}
+
+// apply a szűrt és rendezett lista, valamint az állapotonkénti darabszámok
+func (f requestFilter) apply(all []WebRequest) ([]WebRequest, map[string]int) {
+	counts := map[string]int{}
+	needle := strings.ToLower(f.Query)
+	var out []WebRequest
+	for _, r := range all {
+		if needle != "" && !strings.Contains(strings.ToLower(r.Title+" "+r.RequestedBy+" "+r.PIN+" "+strconv.Itoa(r.Year)), needle) {
+			continue
+		}
+		counts[r.State]++
+		counts[""]++
+		if f.State == "" || r.State == f.State {
+			out = append(out, r)
+		}
+	}
+
+	less := func(a, b WebRequest) bool {
+		switch f.Sort {
+		case "title":
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case "year":
+			return a.Year < b.Year
+		case "requester":
+			return strings.ToLower(a.RequestedBy) < strings.ToLower(b.RequestedBy)
+		case "pin":
+			return a.PIN < b.PIN
+		case "completed":
+			return timeValue(a.Completed).Before(timeValue(b.Completed))
+		}
+		return timeValue(a.Requested).Before(timeValue(b.Requested))
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if f.Desc {
+			return less(out[j], out[i])
+		}
+		return less(out[i], out[j])
+	})
+	return out, counts
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func (w *RequestsWeb) list(r *http.Request) ([]WebRequest, map[string]int, requestFilter, error) {
+	f := parseFilter(r.URL.Query())
+	all, err := ListRequests(w.movies)
+	if err != nil {
+		mediaLog.Error("Web list error", "err", err)
+		return nil, nil, f, err
+	}
+	list, counts := f.apply(all)
+	return list, counts, f, nil
+}
+
+// ServeJSON: GET /media/requests.json
+func (w *RequestsWeb) ServeJSON(rw http.ResponseWriter, r *http.Request) {
+	list, _, _, err := w.list(r)
+	if err != nil {
+		http.Error(rw, "adatbázis hiba", http.StatusInternalServerError)
+		return
+	}
+	if list == nil {
+		list = []WebRequest{}
+	}
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", "  ")
+	enc.Encode(list)
+}
+
+// ServeCSV: GET /media/requests.csv
+func (w *RequestsWeb) ServeCSV(rw http.ResponseWriter, r *http.Request) {
+	list, _, _, err := w.list(r)
+	if err != nil {
+		http.Error(rw, "adatbázis hiba", http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	rw.Header().Set("Content-Disposition", `attachment; filename="filmkeresek.csv"`)
+	cw := csv.NewWriter(rw)
+	cw.Write([]string{"pin", "title", "year", "requested_by", "state", "requested", "completed"})
+	for _, req := range list {
+		cw.Write([]string{req.PIN, req.Title, strconv.Itoa(req.Year), req.RequestedBy, req.State,
+			formatWebTime(req.Requested), formatWebTime(req.Completed)})
+	}
+	cw.Flush()
+}
+
+func formatWebTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// ServePage: GET /media
+func (w *RequestsWeb) ServePage(rw http.ResponseWriter, r *http.Request) {
+	list, counts, f, err := w.list(r)
+	if err != nil {
+		http.Error(rw, "adatbázis hiba", http.StatusInternalServerError)
+		return
+	}
+	admin, _ := w.admin(r)
+	data := struct {
+		Requests []WebRequest
+		Counts   map[string]int
+		Filter   requestFilter
+		Admin    string
+	}{list, counts, f, admin}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := requestsPage.Execute(rw, data); err != nil {
+		mediaLog.Error("Web page render error", "err", err)
+	}
+}
+
+// Complete: POST /media/requests/{pin}/complete (csak adminnak)
+func (w *RequestsWeb) Complete(r *http.Request) (interface{}, error) {
+	admin, pin, err := w.action(r)
+	if err != nil {
+		return nil, err
+	}
+	movie, err := CompleteRequest(w.movies, pin)
+	switch {
+	case errors.Is(err, ErrRequestNotFound):
+		return nil, httpapi.Errorf(http.StatusNotFound, "nincs film a(z) %s PIN-hez", pin)
+	case errors.Is(err, ErrAlreadyCompleted):
+		return nil, httpapi.Errorf(http.StatusConflict, "a(z) %s PIN már teljesítve lett korábban", pin)
+	case err != nil:
+		mediaLog.Error("Web complete error", "pin", pin, "err", err)
+		return nil, err
+	}
+	log.Printf("✅ Web: PIN %s teljesítve (%s) – %s", pin, admin, movie.Title)
+	w.notify(func(loc *i18n.Locale) string {
+		return loc.T("web.done", pin, movie.Title, movie.Year, movie.RequestedBy, admin)
+	})
+	return map[string]interface{}{"ok": true, "pin": pin, "title": movie.Title}, nil
+}
+
+// Delete: DELETE /media/requests/{pin} (csak adminnak)
+func (w *RequestsWeb) Delete(r *http.Request) (interface{}, error) {
+	admin, pin, err := w.action(r)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := DeleteRequest(w.movies, pin)
+	if err != nil {
+		mediaLog.Error("Web delete error", "pin", pin, "err", err)
+		return nil, err
+	}
+	if !deleted {
+		return nil, httpapi.Errorf(http.StatusNotFound, "nincs film a(z) %s PIN-hez", pin)
+	}
+	log.Printf("✅ Web: PIN %s törölve (%s)", pin, admin)
+	w.notify(func(loc *i18n.Locale) string {
+		return loc.T("web.deleted", pin, admin)
+	})
+	return map[string]interface{}{"ok": true, "pin": pin}, nil
+}
+
+// action a módosító kérések közös ellenőrzése: admin és érvényes PIN
+func (w *RequestsWeb) action(r *http.Request) (string, string, error) {
+	admin, ok := w.admin(r)
+	if !ok {
+		return "", "", httpapi.Errorf(http.StatusUnauthorized, "bejelentkezés szükséges (!weblogin)")
+	}
+	pin := r.PathValue("pin")
+	if !isValidPIN(pin) {
+		return "", "", httpapi.Errorf(http.StatusBadRequest, "helytelen PIN formátum, 4-6 számjegy szükséges")
+	}
+	return admin, pin, nil
+}
+
+func (w *RequestsWeb) notify(text func(loc *i18n.Locale) string) {
+	if w.announce != nil {
+		w.announce(text)
+	}
+}
+
+var stateNames = map[string]string{
+	"":             "Összes",
+	statePending:   "Függőben",
+	stateCompleted: "Teljesítve",
+	stateRejected:  "Elutasítva",
+}
+
+var requestsPage = template.Must(template.New("media").Funcs(template.FuncMap{
+	"stateName": func(s string) string { return stateNames[s] },
+	"date":      formatWebTime,
+	"sortURL": func(f requestFilter, col string) template.URL {
+		desc := col == "date" || col == "completed" // az időpontok alapból csökkenőek
+		if f.Sort == col {
+			desc = !f.Desc
+		}
+		return f.values(col, desc)
+	},
+	"stateURL": func(f requestFilter, state string) template.URL {
+		f.State = state
+		return f.values(f.Sort, f.Desc)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="hu">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>YnM Media – filmkérések</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 1100px; padding: 0 1em; color: #222; }
+h1 { font-size: 1.5em; }
+nav a { margin-right: 1em; }
+nav a.active { font-weight: bold; text-decoration: none; color: #222; }
+form.search { margin: 1em 0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: .4em .6em; border-bottom: 1px solid #ddd; text-align: left; }
+th a { color: inherit; }
+tr.completed td { color: #2a7a2a; }
+tr.rejected td { color: #999; text-decoration: line-through; }
+.muted { color: #888; font-size: .9em; }
+button { cursor: pointer; }
+</style>
+</head>
+<body>
+<h1>🎬 YnM Media – filmkérések</h1>
+<nav>
+{{- $f := .Filter}}{{$counts := .Counts}}
+<a href="?{{stateURL $f ""}}"{{if eq $f.State ""}} class="active"{{end}}>{{stateName ""}} ({{index $counts ""}})</a>
+<a href="?{{stateURL $f "pending"}}"{{if eq $f.State "pending"}} class="active"{{end}}>{{stateName "pending"}} ({{index $counts "pending"}})</a>
+<a href="?{{stateURL $f "completed"}}"{{if eq $f.State "completed"}} class="active"{{end}}>{{stateName "completed"}} ({{index $counts "completed"}})</a>
+<a href="?{{stateURL $f "rejected"}}"{{if eq $f.State "rejected"}} class="active"{{end}}>{{stateName "rejected"}} ({{index $counts "rejected"}})</a>
+</nav>
+<form class="search" method="get">
+<input type="search" name="q" value="{{$f.Query}}" placeholder="Cím, kérő, PIN, év…">
+{{if $f.State}}<input type="hidden" name="state" value="{{$f.State}}">{{end}}
+<button type="submit">Keresés</button>
+<span class="muted">Export: <a href="media/requests.json?{{stateURL $f $f.State}}">JSON</a> · <a href="media/requests.csv?{{stateURL $f $f.State}}">CSV</a></span>
+</form>
+<table>
+<thead><tr>
+<th><a href="?{{sortURL $f "pin"}}">PIN</a></th>
+<th><a href="?{{sortURL $f "title"}}">Cím</a></th>
+<th><a href="?{{sortURL $f "year"}}">Év</a></th>
+<th><a href="?{{sortURL $f "requester"}}">Kérő</a></th>
+<th><a href="?{{sortURL $f "date"}}">Kérve</a></th>
+<th><a href="?{{sortURL $f "completed"}}">Teljesítve</a></th>
+<th>Állapot</th>
+{{if .Admin}}<th></th>{{end}}
+</tr></thead>
+<tbody>
+{{- $admin := .Admin}}
+{{range .Requests}}
+<tr class="{{.State}}">
+<td>{{.PIN}}</td><td>{{.Title}}</td><td>{{.Year}}</td><td>@{{.RequestedBy}}</td>
+<td>{{date .Requested}}</td><td>{{date .Completed}}</td><td>{{stateName .State}}</td>
+{{if $admin}}<td>
+{{if eq .State "pending"}}<button data-pin="{{.PIN}}" data-action="complete">✅ Teljesítve</button>{{end}}
+<button data-pin="{{.PIN}}" data-action="delete">🗑 Törlés</button>
+</td>{{end}}
+</tr>
+{{else}}
+<tr><td colspan="8" class="muted">Nincs a szűrésnek megfelelő kérés.</td></tr>
+{{end}}
+</tbody>
+</table>
+<p class="muted">
+{{if .Admin}}Bejelentkezve: {{.Admin}} · <a href="#" id="logout">Kilépés</a>
+{{else}}Adminként a bot privátjában kért <code>!weblogin</code> linkkel lehet belépni.{{end}}
+</p>
+{{if .Admin}}
+<script>
+async function call(method, url) {
+  const resp = await fetch(url, {method, headers: {"X-Requested-With": "ynm"}, credentials: "same-origin"});
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) { alert(body.error || resp.statusText); return false; }
+  return true;
+}
+document.querySelectorAll("button[data-pin]").forEach(btn => btn.addEventListener("click", async () => {
+  const pin = btn.dataset.pin;
+  const complete = btn.dataset.action === "complete";
+  if (!confirm((complete ? "Teljesíted" : "Törlöd") + " a(z) " + pin + " PIN-t?")) return;
+  const ok = complete ? await call("POST", "media/requests/" + pin + "/complete")
+                      : await call("DELETE", "media/requests/" + pin);
+  if (ok) location.reload();
+}));
+document.getElementById("logout").addEventListener("click", async e => {
+  e.preventDefault();
+  await fetch("logout", {method: "POST", headers: {"X-Requested-With": "ynm"}, credentials: "same-origin"});
+  location.reload();
+});
+</script>
+{{end}}
+</body>
+</html>
+`))
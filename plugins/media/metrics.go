@@ -0,0 +1,19 @@
+package media
+
+import (
+	"time"
+
+	"github.com/ynmhu/YnM-Go/metrics"
+)
+
+var (
+	dbQueryDuration = metrics.NewHistogram("ynm_media_db_query_duration_seconds",
+		"A media pluginok adatbázis-lekérdezéseinek ideje (a bot adatbázisa és a Jellyfin).", nil, "plugin", "query")
+	uploadAnnouncements = metrics.NewCounter("ynm_media_upload_announcements_total",
+		"A MediaUploadPlugin által bejelentett új tartalmak, típusonként.", "type")
+)
+
+// observeQuery a start óta eltelt időt rögzíti; használat: defer observeQuery("kell", "insert", time.Now())
+func observeQuery(plugin, query string, start time.Time) {
+	dbQueryDuration.ObserveSince(start, plugin, query)
+}
@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/storage"
 )
 
 // TamagotchiAllapot a kisállat aktuális életállapotát reprezentálja
@@ -43,6 +44,14 @@ func (a TamagotchiAllapot) String() string {
 	}
 }
 
+// Felirat az életállapot neve loc nyelvén
+func (a TamagotchiAllapot) Felirat(loc *i18n.Locale) string {
+	if a < AllapotTojas || a > AllapotHalott {
+		return loc.T("tama.stage.unknown")
+	}
+	return loc.T(fmt.Sprintf("tama.stage.%d", int(a)))
+}
+
 // Tamagotchi egy virtuális kisállatot reprezentál
 type Tamagotchi struct {
 	Nev         string            `json:"nev"`
@@ -189,13 +198,13 @@ func (t *Tamagotchi) Frissit() {
 }
 
 // Etet megéteti a kisállatot
-func (t *Tamagotchi) Etet() string {
+func (t *Tamagotchi) Etet(loc *i18n.Locale) string {
 	if t.Allapot == AllapotHalott {
-		return "💀 Nem etethetsz egy halott kisállatot!"
+		return loc.T("tama.feed_dead")
 	}
 	
 	if t.Ehseg >= 90 {
-		return fmt.Sprintf("%s %s már jól lakott!", t.Emoji(), t.Nev)
+		return loc.T("tama.feed_full", t.Emoji(), t.Nev)
 	}
 	
 	t.Ehseg += 30
@@ -204,17 +213,17 @@ func (t *Tamagotchi) Etet() string {
 	}
 	t.UtoljaraEtrek = time.Now()
 	
-	return fmt.Sprintf("🍎 Megetetted %s-t! Éhség: %d/100", t.Nev, t.Ehseg)
+	return loc.T("tama.fed", t.Nev, t.Ehseg)
 }
 
 // Jatszik játszik a kisállattal
-func (t *Tamagotchi) Jatszik() string {
+func (t *Tamagotchi) Jatszik(loc *i18n.Locale) string {
 	if t.Allapot == AllapotHalott {
-		return "💀 Nem játszhatsz egy halott kisállattal!"
+		return loc.T("tama.play_dead")
 	}
 	
 	if t.Boldogsag >= 90 {
-		return fmt.Sprintf("%s %s már nagyon boldog!", t.Emoji(), t.Nev)
+		return loc.T("tama.play_happy", t.Emoji(), t.Nev)
 	}
 	
 	t.Boldogsag += 25
@@ -223,20 +232,19 @@ func (t *Tamagotchi) Jatszik() string {
 	}
 	t.UtoljaraJatszott = time.Now()
 	
-	jatekok := []string{"labdázás", "bújócska", "frizbi", "futócska", "kutyusozás"}
-	jatek := jatekok[rand.Intn(len(jatekok))]
+	jatek := loc.T(fmt.Sprintf("tama.game.%d", rand.Intn(5)))
 	
-	return fmt.Sprintf("🎮 Játszottál %s-t %s-val! Boldogság: %d/100", jatek, t.Nev, t.Boldogsag)
+	return loc.T("tama.played", jatek, t.Nev, t.Boldogsag)
 }
 
 // Tisztit megtisztítja a kisállatot
-func (t *Tamagotchi) Tisztit() string {
+func (t *Tamagotchi) Tisztit(loc *i18n.Locale) string {
 	if t.Allapot == AllapotHalott {
-		return "💀 Nem tisztíthatsz egy halott kisállatot!"
+		return loc.T("tama.clean_dead")
 	}
 	
 	if t.Tisztasag >= 90 {
-		return fmt.Sprintf("%s %s már tiszta!", t.Emoji(), t.Nev)
+		return loc.T("tama.clean_already", t.Emoji(), t.Nev)
 	}
 	
 	t.Tisztasag += 40
@@ -245,38 +253,38 @@ func (t *Tamagotchi) Tisztit() string {
 	}
 	t.UtoljaraTisztitva = time.Now()
 	
-	return fmt.Sprintf("🧼 Megtisztítottad %s-t! Tisztaság: %d/100", t.Nev, t.Tisztasag)
+	return loc.T("tama.cleaned", t.Nev, t.Tisztasag)
 }
 
 // AllapotJelentes visszaadja a kisállat aktuális állapotjelentését
-func (t *Tamagotchi) AllapotJelentes() string {
+func (t *Tamagotchi) AllapotJelentes(loc *i18n.Locale) string {
 	if t.Allapot == AllapotHalott {
-		return fmt.Sprintf("💀 %s meghalt %d órás korában. Nyugodjék békében 😢", t.Nev, t.Kor)
+		return loc.N("tama.report_dead", t.Kor, t.Nev)
 	}
 	
-	jelentes := fmt.Sprintf("%s **%s** (%s) - Kor: %d óra\n", t.Emoji(), t.Nev, t.Allapot, t.Kor)
-	jelentes += fmt.Sprintf("❤️ Egészség: %d/100 | � Éhség: %d/100 | 😊 Boldogság: %d/100 | 🧼 Tisztaság: %d/100\n", 
-		t.Egeszseg, t.Ehseg, t.Boldogsag, t.Tisztasag)
+	jelentes := loc.N("tama.report_title", t.Kor, t.Emoji(), t.Nev, t.Allapot.Felirat(loc)) + "\n"
+	jelentes += loc.T("tama.report_stats", 
+		t.Egeszseg, t.Ehseg, t.Boldogsag, t.Tisztasag) + "\n"
 	
 	// Állapotüzenetek hozzáadása
 	var uzenetek []string
 	if t.Egeszseg < 30 {
-		uzenetek = append(uzenetek, "🤒 Betegnek érzi magát")
+		uzenetek = append(uzenetek, loc.T("tama.sick"))
 	}
 	if t.Ehseg < 30 {
-		uzenetek = append(uzenetek, "😫 Nagyon éhes")
+		uzenetek = append(uzenetek, loc.T("tama.hungry"))
 	}
 	if t.Boldogsag < 30 {
-		uzenetek = append(uzenetek, "😢 Szomorú")
+		uzenetek = append(uzenetek, loc.T("tama.sad"))
 	}
 	if t.Tisztasag < 30 {
-		uzenetek = append(uzenetek, "🤢 Nagyon piszkos")
+		uzenetek = append(uzenetek, loc.T("tama.dirty"))
 	}
 	
 	if len(uzenetek) > 0 {
-		jelentes += "Állapot: " + strings.Join(uzenetek, ", ")
+		jelentes += loc.T("tama.report_state", strings.Join(uzenetek, ", "))
 	} else {
-		jelentes += "Állapot: 😊 Nagyon jól érzi magát!"
+		jelentes += loc.T("tama.report_state", loc.T("tama.fine"))
 	}
 	
 	return jelentes
@@ -286,18 +294,20 @@ func (t *Tamagotchi) AllapotJelentes() string {
 type TamagotchiPlugin struct {
 	aktiv       bool
 	kisallatok  map[string]*Tamagotchi // csatorna -> kisállat leképezés
-	adatKonyvtar string
+	tarolo      *storage.PetRepo // csatornánként egy JSON sor a közös adatbázisban
 	utolsoFrissites time.Time
-	bot         *irc.Client
+	bot         irc.Sender
+	tr          pluginapi.Localizer // a válaszok nyelve
 }
 
-func NewTamagotchiPlugin(adatKonyvtar string, bot *irc.Client) *TamagotchiPlugin {
+func NewTamagotchiPlugin(tarolo *storage.PetRepo, bot irc.Sender, tr pluginapi.Localizer) *TamagotchiPlugin {
     return &TamagotchiPlugin{
         aktiv:      true,
         kisallatok: make(map[string]*Tamagotchi),
-        adatKonyvtar: adatKonyvtar,
+        tarolo:     tarolo,
         utolsoFrissites: time.Now(),
         bot:        bot, // Bot referencia hozzáadva
+        tr:         tr,
     }
 }
 
@@ -305,12 +315,7 @@ func (p *TamagotchiPlugin) Nev() string {
 	return "TamagotchiPlugin"
 }
 
-func (p *TamagotchiPlugin) Initialize(bot *irc.Client, config *config.Config) error {
-	// Adatkönyvtár létrehozása, ha nem létezik
-	if err := os.MkdirAll(p.adatKonyvtar, 0755); err != nil {
-		return err
-	}
-	
+func (p *TamagotchiPlugin) Initialize(bot irc.Sender, config *config.Config) error {
 	// Meglévő kisállatok betöltése
 	return p.kisallatokBetoltese()
 }
@@ -331,135 +336,136 @@ func (p *TamagotchiPlugin) HandleMessage(uzenet irc.Message) string {
     
     switch parancs {
     case "!kisallat", "!tamagotchi":
+        loc := p.tr.LocaleFor(uzenet)
         if len(reszek) < 2 {
-            return p.segitoSzoveg()
+            return p.segitoSzoveg(loc)
         }
         
         alparancs := strings.ToLower(reszek[1])
         switch alparancs {
         case "uj", "letrehoz":
             if len(reszek) < 3 {
-                return "Használat: !kisallat uj <név>"
+                return loc.T("tama.usage_new")
             }
             nev := strings.Join(reszek[2:], " ")
-            return p.kisallatLetrehozasValasz(uzenet.Channel, nev, uzenet.Nick)
+            return p.kisallatLetrehozasValasz(loc, uzenet.Channel, nev, uzenet.Nick)
             
         case "allapot", "status":
-            return p.allapotValasz(uzenet.Channel)
+            return p.allapotValasz(loc, uzenet.Channel)
             
         case "etet":
-            return p.etetValasz(uzenet.Channel, uzenet.Nick)
+            return p.etetValasz(loc, uzenet.Channel, uzenet.Nick)
             
         case "jatszik":
-            return p.jatszikValasz(uzenet.Channel, uzenet.Nick)
+            return p.jatszikValasz(loc, uzenet.Channel, uzenet.Nick)
             
         case "tisztit":
-            return p.tisztitValasz(uzenet.Channel, uzenet.Nick)
+            return p.tisztitValasz(loc, uzenet.Channel, uzenet.Nick)
             
         case "segitség":
-            return p.segitoSzoveg()
+            return p.segitoSzoveg(loc)
             
         default:
-            return p.segitoSzoveg()
+            return p.segitoSzoveg(loc)
         }
     }
     
     return ""
 }
-func (p *TamagotchiPlugin) kisallatLetrehozasValasz(csatorna, nev, tulajdonos string) string {
+func (p *TamagotchiPlugin) kisallatLetrehozasValasz(loc *i18n.Locale, csatorna, nev, tulajdonos string) string {
 	//fmt.Printf("[DEBUG] kisallatLetrehozasValasz: tulajdonos = %q\n", tulajdonos)
 	if _, letezik := p.kisallatok[csatorna]; letezik {
-		return fmt.Sprintf("Már van kisállat ebben a csatornában! Használd a '!kisallat allapot' parancsot, hogy megnézd %s állapotát", p.kisallatok[csatorna].Nev)
+		return loc.T("tama.exists", p.kisallatok[csatorna].Nev)
 	}
 	
 	if len(nev) > 20 {
-		return "A kisállat neve túl hosszú! Maximum 20 karakter lehet."
+		return loc.T("tama.name_too_long", 20)
 	}
 	
 	kisallat := UjTamagotchi(nev, tulajdonos)
 	p.kisallatok[csatorna] = kisallat
 	p.kisallatokMentese()
 	
-	return fmt.Sprintf("🥚 %s létrehozott egy új kisállatot %s néven! Hamarosan kikel... Használd a '!kisallat segitség' parancsot a lehetőségek megtekintéséhez.", tulajdonos, nev)
+	return loc.T("tama.created", tulajdonos, nev)
 }
 
-func (p *TamagotchiPlugin) allapotValasz(csatorna string) string {
+func (p *TamagotchiPlugin) allapotValasz(loc *i18n.Locale, csatorna string) string {
     kisallat, letezik := p.kisallatok[csatorna]
     if !letezik {
-        return "Nincs kisállat ebben a csatornában! Használd: !kisallat uj <név>"
+        return loc.T("tama.none")
     }
     
     // Egyszerűbb formátum
-    return fmt.Sprintf("%s %s (%s) | ❤️%d 🍎%d 😊%d 🧼%d | %s",
+    return loc.T("tama.status",
         kisallat.Emoji(),
         kisallat.Nev,
-        kisallat.Allapot,
+        kisallat.Allapot.Felirat(loc),
         kisallat.Egeszseg,
         kisallat.Ehseg,
         kisallat.Boldogsag,
-        kisallat.Tisztasag,
-        "Használd: !kisallat etet/jatszik/tisztit")
+        kisallat.Tisztasag)
 }
 
-func (p *TamagotchiPlugin) etetValasz(csatorna, kuldo string) string {
+func (p *TamagotchiPlugin) etetValasz(loc *i18n.Locale, csatorna, kuldo string) string {
 	kisallat, letezik := p.kisallatok[csatorna]
 	if !letezik {
-		return "Nincs kisállat ebben a csatornában! Használd a '!kisallat uj <név>' parancsot egy létrehozásához."
+		return loc.T("tama.none")
 	}
 	
-	valasz := kisallat.Etet()
+	valasz := kisallat.Etet(loc)
 	p.kisallatokMentese()
 	return valasz
 }
 
-func (p *TamagotchiPlugin) jatszikValasz(csatorna, kuldo string) string {
+func (p *TamagotchiPlugin) jatszikValasz(loc *i18n.Locale, csatorna, kuldo string) string {
 	kisallat, letezik := p.kisallatok[csatorna]
 	if !letezik {
-		return "Nincs kisállat ebben a csatornában! Használd a '!kisallat uj <név>' parancsot egy létrehozásához."
+		return loc.T("tama.none")
 	}
 	
-	valasz := kisallat.Jatszik()
+	valasz := kisallat.Jatszik(loc)
 	p.kisallatokMentese()
 	return valasz
 }
 
-func (p *TamagotchiPlugin) tisztitValasz(csatorna, kuldo string) string {
+func (p *TamagotchiPlugin) tisztitValasz(loc *i18n.Locale, csatorna, kuldo string) string {
 	kisallat, letezik := p.kisallatok[csatorna]
 	if !letezik {
-		return "Nincs kisállat ebben a csatornában! Használd a '!kisallat uj <név>' parancsot egy létrehozásához."
+		return loc.T("tama.none")
 	}
 	
-	valasz := kisallat.Tisztit()
+	valasz := kisallat.Tisztit(loc)
 	p.kisallatokMentese()
 	return valasz
 }
 
-func (p *TamagotchiPlugin) segitoSzoveg() string {
-	return "🐣 Tamagotchi Kisállat Parancsok !kisallat uj <név> - Új kisállat létrehozása !kisallat allapot - Kisállat állapotának ellenőrzése !kisallat etet - Kisállat etetése !kisallat jatszik - Játék a kisállattal !kisallat tisztit - Kisállat tisztítása !kisallat segitség - Segítség megjelenítése Tartsd a kisállatod boldognak, jól lakottnak és tisztán! 🎮"
+func (p *TamagotchiPlugin) segitoSzoveg(loc *i18n.Locale) string {
+	return loc.T("tama.help")
 }
 
 func (p *TamagotchiPlugin) veletlenEsemeny(csatorna string, kisallat *Tamagotchi) string {
 	if kisallat.Allapot == AllapotHalott {
 		return ""
 	}
+	loc := p.tr.Locale(csatorna)
 	
 	esemenyek := []string{
-		fmt.Sprintf("🎵 %s boldogan énekel!", kisallat.Nev),
-		fmt.Sprintf("😴 %s szunyál...", kisallat.Nev),
-		fmt.Sprintf("🦋 %s pillangót kerget!", kisallat.Nev),
-		fmt.Sprintf("🌟 %s örömtől ragyog!", kisallat.Nev),
-		fmt.Sprintf("🎭 %s táncol!", kisallat.Nev),
+		loc.T("tama.event.sing", kisallat.Nev),
+		loc.T("tama.event.sleep", kisallat.Nev),
+		loc.T("tama.event.butterfly", kisallat.Nev),
+		loc.T("tama.event.shine", kisallat.Nev),
+		loc.T("tama.event.dance", kisallat.Nev),
 	}
 	
 	// Negatív események hozzáadása, ha elhanyagolják
 	if kisallat.Ehseg < 40 {
-		esemenyek = append(esemenyek, fmt.Sprintf("😫 %s ételt keres...", kisallat.Nev))
+		esemenyek = append(esemenyek, loc.T("tama.event.hungry", kisallat.Nev))
 	}
 	if kisallat.Boldogsag < 40 {
-		esemenyek = append(esemenyek, fmt.Sprintf("😢 %s magányosnak érzi magát...", kisallat.Nev))
+		esemenyek = append(esemenyek, loc.T("tama.event.lonely", kisallat.Nev))
 	}
 	if kisallat.Tisztasag < 40 {
-		esemenyek = append(esemenyek, fmt.Sprintf("🤢 %s koszos lesz...", kisallat.Nev))
+		esemenyek = append(esemenyek, loc.T("tama.event.dirty", kisallat.Nev))
 	}
 	
 	if rand.Intn(100) < 20 { // 20% esély véletlen eseményre
@@ -483,7 +489,7 @@ func (p *TamagotchiPlugin) IdozitettFrissites() []irc.Message {
 		if kisallat.Allapot == AllapotHalott && kisallat.HalalIdeje != nil && time.Since(*kisallat.HalalIdeje) < time.Minute {
 			uzenetek = append(uzenetek, irc.Message{
 				Channel: csatorna, // Csatorna -> Channel
-				Text:    fmt.Sprintf("💀 Jaj ne! %s meghalt! 😢 Nyugodjék békében...", kisallat.Nev), // Szoveg -> Text
+				Text:    p.tr.Locale(csatorna).T("tama.died", kisallat.Nev), // Szoveg -> Text
 			})
 		}
 		
@@ -506,25 +512,30 @@ func (p *TamagotchiPlugin) IdozitettFrissites() []irc.Message {
 }
 
 func (p *TamagotchiPlugin) kisallatokMentese() error {
-	adatok, err := json.MarshalIndent(p.kisallatok, "", "  ")
-	if err != nil {
-		return err
+	sorok := make(map[string][]byte, len(p.kisallatok))
+	for csatorna, kisallat := range p.kisallatok {
+		adatok, err := json.Marshal(kisallat)
+		if err != nil {
+			return err
+		}
+		sorok[csatorna] = adatok
 	}
-	
-	return os.WriteFile(filepath.Join(p.adatKonyvtar, "kisallatok.json"), adatok, 0644)
+	return p.tarolo.SaveAll(sorok)
 }
 
 func (p *TamagotchiPlugin) kisallatokBetoltese() error {
-	fajlNev := filepath.Join(p.adatKonyvtar, "kisallatok.json")
-	adatok, err := os.ReadFile(fajlNev)
+	sorok, err := p.tarolo.All()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // Még nincs kisállat fájl, ez rendben van
-		}
 		return err
 	}
-	
-	return json.Unmarshal(adatok, &p.kisallatok)
+	for csatorna, adatok := range sorok {
+		kisallat := &Tamagotchi{}
+		if err := json.Unmarshal(adatok, kisallat); err != nil {
+			return fmt.Errorf("%s kisállata: %v", csatorna, err)
+		}
+		p.kisallatok[csatorna] = kisallat
+	}
+	return nil
 }
 
 func (p *TamagotchiPlugin) Shutdown() error {
@@ -0,0 +1,110 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irctest"
+)
+
+func TestTamagotchiCommands(t *testing.T) {
+	env := irctest.NewEnv(t, nil)
+	p := NewTamagotchiPlugin(env.DB.Pets, env.Bot, env.Ctx)
+	if err := p.Initialize(env.Bot, env.Config); err != nil {
+		t.Fatal(err)
+	}
+	env.Attach(p)
+	hu := i18n.Get("hu")
+	status := func(emoji string, stage TamagotchiAllapot, health, food, joy, clean int) string {
+		return hu.T("tama.status", emoji, "Pötyi", stage.Felirat(hu), health, food, joy, clean)
+	}
+	played := func(got string) bool {
+		for i := 0; i < 5; i++ {
+			if got == hu.T("tama.played", hu.T(fmt.Sprintf("tama.game.%d", i)), "Pötyi", 100) {
+				return true
+			}
+		}
+		return false
+	}
+
+	steps := []struct {
+		name  string
+		text  string
+		want  string
+		check func(got string) bool // ha a válasz véletlenszerű
+	}{
+		{"nincs kisállat", "!kisallat etet", hu.T("tama.none"), nil},
+		{"súgó", "!tamagotchi", hu.T("tama.help"), nil},
+		{"név nélkül", "!kisallat uj", hu.T("tama.usage_new"), nil},
+		{"túl hosszú név", "!kisallat uj " + strings.Repeat("x", 21), hu.T("tama.name_too_long", 20), nil},
+		{"létrehozás", "!kisallat uj Pötyi", hu.T("tama.created", "alice", "Pötyi"), nil},
+		{"már van", "!kisallat uj Másik", hu.T("tama.exists", "Pötyi"), nil},
+		{"állapot", "!kisallat allapot", status("🥚", AllapotTojas, 100, 70, 80, 100), nil},
+		{"etetés", "!kisallat etet", hu.T("tama.fed", "Pötyi", 100), nil},
+		{"jóllakott", "!kisallat etet", hu.T("tama.feed_full", "🥚", "Pötyi"), nil},
+		{"tiszta", "!kisallat tisztit", hu.T("tama.clean_already", "🥚", "Pötyi"), nil},
+		{"játék", "!kisallat jatszik", "", played},
+		{"boldog", "!kisallat jatszik", hu.T("tama.play_happy", "🥚", "Pötyi"), nil},
+		{"ismeretlen alparancs", "!kisallat repul", hu.T("tama.help"), nil},
+		{"más parancs", "!seen Pötyi", "", func(got string) bool { return got == "" }},
+	}
+	for _, st := range steps {
+		got := strings.Join(env.Say(t, "alice", "#test", st.text), "\n")
+		if (st.check != nil && !st.check(got)) || (st.check == nil && got != st.want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, st.want)
+		}
+	}
+
+	// a kisállat az adatbázisból újraindítás után is megvan
+	reloaded := NewTamagotchiPlugin(env.DB.Pets, env.Bot, env.Ctx)
+	if err := reloaded.Initialize(env.Bot, env.Config); err != nil {
+		t.Fatal(err)
+	}
+	msg := env.Say(t, "alice", "#other", "!kisallat allapot")
+	if want := hu.T("tama.none"); len(msg) != 1 || msg[0] != want {
+		t.Errorf("másik csatorna: %q", msg)
+	}
+	pet := reloaded.kisallatok["#test"]
+	if pet == nil || pet.Nev != "Pötyi" || pet.Tulajdonos != "alice" || pet.Ehseg != 100 || pet.Boldogsag != 100 {
+		t.Errorf("betöltött kisállat: %+v", pet)
+	}
+}
+
+func TestTamagotchiFrissit(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration // a születés és minden gondozás óta eltelt idő
+		health    int
+		stage     TamagotchiAllapot
+		food      int
+		joy       int
+		clean     int
+		newHealth int
+	}{
+		{"friss tojás", 0, 100, AllapotTojas, 70, 80, 100, 100},
+		{"baba", 2 * time.Hour, 100, AllapotBaba, 60, 74, 96, 100},
+		{"elhanyagolt gyermek", 30 * time.Hour, 100, AllapotGyermek, 0, 0, 40, 98},
+		{"felnőtt", 100 * time.Hour, 100, AllapotFelnott, 0, 0, 0, 98},
+		{"idős", 200 * time.Hour, 100, AllapotIdos, 0, 0, 0, 98},
+		{"elpusztul", 30 * time.Hour, 2, AllapotHalott, 0, 0, 40, 0},
+	}
+	for _, tt := range tests {
+		pet := UjTamagotchi("Pötyi", "alice")
+		born := time.Now().Add(-tt.age)
+		pet.SzuletesiIdo, pet.UtoljaraEtrek, pet.UtoljaraJatszott, pet.UtoljaraTisztitva = born, born, born, born
+		pet.Egeszseg = tt.health
+
+		pet.Frissit()
+		if pet.Allapot != tt.stage || pet.Ehseg != tt.food || pet.Boldogsag != tt.joy ||
+			pet.Tisztasag != tt.clean || pet.Egeszseg != tt.newHealth {
+			t.Errorf("%s: %v éhség=%d boldogság=%d tisztaság=%d egészség=%d", tt.name,
+				pet.Allapot, pet.Ehseg, pet.Boldogsag, pet.Tisztasag, pet.Egeszseg)
+		}
+		if (tt.stage == AllapotHalott) != (pet.HalalIdeje != nil) {
+			t.Errorf("%s: halál ideje = %v", tt.name, pet.HalalIdeje)
+		}
+	}
+}
@@ -1,12 +1,9 @@
 package ynm
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 	"unicode"
@@ -17,84 +14,101 @@ import (
 	"golang.org/x/text/unicode/norm"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 	"golang.org/x/net/html/charset"
 	"golang.org/x/text/encoding/charmap"
 )
 
 type JokePlugin struct {
-	bot        *irc.Client
-	channels   []string
-	sendAt     string
-	statusFile string
+	bot        irc.Sender
+	ctx        *pluginapi.Context // joke.enabled / joke.time csatornánként
+	filter     pluginapi.ChannelFilter
+	cancelJobs func()
+	url        string        // a vicc forrása
+	pause      time.Duration // szünet az elküldött részek között
 }
 
-func NewJokePlugin(bot *irc.Client, channels []string, sendAt string) *JokePlugin {
-	statusPath := filepath.Join("data", "joke_status.json")
+const jokeURL = "https://www.viccesviccek.hu/viccdoboz.php"
+
+func NewJokePlugin(bot irc.Sender, ctx *pluginapi.Context) *JokePlugin {
 	return &JokePlugin{
-		bot:        bot,
-		channels:   channels,
-		sendAt:     sendAt,
-		statusFile: statusPath,
+		bot:   bot,
+		ctx:   ctx,
+		url:   jokeURL,
+		pause: time.Second,
 	}
 }
 
+// Start csatornánként felveszi a napivicc:#csatorna feladatot a joke.time szerint
 func (p *JokePlugin) Start() {
-	log.Printf("ℹ️ Vicc plugin elindult. Küldési idő: %s", p.sendAt)
-	go p.scheduler()
+	log.Printf("ℹ️ Vicc plugin elindult. Alap küldési idő: %s", p.ctx.Setting("", "joke.time"))
+	p.cancelJobs = p.ctx.ChannelJobs("napivicc", "joke.enabled", "joke.time",
+		scheduler.Job{Missed: scheduler.MissedOnce},
+		func(channel string) error {
+			p.sendDailyJoke([]string{channel})
+			return nil
+		})
 }
 
-func (p *JokePlugin) scheduler() {
-	for {
-		now := time.Now()
-		sendTime, err := time.Parse("15:04", p.sendAt)
-		if err != nil {
-			log.Printf("⛔️ Vicc plugin - hibás időformátum: %v", err)
-			return
-		}
-
-		target := time.Date(now.Year(), now.Month(), now.Day(), sendTime.Hour(), sendTime.Minute(), 0, 0, now.Location())
-		if now.After(target) {
-			target = target.Add(24 * time.Hour)
-		}
-		waitDuration := target.Sub(now)
-		log.Printf("🕒 Vicc plugin vár %v-ot a következő küldésig", waitDuration)
-		time.Sleep(waitDuration)
-		p.sendDailyJoke()
+func (p *JokePlugin) Stop() {
+	if p.cancelJobs != nil {
+		p.cancelJobs()
 	}
 }
 
-func (p *JokePlugin) sendDailyJoke() {
-	today := time.Now().Format("2006-01-02")
-	status := p.loadStatus()
+func (p *JokePlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
+}
+
+func (p *JokePlugin) sendDailyJoke(channels []string) {
+	today := p.ctx.Now().Format("2006-01-02")
+	lastSent, joke, err := p.ctx.Storage.Jokes.Last()
+	if err != nil {
+		log.Printf("Hiba a vicc állapot betöltésekor: %v", err)
+	}
 
-	joke := p.getJoke()
-	joke = cleanInvalidUTF8(joke)
+	// Egy nap egy vicc: a később sorra kerülő csatornák ugyanazt kapják
+	if lastSent != today || joke == "" {
+		joke = cleanInvalidUTF8(p.getJoke())
+	}
 
-	intro := "🤣 A nap vicce érkezik! 🎉"
 	messages := splitMessage(joke, 320, 280)
 
-	for _, ch := range p.channels {
-		p.bot.SendMessage(ch, intro) // az üdvözlő üzenet egyszer
-		time.Sleep(1 * time.Second)
+	for _, ch := range channels {
+		if !p.filter.Allows(ch) {
+			continue
+		}
+		loc := p.ctx.Locale(ch)
+		if joke == "" {
+			p.bot.SendMessage(ch, loc.T("joke.unavailable"))
+			continue
+		}
+		p.bot.SendMessage(ch, loc.T("joke.intro")) // az üdvözlő üzenet egyszer
+		time.Sleep(p.pause)
 		for i, part := range messages {
 			if len(messages) > 1 {
 				part += fmt.Sprintf(" (%d/%d)", i+1, len(messages))
 			}
 			p.bot.SendMessage(ch, part) // IDE kerüljön a part, nem az intro!
 			log.Printf("Vicc rész elküldve csatornára %s: %s", ch, part)
-			time.Sleep(1 * time.Second)
+			time.Sleep(p.pause)
 		}
 	}
 
-	status["last_sent"] = today
-	status["last_joke"] = joke
-	p.saveStatus(status)
+	if joke == "" {
+		return // a sikertelen lekérést a következő alkalom újrapróbálja
+	}
+	if err := p.ctx.Storage.Jokes.SetLast(today, joke); err != nil {
+		log.Printf("Hiba a vicc állapot mentésekor: %v", err)
+	}
 }
 
 
 
+// getJoke a vicc szövege; hiba esetén üres
 func (p *JokePlugin) getJoke() string {
-    url := "https://www.viccesviccek.hu/viccdoboz.php"
+    url := p.url
     client := &http.Client{
         Timeout: 10 * time.Second,
     }
@@ -102,14 +116,14 @@ func (p *JokePlugin) getJoke() string {
     req, err := http.NewRequest("GET", url, nil)
     if err != nil {
         log.Printf("Hiba a vicc lekérésében: %v", err)
-        return "Nem sikerült viccet lekérni. 😕"
+        return ""
     }
     req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; YnM-GoBot)")
 
     resp, err := client.Do(req)
     if err != nil {
         log.Printf("Hiba a vicc lekérésében: %v", err)
-        return "Nem sikerült viccet lekérni. 😕"
+        return ""
     }
     defer resp.Body.Close()
 
@@ -124,7 +138,7 @@ func (p *JokePlugin) getJoke() string {
         reader, err = charset.NewReader(resp.Body, contentType)
         if err != nil {
             log.Printf("Charset konvertálás hiba: %v", err)
-            return "Nem sikerült a karakterkódolás kezelése. 😕"
+            return ""
         }
     }
 
@@ -132,7 +146,7 @@ func (p *JokePlugin) getJoke() string {
     doc, err := goquery.NewDocumentFromReader(reader)
     if err != nil {
         log.Printf("Hiba a HTML feldolgozásában: %v", err)
-        return "Nem sikerült feldolgozni a vicc oldalt. 😕"
+        return ""
     }
 
     text := doc.Find("body").Text()
@@ -145,7 +159,7 @@ func (p *JokePlugin) getJoke() string {
     text = cleanJokeText(text)
 
     if len(text) < 10 {
-        return "Nem sikerült viccet találni az oldalon. 😕"
+        return ""
     }
 
     return text
@@ -213,35 +227,6 @@ func splitMessage(text string, maxLen int, minLen int) []string {
 }
 
 
-func (p *JokePlugin) loadStatus() map[string]string {
-	status := make(map[string]string)
-	file, err := os.Open(p.statusFile)
-	if err != nil {
-		return status
-	}
-	defer file.Close()
-	err = json.NewDecoder(file).Decode(&status)
-	if err != nil {
-		return map[string]string{}
-	}
-	return status
-}
-
-func (p *JokePlugin) saveStatus(status map[string]string) {
-	os.MkdirAll(filepath.Dir(p.statusFile), 0755)
-	file, err := os.Create(p.statusFile)
-	if err != nil {
-		log.Printf("Hiba a státusz fájl létrehozásakor: %v", err)
-		return
-	}
-	defer file.Close()
-	enc := json.NewEncoder(file)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(status); err != nil {
-		log.Printf("Hiba a státusz mentésekor: %v", err)
-	}
-}
-
 func (p *JokePlugin) HandleMessage(msg irc.Message) string {
 	return ""
 }
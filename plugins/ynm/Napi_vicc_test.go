@@ -0,0 +1,101 @@
+package ynm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestDailyJoke(t *testing.T) {
+	var mu sync.Mutex
+	joke, hits := "", 0
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		hits++
+		body, _ := charmap.ISO8859_2.NewEncoder().String("<html><body>\n" + joke + "\nTovábbi viccek: ...</body></html>")
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-2")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(site.Close)
+	setJoke := func(s string) {
+		mu.Lock()
+		joke = s
+		mu.Unlock()
+	}
+
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) { cfg.JokeChannels = []string{"#test"} })
+	p := NewJokePlugin(env.Bot, env.Ctx)
+	p.url, p.pause = site.URL, 0
+	p.Start()
+	t.Cleanup(p.Stop)
+	hu := i18n.Get("hu")
+
+	first := "Móricka megkérdezi a tanító nénit, hogy mi az a fűzőlyuk."
+	second := "Két őz beszélget az erdőben."
+	steps := []struct {
+		name    string
+		joke    string
+		advance time.Duration
+		send    bool // a napi feladat helyett közvetlen küldés (második csatorna ugyanazon a napon)
+		want    []string
+		hits    int
+	}{
+		{"nap közben nincs vicc", first, 23 * time.Hour, false, nil, 0},
+		{"reggel 8-kor", first, time.Hour, false, []string{"#test: " + hu.T("joke.intro"), "#test: " + first}, 1},
+		{"ugyanaznap ugyanaz, új lekérés nélkül", second, 0, true, []string{"#test: " + hu.T("joke.intro"), "#test: " + first}, 1},
+		{"másnap új vicc", second, 24 * time.Hour, false, []string{"#test: " + hu.T("joke.intro"), "#test: " + second}, 2},
+		{"elérhetetlen vicc", "", 24 * time.Hour, false, []string{"#test: " + hu.T("joke.unavailable")}, 3},
+	}
+	for _, st := range steps {
+		setJoke(st.joke)
+		var got []string
+		if st.send {
+			got = env.Sent(t, func() { p.sendDailyJoke([]string{"#test"}) })
+		} else {
+			got = env.Tick(t, st.advance)
+		}
+		if !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", st.name, got, st.want)
+		}
+		mu.Lock()
+		if hits != st.hits {
+			t.Errorf("%s: %d lekérés, várt %d", st.name, hits, st.hits)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	sentence := strings.Repeat("a", 290) + ". "
+	words := strings.Repeat("szó ", 100)
+	tests := []struct {
+		name string
+		text string
+		want []int // a részek hossza runákban
+	}{
+		{"rövid", "egy vicc", []int{8}},
+		{"mondathatáron", sentence + strings.Repeat("b", 100), []int{291, 100}},
+		{"szóhatáron", words, []int{319, 79}},
+		{"szóköz nélkül", strings.Repeat("x", 700), []int{320, 320, 60}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, part := range splitMessage(tt.text, 320, 280) {
+			got = append(got, len([]rune(part)))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: %v, várt %v", tt.name, got, tt.want)
+		}
+	}
+}
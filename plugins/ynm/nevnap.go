@@ -14,25 +14,24 @@
 package ynm
 
 import (
+	"log"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
-	
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 
 )
 
 type NameDayPlugin struct {
-    AnnounceChannels []string
-    userRequestTimes map[string][]time.Time   // felhasználó lekérései időpontjai
-    userBanUntil    map[string]time.Time      // felhasználói tiltások lejárati ideje
-    userBanNotified map[string]bool            // jelezte-e már a tiltást
-	lastMorningAnnounce string
-	lastEveningAnnounce string
-	NevnapReggel time.Time
-    NevnapEste   time.Time
+    bot             irc.Sender
+    ctx             *pluginapi.Context         // nevnap.enabled / nevnap.morning / nevnap.evening
+	filter          pluginapi.ChannelFilter
+	cancelJobs      []func()
 	mu              sync.Mutex
 }
 
@@ -409,25 +408,20 @@ var nameDays = map[string][]string{
 
 
 
-func NewNameDayPlugin(channels []string, reggelStr, esteStr string) (*NameDayPlugin, error) {
-    loc := time.Now().Location()
-    reggel, err := time.ParseInLocation("15:04", reggelStr, loc)
-    if err != nil {
-        return nil, err
-    }
-    este, err := time.ParseInLocation("15:04", esteStr, loc)
-    if err != nil {
-        return nil, err
+func NewNameDayPlugin(bot irc.Sender, ctx *pluginapi.Context) *NameDayPlugin {
+    p := &NameDayPlugin{
+        bot:              bot,
+        ctx:              ctx,
     }
 
-    return &NameDayPlugin{
-        AnnounceChannels: channels,
-        NevnapReggel:     reggel,
-        NevnapEste:       este,
-        userRequestTimes: make(map[string][]time.Time),
-        userBanUntil:     make(map[string]time.Time),
-        userBanNotified:  make(map[string]bool),
-    }, nil
+    // Reggeli és esti bejelentés csatornánként (nevnap:reggel:#csatorna, nevnap:este:#csatorna)
+    p.cancelJobs = []func(){
+        ctx.ChannelJobs("nevnap:reggel", "nevnap.enabled", "nevnap.morning",
+            scheduler.Job{Missed: scheduler.MissedOnce}, p.announceMorning),
+        ctx.ChannelJobs("nevnap:este", "nevnap.enabled", "nevnap.evening",
+            scheduler.Job{Missed: scheduler.MissedSkip}, p.announceEvening),
+    }
+    return p
 }
 
 func (p *NameDayPlugin) HandleMessage(msg irc.Message) string {
@@ -437,144 +431,105 @@ func (p *NameDayPlugin) HandleMessage(msg irc.Message) string {
         return ""
     }
 
-    p.mu.Lock()
-    defer p.mu.Unlock()
-
-    now := time.Now()
-    user := msg.Sender
-
-    // Tiltás ellenőrzése
-    banUntil, banned := p.userBanUntil[user]
-    if banned && now.Before(banUntil) {
-        if !p.userBanNotified[user] {
-            remaining := banUntil.Sub(now).Round(time.Second)
-            p.userBanNotified[user] = true
-            return fmt.Sprintf("Túl sok !nevnap parancs, kérlek várj még %s-ig!", remaining)
-        }
-        return ""  // már jelezte, nem válaszol többször
-    } else if banned && now.After(banUntil) {
-        // Tiltás lejárt, töröljük
-        delete(p.userBanUntil, user)
-        delete(p.userBanNotified, user)
-        p.userRequestTimes[user] = nil
-    }
-
-    // Kitisztítjuk az 5 percnél régebbi hívásokat
-    times := p.userRequestTimes[user]
-    validTimes := []time.Time{}
-    for _, t := range times {
-        if now.Sub(t) <= 5*time.Minute {
-            validTimes = append(validTimes, t)
-        }
-    }
-    p.userRequestTimes[user] = validTimes
-
-    // Ha 3 vagy több hívás van 5 perc alatt, tiltjuk 24 órára
-    if len(validTimes) >= 3 {
-        p.userBanUntil[user] = now.Add(24 * time.Hour)
-        p.userBanNotified[user] = true  // azonnal jelezzük
-        p.userRequestTimes[user] = nil
-        return "Túl sok !nevnap parancs, ezért 24 órára le vagy tiltva erről a parancsról."
-    }
-
-    // Hozzáadjuk az aktuális hívást
-    p.userRequestTimes[user] = append(p.userRequestTimes[user], now)
+    // A gyakoriság korlátozását a közös ratelimit réteg végzi
 
     // Eredeti parancs feldolgozása (eredeti kódod, itt van vágva pl.)
     args := strings.TrimSpace(cmd[len("!nevnap"):])
+    loc := p.ctx.LocaleFor(msg)
     
     if args == "" {
-        return p.getTodayTomorrow()
+        return p.getTodayTomorrow(loc)
     }
     if day, month, ok := p.parseDate(args); ok {
-        return p.getNameDayByDate(month, day)
+        return p.getNameDayByDate(loc, month, day)
     }
-    return p.searchNameDay(args)
+    return p.searchNameDay(loc, args)
 }
 
 
 func (p *NameDayPlugin) OnTick() []irc.Message {
+	return nil
+}
+
+// announceMorning a mai névnapokat jelenti be
+func (p *NameDayPlugin) announceMorning(channel string) error {
 	p.mu.Lock()
-    defer p.mu.Unlock()
-	now := time.Now()
-	todayKey := now.Format("2006-01-02")
-	var messages []irc.Message
+	todayNames := p.getTodaysNameDay()
+	p.mu.Unlock()
+
+	if todayNames == "" || !p.filter.Allows(channel) {
+		return nil
+	}
+	p.bot.SendMessage(channel, p.ctx.Locale(channel).T("nevnap.morning", todayNames))
+	log.Printf("[Névnap] Küldés reggel %s csatornára", channel)
+	return nil
+}
 
+// announceEvening a mai és a holnapi névnapokat jelenti be
+func (p *NameDayPlugin) announceEvening(channel string) error {
+	p.mu.Lock()
 	todayNames := p.getTodaysNameDay()
 	tomorrowNames := p.getTomorrowsNameDay()
+	p.mu.Unlock()
 
-
-	// Reggeli bejelentés (pontos idő az configból, ±1 perc tolerancia)
-	if now.Hour() == p.NevnapReggel.Hour() && now.Minute() <= p.NevnapReggel.Minute()+1 && p.lastMorningAnnounce != todayKey && todayNames != "" {
-		for _, channel := range p.AnnounceChannels {
-			messages = append(messages, irc.Message{
-				Channel: channel,
-				Text:    fmt.Sprintf("Ma *%s* névnapja van! Boldog névnapot! 🎉", todayNames),
-			})
-			fmt.Printf("[Névnap] Küldés reggel %s csatornára\n", channel)
-		}
-		p.lastMorningAnnounce = todayKey
+	if !p.filter.Allows(channel) {
+		return nil
 	}
-
-	// Esti bejelentés (pontos idő az configból, ±1 perc tolerancia)
-	if now.Hour() == p.NevnapEste.Hour() && now.Minute() == p.NevnapEste.Minute() && p.lastEveningAnnounce != todayKey {
-		for _, channel := range p.AnnounceChannels {
-			if todayNames != "" {
-				messages = append(messages, irc.Message{
-					Channel: channel,
-					Text:    fmt.Sprintf("Ma *%s* névnapja volt.", todayNames),
-				})
-				fmt.Printf("[Névnap] Küldés este (ma) %s csatornára\n", channel)
-			}
-			if tomorrowNames != "" {
-				messages = append(messages, irc.Message{
-					Channel: channel,
-					Text:    fmt.Sprintf("Holnap *%s* névnapja lesz.", tomorrowNames),
-				})
-				fmt.Printf("[Névnap] Küldés este (holnap) %s csatornára\n", channel)
-			}
-		}
-		p.lastEveningAnnounce = todayKey
+	loc := p.ctx.Locale(channel)
+	if todayNames != "" {
+		p.bot.SendMessage(channel, loc.T("nevnap.evening_today", todayNames))
+		log.Printf("[Névnap] Küldés este (ma) %s csatornára", channel)
 	}
-
-	return messages
+	if tomorrowNames != "" {
+		p.bot.SendMessage(channel, loc.T("nevnap.evening_tomorrow", tomorrowNames))
+		log.Printf("[Névnap] Küldés este (holnap) %s csatornára", channel)
+	}
+	return nil
 }
 
+func (p *NameDayPlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
+}
 
-
+// Stop leállítja a névnap bejelentéseket
+func (p *NameDayPlugin) Stop() {
+	for _, cancel := range p.cancelJobs {
+		cancel()
+	}
+}
 
 // Helper functions
-func (p *NameDayPlugin) getTodayTomorrow() string {
+func (p *NameDayPlugin) getTodayTomorrow(loc *i18n.Locale) string {
 	todayNames := p.getTodaysNameDay()
 	tomorrowNames := p.getTomorrowsNameDay()
 	
 	if todayNames == "" && tomorrowNames == "" {
-		return "Ma és holnap sincs névnap."
+		return loc.T("nevnap.none_today_tomorrow")
 	}
 	
 	msg := ""
 	if todayNames != "" {
-		msg += fmt.Sprintf("Névnap: Ma (%s): %s", time.Now().Format("2006.1. 2"), todayNames)
+		msg += loc.T("nevnap.today", loc.Date(p.ctx.Now()), todayNames)
 	}
 	if tomorrowNames != "" {
 		if msg != "" {
 			msg += ", "
 		}
-		msg += fmt.Sprintf("holnap: %s", tomorrowNames)
+		msg += loc.T("nevnap.tomorrow", tomorrowNames)
 	}
 	
 	return msg
 }
 
-func (p *NameDayPlugin) getNameDayByDate(month, day int) string {
+func (p *NameDayPlugin) getNameDayByDate(loc *i18n.Locale, month, day int) string {
 	key := fmt.Sprintf("%02d-%02d", month, day)
 	if names, ok := nameDays[key]; ok {
-		return fmt.Sprintf("névnap: %d.%d: %s", month, day, strings.Join(names, ", "))
+		return loc.T("nevnap.on_date", loc.DayMonth(time.Month(month), day), strings.Join(names, ", "))
 	}
-	return fmt.Sprintf("Nincs névnap %d.%d.-án.", month, day)
+	return loc.T("nevnap.none_on_date", loc.DayMonth(time.Month(month), day))
 }
 
-func (p *NameDayPlugin) searchNameDay(name string) string {
+func (p *NameDayPlugin) searchNameDay(loc *i18n.Locale, name string) string {
     var results []string
     normalizedSearch := normalizeString(strings.TrimSpace(name))
     
@@ -585,15 +540,15 @@ func (p *NameDayPlugin) searchNameDay(name string) string {
                 parts := strings.Split(date, "-")
                 month, _ := strconv.Atoi(parts[0])
                 day, _ := strconv.Atoi(parts[1])
-                results = append(results, fmt.Sprintf("%s%d.", getHungarianMonth(month), day))
+                results = append(results, loc.DayMonth(time.Month(month), day))
             }
         }
     }
     
     if len(results) > 0 {
-        return fmt.Sprintf("Névnap: Mikor van %s nap: %s", name, strings.Join(results, " "))
+        return loc.T("nevnap.found", name, strings.Join(results, ", "))
     }
-    return fmt.Sprintf("Névnap: Ilyen nevű névnap nincs (%s).", name)
+    return loc.T("nevnap.not_found", name)
 }
 
 // Helper function to normalize strings (remove accents and case)
@@ -616,17 +571,8 @@ func normalizeString(s string) string {
     return string(result)
 }
 
-// Helper function to get Hungarian month names
-func getHungarianMonth(month int) string {
-    months := []string{"", "I.", "II.", "III.", "IV.", "V.", "VI.", 
-                      "VII.", "VIII.", "IX.", "X.", "XI.", "XII."}
-    if month >= 1 && month <= 12 {
-        return months[month]
-    }
-    return ""
-}
 func (p *NameDayPlugin) getTodaysNameDay() string {
-	today := time.Now().Format("01-02")
+	today := p.ctx.Now().Format("01-02")
 	if names, ok := nameDays[today]; ok {
 		return strings.Join(names, ", ")
 	}
@@ -634,7 +580,7 @@ func (p *NameDayPlugin) getTodaysNameDay() string {
 }
 
 func (p *NameDayPlugin) getTomorrowsNameDay() string {
-	tomorrow := time.Now().Add(24 * time.Hour).Format("01-02")
+	tomorrow := p.ctx.Now().Add(24 * time.Hour).Format("01-02")
 	if names, ok := nameDays[tomorrow]; ok {
 		return strings.Join(names, ", ")
 	}
@@ -0,0 +1,73 @@
+package ynm
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irctest"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestNameDayCommand(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	p := NewNameDayPlugin(env.Bot, env.Ctx)
+	t.Cleanup(p.Stop)
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"ma és holnap", "!nevnap", hu.T("nevnap.today", hu.Date(irctest.Start), "Kármen, Anita") + ", " + hu.T("nevnap.tomorrow", "Klotild")},
+		{"dátum", "!nevnap 05.03", hu.T("nevnap.on_date", hu.DayMonth(time.March, 5), "Adorján, Adrián")},
+		{"név", "!nevnap klotild", hu.T("nevnap.found", "klotild", hu.DayMonth(time.June, 3))},
+		{"ékezet nélkül", "!nevnap bulcsu", hu.T("nevnap.found", "bulcsu", hu.DayMonth(time.June, 4))},
+		{"ismeretlen név", "!nevnap xyz", hu.T("nevnap.not_found", "xyz")},
+		{"más parancs", "!vicc", ""},
+	}
+	for _, tt := range tests {
+		got := env.Say(t, "alice", "#test", tt.text)
+		if (tt.want == "" && len(got) != 0) || (tt.want != "" && !slices.Equal(got, []string{tt.want})) {
+			t.Errorf("%s: %q → %q, várt %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNameDayAnnouncements(t *testing.T) {
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) { cfg.NevnapChannels = []string{"#test"} })
+	p := NewNameDayPlugin(env.Bot, env.Ctx)
+	t.Cleanup(p.Stop)
+	hu := i18n.Get("hu")
+
+	// a kezdő időpont hétfő 8:00, az alapértelmezés 7:30 és 21:30
+	steps := []struct {
+		advance time.Duration
+		want    []string
+	}{
+		{13 * time.Hour, nil},
+		{30 * time.Minute, []string{
+			"#test: " + hu.T("nevnap.evening_today", "Kármen, Anita"),
+			"#test: " + hu.T("nevnap.evening_tomorrow", "Klotild"),
+		}},
+		{10 * time.Hour, []string{"#test: " + hu.T("nevnap.morning", "Klotild")}},
+		{time.Hour, nil},
+	}
+	for _, st := range steps {
+		got := env.Tick(t, st.advance)
+		if !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", env.Clock.Now().Format("01-02 15:04"), got, st.want)
+		}
+	}
+
+	// a csatornaszűrő (pl. moderált csatorna) letiltja a küldést
+	p.SetChannelFilter(func(channel string) bool { return false })
+	got := env.Tick(t, 24*time.Hour)
+	if len(got) != 0 {
+		t.Errorf("szűrve is küldött: %q", got)
+	}
+}
@@ -7,10 +7,8 @@
 package ynm
 
 import (
-	"database/sql"
+	"log"
 	"fmt"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -18,75 +16,35 @@ import (
 	"time"
 
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
-	"github.com/ynmhu/YnM-Go/config"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/ynmhu/YnM-Go/storage"
 	
 )
 
-type OraReminder struct {
-	ID        int64
-	Nick      string
-	Message   string
-	RemindAt  time.Time
-	CreatedAt time.Time  // Új mező a beállítás időpontjának tárolásához
-}
+// OraReminder egy emlékeztető a közös adatbázisból
+type OraReminder = storage.Reminder
 
 type OraPlugin struct {
-	db          *sql.DB
+	reminders   *storage.ReminderRepo
 	mutex       sync.Mutex
-	timers      map[int64]*time.Timer
-	ircClient   *irc.Client
+	ircClient   irc.Sender
 	usageCount  map[string]int       // nick -> hányszor kapott használati útmutatót
-	channels    []string             // configból jövő csatornák
+	ctx         *pluginapi.Context   // ora.enabled csatornánként
 	adminPlugin *admin.AdminPlugin         // admin szint ellenőrzéshez
+	filter      pluginapi.ChannelFilter
 }
 
-func NewOraPlugin(client *irc.Client, admin *admin.AdminPlugin, cfg *config.Config) *OraPlugin {
+func NewOraPlugin(client irc.Sender, admin *admin.AdminPlugin, ctx *pluginapi.Context) *OraPlugin {
 	p := &OraPlugin{
-		timers:      make(map[int64]*time.Timer),
+		reminders:   ctx.Storage.Reminders,
 		ircClient:   client,
-		channels:    cfg.OraChan,
+		ctx:         ctx,
 		adminPlugin: admin,
 		usageCount:  make(map[string]int),
 	}
 
-	dbPath := cfg.OraDBFile
-	if dbPath == "" {
-		dbPath = filepath.Join("data", "ora_reminders.db")
-	}
-	_ = os.MkdirAll(filepath.Dir(dbPath), 0755)
-
-	// Nyisd meg az adatbázist
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		panic(fmt.Errorf("Adatbázis megnyitási hiba: %v", err))
-	}
-	p.db = db
-
-	// Táblák létrehozása (ha nem léteznek)
-	db.Exec(`CREATE TABLE IF NOT EXISTS reminders (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		nick TEXT NOT NULL,
-		message TEXT NOT NULL,
-		remind_at TIMESTAMP NOT NULL
-	);`)
-
-	// Új oszlopok hozzáadása, ha még nem léteznek
-	// Először ellenőrizzük, hogy létezik-e a status oszlop
-	var statusExists int
-	db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('reminders') WHERE name='status'").Scan(&statusExists)
-	if statusExists == 0 {
-		db.Exec(`ALTER TABLE reminders ADD COLUMN status TEXT DEFAULT 'active';`)
-	}
-
-	// Ellenőrizzük, hogy létezik-e a created_at oszlop
-	var createdAtExists int
-	db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('reminders') WHERE name='created_at'").Scan(&createdAtExists)
-	if createdAtExists == 0 {
-		db.Exec(`ALTER TABLE reminders ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;`)
-	}
-
 	p.loadAndSchedule()
 	return p
 }
@@ -125,89 +83,57 @@ func parseDuration(input string) (time.Duration, error) {
 func (p *OraPlugin) loadAndSchedule() {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	
-	// Először ellenőrizzük, hogy létezik-e a created_at oszlop
-	var createdAtExists int
-	p.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('reminders') WHERE name='created_at'").Scan(&createdAtExists)
-	
-	var query string
-	if createdAtExists > 0 {
-		query = "SELECT id, nick, message, remind_at, created_at FROM reminders WHERE status = 'active'"
-	} else {
-		query = "SELECT id, nick, message, remind_at FROM reminders WHERE status = 'active'"
-	}
-	
-	rows, err := p.db.Query(query)
+
+	reminders, err := p.reminders.Active()
 	if err != nil {
-		fmt.Printf("Hiba az emlékeztetők betöltésekor: %v\n", err)
-		return
-	}
-	if rows == nil {
+		log.Printf("Hiba az emlékeztetők betöltésekor: %v", err)
 		return
 	}
-	defer rows.Close()
-	
-	now := time.Now()
-	for rows.Next() {
-		var r OraReminder
-		var remindAtStr sql.NullString
-		var createdAtStr sql.NullString
-		
-		var err error
-		if createdAtExists > 0 {
-			err = rows.Scan(&r.ID, &r.Nick, &r.Message, &remindAtStr, &createdAtStr)
-		} else {
-			err = rows.Scan(&r.ID, &r.Nick, &r.Message, &remindAtStr)
-		}
-		
-		if err != nil {
-			fmt.Printf("Hiba az emlékeztető beolvasásakor: %v\n", err)
-			continue
-		}
-		
-		if remindAtStr.Valid {
-			r.RemindAt, _ = time.Parse(time.RFC3339, remindAtStr.String)
-		} else {
-			continue // Ha nincs remind_at, akkor ugorjuk át
-		}
-		
-		// Ha a created_at NULL vagy nem létezik az oszlop, akkor a remind_at-et használjuk alapértelmezésként
-		if createdAtExists > 0 && createdAtStr.Valid && createdAtStr.String != "" {
-			r.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr.String)
-		} else {
-			r.CreatedAt = r.RemindAt
-		}
-		
+
+	now := p.ctx.Now()
+	for _, r := range reminders {
 		if r.RemindAt.Before(now) {
-			go p.sendReminder(r)
-		} else {
-			p.scheduleReminder(r)
+			log.Printf("Lejárt emlékeztető pótlása: ID:%d", r.ID)
 		}
+		p.scheduleReminder(r)
 	}
 }
 
+func reminderJobName(id int64) string {
+	return fmt.Sprintf("ora:%d", id)
+}
+
+// scheduleReminder egyszeri feladatot vesz fel; a már lejárt időpont azonnal fut
 func (p *OraPlugin) scheduleReminder(r OraReminder) {
-	delay := time.Until(r.RemindAt)
-	t := time.AfterFunc(delay, func() { p.sendReminder(r) })
-	p.timers[r.ID] = t
+	err := p.ctx.Schedule(scheduler.Job{
+		Name:   reminderJobName(r.ID),
+		At:     r.RemindAt,
+		Missed: scheduler.MissedOnce,
+		Run: func() error {
+			p.sendReminder(r)
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("Hiba az emlékeztető ütemezésekor: %v", err)
+	}
 }
 
 func (p *OraPlugin) sendReminder(r OraReminder) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	// Itt használjuk a CreatedAt mezőt a beállítás időpontjának megjelenítésére
-	msg := fmt.Sprintf("@%s emlékeztető: %s (beállítva: %s)", r.Nick, r.Message, r.CreatedAt.Format("2006-01-02 15:04:05"))
-
-	for _, ch := range p.channels {
-		p.ircClient.SendMessage(ch, msg)
+	for _, ch := range p.ctx.Channels("ora.enabled") {
+		if !p.filter.Allows(ch) {
+			continue
+		}
+		// Itt használjuk a CreatedAt mezőt a beállítás időpontjának megjelenítésére
+		loc := p.ctx.Locale(ch)
+		p.ircClient.SendMessage(ch, loc.T("ora.reminder", r.Nick, r.Message, loc.DateTime(r.CreatedAt)))
 	}
 
-	p.db.Exec("UPDATE reminders SET status = 'expired' WHERE id = ?", r.ID)
-
-	if t, ok := p.timers[r.ID]; ok {
-		t.Stop()
-		delete(p.timers, r.ID)
+	if err := p.reminders.Expire(r.ID); err != nil {
+		log.Printf("Hiba az emlékeztető lezárásakor: %v", err)
 	}
 }
 
@@ -217,6 +143,7 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 	channel := msg.Channel
 	hostmask := msg.Sender
 	level := p.adminPlugin.GetAdminLevel(nick, hostmask)
+	loc := p.ctx.LocaleFor(msg)
 
 	switch {
 	case text == "!ora":
@@ -226,7 +153,7 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 		count := p.usageCount[nick]
 		if count < 2 {
 			p.usageCount[nick] = count + 1
-			return fmt.Sprintf("@%s Használat: !ora <idő> <üzenet> (pl:  !ora 1h30m Emlékeztető szöveg  |  !ora 2d Figyelmeztetés | !ora 15m Gyors emlékeztető)", nick)
+			return loc.T("ora.help", nick)
 		}
 		return ""
 
@@ -236,26 +163,24 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 		}
 		parts := strings.Fields(text)
 		if len(parts) < 3 {
-			return fmt.Sprintf("@%s Használat: !ora <idő> <üzenet>", nick)
+			return loc.T("ora.usage", nick)
 		}
 
 		dur, err := parseDuration(parts[1])
 		if err != nil {
-			return fmt.Sprintf("@%s Hiba: %v", nick, err)
+			return loc.T("ora.bad_duration", nick, parts[1])
 		}
 
 		message := strings.Join(parts[2:], " ")
-		now := time.Now()
+		now := p.ctx.Now()
 		remindAt := now.Add(dur)
 
-		// Most már a created_at mezőt is mentjük
-		res, err := p.db.Exec("INSERT INTO reminders (nick, message, remind_at, created_at) VALUES (?, ?, ?, ?)", 
-			nick, message, remindAt.Format(time.RFC3339), now.Format(time.RFC3339))
+		id, err := p.reminders.Add(nick, message, remindAt, now)
 		if err != nil {
-			return fmt.Sprintf("@%s Hiba az adatbázisba íráskor: %v", nick, err)
+			log.Printf("Hiba az emlékeztető mentésekor: %v", err)
+			return loc.T("ora.error", nick)
 		}
 
-		id, _ := res.LastInsertId()
 		p.scheduleReminder(OraReminder{
 			ID: id, 
 			Nick: nick, 
@@ -264,66 +189,23 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 			CreatedAt: now,
 		})
 
-		prettyDuration := formatDurationPretty(dur)
-
-		return fmt.Sprintf("@%s Emlékeztető mentve %s múlva, jelez %s-kor.", nick, prettyDuration, remindAt.Format("15:04:05"))
+		return loc.T("ora.saved", nick, loc.Duration(dur), loc.Clock(remindAt))
 
 	case text == "!orak":
 		if level < 1 || level > 3 {
-			return fmt.Sprintf("@%s Nincs jogosultságod emlékeztetők lekérésére.", nick)
-		}
-		
-		// Ellenőrizzük, hogy létezik-e a created_at oszlop
-		var createdAtExists int
-		p.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('reminders') WHERE name='created_at'").Scan(&createdAtExists)
-		
-		var query string
-		if createdAtExists > 0 {
-			query = "SELECT id, nick, message, remind_at, status, created_at FROM reminders ORDER BY remind_at ASC"
-		} else {
-			query = "SELECT id, nick, message, remind_at, status FROM reminders ORDER BY remind_at ASC"
+			return loc.T("ora.no_list_permission", nick)
 		}
 		
-		rows, err := p.db.Query(query)
+		reminders, err := p.reminders.All()
 		if err != nil {
-			return fmt.Sprintf("@%s Hiba az adatbázis lekérdezéskor: %v", nick, err)
-		}
-		if rows == nil {
-			return fmt.Sprintf("@%s Nincs emlékeztető.", nick)
+			log.Printf("Hiba az emlékeztetők lekérdezésekor: %v", err)
+			return loc.T("ora.error", nick)
 		}
-		defer rows.Close()
 
 		var lines []string
-		now := time.Now()
-
-		for rows.Next() {
-			var r OraReminder
-			var remindAtStr, status sql.NullString
-			var createdAtStr sql.NullString
-			
-			var err error
-			if createdAtExists > 0 {
-				err = rows.Scan(&r.ID, &r.Nick, &r.Message, &remindAtStr, &status, &createdAtStr)
-			} else {
-				err = rows.Scan(&r.ID, &r.Nick, &r.Message, &remindAtStr, &status)
-			}
-			
-			if err != nil {
-				continue // Ha hiba van, ugorjuk át ezt a sort
-			}
-			
-			if !remindAtStr.Valid {
-				continue // Ha nincs remind_at, akkor ugorjuk át
-			}
-			r.RemindAt, _ = time.Parse(time.RFC3339, remindAtStr.String)
-			
-			// Ha a created_at NULL vagy nem létezik az oszlop, akkor a remind_at-et használjuk alapértelmezésként
-			if createdAtExists > 0 && createdAtStr.Valid && createdAtStr.String != "" {
-				r.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr.String)
-			} else {
-				r.CreatedAt = r.RemindAt
-			}
+		now := p.ctx.Now()
 
+		for _, r := range reminders {
 			ownerLevel := p.adminPlugin.GetAdminLevel(r.Nick, "") 
 
 			if nick == r.Nick {
@@ -344,17 +226,17 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 			}
 
 			dur := r.RemindAt.Sub(now)
-			statusText := "Aktív"
-			if status.Valid && status.String != "active" {
-				statusText = "Lejárt"
+			statusText := loc.T("ora.status_active")
+			if r.Expired {
+				statusText = loc.T("ora.status_expired")
 			}
 
-			lines = append(lines, fmt.Sprintf("ID:%d - @%s (%s) - Beállítva: %s - Állapot: %s - Üzenet: %s",
-				r.ID, r.Nick, dur.Truncate(time.Second), r.CreatedAt.Format("2006-01-02 15:04:05"), statusText, r.Message))
+			lines = append(lines, loc.T("ora.list_line",
+				r.ID, r.Nick, loc.Duration(dur), loc.DateTime(r.CreatedAt), statusText, r.Message))
 		}
 
 		if len(lines) == 0 {
-			return fmt.Sprintf("@%s Nincs emlékeztető.", nick)
+			return loc.T("ora.none", nick)
 		}
 
 		for _, line := range lines {
@@ -366,21 +248,22 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 	case strings.HasPrefix(text, "!delora"):
 		parts := strings.Fields(text)
 		if len(parts) != 2 {
-			return fmt.Sprintf("@%s Használat: !delora <ID>", nick)
+			return loc.T("ora.usage_delete", nick)
 		}
 
 		id, err := strconv.ParseInt(parts[1], 10, 64)
 		if err != nil {
-			return fmt.Sprintf("@%s Hibás ID: %v", nick, err)
+			return loc.T("ora.bad_id", nick, parts[1])
 		}
 
-		var owner string
-		err = p.db.QueryRow("SELECT nick FROM reminders WHERE id = ?", id).Scan(&owner)
-		if err == sql.ErrNoRows {
-			return fmt.Sprintf("@%s Nincs ilyen ID-jú emlékeztető.", nick)
-		} else if err != nil {
-			return fmt.Sprintf("@%s Hiba történt: %v", nick, err)
+		reminder, err := p.reminders.Get(id)
+		if err != nil {
+			log.Printf("Hiba az emlékeztető lekérdezésekor: %v", err)
+			return loc.T("ora.error", nick)
+		} else if reminder == nil {
+			return loc.T("ora.not_found", nick)
 		}
+		owner := reminder.Nick
 
 		ownerLevel := p.adminPlugin.GetAdminLevel(owner, "")
 
@@ -388,65 +271,52 @@ func (p *OraPlugin) HandleMessage(msg irc.Message) string {
 		if owner == nick {
 			// Sajátját mindenki törölheti, aki 1-3 szintű
 			if level < 1 || level > 3 {
-				return fmt.Sprintf("@%s Nincs jogosultságod az emlékeztetők törlésére.", nick)
+				return loc.T("ora.no_delete_permission", nick)
 			}
 		} else {
 			switch level {
 			case 1:
-				return fmt.Sprintf("@%s Nem jogosult mások emlékeztetőjének törlésére.", nick)
+				return loc.T("ora.no_delete_others", nick)
 			case 2:
 				if ownerLevel != 1 {
-					return fmt.Sprintf("@%s Nem jogosult törölni ezt az emlékeztetőt.", nick)
+					return loc.T("ora.no_delete_this", nick)
 				}
 			case 3:
 				// 3-as szint törölhet bárkit
 			default:
-				return fmt.Sprintf("@%s Nincs jogosultságod az emlékeztetők törlésére.", nick)
+				return loc.T("ora.no_delete_permission", nick)
 			}
 		}
 
-		res, err := p.db.Exec("DELETE FROM reminders WHERE id = ?", id)
+		deleted, err := p.reminders.Delete(id)
 		if err != nil {
-			return fmt.Sprintf("@%s Hiba törlés közben: %v", nick, err)
+			log.Printf("Hiba az emlékeztető törlésekor: %v", err)
+			return loc.T("ora.error", nick)
 		}
 
-		affected, _ := res.RowsAffected()
-		if affected == 0 {
-			return fmt.Sprintf("@%s Nincs ilyen ID-jú emlékeztető.", nick)
+		if !deleted {
+			return loc.T("ora.not_found", nick)
 		}
 
-		if t, ok := p.timers[id]; ok {
-			t.Stop()
-			delete(p.timers, id)
-		}
+		p.ctx.Scheduler.Forget(reminderJobName(id))
 
-		return fmt.Sprintf("@%s Törölve: %d", nick, id)
+		return loc.T("ora.deleted", nick, id)
 	}
 
 	return ""
 }
 
-func formatDurationPretty(d time.Duration) string {
-	d = d.Round(time.Second)
-	seconds := int(d.Seconds())
+func (p *OraPlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
+}
 
-	if seconds < 60 {
-		return fmt.Sprintf("%d másodperc", seconds)
-	}
-	minutes := seconds / 60
-	seconds = seconds % 60
-	if minutes < 60 {
-		if seconds == 0 {
-			return fmt.Sprintf("%d perc", minutes)
-		}
-		return fmt.Sprintf("%d perc %d másodperc", minutes, seconds)
-	}
-	hours := minutes / 60
-	minutes = minutes % 60
-	if minutes == 0 {
-		return fmt.Sprintf("%d óra", hours)
-	}
-	return fmt.Sprintf("%d óra %d perc", hours, minutes)
+// Close leveszi a függő emlékeztetőket az ütemezőből; a következő indításkor
+// az adatbázisból újra felkerülnek.
+func (p *OraPlugin) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.ctx.Scheduler.RemovePrefix("ora:")
+	return nil
 }
 
 func (p *OraPlugin) OnTick() []irc.Message { return nil }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package ynm
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irctest"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestOra(t *testing.T) {
+	env, adm := admintest.NewEnv(t, func(cfg *config.Config) { cfg.OraChan = []string{"#test"} }, "vip 1 *!vip@user.test")
+	p := NewOraPlugin(env.Bot, adm, env.Ctx)
+	env.Attach(p)
+	hu := i18n.Get("hu")
+	start := irctest.Start
+	coffee, lunch := start.Add(90*time.Minute), start.Add(2*time.Hour)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		from    string
+		text    string
+		want    []string
+	}{
+		{"idegen", 0, "alice", "!ora", nil},
+		{"súgó", 0, "owner", "!ora", []string{hu.T("ora.help", "owner")}},
+		{"súgó másodszor", 0, "owner", "!ora", []string{hu.T("ora.help", "owner")}},
+		{"súgó harmadszor néma", 0, "owner", "!ora", nil},
+		{"üzenet nélkül", 0, "owner", "!ora 1h", []string{hu.T("ora.usage", "owner")}},
+		{"hibás idő", 0, "owner", "!ora holnap kávé", []string{hu.T("ora.bad_duration", "owner", "holnap")}},
+		{"mentés", 0, "owner", "!ora 1h30m Kávé", []string{hu.T("ora.saved", "owner", hu.Duration(90*time.Minute), hu.Clock(coffee))}},
+		{"VIP mentés", 0, "vip", "!ora 2h Ebéd", []string{hu.T("ora.saved", "vip", hu.Duration(2*time.Hour), hu.Clock(lunch))}},
+		{"idegen lista", 0, "alice", "!orak", []string{hu.T("ora.no_list_permission", "alice")}},
+		{"VIP csak a sajátját látja", 0, "vip", "!orak", []string{
+			hu.T("ora.list_line", 2, "vip", hu.Duration(2*time.Hour), hu.DateTime(start), hu.T("ora.status_active"), "Ebéd"),
+		}},
+		{"owner mindent lát", 30 * time.Minute, "owner", "!orak", []string{
+			hu.T("ora.list_line", 1, "owner", hu.Duration(time.Hour), hu.DateTime(start), hu.T("ora.status_active"), "Kávé"),
+			hu.T("ora.list_line", 2, "vip", hu.Duration(90*time.Minute), hu.DateTime(start), hu.T("ora.status_active"), "Ebéd"),
+		}},
+		{"VIP nem törölhet másét", 0, "vip", "!delora 1", []string{hu.T("ora.no_delete_others", "vip")}},
+		{"hibás ID", 0, "owner", "!delora x", []string{hu.T("ora.bad_id", "owner", "x")}},
+		{"nincs ilyen", 0, "owner", "!delora 99", []string{hu.T("ora.not_found", "owner")}},
+		{"törlés", 0, "owner", "!delora 2", []string{hu.T("ora.deleted", "owner", 2)}},
+	}
+	for _, st := range steps {
+		env.Clock.Advance(st.advance)
+		if got := env.Say(t, st.from, "#test", st.text); !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, st.want)
+		}
+	}
+
+	// az óra előtt nem jelez, utána egyszer, a törölt emlékeztető pedig nem
+	for _, tt := range []struct {
+		advance time.Duration
+		want    []string
+	}{
+		{59 * time.Minute, nil},
+		{time.Minute, []string{"#test: " + hu.T("ora.reminder", "owner", "Kávé", hu.DateTime(start))}},
+		{time.Hour, nil},
+	} {
+		if got := env.Tick(t, tt.advance); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, várt %q", env.Clock.Now().Format("15:04"), got, tt.want)
+		}
+	}
+
+	if got := env.Say(t, "owner", "#test", "!orak"); !slices.Equal(got, []string{
+		hu.T("ora.list_line", 1, "owner", hu.Duration(-time.Hour), hu.DateTime(start), hu.T("ora.status_expired"), "Kávé"),
+	}) {
+		t.Errorf("lejárt lista: %q", got)
+	}
+}
+
+func TestOraRestart(t *testing.T) {
+	env, adm := admintest.NewEnv(t, func(cfg *config.Config) { cfg.OraChan = []string{"#test"} })
+	p := NewOraPlugin(env.Bot, adm, env.Ctx)
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	env.Say(t, "owner", "#test", "!ora 1d Holnap")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	env.Clock.Advance(2 * 24 * time.Hour)
+
+	// újraindítás után az adatbázisból kerül vissza, és a lekésett emlékeztető egyszer lefut
+	NewOraPlugin(env.Bot, adm, env.Ctx)
+	want := []string{"#test: " + hu.T("ora.reminder", "owner", "Holnap", hu.DateTime(irctest.Start))}
+	if got := env.Tick(t, 0); !slices.Equal(got, want) {
+		t.Errorf("pótlás: %q, várt %q", got, want)
+	}
+	if got := env.Tick(t, 0); len(got) != 0 {
+		t.Errorf("ismételt küldés: %q", got)
+	}
+}
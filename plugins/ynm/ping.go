@@ -18,32 +18,31 @@ import (
     "time"
     "fmt"
     "sync"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
 )
 
 type PingPlugin struct {
     pingSentAt      map[string]time.Time
     pingChannel     map[string]string
-    userPingTimes   map[string][]time.Time
-    userBanUntil    map[string]time.Time
-    userBanNotified map[string]bool
+    pingLocale      map[string]*i18n.Locale // a kérő nyelve
     mu              sync.Mutex
-    bot             *irc.Client
-    cooldown        time.Duration
+    bot             irc.Sender
     adminPlugin     *admin.AdminPlugin  // hozzáadva
+    tr              pluginapi.Localizer
 }
 
-// Konstruktor a PingPluginhez
-func NewPingPlugin(bot *irc.Client, cooldown time.Duration, adminPlugin *admin.AdminPlugin) *PingPlugin {
+// Konstruktor a PingPluginhez.
+// A gyakoriság korlátozását (ping.cooldown, tiltás) a közös ratelimit réteg végzi.
+func NewPingPlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, tr pluginapi.Localizer) *PingPlugin {
     return &PingPlugin{
         pingSentAt:      make(map[string]time.Time),
         pingChannel:     make(map[string]string),
-        userPingTimes:   make(map[string][]time.Time),
-        userBanUntil:    make(map[string]time.Time),
-        userBanNotified: make(map[string]bool),
+        pingLocale:      make(map[string]*i18n.Locale),
         bot:             bot,
-        cooldown:        cooldown,
         adminPlugin:     adminPlugin,  // beállítva
+        tr:              tr,
     }
 }
 
@@ -71,59 +70,12 @@ func (p *PingPlugin) HandleMessage(msg irc.Message) string {
     defer p.mu.Unlock()
 
     now := time.Now()
-    user := msg.Sender
-
-    // Kitöröljük az 5 percnél régebbi ping időpontokat
-    times := p.userPingTimes[user]
-    validTimes := []time.Time{}
-    for _, t := range times {
-        if now.Sub(t) <= 5*time.Minute {
-            validTimes = append(validTimes, t)
-        }
-    }
-    p.userPingTimes[user] = validTimes
-
-    // Tiltás ellenőrzése
-    banUntil, banned := p.userBanUntil[user]
-    if banned {
-        if now.Before(banUntil) {
-            // Tiltás alatt van
-            if !p.userBanNotified[user] {
-                remaining := banUntil.Sub(now).Round(time.Second)
-                p.userBanNotified[user] = true  // jelezzük, hogy már figyelmeztettünk
-                return fmt.Sprintf("Túl sok !ping parancs, kérlek várj még %s-ig!", remaining)
-            }
-            return "" // már jeleztük, nem válaszolunk többször
-        } else {
-            // Tiltás lejárt, töröljük az adatokat és a figyelmeztető flag-et
-            delete(p.userBanUntil, user)
-            delete(p.userBanNotified, user)
-        }
-    }
-
-    // Ha 3 vagy több hívás van 5 perc alatt, tiltjuk 24 órára
-    if len(validTimes) >= 3 {
-        p.userBanUntil[user] = now.Add(24 * time.Hour)
-        p.userPingTimes[user] = nil // reseteljük az időpontokat
-        p.userBanNotified[user] = true // azonnal jelezzük az új tiltást
-        return "Túl sok !ping parancs, ezért 24 órára le vagy tiltva erről a parancsról."
-    }
-
-    // Alap cooldown ellenőrzése (pl. 30s)
-    if len(validTimes) > 0 {
-        last := validTimes[len(validTimes)-1]
-        if now.Sub(last) < p.cooldown {
-            return "" // túl gyors, nem válaszolunk
-        }
-    }
-
-    // Hozzáadjuk az aktuális időpontot
-    p.userPingTimes[user] = append(p.userPingTimes[user], now)
 
     // Ping küldése az IRC szerver felé
     id := fmt.Sprintf("%d", now.UnixNano())
     p.pingSentAt[id] = now
     p.pingChannel[id] = msg.Channel
+    p.pingLocale[id] = p.tr.LocaleFor(msg)
     p.bot.SendRaw(fmt.Sprintf("PING %s", id))
 
     return ""
@@ -135,16 +87,18 @@ func (p *PingPlugin) HandlePong(id string) {
     p.mu.Lock()
     start, ok := p.pingSentAt[id]
     channel, chOk := p.pingChannel[id]
+    loc := p.pingLocale[id]
     if !ok || !chOk {
         p.mu.Unlock()
         return
     }
     delete(p.pingSentAt, id)
     delete(p.pingChannel, id)
+    delete(p.pingLocale, id)
     p.mu.Unlock()
 
     elapsed := time.Since(start)
-    p.bot.SendMessage(channel, fmt.Sprintf("PING reply of %.3f s", elapsed.Seconds()))
+    p.bot.SendMessage(channel, loc.T("ping.reply", elapsed.Seconds()))
 }
 
 func (p *PingPlugin) OnTick() []irc.Message {
@@ -0,0 +1,68 @@
+package ynm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestPing(t *testing.T) {
+	env, adm := admintest.NewEnv(t, nil)
+	p := NewPingPlugin(env.Bot, adm, env.Ctx)
+	env.SetPongHandler(p.HandlePong)
+	env.Attach(p)
+	if err := env.Ctx.Set("#en", "language", "en"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		from    string
+		channel string
+		text    string
+		prefix  string // a válasz eleje ("" ha nincs válasz)
+	}{
+		{"owner", "owner", "#test", "!ping", "🏓 PING"},
+		{"nagybetűvel", "owner", "#test", "!PING", "🏓 PING"},
+		{"angol csatorna", "owner", "#en", "!ping", "🏓 PING reply"},
+		{"idegen", "alice", "#test", "!ping", ""},
+		{"paraméterrel", "owner", "#test", "!ping most", ""},
+	}
+	for _, tt := range tests {
+		from := env.Server.Len()
+		env.Say(t, tt.from, tt.channel, tt.text)
+		// a PONG a szinkron után érkezik vissza a bothoz
+		if err := env.Server.Sync(); err != nil {
+			t.Fatal(err)
+		}
+		var sent, replies []string
+		for _, l := range env.Server.Lines()[from:] {
+			switch {
+			case l.Command == "PING":
+				sent = append(sent, l.Text())
+			case l.Command == "PRIVMSG" && l.Target() == tt.channel:
+				replies = append(replies, l.Text())
+			}
+		}
+		if tt.prefix == "" {
+			if len(sent) != 0 || len(replies) != 0 {
+				t.Errorf("%s: nem várt PING %q / válasz %q", tt.name, sent, replies)
+			}
+			continue
+		}
+		if len(sent) != 1 || len(replies) != 1 || !strings.HasPrefix(replies[0], tt.prefix) {
+			t.Errorf("%s: PING %q, válasz %q", tt.name, sent, replies)
+		}
+	}
+
+	// ismeretlen azonosítójú PONG-ra nincs válasz
+	before := len(env.Server.Messages("#test"))
+	p.HandlePong("ismeretlen")
+	if err := env.Server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if after := len(env.Server.Messages("#test")); after != before {
+		t.Errorf("ismeretlen PONG-ra is válaszolt")
+	}
+}
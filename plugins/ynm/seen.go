@@ -0,0 +1,286 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu
+//  YnM-Go IRC bot plugin: Seen (!seen) – ki mikor járt utoljára a csatornákon
+// ============================================================================
+
+package ynm
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/ignore"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+type seenRecord = storage.SeenRecord
+
+// SeenPlugin nyilvántartja a nickek utolsó üzenetét, belépését, kilépését,
+// nickváltását és kirúgását; a !seen ezekből válaszol. Aki nem kéri, a
+// !seen off paranccsal kimarad a nyilvántartásból.
+type SeenPlugin struct {
+	seen *storage.SeenRepo
+	mu   sync.Mutex
+	bot  irc.Conn
+	tr   pluginapi.Localizer
+	now  func() time.Time
+}
+
+// NewSeenPlugin a közös adatbázis seen tábláira épül
+func NewSeenPlugin(bot irc.Conn, seen *storage.SeenRepo, tr pluginapi.Localizer) *SeenPlugin {
+	return &SeenPlugin{seen: seen, bot: bot, tr: tr, now: time.Now}
+}
+
+func (p *SeenPlugin) Name() string { return "SeenPlugin" }
+
+// ObserveMessage minden csatornaüzenetet rögzít (pluginapi.MessageObserver)
+func (p *SeenPlugin) ObserveMessage(msg irc.Message) {
+	if !isChannelName(msg.Channel) {
+		return
+	}
+	action, text := "message", msg.Text
+	if strings.HasPrefix(text, "\x01ACTION ") {
+		action, text = "action", strings.Trim(strings.TrimPrefix(text, "\x01ACTION "), "\x01")
+	} else if strings.HasPrefix(text, "\x01") {
+		return // egyéb CTCP
+	}
+	p.record(seenRecord{
+		Nick: msg.Nick, Channel: msg.Channel, Account: msg.Account,
+		Mask: msg.Sender, Host: hostOf(msg.Sender), Action: action, Text: text,
+	})
+}
+
+// HandleEvent a JOIN/PART/QUIT/NICK/KICK eseményeket rögzíti (pluginapi.EventHandler)
+func (p *SeenPlugin) HandleEvent(ev irc.Event) {
+	r := seenRecord{Nick: ev.Nick, Channel: ev.Channel, Mask: ev.Sender, Host: hostOf(ev.Sender), Text: ev.Text}
+	switch ev.Type {
+	case "JOIN":
+		r.Action, r.Text = "join", ""
+	case "PART":
+		r.Action = "part"
+	case "QUIT":
+		r.Action = "quit"
+	case "NICK":
+		r.Action, r.Text, r.Other = "nick", "", ev.Target
+		p.record(r)
+		// az új nick ugyanaz a személy: ugyanazzal a hosttal folytatódik
+		r = seenRecord{Nick: ev.Target, Mask: ev.Target + "!" + r.Host, Host: r.Host, Action: "renamed", Other: ev.Nick}
+	case "KICK":
+		// az eseményben a kirúgó adatai vannak, a kirúgotté a korábbi bejegyzéséből jön
+		r = seenRecord{Nick: ev.Target, Channel: ev.Channel, Action: "kick", Text: ev.Text, Other: ev.Nick}
+		if prev, ok := p.latest([]string{ev.Target}); ok {
+			r.Account, r.Host, r.Mask = prev.Account, prev.Host, prev.Mask
+		}
+	default:
+		return
+	}
+	p.record(r)
+}
+
+func (p *SeenPlugin) record(r seenRecord) {
+	if r.Nick == "" || strings.EqualFold(r.Nick, p.bot.GetNick()) {
+		return
+	}
+	r.Time = p.now()
+
+	// a zár alatt a kimaradás ellenőrzése és a mentés nem csúszhat szét egy !seen off-fal
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// a fiók csak az üzenetekben érkezik (account-tag); a többi esemény örökli
+	if r.Account == "" {
+		r.Account, _ = p.seen.Account(r.Nick)
+	}
+	if p.optedOut(r.Nick, r.Account, r.Host) {
+		return
+	}
+	if err := p.seen.Put(r); err != nil {
+		log.Printf("❌ Seen mentési hiba: %v", err)
+	}
+}
+
+// HandleMessage: !seen <nick|maszk> | !seen off | !seen on
+func (p *SeenPlugin) HandleMessage(msg irc.Message) string {
+	parts := strings.Fields(msg.Text)
+	if len(parts) == 0 || strings.ToLower(parts[0]) != "!seen" {
+		return ""
+	}
+	loc := p.tr.LocaleFor(msg)
+	if len(parts) != 2 {
+		return loc.T("seen.usage")
+	}
+	arg := parts[1]
+
+	switch strings.ToLower(arg) {
+	case "off":
+		if err := p.optOut(msg); err != nil {
+			log.Printf("❌ Seen mentési hiba: %v", err)
+			return loc.T("seen.error")
+		}
+		return loc.T("seen.off", msg.Nick)
+	case "on":
+		if err := p.optIn(msg); err != nil {
+			log.Printf("❌ Seen mentési hiba: %v", err)
+			return loc.T("seen.error")
+		}
+		return loc.T("seen.on", msg.Nick)
+	}
+
+	if strings.EqualFold(arg, p.bot.GetNick()) {
+		return loc.T("seen.self_bot")
+	}
+	if strings.EqualFold(arg, msg.Nick) {
+		return loc.T("seen.self", msg.Nick)
+	}
+	if strings.ContainsAny(arg, "*?!@") {
+		return p.seenMask(loc, arg, msg.Channel)
+	}
+	return p.seenNick(loc, arg, msg.Channel)
+}
+
+func (p *SeenPlugin) seenNick(loc *i18n.Locale, nick, asked string) string {
+	own, ok := p.latest([]string{nick})
+	if !ok {
+		// a kimaradást kérők sorait a lekérdezés kiszűri; ha a nicken más
+		// személy bejegyzése látható, arról válaszolunk
+		if out, _ := p.seen.NickOptedOut(nick); out {
+			return loc.T("seen.opted_out", nick)
+		}
+		return loc.T("seen.never", nick)
+	}
+	answer := p.describe(loc, own, asked)
+
+	// nickváltás után az új néven folytatjuk (legfeljebb néhány lépésig)
+	seen := map[string]bool{strings.ToLower(own.Nick): true}
+	for r := own; r.Action == "nick" && r.Other != "" && !seen[strings.ToLower(r.Other)] && len(seen) < 5; {
+		seen[strings.ToLower(r.Other)] = true
+		next, ok := p.latest([]string{r.Other})
+		if !ok || next.Time.Before(r.Time) {
+			break
+		}
+		answer += " " + loc.T("seen.since", p.describe(loc, next, asked))
+		r = next
+	}
+
+	// ugyanaz a személy más nicken (fiók vagy host alapján), ha az frissebb
+	if group, ok := p.latestInGroup(own); ok && group.Time.After(own.Time) && !seen[strings.ToLower(group.Nick)] {
+		answer += " " + loc.T("seen.also", group.Nick, p.describe(loc, group, asked))
+	}
+	return answer
+}
+
+func (p *SeenPlugin) seenMask(loc *i18n.Locale, pattern, asked string) string {
+	mask, err := ignore.NormalizeMask(pattern)
+	if err != nil || strings.HasPrefix(mask, ignore.AccountPrefix) {
+		return loc.T("seen.bad_mask")
+	}
+	// a kimaradást kérők sorait már a lekérdezés kiszűri
+	records, err := p.seen.WithMask()
+	if err != nil {
+		log.Printf("❌ Seen lekérdezési hiba: %v", err)
+		return loc.T("seen.error")
+	}
+
+	matched := make(map[string]bool)
+	var best *seenRecord
+	for _, r := range records {
+		if !ignore.Wildcard(mask, r.Mask) {
+			continue
+		}
+		matched[strings.ToLower(r.Nick)] = true
+		if best == nil {
+			best = &r
+		}
+	}
+	if best == nil {
+		return loc.T("seen.mask_none", mask)
+	}
+	return loc.T("seen.mask", mask, len(matched), p.describe(loc, *best, asked))
+}
+
+// describe mondat a bejegyzésről loc nyelvén; az üzenet szövegét csak a saját csatornáján mutatjuk
+func (p *SeenPlugin) describe(loc *i18n.Locale, r seenRecord, asked string) string {
+	ago := loc.Ago(p.now().Sub(r.Time))
+	reason := ""
+	if r.Text != "" {
+		reason = " (" + r.Text + ")"
+	}
+	switch r.Action {
+	case "message", "action":
+		if !strings.EqualFold(r.Channel, asked) {
+			return loc.T("seen.message_elsewhere", r.Nick, ago, r.Channel)
+		}
+		if r.Action == "action" {
+			return loc.T("seen.action", r.Nick, ago, r.Channel, r.Text)
+		}
+		return loc.T("seen.message", r.Nick, ago, r.Channel, r.Text)
+	case "join":
+		return loc.T("seen.join", r.Nick, ago, r.Channel)
+	case "part":
+		return loc.T("seen.part", r.Nick, ago, r.Channel, reason)
+	case "quit":
+		return loc.T("seen.quit", r.Nick, ago, reason)
+	case "kick":
+		return loc.T("seen.kick", r.Nick, ago, r.Channel, r.Other, reason)
+	case "nick":
+		return loc.T("seen.nick", r.Nick, ago, r.Other)
+	case "renamed":
+		return loc.T("seen.renamed", r.Nick, ago, r.Other)
+	}
+	return loc.T("seen.active", r.Nick, ago)
+}
+
+// latest a nickek közül a legutóbbi bejegyzés (bármely csatornán)
+func (p *SeenPlugin) latest(nicks []string) (seenRecord, bool) {
+	r, ok, err := p.seen.Latest(nicks...)
+	return r, ok && err == nil
+}
+
+// latestInGroup a személy (azonos fiók vagy user@host) más nickjeinek legutóbbi bejegyzése
+func (p *SeenPlugin) latestInGroup(r seenRecord) (seenRecord, bool) {
+	g, ok, err := p.seen.LatestInGroup(r.Nick, r.Account, r.Host)
+	if !ok || err != nil {
+		return seenRecord{}, false
+	}
+	return g, true
+}
+
+func (p *SeenPlugin) optedOut(nick, account, host string) bool {
+	out, _ := p.seen.OptedOut(nick, account, host)
+	return out
+}
+
+// optOut a hívó saját kulcsára (fiók, különben nick!user@host) kéri a
+// kimaradást, így egy azonos nicket használó más felhasználó nem törölheti
+// a valódi tulajdonos bejegyzéseit
+func (p *SeenPlugin) optOut(msg irc.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.seen.OptOut(msg.Nick, msg.Account, hostOf(msg.Sender), p.now())
+}
+
+func (p *SeenPlugin) optIn(msg irc.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.seen.OptIn(msg.Nick, msg.Account, hostOf(msg.Sender))
+}
+
+// hostOf a nick!user@host előtag user@host része, a "~" ident-jelölés nélkül
+func hostOf(sender string) string {
+	_, host, ok := strings.Cut(sender, "!")
+	if !ok {
+		return ""
+	}
+	return strings.TrimPrefix(host, "~")
+}
+
+func isChannelName(target string) bool {
+	return strings.HasPrefix(target, "#") || strings.HasPrefix(target, "&")
+}
+
+func (p *SeenPlugin) OnTick() []irc.Message { return nil }
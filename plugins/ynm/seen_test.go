@@ -0,0 +1,111 @@
+package ynm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestSeen(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	p := NewSeenPlugin(env.Bot, env.DB.Seen, env.Ctx)
+	p.now = env.Clock.Now
+	env.Attach(p)
+	hu := i18n.Get("hu")
+	srv := env.Server
+
+	events := func(fn func()) func() {
+		return func() {
+			if _, err := srv.Exchange(fn); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	steps := []struct {
+		name    string
+		before  func()
+		advance time.Duration
+		from    string
+		channel string
+		text    string
+		want    string
+	}{
+		{"használat", nil, 0, "bob", "#test", "!seen", hu.T("seen.usage")},
+		{"ismeretlen", nil, 0, "bob", "#test", "!seen zed", hu.T("seen.never", "zed")},
+		{"saját maga", nil, 0, "bob", "#test", "!seen bob", hu.T("seen.self", "bob")},
+		{"a bot", nil, 0, "bob", "#test", "!seen YnM", hu.T("seen.self_bot")},
+		{"üzenet", func() { env.Say(t, "alice", "#test", "hello") }, 3 * time.Hour,
+			"bob", "#test", "!seen alice", hu.T("seen.message", "alice", hu.Ago(3*time.Hour), "#test", "hello")},
+		{"üzenet máshonnan kérdezve", nil, 0,
+			"bob", "#other", "!seen ALICE", hu.T("seen.message_elsewhere", "alice", hu.Ago(3*time.Hour), "#test")},
+		{"kilépés", events(func() { srv.Part("alice", "#test", "bye") }), time.Hour,
+			"bob", "#test", "!seen alice", hu.T("seen.part", "alice", hu.Ago(time.Hour), "#test", " (bye)")},
+		{"nickváltás", events(func() { srv.Join("dave", "#test"); srv.Nick("dave", "dave2") }), 2 * time.Minute,
+			"bob", "#test", "!seen dave", hu.T("seen.nick", "dave", hu.Ago(2*time.Minute), "dave2") + " " +
+				hu.T("seen.since", hu.T("seen.renamed", "dave2", hu.Ago(2*time.Minute), "dave"))},
+		{"maszk", nil, 0, "bob", "#test", "!seen dave2!*@*",
+			hu.T("seen.mask", "dave2!*@*", 1, hu.T("seen.renamed", "dave2", hu.Ago(2*time.Minute), "dave"))},
+		{"hibás maszk", nil, 0, "bob", "#test", "!seen $a:x*", hu.T("seen.bad_mask")},
+		{"kirúgás", events(func() { srv.Kick("op", "#test", "dave2", "flood") }), time.Minute,
+			"bob", "#test", "!seen dave2", hu.T("seen.kick", "dave2", hu.Ago(time.Minute), "#test", "op", " (flood)")},
+		{"kijelentkezés a nyilvántartásból", nil, 0, "carol", "#test", "!seen off", hu.T("seen.off", "carol")},
+		{"kijelentkezett", func() { env.Say(t, "carol", "#test", "itt vagyok") }, 0,
+			"bob", "#test", "!seen carol", hu.T("seen.opted_out", "carol")},
+		{"visszajelentkezés", nil, 0, "carol", "#test", "!seen on", hu.T("seen.on", "carol")},
+		{"újra nyilvántartva", func() { env.Say(t, "carol", "#test", "\x01ACTION integet\x01") }, time.Minute,
+			"bob", "#test", "!seen carol", hu.T("seen.action", "carol", hu.Ago(time.Minute), "#test", "integet")},
+	}
+	for _, st := range steps {
+		if st.before != nil {
+			st.before()
+		}
+		env.Clock.Advance(st.advance)
+		got := env.Say(t, st.from, st.channel, st.text)
+		if len(got) != 1 || got[0] != st.want {
+			t.Errorf("%s: %q → %q, várt %q", st.name, st.text, got, st.want)
+		}
+	}
+
+	// a bot saját sorai nem kerülnek a nyilvántartásba
+	if _, ok, _ := env.DB.Seen.Latest("YnM"); ok {
+		t.Error("a bot önmagát is nyilvántartja")
+	}
+	if _, ok, _ := env.DB.Seen.Latest("carol"); !ok {
+		t.Error("carol hiányzik")
+	}
+}
+
+// Aki ugyanazt a nicket használja más hostról, nem jelentheti ki a valódi tulajdonost
+func TestSeenOptOutOwnOnly(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	p := NewSeenPlugin(env.Bot, env.DB.Seen, env.Ctx)
+	p.now = env.Clock.Now
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	env.SayAs(t, "alice", "alice", "#test", "hello")
+	env.Clock.Advance(time.Hour)
+	// privátban, hogy a saját üzenete ne írja felül alice csatornás sorát
+	if got := env.Say(t, "alice!x@evil.test", "YnM", "!seen off"); len(got) != 1 || got[0] != hu.T("seen.off", "alice") {
+		t.Fatalf("!seen off: %q", got)
+	}
+	if out, _ := env.DB.Seen.OptedOut("alice", "alice", "alice@user.test"); out {
+		t.Error("a fiókos felhasználó kimaradt egy idegen kérésére")
+	}
+	if _, ok, _ := env.DB.Seen.Latest("alice"); !ok {
+		t.Fatal("az idegen kérés törölte a valódi alice bejegyzéseit")
+	}
+
+	// a fiókhoz kötött kimaradás minden hostról érvényes
+	env.SayAs(t, "alice", "alice", "#test", "!seen off")
+	env.SayAs(t, "alice!a@other.test", "alice", "#test", "itt vagyok")
+	if got := env.Say(t, "bob", "#test", "!seen alice"); len(got) != 1 || got[0] != hu.T("seen.opted_out", "alice") {
+		t.Errorf("!seen alice: %q", got)
+	}
+	if got := env.Say(t, "bob", "#test", "!seen alice!*@*"); len(got) != 1 || got[0] != hu.T("seen.mask_none", "alice!*@*") {
+		t.Errorf("!seen maszk: %q", got)
+	}
+}
@@ -11,26 +11,30 @@ import (
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/mem"
 	"github.com/shirou/gopsutil/process"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 )
 
 type StatusPlugin struct {
-	client    *irc.Client
+	client    irc.Conn
 	startTime time.Time
+	tr        pluginapi.Localizer
 }
 
 var threadNames = []string{"MainThread", "uptime", "known_users", "message_sender", "auto_update"}
 
-func NewStatusPlugin(client *irc.Client) *StatusPlugin {
+func NewStatusPlugin(client irc.Conn, tr pluginapi.Localizer) *StatusPlugin {
 	return &StatusPlugin{
 		client:    client,
 		startTime: time.Now(),
+		tr:        tr,
 	}
 }
 
 func (p *StatusPlugin) HandleMessage(msg irc.Message) string {
 	if strings.TrimSpace(msg.Text) == "!status" {
-		p.StatusCommand(msg.Sender, msg.Channel)
+		p.StatusCommand(p.tr.LocaleFor(msg), msg.Channel)
 		return ""
 	}
 	return ""
@@ -88,7 +92,7 @@ func (p *StatusPlugin) getProcessMemoryMB() float64 {
 	return float64(memInfo.RSS) / 1024.0 / 1024.0 // MB
 }
 
-func (p *StatusPlugin) StatusCommand(nick, channel string) {
+func (p *StatusPlugin) StatusCommand(loc *i18n.Locale, channel string) {
 	threadCount := runtime.NumGoroutine()
 	threadList := strings.Join(threadNames, ", ")
 
@@ -109,34 +113,34 @@ func (p *StatusPlugin) StatusCommand(nick, channel string) {
 
 	processMemMB := p.getProcessMemoryMB()
     
-		tlsStatus := "🔓 Insecure"
+		tlsStatus := loc.T("status.insecure")
 	if p.client.IsTLS() {
-		tlsStatus = "🔐 TLS enabled"
+		tlsStatus = loc.T("status.tls")
 	}
 	osType := runtime.GOOS
 	arch := runtime.GOARCH
-	uptime := time.Since(p.startTime).Truncate(time.Second)
+	uptime := time.Since(p.startTime)
 
 	// A botod adatainak lekérése (dummy értékek, cseréld saját adataidra)
 	loggedUsers := len(p.client.GetLoggedUsers())    // vagy hasonló
 	channels := len(p.client.GetJoinedChannels())    // ha van ilyen metódusod, különben dummy
 	botNick := p.client.GetNick()                     // ha van getter, különben konstans
 
-	p.SendMessage(channel, "📊 *Advanced Status Report*")
-	p.SendMessage(channel, fmt.Sprintf("🔢 Threads: %d — %s", threadCount, threadList))
-	p.SendMessage(channel, fmt.Sprintf("👥 Logged Users: %d | 🧑‍🤝‍🧑 Channels: %d", loggedUsers, channels))
-	p.SendMessage(channel, fmt.Sprintf("📦 GC Objects: %d", gcObjects))
+	p.SendMessage(channel, loc.T("status.title"))
+	p.SendMessage(channel, loc.T("status.threads", threadCount, threadList))
+	p.SendMessage(channel, loc.T("status.users", loggedUsers, channels))
+	p.SendMessage(channel, loc.T("status.gc", gcObjects))
 	p.SendMessage(channel, tlsStatus)
-	p.SendMessage(channel, fmt.Sprintf("🧠 RAM (Go heap): %.2f MB | RAM (process): %.2f MB / %.0f MB", ramUsed, processMemMB, totalMemMB))
-	p.SendMessage(channel, fmt.Sprintf("🔄 CPU: %s", func() string {
+	p.SendMessage(channel, loc.T("status.ram", ramUsed, processMemMB, totalMemMB))
+	p.SendMessage(channel, loc.T("status.cpu", func() string {
 		if cpuPercent < 0 {
-			return "n/a"
+			return loc.T("status.na")
 		}
 		return fmt.Sprintf("%.2f%%", cpuPercent)
 	}()))
-	p.SendMessage(channel, fmt.Sprintf("💻 System: %s | CPU Arch: %s", osType, arch))
-	p.SendMessage(channel, fmt.Sprintf("⏱️ Uptime: %s", uptime.String()))
-	p.SendMessage(channel, fmt.Sprintf("🤖 Bot nick: %s", botNick))
+	p.SendMessage(channel, loc.T("status.system", osType, arch))
+	p.SendMessage(channel, loc.T("status.uptime", loc.Duration(uptime)))
+	p.SendMessage(channel, loc.T("status.nick", botNick))
 }
 
 func (p *StatusPlugin) OnTick() []irc.Message {
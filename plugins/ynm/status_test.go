@@ -0,0 +1,45 @@
+package ynm
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestStatus(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	env.Server.Join("alice", "#test")
+	env.Enter(t, "#test")
+	p := NewStatusPlugin(env.Bot, env.Ctx)
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	tests := []struct {
+		text string
+		want map[int]string // sor indexe → várt szöveg
+		n    int
+	}{
+		{"!status", map[int]string{
+			0: hu.T("status.title"),
+			2: hu.T("status.users", 2, 1),
+			4: hu.T("status.insecure"),
+			7: hu.T("status.system", runtime.GOOS, runtime.GOARCH),
+			9: hu.T("status.nick", "YnM"),
+		}, 10},
+		{"!status most", nil, 0},
+		{"status", nil, 0},
+	}
+	for _, tt := range tests {
+		got := env.Say(t, "alice", "#test", tt.text)
+		if len(got) != tt.n {
+			t.Fatalf("%q: %d sor, várt %d: %q", tt.text, len(got), tt.n, got)
+		}
+		for i, want := range tt.want {
+			if got[i] != want {
+				t.Errorf("%q %d. sor: %q, várt %q", tt.text, i, got[i], want)
+			}
+		}
+	}
+}
@@ -4,75 +4,110 @@
 package ynm
 
 import (
+	"fmt"
 	"log"
 	"sync"
 	"time"
 	"github.com/mmcdole/gofeed"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/scheduler"
 )
 
+// a hírellenőrzés feladatneve az ütemezőben
+const szekelyhonJob = "szekelyhon"
+
+const szekelyhonFeedURL = "https://szekelyhon.ro/rss/szekelyhon_hirek.xml"
+
 type SzekelyhonPlugin struct {
-	bot       *irc.Client
-	channels  []string
-	startHour int
-	endHour   int
+	bot       irc.Sender
+	ctx       *pluginapi.Context // szekelyhon.enabled / start_hour / end_hour csatornánként
 	interval  time.Duration
+	feedURL   string
 	lastCheck *time.Time
 	mutex     sync.RWMutex
-	ticker    *time.Ticker
-	stopChan  chan struct{}
+	filter    pluginapi.ChannelFilter
 }
 
-func NewSzekelyhonPlugin(bot *irc.Client, channels []string, interval time.Duration, startHour, endHour int) *SzekelyhonPlugin {
+func NewSzekelyhonPlugin(bot irc.Sender, ctx *pluginapi.Context, interval time.Duration) *SzekelyhonPlugin {
 	// Inicializáljuk a lastCheck-et az aktuális időre, hogy ne küldjön minden hírt az első futáskor
-	now := time.Now()
+	now := ctx.Now()
 	return &SzekelyhonPlugin{
 		bot:       bot,
-		channels:  channels,
+		ctx:       ctx,
 		interval:  interval,
-		startHour: startHour,
-		endHour:   endHour,
+		feedURL:   szekelyhonFeedURL,
 		lastCheck: &now,
-		stopChan:  make(chan struct{}),
 	}
 }
 
 func (p *SzekelyhonPlugin) Start() {
-	log.Printf("ℹ️ Székelyhon plugin elindult. Időzítés: %v, időablak: %02d–%02d", p.interval, p.startHour, p.endHour)
-	
-	p.ticker = time.NewTicker(p.interval)
-	
-	go func() {
-		for {
-			select {
-			case <-p.ticker.C:
-				p.checkAndSendNews()
-			case <-p.stopChan:
-				p.ticker.Stop()
-				return
-			}
-		}
-	}()
+	p.mutex.RLock()
+	interval := p.interval
+	p.mutex.RUnlock()
+	log.Printf("ℹ️ Székelyhon plugin elindult. Időzítés: %v, alap időablak: %02d–%02d", interval,
+		p.ctx.SettingInt("", "szekelyhon.start_hour"), p.ctx.SettingInt("", "szekelyhon.end_hour"))
+	p.schedule(interval)
+}
+
+// schedule felveszi (vagy az azonos nevű feladatot lecserélve átütemezi) a hírellenőrzést
+func (p *SzekelyhonPlugin) schedule(interval time.Duration) {
+	err := p.ctx.Schedule(scheduler.Job{
+		Name:   szekelyhonJob,
+		Spec:   "@every " + interval.String(),
+		Missed: scheduler.MissedSkip,
+		Run: func() error {
+			p.checkAndSendNews()
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Székelyhon ütemezési hiba: %v", err)
+	}
 }
 
 func (p *SzekelyhonPlugin) Stop() {
-	close(p.stopChan)
-	if p.ticker != nil {
-		p.ticker.Stop()
+	p.ctx.Scheduler.Remove(szekelyhonJob)
+}
+
+// ReloadConfig csak az ellenőrzési időközt cseréli, a futó feladatot átütemezi
+// (nem indítja újra a plugint); az időablak a beállításokból élőben jön
+func (p *SzekelyhonPlugin) ReloadConfig(ev pluginapi.ReloadEvent) error {
+	if !ev.Changes.Has("SzekelyhonInterval") {
+		return nil
 	}
+	if ev.New.SzekelyhonInterval <= 0 {
+		return fmt.Errorf("a SzekelyhonInterval nincs megadva")
+	}
+	interval := ev.New.SzekelyhonInterval.Std()
+	p.mutex.Lock()
+	p.interval = interval
+	p.mutex.Unlock()
+	p.schedule(interval)
+	log.Printf("ℹ️ Székelyhon időzítés módosítva: %v", interval)
+	return nil
 }
 
 func (p *SzekelyhonPlugin) checkAndSendNews() {
-	now := time.Now()
+	now := p.ctx.Now()
 	log.Printf("🕒 Székelyhon ellenőrzés fut: %02d:%02d", now.Hour(), now.Minute())
-	
-	if now.Hour() < p.startHour || now.Hour() >= p.endHour {
-		log.Printf("⏰ Székelyhon: Az aktuális idő (%02d:%02d) kívül esik az aktív időablakon (%02d–%02d)", 
-			now.Hour(), now.Minute(), p.startHour, p.endHour)
+
+	// Csak azok a csatornák, amelyek időablakában vagyunk
+	var channels []string
+	for _, ch := range p.ctx.Channels("szekelyhon.enabled") {
+		start := p.ctx.SettingInt(ch, "szekelyhon.start_hour")
+		end := p.ctx.SettingInt(ch, "szekelyhon.end_hour")
+		if now.Hour() >= start && now.Hour() < end {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		log.Printf("⏰ Székelyhon: Az aktuális idő (%02d:%02d) egyik csatorna időablakába sem esik",
+			now.Hour(), now.Minute())
 		return
 	}
 	
-	feed, err := gofeed.NewParser().ParseURL("https://szekelyhon.ro/rss/szekelyhon_hirek.xml")
+	feed, err := gofeed.NewParser().ParseURL(p.feedURL)
 	if err != nil {
 		log.Printf("❌ Székelyhon RSS olvasási hiba: %v", err)
 		return
@@ -99,14 +134,29 @@ func (p *SzekelyhonPlugin) checkAndSendNews() {
 		p.lastCheck = latest.PublishedParsed
 		p.mutex.Unlock()
 		
-		msg := "📰: " + latest.Title + " - Link: " + latest.Link + " - Közzétéve: " + latest.Published
-		
-		for _, ch := range p.channels {
-			p.bot.SendMessage(ch, msg)
+		for _, ch := range channels {
+			if !p.filter.Allows(ch) {
+				continue
+			}
+			loc := p.ctx.Locale(ch)
+			p.bot.SendMessage(ch, loc.T("szekelyhon.news", latest.Title, latest.Link, loc.DateTime(latest.PublishedParsed.Local())))
 			log.Printf("✅ Székelyhon hír elküldve a %s csatornára: %s", ch, latest.Title)
 		}
 	}
 }
 func (p *SzekelyhonPlugin) Name() string {
 	return "Székelyhon RSS"
+}
+
+func (p *SzekelyhonPlugin) SetChannelFilter(f pluginapi.ChannelFilter) {
+	p.filter = f
+}
+
+// A plugin nem reagál parancsokra, csak időzítetten küld
+func (p *SzekelyhonPlugin) HandleMessage(msg irc.Message) string {
+	return ""
+}
+
+func (p *SzekelyhonPlugin) OnTick() []irc.Message {
+	return nil
 }
\ No newline at end of file
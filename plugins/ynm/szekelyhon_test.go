@@ -0,0 +1,71 @@
+package ynm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irctest"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestSzekelyhon(t *testing.T) {
+	type item struct {
+		title     string
+		published time.Time
+	}
+	var mu sync.Mutex
+	var latest item
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Székelyhon</title>
+<item><title>%s</title><link>https://szekelyhon.ro/%d</link><pubDate>%s</pubDate></item>
+</channel></rss>`, latest.title, latest.published.Unix(), latest.published.Format(time.RFC1123Z))
+	}))
+	t.Cleanup(feed.Close)
+	publish := func(title string, at time.Time) {
+		mu.Lock()
+		latest = item{title, at}
+		mu.Unlock()
+	}
+
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) { cfg.SzekelyhonChannels = []string{"#test"} })
+	p := NewSzekelyhonPlugin(env.Bot, env.Ctx, 10*time.Minute)
+	p.feedURL = feed.URL
+	p.Start()
+	t.Cleanup(p.Stop)
+	hu := i18n.Get("hu")
+	start := irctest.Start
+	news := func(title string, at time.Time) []string {
+		return []string{"#test: " + hu.T("szekelyhon.news", title, fmt.Sprintf("https://szekelyhon.ro/%d", at.Unix()), hu.DateTime(at))}
+	}
+
+	// a kezdő időpont 8:00, az alapértelmezett időablak 7–22 óra
+	steps := []struct {
+		name      string
+		title     string
+		published time.Time
+		advance   time.Duration
+		want      []string
+	}{
+		{"indulás előtti hír", "Régi hír", start.Add(-time.Hour), 10 * time.Minute, nil},
+		{"friss hír", "Új hír", start.Add(15 * time.Minute), 10 * time.Minute, news("Új hír", start.Add(15*time.Minute))},
+		{"ugyanaz a hír", "Új hír", start.Add(15 * time.Minute), 10 * time.Minute, nil},
+		{"időablakon kívül", "Esti hír", start.Add(14*time.Hour + 5*time.Minute), 14 * time.Hour, nil},
+		{"reggel pótolja", "Esti hír", start.Add(14*time.Hour + 5*time.Minute), 9*time.Hour + 10*time.Minute, news("Esti hír", start.Add(14*time.Hour+5*time.Minute))},
+	}
+	for _, st := range steps {
+		publish(st.title, st.published)
+		if got := env.Tick(t, st.advance); !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", st.name, got, st.want)
+		}
+	}
+}
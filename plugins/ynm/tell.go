@@ -0,0 +1,286 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu
+//  YnM-Go IRC bot plugin: Üzenethagyás (!tell) – átadás a címzett következő felbukkanásakor
+// ============================================================================
+
+package ynm
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+	"github.com/ynmhu/YnM-Go/storage"
+)
+
+const (
+	tellDefaultMaxInbox     = 20
+	tellDefaultMaxPerSender = 5
+	tellDefaultMaxAgeDays   = 90
+	tellDeliverBatch        = 3   // egy felbukkanáskor ennyi üzenet megy ki, a többi az !inbox-szal
+	tellMaxTextLen          = 400 // az üzenet szövege legfeljebb ennyi bájt
+)
+
+type tellMemo = storage.Memo
+
+// TellPlugin a !tell üzeneteket tárolja, és a címzett következő üzenetekor vagy
+// belépésekor átadja. A címzettet a fiókja alapján is felismeri, így a nickváltás
+// után sem vész el az üzenet; az átadásról a feladó visszaigazolást kap.
+type TellPlugin struct {
+	memos *storage.MemoRepo
+	mu    sync.Mutex
+	bot   irc.Conn
+	cfg   atomic.Pointer[config.Config] // ReloadConfig cseréli
+	tr    pluginapi.Localizer
+	now   func() time.Time
+
+	accounts map[string]string // kisbetűs nick → fiók (az account-tagből)
+}
+
+// NewTellPlugin a közös adatbázis memos táblájára épül
+func NewTellPlugin(bot irc.Conn, cfg *config.Config, memos *storage.MemoRepo, tr pluginapi.Localizer) *TellPlugin {
+	p := &TellPlugin{memos: memos, bot: bot, tr: tr, now: time.Now, accounts: make(map[string]string)}
+	p.cfg.Store(cfg)
+	return p
+}
+
+// ReloadConfig átveszi az új tell korlátokat (pluginapi.ConfigReloader)
+func (p *TellPlugin) ReloadConfig(ev pluginapi.ReloadEvent) error {
+	p.cfg.Store(ev.New)
+	return nil
+}
+
+func (p *TellPlugin) Name() string { return "TellPlugin" }
+
+// ObserveMessage: a címzett megszólalt, átadjuk a neki szóló üzeneteket (pluginapi.MessageObserver)
+func (p *TellPlugin) ObserveMessage(msg irc.Message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if msg.Account != "" && msg.Account != "*" {
+		p.accounts[strings.ToLower(msg.Nick)] = msg.Account
+	}
+	if strings.TrimSpace(msg.Text) == "!inbox" {
+		return // az !inbox mindent privátban ad át
+	}
+	p.deliver(msg.Nick, p.accounts[strings.ToLower(msg.Nick)], msg.Channel, tellDeliverBatch)
+}
+
+// HandleEvent: belépéskor is átadjuk az üzeneteket; a nickváltást követjük (pluginapi.EventHandler)
+func (p *TellPlugin) HandleEvent(ev irc.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch ev.Type {
+	case "JOIN":
+		p.deliver(ev.Nick, p.accounts[strings.ToLower(ev.Nick)], ev.Channel, tellDeliverBatch)
+	case "NICK":
+		old := strings.ToLower(ev.Nick)
+		if account, ok := p.accounts[old]; ok {
+			p.accounts[strings.ToLower(ev.Target)] = account
+			delete(p.accounts, old)
+		}
+	case "QUIT":
+		delete(p.accounts, strings.ToLower(ev.Nick))
+	}
+}
+
+// HandleMessage: !tell [-p] <nick> <üzenet> | !tell cancel <szám|nick> | !inbox
+func (p *TellPlugin) HandleMessage(msg irc.Message) string {
+	parts := strings.Fields(msg.Text)
+	if len(parts) == 0 {
+		return ""
+	}
+	switch strings.ToLower(parts[0]) {
+	case "!tell":
+	case "!inbox":
+		if len(parts) != 1 {
+			return ""
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.inbox(msg)
+	default:
+		return ""
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	loc := p.tr.LocaleFor(msg)
+	if len(parts) == 3 && strings.EqualFold(parts[1], "cancel") {
+		return p.cancel(loc, msg, parts[2])
+	}
+
+	args := parts[1:]
+	private := !isChannelName(msg.Channel) // privátban kért üzenet privátban megy tovább
+	if len(args) > 0 && strings.EqualFold(args[0], "-p") {
+		private, args = true, args[1:]
+	}
+	if len(args) < 2 {
+		return loc.T("tell.usage")
+	}
+	target, text := args[0], strings.Join(args[1:], " ")
+	return p.store(loc, msg, target, text, private)
+}
+
+func (p *TellPlugin) store(loc *i18n.Locale, msg irc.Message, target, text string, private bool) string {
+	switch {
+	case isChannelName(target) || strings.ContainsAny(target, "!@*?,"):
+		return loc.T("tell.nick_only") + " | " + loc.T("tell.usage")
+	case strings.EqualFold(target, p.bot.GetNick()):
+		return loc.T("tell.to_bot")
+	case strings.EqualFold(target, msg.Nick):
+		return loc.T("tell.to_self", msg.Nick)
+	case len(text) > tellMaxTextLen:
+		return loc.T("tell.too_long", tellMaxTextLen)
+	}
+	p.purgeExpired()
+
+	account := p.accounts[strings.ToLower(target)]
+	inbox, fromSender, err := p.memos.Counts(target, account, msg.Nick)
+	if err != nil {
+		log.Printf("❌ Tell lekérdezési hiba: %v", err)
+		return loc.T("tell.error")
+	}
+	if max := p.limit(p.cfg.Load().Tell.MaxInbox, tellDefaultMaxInbox); inbox >= max {
+		return loc.N("tell.inbox_full", inbox, target)
+	}
+	if max := p.limit(p.cfg.Load().Tell.MaxPerSender, tellDefaultMaxPerSender); fromSender >= max {
+		return loc.N("tell.sender_limit", fromSender, target)
+	}
+
+	id, err := p.memos.Add(tellMemo{
+		Sender: msg.Nick, SenderAccount: msg.Account, Target: target, TargetAccount: account,
+		Text: text, Private: private, Created: p.now(),
+	})
+	if err != nil {
+		log.Printf("❌ Tell mentési hiba: %v", err)
+		return loc.T("tell.error")
+	}
+	if private {
+		return loc.T("tell.stored_private", msg.Nick, target, id)
+	}
+	return loc.T("tell.stored", msg.Nick, target, id)
+}
+
+// deliver a nick (vagy a fiókja) üzenetei közül legfeljebb max darabot átad
+func (p *TellPlugin) deliver(nick, account, channel string, max int) {
+	if nick == "" || strings.EqualFold(nick, p.bot.GetNick()) {
+		return
+	}
+	memos, err := p.memos.Pending(nick, account)
+	if err != nil {
+		log.Printf("❌ Tell lekérdezési hiba: %v", err)
+		return
+	}
+	if len(memos) == 0 {
+		return
+	}
+	public := channel
+	if !isChannelName(channel) {
+		public = nick
+	}
+	loc := p.tr.UserLocale(nick, account, public)
+	for i, m := range memos {
+		if i == max {
+			p.bot.SendMessage(public, loc.N("tell.more", len(memos)-max, nick))
+			break
+		}
+		to := public
+		if m.Private {
+			to = nick
+		}
+		p.bot.SendMessage(to, loc.T("tell.deliver", nick, m.Sender, loc.Ago(p.now().Sub(m.Created)), m.Text))
+		if err := p.memos.Delete(m.ID); err != nil {
+			log.Printf("❌ Tell törlési hiba: %v", err)
+			continue
+		}
+		// visszaigazolás a feladónak (privátban; ha épp nincs fent, elvész)
+		if !strings.EqualFold(m.Sender, nick) {
+			sender := p.tr.UserLocale(m.Sender, m.SenderAccount, m.Sender)
+			p.bot.SendMessage(m.Sender, sender.T("tell.delivered", nick, m.ID, tellPreview(m.Text)))
+		}
+	}
+}
+
+// inbox privátban átadja az összes várakozó üzenetet, és felsorolja a kérdező
+// még át nem adott, elküldött üzeneteit
+func (p *TellPlugin) inbox(msg irc.Message) string {
+	loc := p.tr.LocaleFor(msg)
+	account := p.accounts[strings.ToLower(msg.Nick)]
+	incoming, err := p.memos.Pending(msg.Nick, account)
+	if err != nil {
+		log.Printf("❌ Tell lekérdezési hiba: %v", err)
+		return loc.T("tell.error")
+	}
+	p.deliver(msg.Nick, account, msg.Nick, len(incoming))
+
+	outgoing, err := p.memos.Outgoing(msg.Nick, msg.Account)
+	if err != nil {
+		log.Printf("❌ Tell lekérdezési hiba: %v", err)
+		return loc.T("tell.error")
+	}
+
+	if len(incoming) == 0 && len(outgoing) == 0 {
+		return loc.T("tell.inbox_empty_all", msg.Nick)
+	}
+	if len(incoming) == 0 {
+		p.bot.SendMessage(msg.Nick, loc.T("tell.inbox_empty"))
+	}
+	for _, m := range outgoing {
+		p.bot.SendMessage(msg.Nick, loc.T("tell.outgoing", m.ID, m.Target, loc.Ago(p.now().Sub(m.Created)), tellPreview(m.Text)))
+	}
+	if len(outgoing) > 0 {
+		p.bot.SendMessage(msg.Nick, loc.T("tell.cancel_hint"))
+	}
+	return ""
+}
+
+// cancel a kérdező egy (szám szerint) vagy egy címzettnek szóló összes üzenetét törli
+func (p *TellPlugin) cancel(loc *i18n.Locale, msg irc.Message, what string) string {
+	var n int64
+	var err error
+	if id, convErr := strconv.ParseInt(strings.TrimPrefix(what, "#"), 10, 64); convErr == nil {
+		n, err = p.memos.CancelID(id, msg.Nick, msg.Account)
+	} else {
+		n, err = p.memos.CancelTarget(what, msg.Nick, msg.Account)
+	}
+	if err != nil {
+		log.Printf("❌ Tell törlési hiba: %v", err)
+		return loc.T("tell.error")
+	}
+	if n == 0 {
+		return loc.T("tell.cancel_none", what)
+	}
+	return loc.N("tell.cancelled", int(n))
+}
+
+// purgeExpired törli a túl régóta átadatlan üzeneteket
+func (p *TellPlugin) purgeExpired() {
+	days := p.limit(p.cfg.Load().Tell.MaxAgeDays, tellDefaultMaxAgeDays)
+	if err := p.memos.PurgeBefore(p.now().AddDate(0, 0, -days)); err != nil {
+		log.Printf("❌ Tell törlési hiba: %v", err)
+	}
+}
+
+func (p *TellPlugin) limit(value, def int) int {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+func tellPreview(text string) string {
+	if r := []rune(text); len(r) > 60 {
+		return string(r[:60]) + "…"
+	}
+	return text
+}
+
+func (p *TellPlugin) OnTick() []irc.Message { return nil }
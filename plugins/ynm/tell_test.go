@@ -0,0 +1,159 @@
+package ynm
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+)
+
+func TestTell(t *testing.T) {
+	env, _ := admintest.NewEnv(t, func(cfg *config.Config) { cfg.Tell.MaxPerSender = 2 })
+	p := NewTellPlugin(env.Bot, env.Config, env.DB.Memos, env.Ctx)
+	p.now = env.Clock.Now
+	env.Attach(p)
+	hu := i18n.Get("hu")
+	srv := env.Server
+
+	say := func(from, text string) func() {
+		return func() { srv.Privmsg(from, "#test", text) }
+	}
+	sayAs := func(from, account, text string) func() {
+		return func() { srv.PrivmsgAs(from, account, "#test", text) }
+	}
+	long := strings.Repeat("x", tellMaxTextLen+1)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		do      func()
+		want    []string
+	}{
+		{"használat", 0, say("bob", "!tell"), []string{"#test: " + hu.T("tell.usage")}},
+		{"a botnak", 0, say("bob", "!tell YnM szia"), []string{"#test: " + hu.T("tell.to_bot")}},
+		{"magának", 0, say("bob", "!tell BOB szia"), []string{"#test: " + hu.T("tell.to_self", "bob")}},
+		{"csatornának", 0, say("bob", "!tell #x szia"), []string{"#test: " + hu.T("tell.nick_only") + " | " + hu.T("tell.usage")}},
+		{"túl hosszú", 0, say("bob", "!tell alice "+long), []string{"#test: " + hu.T("tell.too_long", tellMaxTextLen)}},
+		{"mentés", 0, say("bob", "!tell alice szia"), []string{"#test: " + hu.T("tell.stored", "bob", "alice", 1)}},
+		{"privát mentés", 0, say("bob", "!tell -p alice titok"), []string{"#test: " + hu.T("tell.stored_private", "bob", "alice", 2)}},
+		{"feladói korlát", 0, say("bob", "!tell alice még"), []string{"#test: " + hu.N("tell.sender_limit", 2, "alice")}},
+		{"átadás megszólaláskor", 2 * time.Hour, say("alice", "hello"), []string{
+			"#test: " + hu.T("tell.deliver", "alice", "bob", hu.Ago(2*time.Hour), "szia"),
+			"bob: " + hu.T("tell.delivered", "alice", 1, "szia"),
+			"alice: " + hu.T("tell.deliver", "alice", "bob", hu.Ago(2*time.Hour), "titok"),
+			"bob: " + hu.T("tell.delivered", "alice", 2, "titok"),
+		}},
+		{"nincs több", 0, say("alice", "hello"), nil},
+		{"mentés visszavonáshoz", 0, say("bob", "!tell carol egy"), []string{"#test: " + hu.T("tell.stored", "bob", "carol", 3)}},
+		{"visszavonás számmal", 0, say("bob", "!tell cancel #3"), []string{"#test: " + hu.N("tell.cancelled", 1)}},
+		{"idegen nem vonhatja vissza", 0, say("bob", "!tell carol kettő"), []string{"#test: " + hu.T("tell.stored", "bob", "carol", 4)}},
+		{"idegen visszavonás", 0, say("dave", "!tell cancel 4"), []string{"#test: " + hu.T("tell.cancel_none", "4")}},
+		{"visszavonás nickre", 0, say("bob", "!tell cancel carol"), []string{"#test: " + hu.N("tell.cancelled", 1)}},
+		{"mentés belépéshez", 0, say("bob", "!tell erin jó reggelt"), []string{"#test: " + hu.T("tell.stored", "bob", "erin", 5)}},
+		{"átadás belépéskor", time.Minute, func() { srv.Join("erin", "#test") }, []string{
+			"#test: " + hu.T("tell.deliver", "erin", "bob", hu.Ago(time.Minute), "jó reggelt"),
+			"bob: " + hu.T("tell.delivered", "erin", 5, "jó reggelt"),
+		}},
+		{"fiók megismerése", 0, sayAs("hank", "hacc", "itt"), nil},
+		{"mentés fiókra", 0, say("bob", "!tell hank hali"), []string{"#test: " + hu.T("tell.stored", "bob", "hank", 6)}},
+		{"nickváltás", 0, func() { srv.Nick("hank", "hank2") }, nil},
+		{"átadás új nicknek", 0, say("hank2", "megjöttem"), []string{
+			"#test: " + hu.T("tell.deliver", "hank2", "bob", hu.Ago(0), "hali"),
+			"bob: " + hu.T("tell.delivered", "hank2", 6, "hali"),
+		}},
+		{"mentés fiókra újra", 0, say("bob", "!tell hank2 titok"), []string{"#test: " + hu.T("tell.stored", "bob", "hank2", 7)}},
+		{"kilépés", 0, func() { srv.Quit("hank2", "bye") }, nil},
+		{"fiók nélkül nem kapja meg", 0, say("hank2", "én vagyok hank"), nil},
+		{"átadás a fióknak", 0, sayAs("hank2", "hacc", "visszajöttem"), []string{
+			"#test: " + hu.T("tell.deliver", "hank2", "bob", hu.Ago(0), "titok"),
+			"bob: " + hu.T("tell.delivered", "hank2", 7, "titok"),
+		}},
+	}
+	for _, st := range steps {
+		env.Clock.Advance(st.advance)
+		if got := env.Sent(t, st.do); !slices.Equal(got, st.want) {
+			t.Errorf("%s:\n%q\nvárt:\n%q", st.name, got, st.want)
+		}
+	}
+}
+
+func TestTellInbox(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	p := NewTellPlugin(env.Bot, env.Config, env.DB.Memos, env.Ctx)
+	p.now = env.Clock.Now
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	senders := []string{"bob", "carol", "dave", "erin"}
+	for i, from := range senders {
+		want := hu.T("tell.stored", from, "gus", i+1)
+		if got := env.Say(t, from, "#test", "!tell gus üzenet "+from); !slices.Equal(got, []string{want}) {
+			t.Fatalf("mentés: %q, várt %q", got, want)
+		}
+	}
+
+	// egyszerre legfeljebb tellDeliverBatch üzenet megy ki, a többi az !inbox-szal
+	got := env.Say(t, "gus", "#test", "szia")
+	if len(got) != 2*tellDeliverBatch+1 || got[len(got)-1] != hu.N("tell.more", 1, "gus") {
+		t.Fatalf("első átadás: %q", got)
+	}
+
+	got = env.Sent(t, func() { env.Server.Privmsg("gus", "YnM", "!inbox") })
+	want := []string{
+		"gus: " + hu.T("tell.deliver", "gus", "erin", hu.Ago(0), "üzenet erin"),
+		"erin: " + hu.T("tell.delivered", "gus", 4, "üzenet erin"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("!inbox:\n%q\nvárt:\n%q", got, want)
+	}
+
+	if got := env.Say(t, "gus", "#test", "!inbox"); !slices.Equal(got, []string{hu.T("tell.inbox_empty_all", "gus")}) {
+		t.Errorf("üres !inbox: %q", got)
+	}
+
+	// a feladó a saját, még át nem adott üzeneteit látja
+	env.Say(t, "bob", "#test", "!tell zed később")
+	got = env.Sent(t, func() { env.Server.Privmsg("bob", "#test", "!inbox") })
+	want = []string{
+		"bob: " + hu.T("tell.inbox_empty"),
+		"bob: " + hu.T("tell.outgoing", 5, "zed", hu.Ago(0), "később"),
+		"bob: " + hu.T("tell.cancel_hint"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("kimenő üzenetek:\n%q\nvárt:\n%q", got, want)
+	}
+}
+
+// A fiókból küldött üzenetet csak a fiók látja és vonhatja vissza, a nick önmagában kevés
+func TestTellSenderAccount(t *testing.T) {
+	env, _ := admintest.NewEnv(t, nil)
+	p := NewTellPlugin(env.Bot, env.Config, env.DB.Memos, env.Ctx)
+	p.now = env.Clock.Now
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	steps := []struct {
+		name          string
+		from, account string
+		text          string
+		want          []string
+	}{
+		{"mentés fiókból", "bob", "bacc", "!tell zed titok", []string{hu.T("tell.stored", "bob", "zed", 1)}},
+		{"álnév !inbox", "bob", "", "!inbox", []string{hu.T("tell.inbox_empty_all", "bob")}},
+		{"álnév visszavonás számmal", "bob", "", "!tell cancel 1", []string{hu.T("tell.cancel_none", "1")}},
+		{"álnév visszavonás nickre", "bob", "", "!tell cancel zed", []string{hu.T("tell.cancel_none", "zed")}},
+		{"más fiók", "bob", "masik", "!tell cancel 1", []string{hu.T("tell.cancel_none", "1")}},
+		{"fiók más nicken", "bobby", "bacc", "!tell cancel 1", []string{hu.N("tell.cancelled", 1)}},
+		{"mentés fiók nélkül", "carl", "", "!tell zed szia", []string{hu.T("tell.stored", "carl", "zed", 2)}},
+		{"fiók nélkül a nick dönt", "carl", "", "!tell cancel 2", []string{hu.N("tell.cancelled", 1)}},
+	}
+	for _, st := range steps {
+		if got := env.SayAs(t, st.from, st.account, "#test", st.text); !slices.Equal(got, st.want) {
+			t.Errorf("%s: %q, várt %q", st.name, got, st.want)
+		}
+	}
+}
@@ -12,27 +12,33 @@ import (
     "golang.org/x/text/transform"
    // "io"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ynmhu/YnM-Go/i18n"
 	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
 	"github.com/ynmhu/YnM-Go/plugins/admin"
 )
 
 const (
 	CACHE_DURATION     = 30 * time.Minute // 30 perc
 	MAX_MESSAGE_LENGTH = 350              // Maximum üzenet hossz
+	VICC_URL           = "https://www.viccesviccek.hu/vicces_viccek"
 )
 
 // ViccPlugin struktura
 type ViccPlugin struct {
-	bot             *irc.Client
+	bot             irc.Sender
 	viccCache       []string
 	usedViccek      map[string]bool
 	lastFetchTime   time.Time
 	fallbackViccek  []string
 	adminPlugin     *admin.AdminPlugin  // hozzáadva
+	tr              pluginapi.Localizer
+	baseURL         string        // a viccoldal első lapja
+	pagePause       time.Duration // szünet a lapok lekérése között
 }
 
 // NewViccPlugin létrehozza az új vicc plugin példányt
-func NewViccPlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin) *ViccPlugin {
+func NewViccPlugin(bot irc.Sender, adminPlugin *admin.AdminPlugin, tr pluginapi.Localizer) *ViccPlugin {
 	fallbackViccek := []string{
 		"Offline.",
 	}
@@ -44,6 +50,9 @@ func NewViccPlugin(bot *irc.Client, adminPlugin *admin.AdminPlugin) *ViccPlugin
 		lastFetchTime:  time.Time{},
 		fallbackViccek: fallbackViccek,
 		adminPlugin:     adminPlugin,  // beállítva
+		tr:              tr,
+		baseURL:         VICC_URL,
+		pagePause:       1 * time.Second,
 	}
 }
 
@@ -60,6 +69,7 @@ func (v *ViccPlugin) HandleMessage(msg irc.Message) string {
 	viccTestPattern := regexp.MustCompile(`^!vicc_test$`)
 	viccDebugPattern := regexp.MustCompile(`^!vicc_debug$`)
 	viccLengthPattern := regexp.MustCompile(`^!vicc_length$`)
+	loc := v.tr.LocaleFor(msg)
 
 	switch {
 	case viccPattern.MatchString(msg.Text):
@@ -72,9 +82,9 @@ func (v *ViccPlugin) HandleMessage(msg irc.Message) string {
 			return "" // Csak admin (2) és owner (3) használhatja
 		}
 		
-		return v.handleViccCommand(msg)
+		return v.handleViccCommand(loc, msg)
 	case viccStatPattern.MatchString(msg.Text):
-		return v.handleViccStatCommand(msg)
+		return v.handleViccStatCommand(loc, msg)
 	case viccRefreshPattern.MatchString(msg.Text):
 	// Admin ellenőrzés a refresh parancsra is
 	nick := strings.Split(msg.Sender, "!")[0]
@@ -86,14 +96,13 @@ func (v *ViccPlugin) HandleMessage(msg irc.Message) string {
 		return ""
 	}
 	
-	return v.handleViccRefreshCommand(msg)
-		return v.handleViccRefreshCommand(msg)
+	return v.handleViccRefreshCommand(loc, msg)
 	case viccTestPattern.MatchString(msg.Text):
-		return v.handleViccTestCommand(msg)
+		return v.handleViccTestCommand(loc, msg)
 	case viccDebugPattern.MatchString(msg.Text):
-		return v.handleViccDebugCommand(msg)
+		return v.handleViccDebugCommand(loc, msg)
 	case viccLengthPattern.MatchString(msg.Text):
-		return v.handleViccLengthCommand(msg)
+		return v.handleViccLengthCommand(loc, msg)
 	}
 
 	return ""
@@ -247,9 +256,9 @@ func (v *ViccPlugin) fetchViccek() []string {
 	for _, pageNum := range pagesToFetch {
 		var url string
 		if pageNum == 0 {
-			url = "https://www.viccesviccek.hu/vicces_viccek"
+			url = v.baseURL
 		} else {
-			url = fmt.Sprintf("https://www.viccesviccek.hu/vicces_viccek&honnan=%d", pageNum)
+			url = fmt.Sprintf("%s&honnan=%d", v.baseURL, pageNum)
 		}
 
 		log.Printf("Lekérem az oldalt: %s", url)
@@ -313,7 +322,7 @@ func (v *ViccPlugin) fetchViccek() []string {
 		}
 
 		resp.Body.Close()
-		time.Sleep(1 * time.Second) // Szünet az oldalak között
+		time.Sleep(v.pagePause) // Szünet az oldalak között
 	}
 
 	if len(viccek) > 0 {
@@ -335,7 +344,7 @@ func (v *ViccPlugin) fetchViccek() []string {
 	return viccek
 }
 
-// getUnusedVicc visszaad egy még nem használt viccet
+// getUnusedVicc visszaad egy még nem használt viccet; ha nincs, üres
 func (v *ViccPlugin) getUnusedVicc() string {
 	viccek := v.fetchViccek()
 
@@ -363,14 +372,14 @@ func (v *ViccPlugin) getUnusedVicc() string {
 		return viccek[rand.Intn(len(viccek))]
 	}
 
-	return "Sajnos nincs elérhető vicc!"
+	return ""
 }
 
 // handleViccCommand kezeli a !vicc parancsot
-func (v *ViccPlugin) handleViccCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccCommand(loc *i18n.Locale, msg irc.Message) string {
 	vicc := v.getUnusedVicc()
 
-	if vicc != "" && vicc != "Sajnos nincs elérhető vicc!" {
+	if vicc != "" {
 		// Feldaraboljuk a viccet, ha túl hosszú
 		viccParts := v.splitLongMessage(vicc, MAX_MESSAGE_LENGTH)
 
@@ -392,41 +401,41 @@ func (v *ViccPlugin) handleViccCommand(msg irc.Message) string {
 		}
 	}
 
-	return "😅 Sajnos most nincs elérhető vicc, próbáld később!"
+	return loc.T("vicc.unavailable")
 }
 
 // handleViccStatCommand kezeli a !vicc_stat parancsot
-func (v *ViccPlugin) handleViccStatCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccStatCommand(loc *i18n.Locale, msg irc.Message) string {
 	viccek := v.fetchViccek()
 	totalViccek := len(viccek)
 	usedCount := len(v.usedViccek)
 	remaining := totalViccek - usedCount
 
-	return fmt.Sprintf("📊 Vicc statisztika: %d vicc van cache-ben, %d már elhangzott, %d maradt", totalViccek, usedCount, remaining)
+	return loc.T("vicc.stat", totalViccek, usedCount, remaining)
 }
 
 // handleViccRefreshCommand kezeli a !vicc_refresh parancsot
-func (v *ViccPlugin) handleViccRefreshCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccRefreshCommand(loc *i18n.Locale, msg irc.Message) string {
 	v.lastFetchTime = time.Time{}
 	v.viccCache = []string{}
 	v.usedViccek = make(map[string]bool)
 
 	viccek := v.fetchViccek()
-	return fmt.Sprintf("🔄 Cache frissítve! %d vicc betöltve.", len(viccek))
+	return loc.N("vicc.refreshed", len(viccek))
 }
 
 // handleViccTestCommand kezeli a !vicc_test parancsot
-func (v *ViccPlugin) handleViccTestCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccTestCommand(loc *i18n.Locale, msg irc.Message) string {
 	go func() {
 		for i := 0; i < 3; i++ {
 			vicc := v.getUnusedVicc()
-			if vicc != "" && vicc != "Sajnos nincs elérhető vicc!" {
+			if vicc != "" {
 				viccParts := v.splitLongMessage(vicc, MAX_MESSAGE_LENGTH)
 
 				for j, part := range viccParts {
 					var message string
 					if j == 0 {
-						message = fmt.Sprintf("🤣 Teszt %d: %s", i+1, part)
+						message = loc.T("vicc.test_joke", i+1, part)
 					} else {
 						message = fmt.Sprintf("       %s", part)
 					}
@@ -438,7 +447,7 @@ func (v *ViccPlugin) handleViccTestCommand(msg irc.Message) string {
 					}
 				}
 			} else {
-				v.bot.SendMessage(msg.Channel, fmt.Sprintf("😅 Teszt %d: Nincs elérhető vicc", i+1))
+				v.bot.SendMessage(msg.Channel, loc.T("vicc.test_none", i+1))
 			}
 
 			if i < 2 {
@@ -447,18 +456,18 @@ func (v *ViccPlugin) handleViccTestCommand(msg irc.Message) string {
 		}
 	}()
 
-	return "🧪 Teszt indítva, 3 vicc következik..."
+	return loc.T("vicc.test_started")
 }
 
 // handleViccDebugCommand kezeli a !vicc_debug parancsot
-func (v *ViccPlugin) handleViccDebugCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccDebugCommand(loc *i18n.Locale, msg irc.Message) string {
 	go func() {
-		url := "https://www.viccesviccek.hu/vicces_viccek"
+		url := v.baseURL
 		client := &http.Client{Timeout: 10 * time.Second}
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			v.bot.SendMessage(msg.Channel, fmt.Sprintf("😔 Debug hiba: %v", err))
+			v.bot.SendMessage(msg.Channel, loc.T("vicc.debug_error", err))
 			return
 		}
 
@@ -466,19 +475,19 @@ func (v *ViccPlugin) handleViccDebugCommand(msg irc.Message) string {
 
 		resp, err := client.Do(req)
 		if err != nil {
-			v.bot.SendMessage(msg.Channel, fmt.Sprintf("😔 Debug hiba: %v", err))
+			v.bot.SendMessage(msg.Channel, loc.T("vicc.debug_error", err))
 			return
 		}
 		defer resp.Body.Close()
 
 		doc, err := goquery.NewDocumentFromReader(resp.Body)
 		if err != nil {
-			v.bot.SendMessage(msg.Channel, fmt.Sprintf("😔 Debug hiba: %v", err))
+			v.bot.SendMessage(msg.Channel, loc.T("vicc.debug_error", err))
 			return
 		}
 
 		jokeTables := doc.Find("table[style*='BACKGROUND: white'][style*='FONT-SIZE: 18px']")
-		v.bot.SendMessage(msg.Channel, fmt.Sprintf("🐛 Debug: %d vicc táblázat találva az oldalon", jokeTables.Length()))
+		v.bot.SendMessage(msg.Channel, loc.T("vicc.debug_tables", jokeTables.Length()))
 
 		if jokeTables.Length() > 0 {
 			firstTable := jokeTables.First()
@@ -490,37 +499,37 @@ func (v *ViccPlugin) handleViccDebugCommand(msg irc.Message) string {
 					if len(rawText) > 100 {
 						rawText = rawText[:100]
 					}
-					v.bot.SendMessage(msg.Channel, fmt.Sprintf("🐛 Első vicc nyers szöveg: %s...", rawText))
+					v.bot.SendMessage(msg.Channel, loc.T("vicc.debug_raw", rawText))
 				}
 			}
 		}
 	}()
 
-	return "🐛 Debug folyamatban..."
+	return loc.T("vicc.debug_started")
 }
 
 // handleViccLengthCommand kezeli a !vicc_length parancsot
-func (v *ViccPlugin) handleViccLengthCommand(msg irc.Message) string {
+func (v *ViccPlugin) handleViccLengthCommand(loc *i18n.Locale, msg irc.Message) string {
 	go func() {
 		// Tesztelés céljából egy hosszú viccet készítünk
 		longJoke := strings.Repeat("Ez egy nagyon hosszú vicc lesz, ami több mint 450 karaktert tartalmaz, hogy teszteljük a feldarabolás funkcióját. ", 5)
 
-		v.bot.SendMessage(msg.Channel, fmt.Sprintf("📏 Teszt vicc hossza: %d karakter", len(longJoke)))
+		v.bot.SendMessage(msg.Channel, loc.T("vicc.length_total", len(longJoke)))
 
 		// Feldaraboljuk
 		parts := v.splitLongMessage(longJoke, MAX_MESSAGE_LENGTH)
-		v.bot.SendMessage(msg.Channel, fmt.Sprintf("📏 Feldarabolva %d részre", len(parts)))
+		v.bot.SendMessage(msg.Channel, loc.N("vicc.length_parts", len(parts)))
 
 		for i, part := range parts {
 			preview := part
 			if len(preview) > 50 {
 				preview = preview[:50]
 			}
-			v.bot.SendMessage(msg.Channel, fmt.Sprintf("📏 %d. rész (%d kar): %s...", i+1, len(part), preview))
+			v.bot.SendMessage(msg.Channel, loc.T("vicc.length_part", i+1, len(part), preview))
 		}
 	}()
 
-	return "📏 Hossz teszt folyamatban..."
+	return loc.T("vicc.length_started")
 }
 
 // min helper function
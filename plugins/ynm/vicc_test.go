@@ -0,0 +1,110 @@
+package ynm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/plugins/admin/admintest"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestVicc(t *testing.T) {
+	jokes := []string{
+		"Móricka hazaér az iskolából, és büszkén mutatja az ellenőrzőjét az apjának",
+		"A nyuszi bemegy a boltba, és megkérdezi, hogy van-e répalé, de csak almalé van",
+	}
+	var hits, down atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() != 0 {
+			http.Error(w, "nincs", http.StatusServiceUnavailable)
+			return
+		}
+		var b strings.Builder
+		b.WriteString("<html><body>")
+		for _, joke := range jokes {
+			fmt.Fprintf(&b, `<table style="BACKGROUND: white; FONT-SIZE: 18px"><tr><td>fejléc</td></tr><tr><td>%s.
+Szerinted hány pontos volt ez a vicc?</td></tr></table>`, joke)
+		}
+		b.WriteString("</body></html>")
+		body, _ := charmap.Windows1250.NewEncoder().String(b.String())
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(site.Close)
+
+	env, adm := admintest.NewEnv(t, nil)
+	p := NewViccPlugin(env.Bot, adm, env.Ctx)
+	p.baseURL, p.pagePause = site.URL+"/vicces_viccek", 0
+	env.Attach(p)
+	hu := i18n.Get("hu")
+
+	isJoke := func(got []string) bool {
+		for _, joke := range jokes {
+			if slices.Equal(got, []string{"🤣 " + joke}) {
+				return true
+			}
+		}
+		return false
+	}
+	var told []string
+
+	steps := []struct {
+		name  string
+		setup func()
+		from  string
+		text  string
+		check func(got []string) bool
+		hits  int32 // öt lap minden friss lekéréskor
+	}{
+		{"idegennek nem mesél", nil, "alice", "!vicc", func(got []string) bool { return len(got) == 0 }, 0},
+		{"első vicc", nil, "owner", "!vicc", func(got []string) bool { told = got; return isJoke(got) }, 5},
+		{"statisztika a cache-ből", nil, "alice", "!vicc_stat", func(got []string) bool {
+			return slices.Equal(got, []string{hu.T("vicc.stat", 2, 1, 1)})
+		}, 5},
+		{"másik vicc", nil, "owner", "!vicc", func(got []string) bool { return isJoke(got) && !slices.Equal(got, told) }, 5},
+		{"frissítés", nil, "owner", "!vicc_refresh", func(got []string) bool {
+			return slices.Equal(got, []string{hu.N("vicc.refreshed", 2)})
+		}, 10},
+		{"elérhetetlen oldal", func() { down.Store(1) }, "owner", "!vicc_refresh", func(got []string) bool {
+			return slices.Equal(got, []string{hu.N("vicc.refreshed", 1)})
+		}, 15},
+		{"tartalék vicc", nil, "owner", "!vicc", func(got []string) bool { return slices.Equal(got, []string{"🤣 Offline."}) }, 15},
+	}
+	for _, st := range steps {
+		if st.setup != nil {
+			st.setup()
+		}
+		got := env.Say(t, st.from, "#test", st.text)
+		if !st.check(got) {
+			t.Errorf("%s: %q → %q", st.name, st.text, got)
+		}
+		if n := hits.Load(); n != st.hits {
+			t.Errorf("%s: %d lekérés, várt %d", st.name, n, st.hits)
+		}
+	}
+}
+
+func TestViccSplitLongMessage(t *testing.T) {
+	v := &ViccPlugin{}
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{"rövid", "egy kettő", 20, []string{"egy kettő"}},
+		{"szóhatáron (bájtra)", "egy kettő három négy", 10, []string{"egy kettő", "három", "négy"}},
+		{"túl hosszú szó", "abcdefghijkl mn", 5, []string{"abcde", "fghijkl", "mn"}},
+	}
+	for _, tt := range tests {
+		if got := v.splitLongMessage(tt.text, tt.max); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, várt %q", tt.name, got, tt.want)
+		}
+	}
+}
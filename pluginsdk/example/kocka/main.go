@@ -0,0 +1,42 @@
+// Példa külső plugin: !dobas [oldalak] – kockadobás.
+//
+// Fordítás: go build -o ext/kocka ./pluginsdk/example/kocka
+// config.yaml:
+//
+//	external_plugins:
+//	  - name: "kocka"
+//	    command: "./ext/kocka"
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+
+	"github.com/ynmhu/YnM-Go/pluginsdk"
+)
+
+func main() {
+	p := pluginsdk.New("kocka")
+
+	p.Command("dobas", "!dobas [oldalak] – kockadobás", func(c pluginsdk.Command) string {
+		sides := 6
+		if len(c.Args) > 0 {
+			if n, err := strconv.Atoi(c.Args[0]); err == nil && n > 1 && n <= 1000 {
+				sides = n
+			}
+		}
+		return fmt.Sprintf("🎲 %s dobása (%d oldalú): %d", c.Message.Nick, sides, rand.Intn(sides)+1)
+	})
+
+	p.OnEvent(func(ev pluginsdk.Event) {
+		if ev.Type == "JOIN" && ev.Nick != p.Bot.BotNick {
+			p.Logf("%s belépett ide: %s", ev.Nick, ev.Channel)
+		}
+	})
+
+	if err := p.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,205 @@
+package pluginsdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Command egy parancshívás a kezelő számára
+type Command struct {
+	Name    string
+	Args    []string
+	Message Message
+}
+
+// CommandFunc egy parancs kezelője; a nem üres visszatérési érték a válasz
+type CommandFunc func(cmd Command) string
+
+// Plugin egy külső plugin. Használat:
+//
+//	p := pluginsdk.New("kocka")
+//	p.Command("dobas", "!dobas [oldalak] – kockadobás", func(c pluginsdk.Command) string { ... })
+//	if err := p.Run(); err != nil { log.Fatal(err) }
+type Plugin struct {
+	name      string
+	commands  map[string]CommandFunc
+	specs     []CommandSpec
+	onMessage func(Message)
+	onEvent   func(Event)
+
+	// Bot az initialize során kapott adatok
+	Bot InitializeParams
+
+	in      io.Reader
+	out     io.Writer
+	writeMu sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// New új plugint hoz létre, amely a standard be- és kimeneten kommunikál
+func New(name string) *Plugin {
+	return &Plugin{
+		name:     name,
+		commands: make(map[string]CommandFunc),
+		in:       os.Stdin,
+		out:      os.Stdout,
+	}
+}
+
+// SetIO a be- és kimenet cseréje (teszteléshez)
+func (p *Plugin) SetIO(in io.Reader, out io.Writer) {
+	p.in, p.out = in, out
+}
+
+// Command parancsot regisztrál; a név "!" nélkül vagy azzal is megadható
+func (p *Plugin) Command(name, help string, fn CommandFunc) {
+	name = strings.ToLower(strings.TrimPrefix(name, "!"))
+	p.commands[name] = fn
+	p.specs = append(p.specs, CommandSpec{Name: name, Help: help})
+}
+
+// OnMessage minden csatornaüzenetre meghívódik
+func (p *Plugin) OnMessage(fn func(Message)) {
+	p.onMessage = fn
+}
+
+// OnEvent a JOIN/PART/QUIT/NICK/KICK/TOPIC eseményekre hívódik meg
+func (p *Plugin) OnEvent(fn func(Event)) {
+	p.onEvent = fn
+}
+
+// Send üzenetet küld egy csatornára vagy nicknek
+func (p *Plugin) Send(target, text string) error {
+	return p.notify(MethodSend, SendParams{Target: target, Text: text})
+}
+
+// Logf a bot naplójába ír
+func (p *Plugin) Logf(format string, args ...interface{}) error {
+	return p.notify(MethodLog, LogParams{Message: fmt.Sprintf(format, args...)})
+}
+
+// Run a fő ciklus: a bot üzeneteit dolgozza fel, amíg le nem állítják,
+// vagy be nem zárul a bemenet.
+func (p *Plugin) Run() error {
+	defer p.wg.Wait()
+
+	scanner := bufio.NewScanner(p.in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var env Envelope
+		if err := json.Unmarshal([]byte(line), &env); err != nil {
+			p.reply(nil, nil, &RPCError{Code: ErrCodeParse, Message: err.Error()})
+			continue
+		}
+		if env.Method == "" {
+			continue // válasz a mi kérésünkre; az SDK csak értesítést küld
+		}
+		if env.Method == MethodShutdown {
+			return nil
+		}
+		p.dispatch(&env)
+	}
+	return scanner.Err()
+}
+
+func (p *Plugin) dispatch(env *Envelope) {
+	switch env.Method {
+	case MethodInitialize:
+		if err := json.Unmarshal(env.Params, &p.Bot); err != nil {
+			p.reply(env.ID, nil, &RPCError{Code: ErrCodeInvalidParams, Message: err.Error()})
+			return
+		}
+		p.reply(env.ID, InitializeResult{
+			Protocol: ProtocolVersion,
+			Name:     p.name,
+			Commands: p.specs,
+			Messages: p.onMessage != nil,
+			Events:   p.onEvent != nil,
+		}, nil)
+
+	case MethodMessage:
+		var msg Message
+		if err := json.Unmarshal(env.Params, &msg); err == nil && p.onMessage != nil {
+			p.onMessage(msg)
+		}
+
+	case MethodEvent:
+		var ev Event
+		if err := json.Unmarshal(env.Params, &ev); err == nil && p.onEvent != nil {
+			p.onEvent(ev)
+		}
+
+	case MethodCommand:
+		var params CommandParams
+		if err := json.Unmarshal(env.Params, &params); err != nil {
+			p.reply(env.ID, nil, &RPCError{Code: ErrCodeInvalidParams, Message: err.Error()})
+			return
+		}
+		fn, ok := p.commands[params.Command]
+		if !ok {
+			p.reply(env.ID, nil, &RPCError{Code: ErrCodeMethodNotFound, Message: "ismeretlen parancs: " + params.Command})
+			return
+		}
+		// A lassú parancsok ne tartsák fel a többi üzenetet
+		p.wg.Add(1)
+		go func(id *int64) {
+			defer p.wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					p.reply(id, nil, &RPCError{Code: ErrCodeInternal, Message: fmt.Sprint(r)})
+				}
+			}()
+			reply := fn(Command{Name: params.Command, Args: params.Args, Message: params.Message})
+			p.reply(id, CommandResult{Reply: reply}, nil)
+		}(env.ID)
+
+	default:
+		if env.ID != nil {
+			p.reply(env.ID, nil, &RPCError{Code: ErrCodeMethodNotFound, Message: "ismeretlen metódus: " + env.Method})
+		}
+	}
+}
+
+func (p *Plugin) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return p.write(Envelope{JSONRPC: "2.0", Method: method, Params: raw})
+}
+
+func (p *Plugin) reply(id *int64, result interface{}, rpcErr *RPCError) {
+	if id == nil && rpcErr == nil {
+		return // értesítésre nem jár válasz
+	}
+	env := Envelope{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			env.Error = &RPCError{Code: ErrCodeInternal, Message: err.Error()}
+		} else {
+			env.Result = raw
+		}
+	}
+	p.write(env)
+}
+
+func (p *Plugin) write(env Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err = p.out.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,131 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package pluginsdk külső (külön folyamatban futó) YnM-Go pluginok írásához.
+//
+// A bot a plugint elindítja, és soronként egy JSON-RPC 2.0 üzenetet ír a
+// standard bemenetére, illetve olvas a standard kimenetéről. A standard hiba
+// kimenet a bot naplójába kerül. A protokoll nyelvfüggetlen, így Pythonból
+// vagy shellből is megvalósítható; Go-ban elég a Plugin típust használni.
+package pluginsdk
+
+import "encoding/json"
+
+// Protokoll verzió; az initialize válaszban visszaküldhető
+const ProtocolVersion = 1
+
+// A bot → plugin metódusok
+const (
+	MethodInitialize = "initialize" // kérés: InitializeParams → InitializeResult
+	MethodMessage    = "message"    // értesítés: Message
+	MethodEvent      = "event"      // értesítés: Event
+	MethodCommand    = "command"    // kérés: CommandParams → CommandResult
+	MethodShutdown   = "shutdown"   // értesítés, utána a plugin lépjen ki
+)
+
+// A plugin → bot metódusok
+const (
+	MethodSend = "send" // értesítés vagy kérés: SendParams
+	MethodLog  = "log"  // értesítés: LogParams
+)
+
+// JSON-RPC hibakódok
+const (
+	ErrCodeParse          = -32700
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+)
+
+// Envelope egy JSON-RPC üzenet soron belül. Ha a Method nem üres, kérés
+// (ID-vel) vagy értesítés (ID nélkül); különben válasz.
+type Envelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError a JSON-RPC hibaobjektum
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// InitializeParams – a bot adatai indításkor
+type InitializeParams struct {
+	Protocol int      `json:"protocol"`
+	BotNick  string   `json:"bot_nick"`
+	Channels []string `json:"channels"`
+}
+
+// CommandSpec egy plugin által regisztrált parancs
+type CommandSpec struct {
+	Name string `json:"name"` // "!" nélkül, pl. "dice"
+	Help string `json:"help,omitempty"`
+}
+
+// InitializeResult – mit kér a plugin a bottól
+type InitializeResult struct {
+	Protocol int           `json:"protocol"`
+	Name     string        `json:"name"`
+	Commands []CommandSpec `json:"commands"`
+	Messages bool          `json:"messages"` // minden csatornaüzenetet kér
+	Events   bool          `json:"events"`   // JOIN/PART/QUIT/NICK/KICK/TOPIC eseményeket kér
+}
+
+// Message egy beérkezett PRIVMSG
+type Message struct {
+	Sender  string `json:"sender"` // nick!user@host
+	Nick    string `json:"nick"`
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+}
+
+// Event egy csatorna/felhasználó esemény
+type Event struct {
+	Type    string `json:"type"` // "JOIN", "PART", "QUIT", "NICK", "KICK", "TOPIC"
+	Sender  string `json:"sender"`
+	Nick    string `json:"nick"`
+	Channel string `json:"channel,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+// CommandParams egy regisztrált parancs meghívása
+type CommandParams struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Message Message  `json:"message"`
+}
+
+// CommandResult – a Reply (ha nem üres) a parancs csatornájára megy
+type CommandResult struct {
+	Reply string `json:"reply,omitempty"`
+}
+
+// SendParams üzenetküldés csatornára vagy nicknek
+type SendParams struct {
+	Target string `json:"target"`
+	Text   string `json:"text"`
+}
+
+// LogParams naplóbejegyzés a bot naplójába
+type LogParams struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,338 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package ratelimit a parancsok közös visszaélés-védelme: csúszó ablakos
+// korlát, burst és várakozási idő, valamint egymás után egyre hosszabb
+// tiltások. A tiltások fájlba mentődnek, és fiók vagy hosztmaszk szerint
+// azonosítanak, így nick-cserével vagy újraindítással nem kerülhetők meg.
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// egy tiltás lejárta után ennyi ideig emlékszünk a korábbi tiltásokra (eszkaláció)
+const strikeMemory = 30 * 24 * time.Hour
+
+// Policy egy parancs korlátozása
+type Policy struct {
+	Window    time.Duration   // csúszó ablak hossza
+	Limit     int             // ennyi hívás fér az ablakba; a következő tiltást von maga után (0: nincs)
+	Burst     int             // ennyi hívás jöhet egymás után a Cooldown kivárása nélkül
+	Cooldown  time.Duration   // két hívás közti minimális idő; a túl gyors hívást csendben eldobjuk
+	Penalties []time.Duration // egymást követő tiltások hossza, az utolsó ismétlődik
+}
+
+// Active true, ha a szabály bármit korlátoz
+func (p Policy) Active() bool {
+	return (p.Limit > 0 && p.Window > 0) || p.Cooldown > 0
+}
+
+// Ban egy tiltás
+type Ban struct {
+	Key       string    `json:"key"`     // "account:név" vagy "host:*!*@host"
+	Nick      string    `json:"nick"`    // az utoljára látott nick
+	Command   string    `json:"command"` // a tiltott parancs, "*" = minden
+	Until     time.Time `json:"until"`
+	Strikes   int       `json:"strikes"` // hányadik tiltás (eszkalációhoz)
+	CreatedAt time.Time `json:"created_at"`
+	Reason    string    `json:"reason,omitempty"`
+	Notified  bool      `json:"notified"` // jeleztük-e már a tiltás alatt
+}
+
+// Active true, ha a tiltás még érvényes
+func (b *Ban) Active(now time.Time) bool {
+	return now.Before(b.Until)
+}
+
+// Verdict a Check eredménye
+type Verdict int
+
+const (
+	Allow  Verdict = iota
+	Drop           // csendben eldobjuk (túl gyors, vagy a tiltást már jeleztük)
+	Denied         // eldobjuk, és a hívó a Result alapján jelzi a tiltást
+)
+
+// Result a döntés; Denied esetén a tiltás adatai, amelyekből a hívó a
+// felhasználó nyelvén állítja össze a választ
+type Result struct {
+	Verdict Verdict
+	Command string        // a tiltott parancs
+	Until   time.Time     // a tiltás lejárta
+	Penalty time.Duration // az új tiltás hossza (NewBan esetén)
+	NewBan  bool          // ez a hívás váltotta ki a tiltást (különben egy meglévő alatt jött)
+}
+
+// Limiter a hívások nyilvántartója és a tiltások tárolója
+type Limiter struct {
+	mu    sync.Mutex
+	path  string
+	calls map[string]*callLog // kulcs|parancs → a hívások időpontjai
+	bans  map[string]*Ban     // kulcs|parancs → tiltás
+	now   func() time.Time
+}
+
+// callLog egy felhasználó egy parancsának ablakon belüli hívásai
+type callLog struct {
+	times  []time.Time
+	window time.Duration // az ablak hossza; ennél régebbi utolsó hívás után a Sweep törli
+}
+
+// New létrehozza a korlátozót; a tiltások a path fájlba mentődnek (betöltés: Load)
+func New(path string) *Limiter {
+	return &Limiter{
+		path:  path,
+		calls: make(map[string]*callLog),
+		bans:  make(map[string]*Ban),
+		now:   time.Now,
+	}
+}
+
+// Load betölti a mentett tiltásokat
+func (l *Limiter) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var bans []*Ban
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return err
+	}
+	for _, b := range bans {
+		l.bans[banKey(b.Key, b.Command)] = b
+	}
+	return nil
+}
+
+func (l *Limiter) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	bans := make([]*Ban, 0, len(l.bans))
+	for _, b := range l.bans {
+		bans = append(bans, b)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].CreatedAt.Before(bans[j].CreatedAt) })
+	data, err := json.MarshalIndent(bans, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0644)
+}
+
+// Identity a felhasználó azonosító kulcsa: bejelentkezett fiók, ha ismert, különben *!*@host
+func Identity(sender, account string) string {
+	if account != "" && account != "*" {
+		return "account:" + strings.ToLower(account)
+	}
+	host := sender
+	if i := strings.Index(sender, "@"); i >= 0 {
+		host = sender[i+1:]
+	}
+	return "host:*!*@" + strings.ToLower(host)
+}
+
+func banKey(key, command string) string {
+	return key + "|" + command
+}
+
+// Check eldönti, hogy a hívás mehet-e tovább, és nyilvántartja
+func (l *Limiter) Check(key, nick, command string, p Policy) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bk := banKey(key, command)
+
+	// Tiltás ellenőrzése (parancsra vagy mindenre)
+	for _, k := range []string{bk, banKey(key, "*")} {
+		if b, ok := l.bans[k]; ok && b.Active(now) {
+			b.Nick = nick
+			if b.Notified {
+				return Result{Verdict: Drop}
+			}
+			b.Notified = true
+			l.saveLocked()
+			return Result{Verdict: Denied, Command: command, Until: b.Until}
+		}
+	}
+
+	if !p.Active() {
+		return Result{Verdict: Allow}
+	}
+
+	// Csúszó ablak: az ablaknál régebbi hívások kiesnek
+	window := p.Window
+	if window < p.Cooldown {
+		window = p.Cooldown
+	}
+	entry := l.calls[bk]
+	if entry == nil {
+		entry = &callLog{}
+		l.calls[bk] = entry
+	}
+	entry.window = window
+	times := entry.times[:0]
+	for _, t := range entry.times {
+		if now.Sub(t) <= window {
+			times = append(times, t)
+		}
+	}
+
+	if p.Limit > 0 && p.Window > 0 && countSince(times, now.Add(-p.Window)) >= p.Limit {
+		delete(l.calls, bk)
+		b := l.banLocked(key, nick, command, p, now)
+		return Result{Verdict: Denied, Command: command, Until: b.Until, Penalty: b.Until.Sub(now), NewBan: true}
+	}
+
+	// Burst és várakozási idő: a Cooldown-on belüli hívások száma legfeljebb Burst lehet
+	if p.Cooldown > 0 {
+		burst := p.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		if countSince(times, now.Add(-p.Cooldown)) >= burst {
+			entry.times = times
+			return Result{Verdict: Drop}
+		}
+	}
+
+	entry.times = append(times, now)
+	return Result{Verdict: Allow}
+}
+
+// Sweep eldobja azokat a nyilvántartásokat, amelyeknek az utolsó hívása is
+// kiesett az ablakból, és elfelejti a strikeMemory-nál régebben lejárt
+// tiltásokat. Enélkül minden valaha látott nick és hoszt a memóriában maradna;
+// az app időzítve hívja. A törölt elemek számával tér vissza.
+func (l *Limiter) Sweep() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	removed := 0
+	for bk, entry := range l.calls {
+		if len(entry.times) == 0 || now.Sub(entry.times[len(entry.times)-1]) > entry.window {
+			delete(l.calls, bk)
+			removed++
+		}
+	}
+	expired := 0
+	for bk, b := range l.bans {
+		if !b.Active(now) && now.Sub(b.Until) > strikeMemory {
+			delete(l.bans, bk)
+			expired++
+		}
+	}
+	if expired > 0 {
+		l.saveLocked()
+	}
+	return removed + expired
+}
+
+func countSince(times []time.Time, since time.Time) int {
+	n := 0
+	for _, t := range times {
+		if t.After(since) {
+			n++
+		}
+	}
+	return n
+}
+
+// banLocked új tiltást rögzít; a büntetés a korábbi tiltások számával nő
+func (l *Limiter) banLocked(key, nick, command string, p Policy, now time.Time) *Ban {
+	bk := banKey(key, command)
+	strikes := 0
+	if prev, ok := l.bans[bk]; ok && now.Sub(prev.Until) < strikeMemory {
+		strikes = prev.Strikes
+	}
+
+	penalty := 24 * time.Hour
+	if len(p.Penalties) > 0 {
+		i := strikes
+		if i >= len(p.Penalties) {
+			i = len(p.Penalties) - 1
+		}
+		penalty = p.Penalties[i]
+	}
+
+	b := &Ban{
+		Key:       key,
+		Nick:      nick,
+		Command:   command,
+		Until:     now.Add(penalty),
+		Strikes:   strikes + 1,
+		CreatedAt: now,
+		Reason:    fmt.Sprintf("%d hívás %s alatt", p.Limit+1, p.Window),
+		Notified:  true,
+	}
+	l.bans[bk] = b
+	l.saveLocked()
+	return b
+}
+
+// Unban feloldja a nickhez, fiókhoz vagy hosztmaszkhoz tartozó aktív tiltásokat.
+// A korábbi tiltások száma megmarad (eszkaláció).
+func (l *Limiter) Unban(who string) []Ban {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	who = strings.ToLower(who)
+	var lifted []Ban
+	for _, b := range l.bans {
+		if !b.Active(now) {
+			continue
+		}
+		if strings.ToLower(b.Nick) == who || b.Key == who || strings.TrimPrefix(strings.TrimPrefix(b.Key, "account:"), "host:") == who {
+			b.Until = now
+			lifted = append(lifted, *b)
+		}
+	}
+	if len(lifted) > 0 {
+		l.saveLocked()
+	}
+	return lifted
+}
+
+// Bans az aktív tiltások lejárat szerint rendezve
+func (l *Limiter) Bans() []Ban {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var active []Ban
+	for bk, b := range l.bans {
+		if b.Active(now) {
+			active = append(active, *b)
+		} else if now.Sub(b.Until) > strikeMemory {
+			delete(l.bans, bk) // elfelejtjük a régi tiltást
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Until.Before(active[j].Until) })
+	return active
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testLimiter átmeneti fájlba mentő korlátozó álló órával
+func testLimiter(t *testing.T) (*Limiter, *time.Time) {
+	t.Helper()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := New(filepath.Join(t.TempDir(), "bans.json"))
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+// call egy hívás: ennyivel később jön, és ezt a döntést várjuk rá
+type call struct {
+	after time.Duration
+	want  Verdict
+}
+
+func TestCheck(t *testing.T) {
+	window := Policy{Window: time.Minute, Limit: 3, Penalties: []time.Duration{10 * time.Minute, time.Hour}}
+	cooldown := Policy{Cooldown: 5 * time.Second, Burst: 2}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		calls  []call
+	}{
+		{"korlát nélkül", Policy{}, []call{{0, Allow}, {0, Allow}, {0, Allow}, {0, Allow}}},
+		{"az ablakon belül", window, []call{{0, Allow}, {10 * time.Second, Allow}, {10 * time.Second, Allow}}},
+		{"a korlát túllépése tilt", window, []call{{0, Allow}, {0, Allow}, {0, Allow}, {0, Denied}, {time.Minute, Drop}}},
+		{"a régi hívások kiesnek", window, []call{{0, Allow}, {0, Allow}, {0, Allow}, {61 * time.Second, Allow}}},
+		{"a tiltás lejár", window, []call{{0, Allow}, {0, Allow}, {0, Allow}, {0, Denied}, {10 * time.Minute, Allow}}},
+		{"burst", cooldown, []call{{0, Allow}, {0, Allow}, {0, Drop}, {5 * time.Second, Allow}}},
+		{"várakozás után", cooldown, []call{{0, Allow}, {6 * time.Second, Allow}, {6 * time.Second, Allow}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, now := testLimiter(t)
+			for i, c := range tt.calls {
+				*now = now.Add(c.after)
+				if got := l.Check("host:*!*@h", "alice", "!vicc", tt.policy).Verdict; got != c.want {
+					t.Fatalf("%d. hívás: %v, várt %v", i+1, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+// Az ismételt tiltás egyre hosszabb, az utolsó büntetés ismétlődik
+func TestPenaltyEscalation(t *testing.T) {
+	l, now := testLimiter(t)
+	p := Policy{Window: time.Minute, Limit: 1, Penalties: []time.Duration{10 * time.Minute, time.Hour}}
+
+	for _, want := range []time.Duration{10 * time.Minute, time.Hour, time.Hour} {
+		l.Check("account:alice", "alice", "!vicc", p)
+		r := l.Check("account:alice", "alice", "!vicc", p)
+		if r.Verdict != Denied || !r.NewBan || r.Command != "!vicc" || r.Penalty != want {
+			t.Fatalf("tiltás: %+v, várt hossz %v", r, want)
+		}
+		bans := l.Bans()
+		if len(bans) != 1 || bans[0].Until.Sub(*now) != want {
+			t.Fatalf("tiltások: %+v, várt hossz %v", bans, want)
+		}
+		*now = bans[0].Until
+	}
+}
+
+func TestUnbanAndLoad(t *testing.T) {
+	l, now := testLimiter(t)
+	p := Policy{Window: time.Minute, Limit: 1}
+	l.Check("account:alice", "Alice", "!vicc", p)
+	l.Check("account:alice", "Alice", "!vicc", p)
+
+	// a mentett tiltás újraindítás után is él (már jeleztük, ezért csendes)
+	reloaded := New(l.path)
+	reloaded.now = l.now
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if r := reloaded.Check("account:alice", "Alice2", "!vicc", p); r.Verdict != Drop {
+		t.Errorf("betöltés után: %v, várt Drop", r.Verdict)
+	}
+
+	if lifted := l.Unban("alice"); len(lifted) != 1 {
+		t.Fatalf("feloldott tiltások: %d, várt 1", len(lifted))
+	}
+	if len(l.Bans()) != 0 {
+		t.Error("a feloldott tiltás aktív maradt")
+	}
+	if r := l.Check("account:alice", "Alice", "!vicc", p); r.Verdict != Allow {
+		t.Errorf("feloldás után: %v, várt Allow", r.Verdict)
+	}
+	*now = now.Add(time.Second)
+	if lifted := l.Unban("bob"); len(lifted) != 0 {
+		t.Errorf("idegen feloldás: %+v", lifted)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l, now := testLimiter(t)
+	short := Policy{Cooldown: 5 * time.Second}
+	long := Policy{Window: time.Hour, Limit: 100}
+
+	l.Check("host:*!*@a", "a", "!vicc", short)
+	l.Check("host:*!*@b", "b", "!vicc", long)
+	l.Check("host:*!*@c", "c", "!ora", Policy{Window: time.Minute, Limit: 0, Cooldown: time.Second})
+	*now = now.Add(2 * time.Minute)
+
+	if n := l.Sweep(); n != 2 {
+		t.Errorf("törölt elemek: %d, várt 2", n)
+	}
+	if _, ok := l.calls[banKey("host:*!*@b", "!vicc")]; !ok || len(l.calls) != 1 {
+		t.Errorf("megmaradt nyilvántartások: %v", l.calls)
+	}
+
+	// a lejárt tiltást csak a strikeMemory után felejtjük el
+	ban := Policy{Window: time.Minute, Limit: 1, Penalties: []time.Duration{time.Hour}}
+	l.Check("host:*!*@d", "d", "!vicc", ban)
+	l.Check("host:*!*@d", "d", "!vicc", ban)
+	*now = now.Add(2 * time.Hour)
+	l.Sweep()
+	if len(l.bans) != 1 {
+		t.Fatalf("a lejárt tiltás túl korán törlődött")
+	}
+	*now = now.Add(strikeMemory)
+	l.Sweep()
+	if len(l.bans) != 0 || len(l.calls) != 0 {
+		t.Errorf("takarítás után: %d tiltás, %d nyilvántartás", len(l.bans), len(l.calls))
+	}
+}
@@ -0,0 +1,268 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule megadja a t utáni első futási időpontot (zero time, ha nincs több)
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Parse értelmezi az időzítést:
+//   - "ÓÓ:PP" – naponta ekkor (a régi config mezők formátuma)
+//   - 5 mezős cron: "perc óra nap hónap hétnapja", pl. "30 7 * * 1-5"
+//   - makrók: @hourly, @daily, @weekly, @monthly, @yearly, @every 30m
+//
+// A cron időpontok a megadott időzóna falióráját követik, így a nyári/téli
+// időszámítás váltásakor is naponta egyszer futnak. Kivétel az ismétlődő óra
+// mező ("*", "*/2"): az ilyen időzítés az őszi visszaállításkor kétszer
+// előforduló órában mindkétszer fut, így nem marad ki egy óra sem.
+func Parse(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("hibás @every időtartam: %q", spec)
+		}
+		return every(d), nil
+	}
+
+	switch spec {
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@hourly":
+		spec = "0 * * * *"
+	}
+
+	if t, err := time.Parse("15:04", spec); err == nil {
+		spec = fmt.Sprintf("%d %d * * *", t.Minute(), t.Hour())
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("hibás időzítés: %q (ÓÓ:PP, 5 mezős cron vagy @every kell)", spec)
+	}
+
+	c := &cronSchedule{loc: loc}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("perc: %v", err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("óra: %v", err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("nap: %v", err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("hónap: %v", err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("hét napja: %v", err)
+	}
+	if c.dow&(1<<7) != 0 { // a 7 is vasárnap
+		c.dow |= 1
+	}
+	c.domStar = fields[2] == "*" || fields[2] == "?"
+	c.dowStar = fields[4] == "*" || fields[4] == "?"
+	c.hourRepeats = strings.ContainsAny(fields[1], "*/")
+	return c, nil
+}
+
+// Validate ellenőrzi az időzítés formátumát
+func Validate(spec string) error {
+	_, err := Parse(spec, time.UTC)
+	return err
+}
+
+// ─── @every ─────────────────────────────────────────────────────────
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// ─── egyszeri időpont ──────────────────────────────────────────────
+
+type once time.Time
+
+func (o once) Next(t time.Time) time.Time {
+	at := time.Time(o)
+	if at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
+
+// ─── cron ───────────────────────────────────────────────────────────
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	hourRepeats                   bool // az óra mező "*" vagy lépésköz: az őszi ismétlődő órában is fut
+	loc                           *time.Location
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("hibás lépésköz: %q", part)
+			}
+			step = s
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = fieldValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			if hi, err = fieldValue(bounds[1], names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := fieldValue(part, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("tartományon kívül (%d-%d): %q", min, max, field)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func fieldValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("hibás érték: %q", s)
+	}
+	return v, nil
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	// a szabványos cron szerint ha mindkettő meg van adva, bármelyik elég
+	if !c.domStar && !c.dowStar {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// Next napról napra halad a falióra szerint; az időpontokat time.Date állítja elő,
+// így a nem létező (tavaszi) időpont a következő létezőre tolódik. Az őszi,
+// kétszer előforduló időpont csak egyszer számít, kivéve ha az óra mező
+// ismétlődő (hourRepeats): ekkor az óra első előfordulása után a második is
+// sorra kerül, különben egy */15 időzítés 75 percig nem futna.
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+
+	for i := 0; i < 366*5; i++ {
+		if c.month&(1<<uint(day.Month())) != 0 && c.dayMatches(day) {
+			for h := 0; h < 24; h++ {
+				if c.hour&(1<<uint(h)) == 0 {
+					continue
+				}
+				var repeated []time.Time
+				for m := 0; m < 60; m++ {
+					if c.minute&(1<<uint(m)) == 0 {
+						continue
+					}
+					first, second, twice := occurrences(time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, c.loc))
+					if first.After(t) {
+						return first
+					}
+					if twice && c.hourRepeats {
+						repeated = append(repeated, second)
+					}
+				}
+				for _, candidate := range repeated {
+					if candidate.After(t) {
+						return candidate
+					}
+				}
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.loc)
+	}
+	return time.Time{}
+}
+
+// occurrences a falióra szerinti időpont első és (őszi visszaállításkor) második
+// előfordulása; twice false, ha csak egyszer fordul elő
+func occurrences(at time.Time) (first, second time.Time, twice bool) {
+	_, before := at.Add(-3 * time.Hour).Zone()
+	_, after := at.Add(3 * time.Hour).Zone()
+	if before <= after {
+		return at, at, false
+	}
+	shift := time.Duration(before-after) * time.Second
+	sameWall := func(u time.Time) bool {
+		return u.Day() == at.Day() && u.Hour() == at.Hour() && u.Minute() == at.Minute()
+	}
+	if earlier := at.Add(-shift); sameWall(earlier) {
+		return earlier, at, true
+	}
+	if later := at.Add(shift); sameWall(later) {
+		return at, later, true
+	}
+	return at, at, false
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNext(t *testing.T) {
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, budapest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want string
+	}{
+		{"ÓÓ:PP ma", "21:00", "2025-03-05 08:00", "2025-03-05 21:00"},
+		{"ÓÓ:PP holnap", "07:30", "2025-03-05 08:00", "2025-03-06 07:30"},
+		{"pontosan az időpontban a következő", "08:00", "2025-03-05 08:00", "2025-03-06 08:00"},
+		{"percenként", "* * * * *", "2025-03-05 08:00", "2025-03-05 08:01"},
+		{"lépésköz", "*/15 * * * *", "2025-03-05 08:16", "2025-03-05 08:30"},
+		{"lista és tartomány", "0 9,18 * * 1-5", "2025-03-07 19:00", "2025-03-10 09:00"},
+		{"napnevek", "0 12 * * sat,sun", "2025-03-05 08:00", "2025-03-08 12:00"},
+		{"a 7 is vasárnap", "0 12 * * 7", "2025-03-05 08:00", "2025-03-09 12:00"},
+		{"hónapnév", "0 0 1 jun *", "2025-03-05 08:00", "2025-06-01 00:00"},
+		{"nap VAGY hétnap", "0 6 13 * 5", "2025-03-05 08:00", "2025-03-07 06:00"},
+		{"február 30 nincs, 31 van", "0 0 31 * *", "2025-02-01 00:00", "2025-03-31 00:00"},
+		{"szökőnap", "0 0 29 2 *", "2025-03-01 00:00", "2028-02-29 00:00"},
+		{"@hourly", "@hourly", "2025-03-05 08:20", "2025-03-05 09:00"},
+		{"@daily", "@daily", "2025-03-05 08:20", "2025-03-06 00:00"},
+		{"@weekly", "@weekly", "2025-03-05 08:20", "2025-03-09 00:00"},
+		{"@monthly", "@monthly", "2025-03-05 08:20", "2025-04-01 00:00"},
+		{"@every", "@every 90m", "2025-03-05 08:20", "2025-03-05 09:50"},
+		// tavaszi óraátállítás: 02:30 nem létezik, 03:30-kor fut
+		{"nem létező időpont", "30 2 * * *", "2025-03-30 00:00", "2025-03-30 03:30"},
+		{"óraátállítás után falióra szerint", "0 8 * * *", "2025-03-29 09:00", "2025-03-30 08:00"},
+	}
+	for _, tt := range tests {
+		sched, err := Parse(tt.spec, budapest)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := sched.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%s: %q %s után: %s, várt %s", tt.name, tt.spec, tt.from,
+				got.In(budapest).Format("2006-01-02 15:04 MST"), tt.want)
+		}
+	}
+}
+
+// Az őszi óraátállításkor kétszer előforduló időpont csak egyszer fut
+func TestNextAutumnOnce(t *testing.T) {
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched, err := Parse("30 2 * * *", budapest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := sched.Next(time.Date(2025, 10, 26, 0, 0, 0, 0, budapest))
+	if first.Day() != 26 || first.Hour() != 2 || first.Minute() != 30 {
+		t.Fatalf("első futás: %v", first)
+	}
+	if next := sched.Next(first); next.Day() != 27 {
+		t.Errorf("második futás ugyanazon a napon: %v", next)
+	}
+}
+
+// Ismétlődő óra mezőnél az őszi visszaállításkor kétszer előforduló óra
+// mindkétszer sorra kerül: a */15 végig negyedóránként fut
+func TestNextAutumnRepeatedHour(t *testing.T) {
+	budapest, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched, err := Parse("*/15 * * * *", budapest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 01:45 CEST → 03:00 CET: 9 futás, közte a 02:00–02:45 kétszer
+	at := time.Date(2025, 10, 26, 1, 45, 0, 0, budapest)
+	for i := 0; i < 9; i++ {
+		next := sched.Next(at)
+		if d := next.Sub(at); d != 15*time.Minute {
+			t.Fatalf("%v után %v következik (%v)", at, next, d)
+		}
+		at = next
+	}
+	if at.Hour() != 3 || at.Minute() != 0 {
+		t.Errorf("utolsó futás: %v, várt 03:00", at)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"25:00",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"* * * foo *",
+		"@every 10ms",
+		"@every holnap",
+		"@sometimes",
+	} {
+		if _, err := Parse(spec, time.UTC); err == nil {
+			t.Errorf("%q: hibát vártunk", spec)
+		}
+		if err := Validate(spec); err == nil {
+			t.Errorf("Validate(%q): hibát vártunk", spec)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"strings"
+
+	"github.com/ynmhu/YnM-Go/metrics"
+)
+
+var (
+	jobRuns = metrics.NewCounter("ynm_scheduler_job_runs_total",
+		"Az ütemezett feladatok futásai eredmény szerint (ok, error, skipped).", "job", "result")
+	jobDuration = metrics.NewHistogram("ynm_scheduler_job_duration_seconds",
+		"Az ütemezett feladatok futásideje.", []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300}, "job")
+)
+
+// jobKind a feladat fajtája a címkéhez: a név ":" előtti része, így a
+// csatornánkénti és az egyszeri (pl. "ora:42") feladatok nem szaporítják a sorozatokat
+func jobKind(name string) string {
+	kind, _, _ := strings.Cut(name, ":")
+	return kind
+}
@@ -0,0 +1,568 @@
+// Package scheduler a bot összes időzített feladatát futtatja: cron kifejezések,
+// beállítható időzóna, véletlen késleltetés (jitter), és a leállás alatt
+// elmaradt futások pótlása vagy kihagyása feladatonként megadott szabály szerint.
+// A feladatok állapota (utolsó futás, szüneteltetés) fájlba mentődik.
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	_ "time/tzdata" // az időzóna adatbázis a binárisba kerül
+)
+
+// MissedPolicy – mi történjen a leállás alatt elmaradt futással
+type MissedPolicy int
+
+const (
+	MissedSkip MissedPolicy = iota // kihagyja, a következő időpontban fut
+	MissedOnce                     // induláskor egyszer pótolja
+)
+
+// ParseMissedPolicy a config "skip" / "once" értékét alakítja át
+func ParseMissedPolicy(s string) (MissedPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "skip", "kihagy":
+		return MissedSkip, nil
+	case "once", "run", "pótol":
+		return MissedOnce, nil
+	}
+	return MissedSkip, fmt.Errorf("ismeretlen missed szabály: %q (skip vagy once)", s)
+}
+
+func (p MissedPolicy) String() string {
+	if p == MissedOnce {
+		return "once"
+	}
+	return "skip"
+}
+
+// az ennél kevésbé késő futás nem számít elmaradtnak (pl. gyors újraindítás)
+const missedGrace = time.Minute
+
+// Job egy időzített feladat
+type Job struct {
+	Name   string        // egyedi név, pl. "napivicc:#magyar"
+	Spec   string        // lásd Parse; egyszeri feladatnál üres
+	At     time.Time     // egyszeri feladat időpontja (Spec helyett)
+	Missed MissedPolicy  // elmaradt futás kezelése
+	Jitter time.Duration // legfeljebb ennyi véletlen késleltetés
+	Run    func() error
+}
+
+// JobInfo a !schedule list kimenetéhez
+type JobInfo struct {
+	Name       string
+	Spec       string
+	Next       time.Time
+	LastRun    time.Time
+	LastStatus string
+	LastError  string
+	Runs       int
+	Paused     bool
+	Running    bool
+}
+
+type entry struct {
+	job      Job
+	schedule Schedule
+	base     time.Time // a következő futás jitter nélküli időpontja
+	next     time.Time // base + jitter
+	running  bool
+}
+
+type jobState struct {
+	LastScheduled time.Time `json:"last_scheduled"` // az utolsó lefutott vagy kihagyott időpont
+	LastRun       time.Time `json:"last_run,omitempty"`
+	LastStatus    string    `json:"last_status,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	Runs          int       `json:"runs"`
+	Paused        bool      `json:"paused,omitempty"`
+}
+
+// Scheduler az időzítő. Egyetlen goroutine várakozik a legközelebbi időpontra,
+// a feladatok saját goroutine-on futnak; ugyanaz a feladat nem fut párhuzamosan.
+type Scheduler struct {
+	loc       *time.Location
+	statePath string
+
+	mu    sync.Mutex
+	jobs  map[string]*entry
+	state map[string]*jobState
+
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	now      func() time.Time
+	runs     sync.WaitGroup // a futó feladatok (RunDue megvárja őket)
+	held     int            // a Hold hívások száma; amíg nem nulla, semmi nem indul
+	dirty    bool           // van még nem mentett állapot (lásd persistLocked)
+}
+
+// New létrehozza az időzítőt; az állapotot a statePath fájlból tölti be
+func New(loc *time.Location, statePath string) *Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	s := &Scheduler{
+		loc:       loc,
+		statePath: statePath,
+		jobs:      make(map[string]*entry),
+		state:     make(map[string]*jobState),
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		now:       time.Now,
+	}
+	if err := s.load(); err != nil {
+		log.Printf("❌ Ütemező állapot betöltési hiba: %v", err)
+	}
+	return s
+}
+
+// Location az időzítő időzónája
+func (s *Scheduler) Location() *time.Location {
+	return s.loc
+}
+
+// SetClock lecseréli az órát (a tesztekben álló vagy léptethető óra); a már
+// felvett feladatok következő időpontja nem számolódik újra
+func (s *Scheduler) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	s.now = now
+	s.mu.Unlock()
+	s.signal()
+}
+
+// Now az időzítő szerinti pillanatnyi idő
+func (s *Scheduler) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now()
+}
+
+// RunDue elindítja az esedékes feladatokat, és megvárja, amíg minden futó
+// feladat befejeződik. Az időzítő goroutine nélkül (Start előtt) a tesztek
+// így léptetik az ütemezést.
+func (s *Scheduler) RunDue() {
+	s.mu.Lock()
+	s.runDueLocked(s.now())
+	s.mu.Unlock()
+	s.runs.Wait()
+}
+
+// Hold felfüggeszti a feladatok indítását, amíg a visszaadott függvényt meg nem
+// hívják. A pluginkezelő így hozza létre a plugint és adja át a csatornaszűrőjét,
+// mielőtt a konstruktorban felvett feladatok (pl. egy pótolt futás) elindulnának.
+// A közben esedékessé vált feladatok a feloldás után indulnak.
+func (s *Scheduler) Hold() (release func()) {
+	s.mu.Lock()
+	s.held++
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.held--
+			s.mu.Unlock()
+			s.signal()
+		})
+	}
+}
+
+// Start elindítja az időzítő goroutine-t
+func (s *Scheduler) Start() {
+	go s.loop()
+}
+
+// Stop leállítja az időzítőt (a futó feladatokat nem szakítja meg)
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.dirty {
+			return
+		}
+		if err := s.saveLocked(); err != nil {
+			log.Printf("❌ Ütemező állapot mentési hiba: %v", err)
+		}
+	})
+}
+
+// Add felveszi vagy frissíti a feladatot. Ha azonos névvel és időzítéssel már
+// létezik, csak a futtatandó függvény cserélődik, a következő időpont marad.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("a feladathoz név és függvény kell")
+	}
+
+	var schedule Schedule
+	if job.Spec == "" {
+		if job.At.IsZero() {
+			return fmt.Errorf("%s: időzítés vagy időpont kell", job.Name)
+		}
+		schedule = once(job.At)
+	} else {
+		var err error
+		if schedule, err = Parse(job.Spec, s.loc); err != nil {
+			return fmt.Errorf("%s: %v", job.Name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	existing, exists := s.jobs[job.Name]
+	if exists && existing.job.Spec == job.Spec && existing.job.At.Equal(job.At) {
+		existing.job = job
+		return nil
+	}
+
+	st := s.state[job.Name]
+	if st == nil {
+		st = &jobState{LastScheduled: now}
+		s.state[job.Name] = st
+	}
+	if exists {
+		// módosított időzítés: a régi szerinti időpontok nem számítanak elmaradtnak
+		st.LastScheduled = now
+	}
+
+	e := &entry{job: job, schedule: schedule}
+	switch {
+	case job.Spec == "":
+		// egyszeri feladat: ha az időpont elmúlt, azonnal fut
+		e.base = job.At
+		if e.base.Before(now) {
+			e.base = now
+		}
+	default:
+		missed := schedule.Next(st.LastScheduled)
+		if !missed.IsZero() && missed.Before(now.Add(-missedGrace)) {
+			if job.Missed == MissedOnce {
+				log.Printf("⏰ %s: elmaradt futás (%s) pótlása", job.Name, missed.In(s.loc).Format("2006-01-02 15:04"))
+				e.base = now
+			} else {
+				log.Printf("⏰ %s: elmaradt futás (%s) kihagyva", job.Name, missed.In(s.loc).Format("2006-01-02 15:04"))
+				st.LastScheduled = now
+			}
+		}
+		if e.base.IsZero() {
+			e.base = schedule.Next(now)
+		}
+	}
+	e.next = s.withJitter(e.base, job.Jitter)
+	s.jobs[job.Name] = e
+	s.persistLocked(job)
+	s.signal()
+	return nil
+}
+
+// Remove eltávolítja a feladatot; az állapota (utolsó futás) megmarad,
+// hogy újbóli felvételkor az elmaradt futás felismerhető legyen.
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	delete(s.jobs, name)
+	s.mu.Unlock()
+	s.signal()
+}
+
+// RemovePrefix minden feladatot eltávolít, amelynek neve prefixszel kezdődik
+func (s *Scheduler) RemovePrefix(prefix string) {
+	s.mu.Lock()
+	for name := range s.jobs {
+		if strings.HasPrefix(name, prefix) {
+			delete(s.jobs, name)
+		}
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// Forget eltávolítja a feladatot az állapotával együtt (pl. törölt emlékeztető)
+func (s *Scheduler) Forget(name string) {
+	s.mu.Lock()
+	delete(s.jobs, name)
+	delete(s.state, name)
+	s.saveLocked()
+	s.mu.Unlock()
+	s.signal()
+}
+
+// Names a feladatok nevei a prefixszel (üres prefix: mind)
+func (s *Scheduler) Names(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.jobs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunNow azonnal lefuttatja a feladatot (a rendes időzítést nem érinti)
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("nincs ilyen feladat: %s", name)
+	}
+	if e.running {
+		return fmt.Errorf("%s éppen fut", name)
+	}
+	s.startLocked(e, s.now(), false)
+	return nil
+}
+
+// SetPaused szünetelteti vagy folytatja a feladatot; az állapot mentődik
+func (s *Scheduler) SetPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("nincs ilyen feladat: %s", name)
+	}
+	st := s.state[name]
+	st.Paused = paused
+	if !paused && e.job.Spec != "" {
+		// folytatáskor nincs pótlás, a következő rendes időponttól fut
+		now := s.now()
+		st.LastScheduled = now
+		e.base = e.schedule.Next(now)
+		e.next = s.withJitter(e.base, e.job.Jitter)
+	}
+	s.signal()
+	return s.saveLocked()
+}
+
+// List a feladatok állapota név szerint rendezve
+func (s *Scheduler) List() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for name, e := range s.jobs {
+		st := s.state[name]
+		spec := e.job.Spec
+		if spec == "" {
+			spec = "@at " + e.job.At.In(s.loc).Format("2006-01-02 15:04")
+		}
+		infos = append(infos, JobInfo{
+			Name:       name,
+			Spec:       spec,
+			Next:       e.next,
+			LastRun:    st.LastRun,
+			LastStatus: st.LastStatus,
+			LastError:  st.LastError,
+			Runs:       st.Runs,
+			Paused:     st.Paused,
+			Running:    e.running,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) withJitter(t time.Time, jitter time.Duration) time.Time {
+	if jitter <= 0 || t.IsZero() {
+		return t
+	}
+	return t.Add(time.Duration(rand.Int63n(int64(jitter))))
+}
+
+func (s *Scheduler) loop() {
+	// legfeljebb ennyit alszunk egyszerre, hogy az óraállítást / felfüggesztést is észrevegyük
+	const maxSleep = time.Minute
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		s.mu.Lock()
+		now := s.now()
+		next := s.runDueLocked(now)
+		s.mu.Unlock()
+
+		wait := maxSleep
+		if !next.IsZero() && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// runDueLocked elindítja az esedékes feladatokat; a legközelebbi időponttal tér vissza
+func (s *Scheduler) runDueLocked(now time.Time) time.Time {
+	var next time.Time
+	if s.held > 0 {
+		return next // a Hold feloldása felébreszti a ciklust
+	}
+	for name, e := range s.jobs {
+		if s.state[name].Paused || e.next.IsZero() {
+			continue
+		}
+		if !e.next.After(now) {
+			s.startLocked(e, now, true)
+		}
+		if !e.next.IsZero() && (next.IsZero() || e.next.Before(next)) {
+			next = e.next
+		}
+	}
+	return next
+}
+
+// startLocked elindítja a feladatot; scheduled esetén a következő időpontot is kiszámolja
+func (s *Scheduler) startLocked(e *entry, now time.Time, scheduled bool) {
+	name := e.job.Name
+	st := s.state[name]
+
+	if scheduled {
+		st.LastScheduled = e.base
+		if e.job.Spec == "" {
+			e.next = time.Time{} // egyszeri feladat
+		} else {
+			base := e.base
+			if now.After(base) {
+				base = now
+			}
+			e.base = e.schedule.Next(base)
+			e.next = s.withJitter(e.base, e.job.Jitter)
+		}
+	}
+
+	if e.running {
+		log.Printf("⚠️ %s: az előző futás még tart, kihagyva", name)
+		st.LastStatus = "kihagyva (még futott)"
+		jobRuns.Inc(jobKind(name), "skipped")
+		return
+	}
+	e.running = true
+	run := e.job.Run
+	clock := s.now
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		started := clock()
+		err := safeRun(run)
+		jobDuration.Observe(clock().Sub(started).Seconds(), jobKind(name))
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		jobRuns.Inc(jobKind(name), result)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e.running = false
+		st.LastRun = started
+		st.Runs++
+		if err != nil {
+			st.LastStatus = "hiba"
+			st.LastError = err.Error()
+			log.Printf("❌ Ütemezett feladat hiba (%s): %v", name, err)
+		} else {
+			st.LastStatus = "ok"
+			st.LastError = ""
+		}
+
+		// a lefutott egyszeri feladat törlődik
+		if e.job.Spec == "" && scheduled {
+			if current, ok := s.jobs[name]; ok && current == e {
+				delete(s.jobs, name)
+				delete(s.state, name)
+			}
+		}
+		s.persistLocked(e.job)
+	}()
+}
+
+// persistLocked a pótlandó (MissedOnce) feladatok állapotát azonnal menti, mert
+// újraindításkor ebből derül ki az elmaradt futás. A többi feladatnál (pl. a
+// percenként futó plugins:tick) csak a statisztika változik: az a következő
+// mentéssel vagy leállításkor kerül a fájlba, így nem írunk percenként a lemezre.
+func (s *Scheduler) persistLocked(job Job) {
+	if job.Missed != MissedOnce {
+		s.dirty = true
+		return
+	}
+	if err := s.saveLocked(); err != nil {
+		log.Printf("❌ Ütemező állapot mentési hiba: %v", err)
+	}
+}
+
+func safeRun(run func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run()
+}
+
+func (s *Scheduler) load() error {
+	data, err := os.ReadFile(s.statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &s.state)
+}
+
+func (s *Scheduler) saveLocked() error {
+	if s.statePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.statePath); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testScheduler állapotfájl nélküli időzítő álló órával
+func testScheduler(t *testing.T) (*Scheduler, *time.Time) {
+	t.Helper()
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	s := New(time.UTC, "")
+	s.SetClock(func() time.Time { return now })
+	return s, &now
+}
+
+func TestHold(t *testing.T) {
+	s, _ := testScheduler(t)
+	var runs atomic.Int32
+	release := s.Hold()
+	if err := s.Add(Job{Name: "egyszeri", At: s.Now(), Run: func() error { runs.Add(1); return nil }}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.RunDue()
+	if n := runs.Load(); n != 0 {
+		t.Fatalf("visszatartás alatt %d futás", n)
+	}
+	release()
+	release() // a második hívás nem old fel egy másik Hold-ot
+	s.RunDue()
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("feloldás után %d futás, várt 1", n)
+	}
+}
+
+// A pótolható feladat futása azonnal mentődik, a percenkénti belső feladaté csak leállításkor
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	s := New(time.UTC, path)
+	s.SetClock(func() time.Time { return now })
+	noop := func() error { return nil }
+
+	if err := s.Add(Job{Name: "plugins:tick", Spec: "* * * * *", Run: noop}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	s.RunDue()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("a belső feladat futása fájlt írt: %v", err)
+	}
+
+	if err := s.Add(Job{Name: "backup", Spec: "0 9 * * *", Missed: MissedOnce, Run: noop}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	s.RunDue()
+	saved := readState(t, path)
+	if saved["backup"] == nil || saved["backup"].Runs != 1 {
+		t.Errorf("a pótolható feladat állapota nem mentődött: %+v", saved["backup"])
+	}
+
+	now = now.Add(time.Minute)
+	s.RunDue()
+	s.Stop()
+	if saved := readState(t, path); saved["plugins:tick"] == nil || saved["plugins:tick"].Runs != 3 {
+		t.Errorf("leállításkor nem mentődött a statisztika: %+v", saved["plugins:tick"])
+	}
+}
+
+func readState(t *testing.T, path string) map[string]*jobState {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state map[string]*jobState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
@@ -0,0 +1,338 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package scripting a scripts/ könyvtár JavaScript fájljait futtatja beágyazott
+// értelmezővel (goja). A scriptek csak a "bot" objektumon keresztül érik el a
+// világot: parancsok, események, időzítők és kulcs-érték tár. Fájl- és
+// hálózatelérésük nincs.
+//
+// Minden script kód egyetlen worker goroutine-on fut, így az IRC olvasás soha
+// nem vár egy scriptre; minden hívásnak futásidő- és memóriakorlátja van.
+package scripting
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/irc"
+	"github.com/ynmhu/YnM-Go/pluginapi"
+)
+
+const (
+	DefaultCPULimit    = 200 * time.Millisecond
+	DefaultMemoryLimit = 32 << 20 // bájt
+
+	queueSize = 256
+	// ennyi korláttúllépés után a script letiltódik a következő !script reload-ig
+	maxFaults = 3
+)
+
+// Limits egyetlen script hívás korlátai
+type Limits struct {
+	CPU    time.Duration // egy hívás max futásideje
+	Memory uint64        // egy hívás alatt megtartható (élő) memória bájtban; az átmeneti foglalás nem számít
+}
+
+// Status egy betöltött (vagy hibás) script állapota a !script list-hez
+type Status struct {
+	Name     string
+	Commands []string
+	Faults   int
+	Disabled bool
+	Error    string
+}
+
+// Engine a scriptek betöltője és futtatója. Megvalósítja az app.Plugin
+// interfészt, így a !plugin disable script is működik.
+type Engine struct {
+	dir       string
+	owner     string
+	limits    Limits
+	sender    pluginapi.Sender
+	registrar pluginapi.Registrar
+	botNick   func() string
+	kv        *kvStore
+	mem       *memMeter // a futó script által megtartott memória (lásd memMeter)
+
+	jobs     chan func()
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// csak a worker goroutine-ból érhető el
+	scripts []*script
+
+	mu     sync.RWMutex
+	status []Status
+	filter pluginapi.ChannelFilter
+}
+
+// New létrehozza a motort és elindítja a worker goroutine-t; a scriptek a
+// Reload hívásakor töltődnek be. owner a routerben használt plugin név.
+func New(dir, kvPath, owner string, limits Limits, sender pluginapi.Sender, registrar pluginapi.Registrar, botNick func() string) *Engine {
+	if limits.CPU <= 0 {
+		limits.CPU = DefaultCPULimit
+	}
+	if limits.Memory == 0 {
+		limits.Memory = DefaultMemoryLimit
+	}
+	kv := newKVStore(kvPath)
+	if err := kv.load(); err != nil {
+		log.Printf("❌ Script tár betöltési hiba: %v", err)
+	}
+
+	e := &Engine{
+		dir:       dir,
+		owner:     owner,
+		limits:    limits,
+		sender:    sender,
+		registrar: registrar,
+		botNick:   botNick,
+		kv:        kv,
+		mem:       newMemMeter(heapObjectBytes, runtime.GC),
+		jobs:      make(chan func(), queueSize),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go e.loop()
+	return e
+}
+
+func (e *Engine) loop() {
+	defer close(e.done)
+	for {
+		select {
+		case job := <-e.jobs:
+			job()
+		case <-e.stopCh:
+			e.unloadAll()
+			return
+		}
+	}
+}
+
+// enqueue beteszi a feladatot a sorba; tele sor esetén eldobja, hogy a hívó ne várjon
+func (e *Engine) enqueue(job func()) bool {
+	select {
+	case <-e.stopCh:
+		return false
+	default:
+	}
+	select {
+	case e.jobs <- job:
+		return true
+	default:
+		log.Printf("⚠️ Script sor megtelt, esemény eldobva")
+		return false
+	}
+}
+
+// Reload újratölti az összes scriptet; visszaadja a betöltöttek számát és a hibákat
+func (e *Engine) Reload() (int, []string) {
+	type result struct {
+		loaded int
+		errs   []string
+	}
+	ch := make(chan result, 1)
+	if !e.enqueue(func() {
+		loaded, errs := e.load()
+		ch <- result{loaded, errs}
+	}) {
+		return 0, []string{"a script motor nem fut vagy túlterhelt"}
+	}
+	select {
+	case r := <-ch:
+		return r.loaded, r.errs
+	case <-e.stopCh:
+		return 0, []string{"a script motor leállt"}
+	}
+}
+
+func (e *Engine) load() (int, []string) {
+	e.unloadAll()
+
+	files, err := filepath.Glob(filepath.Join(e.dir, "*.js"))
+	if err != nil {
+		return 0, []string{err.Error()}
+	}
+	sort.Strings(files)
+
+	var errs []string
+	var status []Status
+	taken := make(map[string]string) // parancs → script
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".js")
+		src, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		s := newScript(e, name)
+		if _, err := e.guard(s, func() error {
+			_, err := s.vm.RunScript(filepath.Base(file), string(src))
+			return err
+		}); err != nil {
+			s.close()
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			status = append(status, Status{Name: name, Disabled: true, Error: err.Error()})
+			continue
+		}
+
+		for cmd, c := range s.commands {
+			if other, ok := taken[cmd]; ok {
+				errs = append(errs, fmt.Sprintf("%s: a !%s parancs már a(z) %s scripté", name, cmd, other))
+				delete(s.commands, cmd)
+				continue
+			}
+			cmdName := cmd
+			if err := e.registrar.RegisterCommand(e.owner, cmdName, c.help, func(msg irc.Message, args []string) string {
+				e.enqueue(func() { e.runCommand(cmdName, msg, args) })
+				return ""
+			}); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				delete(s.commands, cmd)
+				continue
+			}
+			taken[cmd] = name
+		}
+
+		e.scripts = append(e.scripts, s)
+	}
+
+	for _, s := range e.scripts {
+		status = append(status, s.status())
+	}
+	e.setStatus(status)
+
+	log.Printf("✅ %d script betöltve (%s)", len(e.scripts), e.dir)
+	for _, msg := range errs {
+		log.Printf("❌ Script hiba: %s", msg)
+	}
+	return len(e.scripts), errs
+}
+
+func (e *Engine) unloadAll() {
+	for _, s := range e.scripts {
+		s.close()
+	}
+	e.scripts = nil
+	e.registrar.UnregisterCommands(e.owner)
+}
+
+func (e *Engine) runCommand(name string, msg irc.Message, args []string) {
+	for _, s := range e.scripts {
+		c, ok := s.commands[name]
+		if !ok {
+			continue
+		}
+		reply, err := s.call(c.fn, s.messageObject(msg, args))
+		if err != nil {
+			return
+		}
+		if text := valueString(reply); text != "" {
+			s.send(msg.Channel, text)
+		}
+		return
+	}
+}
+
+// dispatch az eseményt minden feliratkozott scriptnek átadja
+func (e *Engine) dispatch(eventType string, build func(s *script) interface{}) {
+	e.enqueue(func() {
+		for _, s := range e.scripts {
+			for _, fn := range s.handlers[eventType] {
+				s.call(fn, build(s))
+			}
+		}
+	})
+}
+
+// Statuses a !script list kimenetéhez
+func (e *Engine) Statuses() []Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Status(nil), e.status...)
+}
+
+func (e *Engine) setStatus(status []Status) {
+	e.mu.Lock()
+	e.status = status
+	e.mu.Unlock()
+}
+
+func (e *Engine) refreshStatus() {
+	status := make([]Status, 0, len(e.scripts))
+	for _, s := range e.scripts {
+		status = append(status, s.status())
+	}
+	// a betöltéskor elbukott scriptek bejegyzése maradjon meg
+	for _, old := range e.Statuses() {
+		if old.Error != "" {
+			status = append(status, old)
+		}
+	}
+	e.setStatus(status)
+}
+
+func (e *Engine) allows(target string) bool {
+	if !strings.HasPrefix(target, "#") {
+		return true
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.filter.Allows(target)
+}
+
+// ───────────────────── app.Plugin ───────────────────────
+
+func (e *Engine) HandleMessage(msg irc.Message) string {
+	e.dispatch("message", func(s *script) interface{} {
+		return s.messageObject(msg, strings.Fields(msg.Text))
+	})
+	return ""
+}
+
+func (e *Engine) OnTick() []irc.Message {
+	return nil
+}
+
+func (e *Engine) HandleEvent(ev irc.Event) {
+	e.dispatch(strings.ToLower(ev.Type), func(s *script) interface{} {
+		return s.eventObject(ev)
+	})
+}
+
+func (e *Engine) SetChannelFilter(f pluginapi.ChannelFilter) {
+	e.mu.Lock()
+	e.filter = f
+	e.mu.Unlock()
+}
+
+// Stop leállítja a workert, a scriptek időzítőit és menti a tárat
+func (e *Engine) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		<-e.done
+		if err := e.kv.save(); err != nil {
+			log.Printf("❌ Script tár mentési hiba: %v", err)
+		}
+	})
+}
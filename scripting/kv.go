@@ -0,0 +1,102 @@
+package scripting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	maxKeysPerScript = 1000
+	maxValueSize     = 4096
+)
+
+// kvStore scriptenként elkülönített, perzisztens kulcs-érték tár
+type kvStore struct {
+	mu   sync.Mutex
+	path string
+	data map[string]map[string]string
+}
+
+func newKVStore(path string) *kvStore {
+	return &kvStore{path: path, data: make(map[string]map[string]string)}
+}
+
+func (k *kvStore) load() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &k.data)
+}
+
+func (k *kvStore) save() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.saveLocked()
+}
+
+func (k *kvStore) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(k.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(k.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(k.path, data, 0644)
+}
+
+func (k *kvStore) get(script, key string) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	v, ok := k.data[script][key]
+	return v, ok
+}
+
+func (k *kvStore) set(script, key, value string) error {
+	if len(value) > maxValueSize {
+		return fmt.Errorf("az érték legfeljebb %d bájt lehet", maxValueSize)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.data[script] == nil {
+		k.data[script] = make(map[string]string)
+	}
+	if _, exists := k.data[script][key]; !exists && len(k.data[script]) >= maxKeysPerScript {
+		return fmt.Errorf("scriptenként legfeljebb %d kulcs tárolható", maxKeysPerScript)
+	}
+	k.data[script][key] = value
+	return k.saveLocked()
+}
+
+func (k *kvStore) del(script, key string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.data[script][key]; !ok {
+		return nil
+	}
+	delete(k.data[script], key)
+	return k.saveLocked()
+}
+
+func (k *kvStore) keys(script string) []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	keys := make([]string, 0, len(k.data[script]))
+	for key := range k.data[script] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package scripting
+
+// memMeter a futó script által megtartott (élő) memóriát becsüli. A heap
+// növekedése önmagában félrevezető: egy sok átmeneti objektumot létrehozó, de
+// semmit meg nem tartó ciklus is gyorsan sokat foglal, amit a GC aztán
+// felszabadít. Ezért csak akkor mérünk pontosan, ha a heap a hívás kezdete óta
+// a korlátnál többel nőtt: ekkor egy teljes GC után megnézzük, mennyi maradt
+// élő. A bot többi része is foglal közben, de tartósan keveset tart meg, így a
+// maradék a futó scripté. A scriptek egyetlen worker goroutine-on, egymás után
+// futnak, ezért a többletet mindig pontosan egy futtatókörnyezetnek tulajdonítjuk.
+//
+// Egy hívás alatt egyszerre csak egy goroutine használja: a begin és az end a
+// workerből, közöttük az over a hívást figyelő goroutine-ból fut.
+type memMeter struct {
+	heap func() uint64 // a heapen lévő objektumok mérete (élők és még be nem gyűjtött szemét)
+	gc   func()        // teljes szemétgyűjtés
+
+	limit     uint64 // a hívás alatt megtartható memória
+	base      uint64 // a heap a hívás kezdetén
+	threshold uint64 // e fölött a heap-méret fölött GC után újramérünk
+}
+
+func newMemMeter(heap func() uint64, gc func()) *memMeter {
+	return &memMeter{heap: heap, gc: gc}
+}
+
+// begin a hívás kezdetén rögzíti a heap méretét
+func (m *memMeter) begin(limit uint64) {
+	m.limit = limit
+	m.base = m.heap()
+	m.threshold = m.base + limit
+}
+
+// over true, ha a script a hívás kezdete óta a korlátnál több memóriát tart
+// meg. A küszöb alatt nem gyűjt szemetet; fölötte igen, és ha a növekmény
+// nagyrészt szemét volt, a küszöb feljebb kerül, hogy a figyelő ne indítson
+// minden mérésnél újabb GC-t (a túllépést legfeljebb fél korlátnyi késéssel veszi észre).
+func (m *memMeter) over() bool {
+	if m.heap() <= m.threshold {
+		return false
+	}
+	m.gc()
+	live := m.heap()
+	if live > m.base+m.limit {
+		return true
+	}
+	m.threshold = max(m.base+m.limit, live+m.limit/2)
+	return false
+}
+
+// end a hívás végén a pontos küszöbbel még egyszer ellenőriz: a hívás által
+// megtartott (pl. globális változóba tett) memória is a korlátba számít
+func (m *memMeter) end() bool {
+	m.threshold = m.base + m.limit
+	return m.over()
+}
@@ -0,0 +1,95 @@
+package scripting
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+func TestMemMeter(t *testing.T) {
+	var heap, live uint64 = 1000, 1000
+	gcs := 0
+	m := newMemMeter(func() uint64 { return heap }, func() { gcs++; heap = live })
+
+	m.begin(100)
+	// a küszöb alatt nincs GC
+	heap += 100
+	if m.over() || gcs != 0 {
+		t.Fatalf("küszöb alatt: GC-k: %d", gcs)
+	}
+	// a küszöb fölött GC: ha a növekmény szemét volt, nincs túllépés
+	heap += 500
+	if m.over() || gcs != 1 {
+		t.Fatalf("szemét: GC-k: %d", gcs)
+	}
+	// a megtartott memória a korlát alatt: a küszöb feljebb kerül (1130)
+	live = 1080
+	heap = 1200
+	if m.over() || gcs != 2 {
+		t.Fatalf("korlát alatti megtartás: GC-k: %d", gcs)
+	}
+	// a megemelt küszöb alatt nincs újabb GC
+	heap = 1125
+	if m.over() || gcs != 2 {
+		t.Fatalf("a megemelt küszöb alatt: GC-k: %d", gcs)
+	}
+	// a megtartott memória a korlát fölött
+	live = 1120
+	heap = 1200
+	if !m.over() {
+		t.Error("a korlát fölötti megtartott memória nem számított")
+	}
+
+	// a hívás végén a pontos küszöb számít
+	heap, live = 1000, 1000
+	m.begin(100)
+	live, heap = 1150, 1150
+	if !m.end() {
+		t.Error("a hívás után megtartott memória nem számított")
+	}
+}
+
+// Az átmeneti objektumokat létrehozó, de semmit meg nem tartó ciklus nem lépi
+// túl a korlátot, a megtartó igen
+func TestGuardMemory(t *testing.T) {
+	e := &Engine{limits: Limits{CPU: 10 * time.Second, Memory: 8 << 20}, mem: newMemMeter(heapObjectBytes, runtime.GC)}
+	run := func(code string) (bool, error) {
+		s := &script{vm: goja.New()}
+		return e.guard(s, func() error {
+			_, err := s.vm.RunString(code)
+			return err
+		})
+	}
+
+	limited, err := run(`for (let i = 0; i < 100000; i++) { const a = new Array(100).fill(i); }`)
+	if limited || err != nil {
+		t.Errorf("nem megtartó ciklus: %v, %v", limited, err)
+	}
+	limited, err = run(`var keep = []; for (let i = 0; i < 100000; i++) { keep.push(new Array(100).fill(i)); }`)
+	if !limited || !errors.Is(err, errMemoryLimit) {
+		t.Errorf("megtartó ciklus: %v, %v", limited, err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"rövid", 10, "rövid"},
+		{"abcdef", 3, "abc"},
+		{"aéb", 2, "a"}, // az "é" két bájt, nem vágjuk félbe
+		{"aéb", 3, "aé"},
+		{"😀😀", 5, "😀"},
+		{"é", 1, ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.text, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, várt %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/metrics"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dop251/goja"
+
+	"github.com/ynmhu/YnM-Go/irc"
+)
+
+const (
+	maxCallStack   = 1000
+	maxTimers      = 20
+	minTimerPeriod = time.Second
+	maxSendsPerRun = 10
+	maxLineLength  = 400
+)
+
+var (
+	errCPULimit    = errors.New("futásidő korlát túllépve")
+	errMemoryLimit = errors.New("memóriakorlát túllépve")
+	errDisabled    = errors.New("a script le van tiltva")
+)
+
+type scriptCommand struct {
+	help string
+	fn   goja.Callable
+}
+
+// script egy betöltött JS fájl saját, elszigetelt futtatókörnyezettel
+type script struct {
+	name   string
+	engine *Engine
+	vm     *goja.Runtime
+
+	commands map[string]scriptCommand
+	handlers map[string][]goja.Callable
+
+	timers    map[int64]*time.Timer
+	nextTimer int64
+
+	sends    int // az aktuális hívásban küldött üzenetek
+	faults   int
+	disabled bool
+	closed   bool
+}
+
+func newScript(e *Engine, name string) *script {
+	s := &script{
+		name:     name,
+		engine:   e,
+		vm:       goja.New(),
+		commands: make(map[string]scriptCommand),
+		handlers: make(map[string][]goja.Callable),
+		timers:   make(map[int64]*time.Timer),
+	}
+	s.vm.SetMaxCallStackSize(maxCallStack)
+	s.installAPI()
+	return s
+}
+
+// guard futtatja a run függvényt a futásidő- és memóriakorlát mellett.
+// Csak a worker goroutine-ból hívható.
+func (e *Engine) guard(s *script, run func() error) (bool, error) {
+	e.mem.begin(e.limits.Memory)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	cpuTimer := time.AfterFunc(e.limits.CPU, func() { s.vm.Interrupt(errCPULimit) })
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if e.mem.over() {
+					s.vm.Interrupt(errMemoryLimit)
+					return
+				}
+			}
+		}
+	}()
+
+	s.sends = 0
+	err := run()
+
+	close(stop)
+	<-stopped
+	cpuTimer.Stop()
+	s.vm.ClearInterrupt()
+
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		if limitErr, ok := interrupted.Value().(error); ok {
+			return true, limitErr
+		}
+	}
+	// a hívás után is megtartott memória (pl. globális változóban) is a korlátba számít
+	if e.mem.end() {
+		return true, errMemoryLimit
+	}
+	return false, err
+}
+
+// call egy JS függvényt hív meg a korlátok mellett; a hibát naplózza
+func (s *script) call(fn goja.Callable, args ...interface{}) (goja.Value, error) {
+	if s.disabled || s.closed {
+		return nil, errDisabled
+	}
+
+	values := make([]goja.Value, len(args))
+	for i, arg := range args {
+		if v, ok := arg.(goja.Value); ok {
+			values[i] = v
+		} else {
+			values[i] = s.vm.ToValue(arg)
+		}
+	}
+
+	var result goja.Value
+	limited, err := s.engine.guard(s, func() error {
+		var err error
+		result, err = fn(goja.Undefined(), values...)
+		return err
+	})
+	if err != nil {
+		log.Printf("⚠️ Script hiba (%s): %v", s.name, err)
+		if limited {
+			s.faults++
+			if s.faults >= maxFaults {
+				log.Printf("❌ %s script letiltva (%d korláttúllépés)", s.name, s.faults)
+				s.disabled = true
+				s.stopTimers()
+			}
+			s.engine.refreshStatus()
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *script) close() {
+	s.closed = true
+	s.stopTimers()
+}
+
+func (s *script) stopTimers() {
+	for id, t := range s.timers {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
+
+func (s *script) status() Status {
+	st := Status{Name: s.name, Faults: s.faults, Disabled: s.disabled}
+	for cmd := range s.commands {
+		st.Commands = append(st.Commands, "!"+cmd)
+	}
+	return st
+}
+
+// send a script üzenetét küldi ki, hívásonként korlátozott számban
+func (s *script) send(target, text string) error {
+	if target == "" || strings.ContainsAny(target, " \r\n") {
+		return fmt.Errorf("érvénytelen cél: %q", target)
+	}
+	if !s.engine.allows(target) {
+		return fmt.Errorf("a script ezen a csatornán tiltva: %s", target)
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if line == "" {
+			continue
+		}
+		if s.sends >= maxSendsPerRun {
+			return fmt.Errorf("hívásonként legfeljebb %d üzenet küldhető", maxSendsPerRun)
+		}
+		s.sends++
+		line = truncate(line, maxLineLength)
+		s.engine.sender.SendMessage(target, line)
+	}
+	return nil
+}
+
+// ───────────────────── a "bot" API ───────────────────────
+
+func (s *script) installAPI() {
+	vm := s.vm
+	bot := vm.NewObject()
+
+	bot.Set("nick", func() string { return s.engine.botNick() })
+
+	bot.Set("command", func(call goja.FunctionCall) goja.Value {
+		name := strings.ToLower(strings.TrimPrefix(call.Argument(0).String(), "!"))
+		help := ""
+		fnArg := call.Argument(1)
+		if len(call.Arguments) > 2 {
+			help = call.Argument(1).String()
+			fnArg = call.Argument(2)
+		}
+		fn, ok := goja.AssertFunction(fnArg)
+		if name == "" || !ok {
+			panic(vm.NewTypeError("használat: bot.command(név, [súgó], függvény)"))
+		}
+		s.commands[name] = scriptCommand{help: help, fn: fn}
+		return goja.Undefined()
+	})
+
+	bot.Set("on", func(call goja.FunctionCall) goja.Value {
+		event := strings.ToLower(call.Argument(0).String())
+		fn, ok := goja.AssertFunction(call.Argument(1))
+		if !ok {
+			panic(vm.NewTypeError("használat: bot.on(esemény, függvény)"))
+		}
+		switch event {
+		case "message", "join", "part", "quit", "nick", "kick", "topic":
+		default:
+			panic(vm.NewTypeError("ismeretlen esemény: " + event))
+		}
+		s.handlers[event] = append(s.handlers[event], fn)
+		return goja.Undefined()
+	})
+
+	bot.Set("send", func(target, text string) {
+		if err := s.send(target, text); err != nil {
+			panic(vm.NewGoError(err))
+		}
+	})
+
+	bot.Set("log", func(call goja.FunctionCall) goja.Value {
+		parts := make([]string, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			parts[i] = arg.String()
+		}
+		log.Printf("[script %s] %s", s.name, strings.Join(parts, " "))
+		return goja.Undefined()
+	})
+
+	bot.Set("setTimeout", func(call goja.FunctionCall) goja.Value {
+		return vm.ToValue(s.addTimer(call, false))
+	})
+	bot.Set("setInterval", func(call goja.FunctionCall) goja.Value {
+		return vm.ToValue(s.addTimer(call, true))
+	})
+	bot.Set("clearTimer", func(id int64) {
+		if t, ok := s.timers[id]; ok {
+			t.Stop()
+			delete(s.timers, id)
+		}
+	})
+
+	store := vm.NewObject()
+	store.Set("get", func(key string) goja.Value {
+		if v, ok := s.engine.kv.get(s.name, key); ok {
+			return vm.ToValue(v)
+		}
+		return goja.Null()
+	})
+	store.Set("set", func(key, value string) {
+		if err := s.engine.kv.set(s.name, key, value); err != nil {
+			panic(vm.NewGoError(err))
+		}
+	})
+	store.Set("del", func(key string) {
+		if err := s.engine.kv.del(s.name, key); err != nil {
+			panic(vm.NewGoError(err))
+		}
+	})
+	store.Set("keys", func() []string { return s.engine.kv.keys(s.name) })
+	bot.Set("store", store)
+
+	vm.Set("bot", bot)
+
+	console := vm.NewObject()
+	console.Set("log", bot.Get("log"))
+	vm.Set("console", console)
+}
+
+func (s *script) addTimer(call goja.FunctionCall, repeat bool) int64 {
+	fn, ok := goja.AssertFunction(call.Argument(0))
+	if !ok {
+		panic(s.vm.NewTypeError("az első paraméter függvény legyen"))
+	}
+	period := time.Duration(call.Argument(1).ToInteger()) * time.Millisecond
+	if period < minTimerPeriod {
+		period = minTimerPeriod
+	}
+	if len(s.timers) >= maxTimers {
+		panic(s.vm.NewGoError(fmt.Errorf("scriptenként legfeljebb %d időzítő lehet", maxTimers)))
+	}
+
+	s.nextTimer++
+	id := s.nextTimer
+	var fire func()
+	fire = func() {
+		s.engine.enqueue(func() {
+			if _, ok := s.timers[id]; !ok || s.closed || s.disabled {
+				return
+			}
+			if repeat {
+				s.timers[id] = time.AfterFunc(period, fire)
+			} else {
+				delete(s.timers, id)
+			}
+			s.call(fn)
+		})
+	}
+	s.timers[id] = time.AfterFunc(period, fire)
+	return id
+}
+
+func (s *script) messageObject(msg irc.Message, args []string) *goja.Object {
+	obj := s.vm.NewObject()
+	obj.Set("sender", msg.Sender)
+	obj.Set("nick", strings.SplitN(msg.Sender, "!", 2)[0])
+	obj.Set("channel", msg.Channel)
+	obj.Set("text", msg.Text)
+	obj.Set("args", args)
+	obj.Set("reply", func(text string) {
+		if err := s.send(msg.Channel, text); err != nil {
+			panic(s.vm.NewGoError(err))
+		}
+	})
+	return obj
+}
+
+func (s *script) eventObject(ev irc.Event) *goja.Object {
+	obj := s.vm.NewObject()
+	obj.Set("type", ev.Type)
+	obj.Set("sender", ev.Sender)
+	obj.Set("nick", ev.Nick)
+	obj.Set("channel", ev.Channel)
+	obj.Set("target", ev.Target)
+	obj.Set("text", ev.Text)
+	return obj
+}
+
+func valueString(v goja.Value) string {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return ""
+	}
+	return v.String()
+}
+
+// truncate legfeljebb max bájtra vágja a szöveget, UTF-8 karakter közepén nem
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	for max > 0 && !utf8.RuneStart(text[max]) {
+		max--
+	}
+	return text[:max]
+}
+
+// heapObjectBytes a heapen lévő objektumok mérete: az élők és a még be nem
+// gyűjtött szemét (a GC után csak az élők)
+func heapObjectBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
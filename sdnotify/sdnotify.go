@@ -0,0 +1,161 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package sdnotify a systemd integráció (Type=notify): az sd_notify üzenetek
+// (READY=1, STATUS=, WATCHDOG=1, STOPPING=1) a NOTIFY_SOCKET-re, a watchdog
+// időköze és a socket-aktivált fájlleírók (LISTEN_FDS). A környezeti változókat
+// minden hívás újraolvassa, így egy teszt saját NOTIFY_SOCKET-tel is kipróbálhatja.
+// Systemd nélkül minden hívás üres művelet.
+package sdnotify
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RestartExitCode: ezzel a kóddal lép ki a bot, ha a !restart-ot a systemd-re
+// bízza (a unit fájlban: RestartForceExitStatus=75)
+const RestartExitCode = 75
+
+// Enabled igaz, ha van NOTIFY_SOCKET (a unit Type=notify)
+func Enabled() bool {
+	return os.Getenv("NOTIFY_SOCKET") != ""
+}
+
+// UnderSystemd igaz, ha a folyamatot a systemd indította (szolgáltatásként)
+func UnderSystemd() bool {
+	return Enabled() || os.Getenv("INVOCATION_ID") != ""
+}
+
+// Notify elküldi az állapotsort (pl. "READY=1\nSTATUS=...") a systemd-nek;
+// NOTIFY_SOCKET nélkül nem csinál semmit
+func Notify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+	if strings.HasPrefix(path, "@") {
+		path = "\x00" + path[1:] // absztrakt névtér
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Ready: a bot elkészült (az IRC regisztráció után)
+func Ready(status string) error {
+	return Notify("READY=1\nSTATUS=" + oneLine(status))
+}
+
+// Status a systemctl status-ban látható állapotszöveg
+func Status(status string) error {
+	return Notify("STATUS=" + oneLine(status))
+}
+
+// Stopping: a bot leáll (vagy újraindul)
+func Stopping(status string) error {
+	return Notify("STOPPING=1\nSTATUS=" + oneLine(status))
+}
+
+// Watchdog egy életjel; WatchdogInterval időn belül ismételni kell
+func Watchdog() error {
+	return Notify("WATCHDOG=1")
+}
+
+// WatchdogInterval a unit WatchdogSec értéke (0, ha nincs watchdog, vagy nem ennek
+// a folyamatnak szól)
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// ───────────────────────────── Socket aktiválás ─────────────────────────────
+
+const listenFDsStart = 3 // SD_LISTEN_FDS_START
+
+var (
+	filesOnce sync.Once
+	files     map[string]*os.File
+	filesMu   sync.Mutex
+)
+
+// activationFiles a systemd által átadott fájlleírók név szerint (LISTEN_FDNAMES,
+// a unitban FileDescriptorName=). Egyszer olvassa ki, és törli a változókat, hogy
+// az újraindított folyamat ne örökölje őket.
+func activationFiles() map[string]*os.File {
+	filesOnce.Do(func() {
+		files = make(map[string]*os.File)
+		defer func() {
+			os.Unsetenv("LISTEN_PID")
+			os.Unsetenv("LISTEN_FDS")
+			os.Unsetenv("LISTEN_FDNAMES")
+		}()
+		if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+			return
+		}
+		n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+		if err != nil || n <= 0 {
+			return
+		}
+		names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+		for i := 0; i < n; i++ {
+			fd := listenFDsStart + i
+			syscall.CloseOnExec(fd)
+			name := "unknown"
+			if i < len(names) && names[i] != "" {
+				name = names[i]
+			}
+			files[name] = os.NewFile(uintptr(fd), name)
+		}
+	})
+	return files
+}
+
+// Listener a név szerinti socket-aktivált listener (pl. "control"); ha a systemd
+// egyetlen fájlleírót adott át, az a névtől függetlenül is jó. Egy listenert csak
+// egyszer ad ki; nil, ha nincs.
+func Listener(name string) (net.Listener, error) {
+	all := activationFiles()
+	filesMu.Lock()
+	defer filesMu.Unlock()
+	f := all[name]
+	if f == nil && len(all) == 1 {
+		for key, only := range all {
+			name, f = key, only
+		}
+	}
+	if f == nil {
+		return nil, nil
+	}
+	delete(all, name)
+	defer f.Close()
+	return net.FileListener(f)
+}
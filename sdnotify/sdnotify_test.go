@@ -0,0 +1,116 @@
+package sdnotify
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeSystemd egy NOTIFY_SOCKET-et figyelő unixgram socket; a kapott üzeneteket adja vissza
+func fakeSystemd(t *testing.T, path string) func() string {
+	t.Helper()
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	name := path
+	if name[0] == 0 {
+		name = "@" + name[1:]
+	}
+	t.Setenv("NOTIFY_SOCKET", name)
+	return func() string {
+		t.Helper()
+		buf := make([]byte, 4096)
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+}
+
+func TestNotify(t *testing.T) {
+	recv := fakeSystemd(t, filepath.Join(t.TempDir(), "notify.sock"))
+	if !Enabled() || !UnderSystemd() {
+		t.Fatal("NOTIFY_SOCKET mellett nem érzékeli a systemd-t")
+	}
+
+	tests := []struct {
+		name string
+		send func() error
+		want string
+	}{
+		{"ready", func() error { return Ready("Kapcsolódva") }, "READY=1\nSTATUS=Kapcsolódva"},
+		{"status", func() error { return Status("két\nsor\r") }, "STATUS=két sor "},
+		{"stopping", func() error { return Stopping("Leállás...") }, "STOPPING=1\nSTATUS=Leállás..."},
+		{"watchdog", Watchdog, "WATCHDOG=1"},
+		{"notify", func() error { return Notify("MAINPID=42") }, "MAINPID=42"},
+	}
+	for _, tt := range tests {
+		if err := tt.send(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := recv(); got != tt.want {
+			t.Errorf("%s: %q, várt %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNotifyAbstract(t *testing.T) {
+	recv := fakeSystemd(t, "\x00ynm-sdnotify-test-"+strconv.Itoa(os.Getpid()))
+	if err := Watchdog(); err != nil {
+		t.Fatal(err)
+	}
+	if got := recv(); got != "WATCHDOG=1" {
+		t.Errorf("absztrakt socket: %q", got)
+	}
+}
+
+func TestWithoutSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	t.Setenv("INVOCATION_ID", "")
+	if Enabled() || UnderSystemd() {
+		t.Error("systemd nélkül is systemd-t érzékel")
+	}
+	if err := Ready("x"); err != nil {
+		t.Errorf("NOTIFY_SOCKET nélkül: %v", err)
+	}
+
+	// Type=simple unit: nincs NOTIFY_SOCKET, de a systemd indította
+	t.Setenv("INVOCATION_ID", "abc")
+	if Enabled() || !UnderSystemd() {
+		t.Error("az INVOCATION_ID-t nem veszi figyelembe")
+	}
+
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "nincs.sock"))
+	if err := Watchdog(); err == nil {
+		t.Error("nem létező socketre sem ad hibát")
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	own := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		usec, pid string
+		want      time.Duration
+	}{
+		{"", "", 0},
+		{"abc", "", 0},
+		{"-5", "", 0},
+		{"30000000", "", 30 * time.Second},
+		{"30000000", own, 30 * time.Second},
+		{"30000000", "1", 0}, // más folyamatnak szól
+	}
+	for _, tt := range tests {
+		t.Setenv("WATCHDOG_USEC", tt.usec)
+		t.Setenv("WATCHDOG_PID", tt.pid)
+		if got := WatchdogInterval(); got != tt.want {
+			t.Errorf("WATCHDOG_USEC=%q WATCHDOG_PID=%q: %v, várt %v", tt.usec, tt.pid, got, tt.want)
+		}
+	}
+}
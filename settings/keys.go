@@ -0,0 +1,148 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/i18n"
+	"github.com/ynmhu/YnM-Go/scheduler"
+)
+
+// Kind egy beállítás értékének típusa
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindBool
+	KindInt
+	KindDuration
+	KindSchedule // "15:04" időpont vagy cron kifejezés (lásd scheduler.Parse)
+	KindLanguage // nyelvkód, amelyhez van üzenetkatalógus (lásd i18n.Languages)
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindBool:
+		return "bool"
+	case KindInt:
+		return "egész szám"
+	case KindDuration:
+		return "időtartam (pl. 30s, 5m)"
+	case KindSchedule:
+		return "időpont (ÓÓ:PP) vagy cron"
+	case KindLanguage:
+		return "nyelv (" + strings.Join(i18n.Languages(), ", ") + ")"
+	default:
+		return "szöveg"
+	}
+}
+
+// Key egy ismert beállítás leírása
+type Key struct {
+	Name    string
+	Kind    Kind
+	Default string
+	Help    string
+}
+
+// Known az összes ismert kulcs; a !set csak ezeket fogadja el, így az elírások
+// nem vesznek el csendben.
+var Known = map[string]Key{}
+
+func register(name string, kind Kind, def, help string) {
+	Known[name] = Key{Name: name, Kind: kind, Default: def, Help: help}
+}
+
+func init() {
+	register("language", KindLanguage, i18n.Default, "a válaszok nyelve (felhasználónként: !lang)")
+
+	register("chanlog.enabled", KindBool, "true", "a csatorna naplózása (LogDir)")
+	register("weblog.enabled", KindBool, "false", "a csatorna naplója olvasható a weben (/logs)")
+	register("weblog.redact", KindString, "", "a webes naplóban elrejtett nickek, vesszővel elválasztva")
+
+	register("ping.cooldown", KindDuration, "30s", "!ping várakozási idő")
+
+	register("nevnap.enabled", KindBool, "false", "névnap bejelentés a csatornán")
+	register("nevnap.morning", KindSchedule, "07:30", "reggeli névnap bejelentés")
+	register("nevnap.evening", KindSchedule, "21:30", "esti névnap bejelentés")
+
+	register("joke.enabled", KindBool, "false", "napi vicc a csatornán")
+	register("joke.time", KindSchedule, "08:00", "napi vicc küldési ideje")
+
+	register("film.enabled", KindBool, "false", "napi filmajánló a csatornán")
+	register("film.time", KindSchedule, "21:35", "napi filmajánló ideje")
+
+	register("upload.enabled", KindBool, "false", "új médiafeltöltések bejelentése")
+
+	register("ora.enabled", KindBool, "false", "emlékeztetők kiküldése a csatornára")
+
+	register("szekelyhon.enabled", KindBool, "false", "Székelyhon hírek a csatornán")
+	register("szekelyhon.start_hour", KindInt, "7", "hírek küldésének kezdő órája")
+	register("szekelyhon.end_hour", KindInt, "22", "hírek küldésének záró órája")
+}
+
+// Validate ellenőrzi, hogy a kulcs ismert-e és az érték megfelel-e a típusának.
+func Validate(key, value string) error {
+	k, ok := Known[key]
+	if !ok {
+		return fmt.Errorf("ismeretlen kulcs: %s", key)
+	}
+	var err error
+	switch k.Kind {
+	case KindBool:
+		_, err = parseBool(value)
+	case KindInt:
+		_, err = strconv.Atoi(value)
+	case KindDuration:
+		var d time.Duration
+		d, err = time.ParseDuration(value)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("az időtartamnak pozitívnak kell lennie")
+		}
+	case KindSchedule:
+		err = scheduler.Validate(value)
+	case KindLanguage:
+		if !i18n.Supported(value) {
+			err = fmt.Errorf("nincs ilyen nyelv")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("hibás érték (%s) a(z) %s kulcshoz: %s", k.Kind, key, value)
+	}
+	return nil
+}
+
+// KnownNames a kulcsok ábécérendben
+func KnownNames() []string {
+	names := make([]string, 0, len(Known))
+	for name := range Known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on", "igen", "be":
+		return true, nil
+	case "0", "false", "no", "off", "nem", "ki":
+		return false, nil
+	}
+	return false, fmt.Errorf("hibás logikai érték: %s", value)
+}
@@ -0,0 +1,360 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+	"github.com/ynmhu/YnM-Go/scheduler"
+)
+
+// Source megmondja, melyik rétegből jött egy érték. Az értékek állandó
+// azonosítók; a megjelenített nevük a katalógusban van (settings.source.<név>).
+type Source string
+
+const (
+	SourceDefault  Source = "default"
+	SourceLegacy   Source = "legacy"
+	SourceGlobal   Source = "global"
+	SourceNetwork  Source = "network"
+	SourceChannel  Source = "channel"
+	SourceOverride Source = "override"
+)
+
+// Store a rétegzett beállítási modell:
+// alapértelmezés → régi config mezők → global → hálózat → csatorna → futásidejű felülírás.
+type Store struct {
+	mu       sync.RWMutex
+	filePath string
+
+	legacyGlobal   map[string]string
+	legacyChannels map[string]map[string]string
+	global         map[string]string
+	network        map[string]string
+	channels       map[string]map[string]string
+
+	// futásidejű, perzisztens csatorna felülírások (!set)
+	overrides map[string]map[string]string
+
+	// kisbetűs csatornanév → eredeti írásmód (a küldéshez)
+	displayNames map[string]string
+
+	// változáskor értesítendők (pl. az időzítések újrahangolása)
+	listeners      map[int]func()
+	nextListenerID int
+}
+
+// NewStore felépíti a rétegeket a configból; a felülírásokat a path fájlba menti.
+func NewStore(cfg *config.Config, path string) *Store {
+	s := &Store{
+		filePath:  path,
+		overrides: make(map[string]map[string]string),
+		listeners: make(map[int]func()),
+	}
+	s.apply(cfg)
+	return s
+}
+
+func (s *Store) apply(cfg *config.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.displayNames = make(map[string]string)
+	s.legacyGlobal = make(map[string]string)
+	s.legacyChannels = make(map[string]map[string]string)
+
+	// A régi, szétszórt mezők a legalsó config réteget adják
+	setGlobal := func(key, value string) {
+		if value != "" {
+			s.legacyGlobal[key] = value
+		}
+	}
+	enableOn := func(key string, channels ...string) {
+		for _, ch := range channels {
+			if ch == "" {
+				continue
+			}
+			lc := s.remember(ch)
+			if s.legacyChannels[lc] == nil {
+				s.legacyChannels[lc] = make(map[string]string)
+			}
+			s.legacyChannels[lc][key] = "true"
+		}
+	}
+
+	if cfg.PingCommandCooldown > 0 {
+		setGlobal("ping.cooldown", cfg.PingCommandCooldown.String())
+	}
+	setGlobal("nevnap.morning", string(cfg.NevnapReggel))
+	setGlobal("nevnap.evening", string(cfg.NevnapEste))
+	setGlobal("joke.time", string(cfg.JokeSendTime))
+	setGlobal("film.time", string(cfg.MediaAjanlat.Time))
+	if cfg.SzekelyhonStartHour != 0 || cfg.SzekelyhonEndHour != 0 {
+		setGlobal("szekelyhon.start_hour", strconv.Itoa(cfg.SzekelyhonStartHour))
+		setGlobal("szekelyhon.end_hour", strconv.Itoa(cfg.SzekelyhonEndHour))
+	}
+
+	enableOn("nevnap.enabled", cfg.NevnapChannels...)
+	enableOn("joke.enabled", cfg.JokeChannels...)
+	enableOn("film.enabled", cfg.MediaAjanlat.Channel)
+	enableOn("ora.enabled", cfg.OraChan...)
+	enableOn("szekelyhon.enabled", cfg.SzekelyhonChannels...)
+	if cfg.MediaUpload.Enabled {
+		enableOn("upload.enabled", cfg.MediaUpload.Channels...)
+	}
+
+	for _, ch := range append([]string{cfg.ConsoleChannel}, cfg.Channels...) {
+		if ch != "" {
+			s.remember(ch)
+		}
+	}
+
+	s.global = copyMap(cfg.Settings.Global)
+	network := cfg.Network
+	if network == "" {
+		network = cfg.Server
+	}
+	s.network = copyMap(cfg.Settings.Networks[network])
+	s.channels = make(map[string]map[string]string)
+	for ch, values := range cfg.Settings.Channels {
+		s.channels[s.remember(ch)] = copyMap(values)
+	}
+}
+
+// Reconfigure újraépíti a config rétegeket (config újratöltéskor); a !set
+// felülírások megmaradnak. A feliratkozók (pl. az időzítések) értesítést kapnak.
+func (s *Store) Reconfigure(cfg *config.Config) {
+	s.apply(cfg)
+
+	s.mu.Lock()
+	for ch := range s.overrides {
+		s.remember(ch)
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// remember eltárolja a csatorna eredeti írásmódját, és a kisbetűs kulcsot adja vissza
+func (s *Store) remember(channel string) string {
+	lc := strings.ToLower(channel)
+	if _, ok := s.displayNames[lc]; !ok {
+		s.displayNames[lc] = channel
+	}
+	return lc
+}
+
+// Load betölti a futásidejű felülírásokat
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // első futás
+		}
+		return err
+	}
+	overrides := make(map[string]map[string]string)
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return err
+	}
+	for ch, values := range overrides {
+		s.overrides[s.remember(ch)] = values
+	}
+	return nil
+}
+
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.overrides, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filePath, data, 0644)
+}
+
+// Set futásidejű felülírást állít be egy csatornára, és elmenti
+func (s *Store) Set(channel, key, value string) error {
+	if err := Validate(key, value); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lc := s.remember(channel)
+	if s.overrides[lc] == nil {
+		s.overrides[lc] = make(map[string]string)
+	}
+	s.overrides[lc][key] = value
+	if err := s.save(); err != nil {
+		return err
+	}
+	go s.notify()
+	return nil
+}
+
+// Unset törli a csatorna felülírását; false, ha nem volt ilyen
+func (s *Store) Unset(channel, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lc := strings.ToLower(channel)
+	if _, ok := s.overrides[lc][key]; !ok {
+		return false, nil
+	}
+	delete(s.overrides[lc], key)
+	if len(s.overrides[lc]) == 0 {
+		delete(s.overrides, lc)
+	}
+	if err := s.save(); err != nil {
+		return true, err
+	}
+	go s.notify()
+	return true, nil
+}
+
+// OnChange feliratkozik a beállítások változására; a visszaadott függvény leiratkoztat
+func (s *Store) OnChange(fn func()) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextListenerID++
+	id := s.nextListenerID
+	s.listeners[id] = fn
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+// notify a zár elengedése után hívja a feliratkozókat
+func (s *Store) notify() {
+	s.mu.RLock()
+	listeners := make([]func(), 0, len(s.listeners))
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	s.mu.RUnlock()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// Lookup visszaadja az érvényes értéket és a forrás réteget.
+// Üres csatorna esetén csak a csatornafüggetlen rétegek számítanak.
+func (s *Store) Lookup(channel, key string) (string, Source) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lc := strings.ToLower(channel)
+	if channel != "" {
+		if v, ok := s.overrides[lc][key]; ok {
+			return v, SourceOverride
+		}
+		if v, ok := s.channels[lc][key]; ok {
+			return v, SourceChannel
+		}
+	}
+	if v, ok := s.network[key]; ok {
+		return v, SourceNetwork
+	}
+	if v, ok := s.global[key]; ok {
+		return v, SourceGlobal
+	}
+	if channel != "" {
+		if v, ok := s.legacyChannels[lc][key]; ok {
+			return v, SourceLegacy
+		}
+	}
+	if v, ok := s.legacyGlobal[key]; ok {
+		return v, SourceLegacy
+	}
+	return Known[key].Default, SourceDefault
+}
+
+// Setting a kulcs szöveges értéke az adott csatornán
+func (s *Store) Setting(channel, key string) string {
+	v, _ := s.Lookup(channel, key)
+	return v
+}
+
+func (s *Store) SettingBool(channel, key string) bool {
+	v, err := parseBool(s.Setting(channel, key))
+	return err == nil && v
+}
+
+func (s *Store) SettingInt(channel, key string) int {
+	v, err := strconv.Atoi(s.Setting(channel, key))
+	if err != nil {
+		v, _ = strconv.Atoi(Known[key].Default)
+	}
+	return v
+}
+
+func (s *Store) SettingDuration(channel, key string) time.Duration {
+	d, err := time.ParseDuration(s.Setting(channel, key))
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(Known[key].Default)
+	}
+	return d
+}
+
+// SettingSchedule az időzítés kulcs értéke; hibás érték esetén az alapértelmezés
+func (s *Store) SettingSchedule(channel, key string) string {
+	v := s.Setting(channel, key)
+	if scheduler.Validate(v) != nil {
+		return Known[key].Default
+	}
+	return v
+}
+
+// Channels azokat az ismert csatornákat adja vissza, ahol a bool kulcs igaz
+func (s *Store) Channels(key string) []string {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.displayNames))
+	for _, display := range s.displayNames {
+		names = append(names, display)
+	}
+	s.mu.RUnlock()
+
+	var result []string
+	for _, ch := range names {
+		if s.SettingBool(ch, key) {
+			result = append(result, ch)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Overrides egy csatorna futásidejű felülírásai "kulcs=érték" formában
+func (s *Store) Overrides(channel string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []string
+	for key, value := range s.overrides[strings.ToLower(channel)] {
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func copyMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package settings
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// A rétegek sorrendje: felülírás > csatorna > hálózat > global > régi
+// csatornamező > régi globális mező > alapértelmezés
+func TestLookupPrecedence(t *testing.T) {
+	// layers: a beállított rétegek, a legfelsőtől
+	newStore := func(t *testing.T, layers string) *Store {
+		has := func(layer string) bool { return strings.Contains(layers, layer) }
+		cfg := &config.Config{Server: "irc.test", Network: "libera"}
+		if has("legacy") {
+			cfg.NevnapChannels = []string{"#ynm"}
+			cfg.NevnapReggel = "06:00"
+		}
+		if has("global") {
+			cfg.Settings.Global = map[string]string{"nevnap.enabled": "global", "nevnap.morning": "07:00"}
+		}
+		if has("network") {
+			cfg.Settings.Networks = map[string]map[string]string{
+				"libera": {"nevnap.enabled": "network"},
+				"efnet":  {"nevnap.enabled": "más hálózat"},
+			}
+		}
+		if has("channel") {
+			cfg.Settings.Channels = map[string]map[string]string{"#YnM": {"nevnap.enabled": "channel"}}
+		}
+		s := NewStore(cfg, filepath.Join(t.TempDir(), "settings.json"))
+		if has("override") {
+			if err := s.Set("#ynm", "nevnap.enabled", "false"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return s
+	}
+
+	all := "override channel network global legacy"
+	tests := []struct {
+		name    string
+		layers  string
+		channel string
+		key     string
+		want    string
+		source  Source
+	}{
+		{"felülírás", all, "#ynm", "nevnap.enabled", "false", SourceOverride},
+		{"csatorna", "channel network global legacy", "#YNM", "nevnap.enabled", "channel", SourceChannel},
+		{"hálózat", "network global legacy", "#ynm", "nevnap.enabled", "network", SourceNetwork},
+		{"global", "global legacy", "#ynm", "nevnap.enabled", "global", SourceGlobal},
+		{"régi csatornamező", "legacy", "#ynm", "nevnap.enabled", "true", SourceLegacy},
+		{"alapértelmezés", "", "#ynm", "nevnap.enabled", "false", SourceDefault},
+		{"global a régi globális előtt", "global legacy", "#ynm", "nevnap.morning", "07:00", SourceGlobal},
+		{"régi globális mező", "legacy", "#ynm", "nevnap.morning", "06:00", SourceLegacy},
+		{"más csatorna", all, "#mas", "nevnap.enabled", "network", SourceNetwork},
+		{"csatorna nélkül", all, "", "nevnap.enabled", "network", SourceNetwork},
+		{"régi mező más csatornán", "legacy", "#mas", "nevnap.enabled", "false", SourceDefault},
+	}
+	for _, tt := range tests {
+		value, source := newStore(t, tt.layers).Lookup(tt.channel, tt.key)
+		if value != tt.want || source != tt.source {
+			t.Errorf("%s: %s = %q (%s), várt %q (%s)", tt.name, tt.key, value, source, tt.want, tt.source)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Admin egy owner/admin/VIP bejegyzés (a JSON tagek a régi owners/admins/vips.json mezői)
+type Admin struct {
+	Nick     string    `json:"nick"`
+	Hostmask string    `json:"hostmask"`
+	Level    int       `json:"level"`
+	AddedBy  string    `json:"added_by"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// AdminRepo az adminok táblája (nickenként egy szint)
+type AdminRepo struct {
+	db *sql.DB
+}
+
+// Get a nick bejegyzése (a nick pontos egyezésével)
+func (r *AdminRepo) Get(nick string) (Admin, bool, error) {
+	row := r.db.QueryRow(`SELECT nick, hostmask, level, added_by, added_at FROM admins WHERE nick = ?`, nick)
+	a, err := scanAdmin(row)
+	if err == sql.ErrNoRows {
+		return Admin{}, false, nil
+	}
+	return a, err == nil, err
+}
+
+// Put felveszi vagy felülírja a bejegyzést (szintváltáskor is)
+func (r *AdminRepo) Put(a Admin) error {
+	_, err := r.db.Exec(`INSERT INTO admins(nick, hostmask, level, added_by, added_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(nick) DO UPDATE SET hostmask = excluded.hostmask, level = excluded.level,
+			added_by = excluded.added_by, added_at = excluded.added_at`,
+		a.Nick, a.Hostmask, a.Level, a.AddedBy, unix(a.AddedAt))
+	return err
+}
+
+// Delete törli a nicket; false, ha nem szerepelt
+func (r *AdminRepo) Delete(nick string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM admins WHERE nick = ?`, nick)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Count az adott szintű bejegyzések száma
+func (r *AdminRepo) Count(level int) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM admins WHERE level = ?`, level).Scan(&n)
+	return n, err
+}
+
+// All minden bejegyzés, szint szerint csökkenő, azon belül nick szerint
+func (r *AdminRepo) All() ([]Admin, error) {
+	rows, err := r.db.Query(`SELECT nick, hostmask, level, added_by, added_at FROM admins
+		ORDER BY level DESC, nick COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Admin
+	for rows.Next() {
+		a, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+func scanAdmin(row rowScanner) (Admin, error) {
+	var a Admin
+	var added int64
+	err := row.Scan(&a.Nick, &a.Hostmask, &a.Level, &a.AddedBy, &added)
+	a.AddedAt = fromUnix(added)
+	return a, err
+}
@@ -0,0 +1,436 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ynmhu/YnM-Go/config"
+)
+
+// Sources a korábbi, külön tárolt adatok helye; az üres vagy nem létező
+// útvonalat az Import kihagyja
+type Sources struct {
+	AdminDir   string // owners.json, admins.json, vips.json
+	JokeStatus string // joke_status.json
+	SentDates  string // media_upload.sent_dates_file
+	Pets       string // kisallatok.json
+	MovieDB    string // movie_db_path
+	ReminderDB string // ora_db_file
+	SeenDB     string // seen.db
+	TellDB     string // tell.db
+}
+
+// LegacySources a régi fájlok helye a config alapján (ahová a korábbi verziók írtak)
+func LegacySources(cfg *config.Config) Sources {
+	reminderDB := cfg.OraDBFile
+	if reminderDB == "" {
+		reminderDB = cfg.DataPath("ora_reminders.db")
+	}
+	return Sources{
+		AdminDir:   filepath.Dir(cfg.DataPath("owners.json")),
+		JokeStatus: filepath.Join("data", "joke_status.json"),
+		SentDates:  cfg.MediaUpload.SentDatesFile,
+		Pets:       legacyPetsPath(cfg),
+		MovieDB:    cfg.MovieDBPath,
+		ReminderDB: reminderDB,
+		SeenDB:     cfg.DataPath("seen.db"),
+		TellDB:     cfg.DataPath("tell.db"),
+	}
+}
+
+// legacyPetsPath a Tamagotchi régi állapotfájlja. A közös adatkönyvtárban
+// keressük (data_dir); a korábbi verziók külön útvonalát (data_directory/data)
+// csak akkor, ha a fájl ott van meg.
+func legacyPetsPath(cfg *config.Config) string {
+	path := cfg.DataPath("kisallatok.json")
+	if _, err := os.Stat(path); err != nil {
+		old := filepath.Join(cfg.DataDirectory, "data", "kisallatok.json")
+		if _, err := os.Stat(old); err == nil {
+			return old
+		}
+	}
+	return path
+}
+
+// Imported egy átvett forrás
+type Imported struct {
+	Source string
+	Path   string
+	Rows   int
+}
+
+// importer egy forrás átvétele a tranzakcióban; a visszaadott szám az átvett sorok száma
+type importer func(tx *sql.Tx, path string) (int, error)
+
+// Import egyszer átveszi a régi fájlok és adatbázisok tartalmát. Minden forrás
+// külön tranzakció, és az imports táblába kerül, így a következő induláskor
+// már nem fut le újra. Az eredeti fájlok változatlanul megmaradnak.
+func (d *DB) Import(src Sources) ([]Imported, error) {
+	sources := []struct {
+		name string
+		path string
+		run  importer
+	}{
+		// az owner az első: ha egy nick több fájlban is szerepel, a magasabb szint marad
+		{"owners.json", joinDir(src.AdminDir, "owners.json"), importAdmins(3)},
+		{"admins.json", joinDir(src.AdminDir, "admins.json"), importAdmins(2)},
+		{"vips.json", joinDir(src.AdminDir, "vips.json"), importAdmins(1)},
+		{"joke_status.json", src.JokeStatus, importJokeStatus},
+		{"sent_dates.json", src.SentDates, importSentDates},
+		{"kisallatok.json", src.Pets, importPets},
+		{"movies.db", src.MovieDB, importMovies},
+		{"ora_reminders.db", src.ReminderDB, importReminders},
+		{"seen.db", src.SeenDB, importSeen},
+		{"tell.db", src.TellDB, importMemos},
+	}
+
+	var done []Imported
+	var errs []error
+	for _, s := range sources {
+		if s.path == "" || filepath.Clean(s.path) == filepath.Clean(d.path) {
+			continue
+		}
+		if _, err := os.Stat(s.path); err != nil {
+			continue
+		}
+		var n int
+		if err := d.db.QueryRow(`SELECT COUNT(*) FROM imports WHERE source = ?`, s.name).Scan(&n); err != nil {
+			return done, err
+		}
+		if n > 0 {
+			continue
+		}
+		rows, err := d.importOne(s.name, s.path, s.run)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", s.name, s.path, err))
+			continue
+		}
+		log.Printf("✅ Régi adatok átvéve: %s (%d sor), a fájl törölhető: %s", s.name, rows, s.path)
+		done = append(done, Imported{Source: s.name, Path: s.path, Rows: rows})
+	}
+	return done, errors.Join(errs...)
+}
+
+func (d *DB) importOne(name, path string, run importer) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	rows, err := run(tx, path)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`INSERT INTO imports(source, path, rows, imported_at) VALUES (?, ?, ?, ?)`,
+		name, path, rows, time.Now().Unix()); err != nil {
+		return 0, err
+	}
+	return rows, tx.Commit()
+}
+
+func joinDir(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// ───────────────────────────── JSON fájlok ─────────────────────────────
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// importAdmins egy szint fájlja (nick → bejegyzés)
+func importAdmins(level int) importer {
+	return func(tx *sql.Tx, path string) (int, error) {
+		var admins map[string]Admin
+		if err := readJSON(path, &admins); err != nil {
+			return 0, err
+		}
+		n := 0
+		for nick, a := range admins {
+			if a.Nick == "" {
+				a.Nick = nick
+			}
+			res, err := tx.Exec(`INSERT OR IGNORE INTO admins(nick, hostmask, level, added_by, added_at) VALUES (?, ?, ?, ?, ?)`,
+				a.Nick, a.Hostmask, level, a.AddedBy, unix(a.AddedAt))
+			if err != nil {
+				return n, err
+			}
+			n += affected(res)
+		}
+		return n, nil
+	}
+}
+
+func importJokeStatus(tx *sql.Tx, path string) (int, error) {
+	var status map[string]string
+	if err := readJSON(path, &status); err != nil {
+		return 0, err
+	}
+	if status["last_sent"] == "" {
+		return 0, nil
+	}
+	res, err := tx.Exec(`INSERT OR IGNORE INTO joke_status(id, last_sent, last_joke) VALUES (1, ?, ?)`,
+		status["last_sent"], status["last_joke"])
+	if err != nil {
+		return 0, err
+	}
+	return affected(res), nil
+}
+
+func importSentDates(tx *sql.Tx, path string) (int, error) {
+	var dates []string
+	if err := readJSON(path, &dates); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, created := range dates {
+		res, err := tx.Exec(`INSERT OR IGNORE INTO media_sent(created, sent_at) VALUES (?, 0)`, created)
+		if err != nil {
+			return n, err
+		}
+		n += affected(res)
+	}
+	return n, nil
+}
+
+func importPets(tx *sql.Tx, path string) (int, error) {
+	var pets map[string]json.RawMessage
+	if err := readJSON(path, &pets); err != nil {
+		return 0, err
+	}
+	n := 0
+	for channel, data := range pets {
+		if string(data) == "null" {
+			continue
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO pets(channel, data, updated_at) VALUES (?, ?, ?)`,
+			channel, string(data), time.Now().Unix())
+		if err != nil {
+			return n, err
+		}
+		n += affected(res)
+	}
+	return n, nil
+}
+
+// ───────────────────────────── Régi adatbázisok ─────────────────────────────
+
+// legacyQuery csak olvasásra nyitja meg a régi adatbázist, és lefuttatja a
+// lekérdezést soronként; hiányzó tábla esetén nincs sor
+func legacyQuery(path, table string, query func(columns map[string]bool) string, row func(scan func(...interface{}) error) error) error {
+	db, err := sql.Open("sqlite3", path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if len(columns) == 0 {
+		return nil
+	}
+
+	rows, err = db.Query(query(columns))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := row(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// column az oszlop neve, ha a régi séma már tartalmazta, különben az alapértelmezés
+func column(columns map[string]bool, name, fallback string) string {
+	if columns[name] {
+		return name
+	}
+	return fallback
+}
+
+func importMovies(tx *sql.Tx, path string) (int, error) {
+	n := 0
+	err := legacyQuery(path, "movies", func(columns map[string]bool) string {
+		// szövegként olvassuk: a CURRENT_TIMESTAMP UTC, a teljesítés ideje helyi idő
+		return `SELECT id, title, pin, requested_by, year, COALESCE(status, 'Nem'),
+			COALESCE(CAST(upload_date AS TEXT), ''), COALESCE(CAST(` + column(columns, "completed_date", "NULL") + ` AS TEXT), '')
+			FROM movies`
+	}, func(scan func(...interface{}) error) error {
+		var id int64
+		var year int
+		var title, pin, requester, status, uploaded, completed string
+		if err := scan(&id, &title, &pin, &requester, &year, &status, &uploaded, &completed); err != nil {
+			return err
+		}
+		var completedAt interface{}
+		if ts := legacyTime(completed, time.Local); ts != 0 {
+			completedAt = ts
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO movies(id, title, pin, requested_by, year, status, requested_at, completed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, title, pin, requester, year, status, legacyTime(uploaded, time.UTC), completedAt)
+		if err != nil {
+			return err
+		}
+		n += affected(res)
+		return nil
+	})
+	return n, err
+}
+
+func importReminders(tx *sql.Tx, path string) (int, error) {
+	n := 0
+	err := legacyQuery(path, "reminders", func(columns map[string]bool) string {
+		return `SELECT id, nick, message, COALESCE(CAST(remind_at AS TEXT), ''),
+			COALESCE(CAST(` + column(columns, "created_at", "NULL") + ` AS TEXT), ''),
+			COALESCE(` + column(columns, "status", "NULL") + `, 'active')
+			FROM reminders`
+	}, func(scan func(...interface{}) error) error {
+		var id int64
+		var nick, message, remindAt, createdAt, status string
+		if err := scan(&id, &nick, &message, &remindAt, &createdAt, &status); err != nil {
+			return err
+		}
+		at := legacyTime(remindAt, time.Local)
+		if at == 0 {
+			return nil // időpont nélkül nem ütemezhető
+		}
+		// a created_at oszlop előtti sémában (vagy NULL értéknél) az átvétel ideje,
+		// ahogy egy utólag felvett oszlop kitöltése is tenné
+		created := legacyTime(createdAt, time.UTC)
+		if created == 0 {
+			created = time.Now().Unix()
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO reminders(id, nick, message, remind_at, created_at, status)
+			VALUES (?, ?, ?, ?, ?, ?)`, id, nick, message, at, created, status)
+		if err != nil {
+			return err
+		}
+		n += affected(res)
+		return nil
+	})
+	return n, err
+}
+
+func importSeen(tx *sql.Tx, path string) (int, error) {
+	n := 0
+	err := legacyQuery(path, "seen", func(map[string]bool) string {
+		return `SELECT ` + seenColumns + ` FROM seen`
+	}, func(scan func(...interface{}) error) error {
+		var s SeenRecord
+		var ts int64
+		if err := scan(&s.Nick, &s.Channel, &s.Account, &s.Host, &s.Mask, &s.Action, &s.Text, &s.Other, &ts); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO seen(`+seenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			s.Nick, s.Channel, s.Account, s.Host, s.Mask, s.Action, s.Text, s.Other, ts)
+		if err != nil {
+			return err
+		}
+		n += affected(res)
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	err = legacyQuery(path, "seen_optout", func(map[string]bool) string {
+		return `SELECT key, nick, ts FROM seen_optout`
+	}, func(scan func(...interface{}) error) error {
+		var key, nick string
+		var ts int64
+		if err := scan(&key, &nick, &ts); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO seen_optout(key, nick, ts) VALUES (?, ?, ?)`, key, nick, ts)
+		if err != nil {
+			return err
+		}
+		n += affected(res)
+		return nil
+	})
+	return n, err
+}
+
+func importMemos(tx *sql.Tx, path string) (int, error) {
+	n := 0
+	err := legacyQuery(path, "memos", func(map[string]bool) string {
+		return `SELECT id, sender, sender_account, target, target_account, text, private, created FROM memos`
+	}, func(scan func(...interface{}) error) error {
+		var m Memo
+		var created int64
+		if err := scan(&m.ID, &m.Sender, &m.SenderAccount, &m.Target, &m.TargetAccount, &m.Text, &m.Private, &created); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`INSERT OR IGNORE INTO memos(id, sender, sender_account, target, target_account, text, private, created)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, m.Sender, m.SenderAccount, m.Target, m.TargetAccount, m.Text, m.Private, created)
+		if err != nil {
+			return err
+		}
+		n += affected(res)
+		return nil
+	})
+	return n, err
+}
+
+// legacyTime a régi szöveges időpont Unix időként (0, ha üres vagy "N/A").
+// Időzóna nélküli értéknél a loc szerint értelmezzük.
+func legacyTime(s string, loc *time.Location) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "N/A" {
+		return 0
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Unix()
+	}
+	layouts := []string{
+		"2006-01-02 15:04:05.999999999-07:00", // a go-sqlite3 így írja a time.Time értéket
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.Unix()
+		}
+	}
+	return 0
+}
+
+func affected(res sql.Result) int {
+	n, _ := res.RowsAffected()
+	return int(n)
+}
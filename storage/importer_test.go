@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// A legrégebbi ora_reminders.db séma: még nincs status és created_at oszlop
+func TestImportLegacyReminders(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "ora_reminders.db")
+	old, err := sql.Open("sqlite3", legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE reminders (id INTEGER PRIMARY KEY AUTOINCREMENT, nick TEXT, message TEXT, remind_at DATETIME)`,
+		`INSERT INTO reminders(nick, message, remind_at) VALUES ('alice', 'tea', '2025-03-01 18:30:00')`,
+		`INSERT INTO reminders(nick, message, remind_at) VALUES ('bob', 'időpont nélkül', NULL)`,
+	} {
+		if _, err := old.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old.Close()
+
+	db, err := Open(filepath.Join(dir, "ynm.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	before := time.Now().Add(-time.Second)
+	imported, err := db.Import(Sources{ReminderDB: legacy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 1 || imported[0].Rows != 1 {
+		t.Fatalf("átvett források: %+v", imported)
+	}
+
+	active, err := db.Reminders.Active()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("aktív emlékeztetők: %+v", active)
+	}
+	r := active[0]
+	want := time.Date(2025, 3, 1, 18, 30, 0, 0, time.Local)
+	if r.Nick != "alice" || !r.RemindAt.Equal(want) {
+		t.Errorf("emlékeztető: %+v", r)
+	}
+	if r.CreatedAt.Before(before) {
+		t.Errorf("a hiányzó created_at nincs kitöltve: %v", r.CreatedAt)
+	}
+
+	// a második indulás már nem vesz át semmit
+	if imported, err := db.Import(Sources{ReminderDB: legacy}); err != nil || len(imported) != 0 {
+		t.Errorf("ismételt átvétel: %+v, %v", imported, err)
+	}
+}
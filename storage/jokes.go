@@ -0,0 +1,25 @@
+package storage
+
+import "database/sql"
+
+// JokeRepo a napi vicc állapota: melyik napon ment ki utoljára, és mi volt az
+// (aznap minden csatorna ugyanazt kapja)
+type JokeRepo struct {
+	db *sql.DB
+}
+
+// Last az utolsó küldés napja (ÉÉÉÉ-HH-NN) és a vicc; üres, ha még nem volt
+func (r *JokeRepo) Last() (day, joke string, err error) {
+	err = r.db.QueryRow(`SELECT last_sent, last_joke FROM joke_status WHERE id = 1`).Scan(&day, &joke)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	return day, joke, err
+}
+
+// SetLast elmenti a napi viccet
+func (r *JokeRepo) SetLast(day, joke string) error {
+	_, err := r.db.Exec(`INSERT INTO joke_status(id, last_sent, last_joke) VALUES (1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET last_sent = excluded.last_sent, last_joke = excluded.last_joke`, day, joke)
+	return err
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// LanguageRepo a felhasználók saját nyelve (!lang); a bejelentkezett
+// felhasználóé a fiókjához, a többieké a nickjükhöz kötődik
+type LanguageRepo struct {
+	db *sql.DB
+}
+
+// languageKeys a keresés sorrendje: előbb a fiók, aztán a nick
+func languageKeys(nick, account string) []string {
+	var keys []string
+	if account != "" && account != "*" {
+		keys = append(keys, "account:"+strings.ToLower(account))
+	}
+	if nick != "" {
+		keys = append(keys, "nick:"+strings.ToLower(nick))
+	}
+	return keys
+}
+
+// Get a felhasználó nyelve ("" ha nem állított be)
+func (r *LanguageRepo) Get(nick, account string) (string, error) {
+	for _, key := range languageKeys(nick, account) {
+		var lang string
+		err := r.db.QueryRow(`SELECT lang FROM languages WHERE key = ?`, key).Scan(&lang)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return lang, nil
+	}
+	return "", nil
+}
+
+// Set beállítja a nyelvet (a fiókhoz, ha ismert, különben a nickhez)
+func (r *LanguageRepo) Set(nick, account, lang string, at time.Time) error {
+	keys := languageKeys(nick, account)
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(`INSERT INTO languages(key, lang, ts) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET lang = excluded.lang, ts = excluded.ts`, keys[0], lang, at.Unix())
+	return err
+}
+
+// Delete törli a felhasználó nyelvét (a fiókhoz és a nickhez kötöttet is);
+// false, ha nem volt beállítva
+func (r *LanguageRepo) Delete(nick, account string) (bool, error) {
+	var total int64
+	for _, key := range languageKeys(nick, account) {
+		res, err := r.db.Exec(`DELETE FROM languages WHERE key = ?`, key)
+		if err != nil {
+			return false, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+		total += n
+	}
+	return total > 0, nil
+}
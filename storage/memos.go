@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Memo egy átadásra váró !tell üzenet
+type Memo struct {
+	ID            int64
+	Sender        string
+	SenderAccount string
+	Target        string
+	TargetAccount string // a címzett fiókja, ha a felvételkor ismert volt
+	Text          string
+	Private       bool // privátban kell átadni (különben ott, ahol a címzett felbukkan)
+	Created       time.Time
+}
+
+// MemoRepo a !tell üzenetek
+type MemoRepo struct {
+	db *sql.DB
+}
+
+const memoColumns = `id, sender, sender_account, target, target_account, text, private, created`
+
+// Add felveszi az üzenetet, és visszaadja az azonosítóját
+func (r *MemoRepo) Add(m Memo) (int64, error) {
+	res, err := r.db.Exec(`INSERT INTO memos(sender, sender_account, target, target_account, text, private, created)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, m.Sender, m.SenderAccount, m.Target, m.TargetAccount, m.Text, m.Private, m.Created.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Counts a címzett (nick vagy fiók) várakozó üzeneteinek száma, és ebből a feladótól származók
+func (r *MemoRepo) Counts(target, targetAccount, sender string) (inbox, fromSender int, err error) {
+	err = r.db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(sender = ? COLLATE NOCASE), 0) FROM memos
+		WHERE target = ? OR (target_account != '' AND target_account = ?)`,
+		sender, target, targetAccount).Scan(&inbox, &fromSender)
+	return inbox, fromSender, err
+}
+
+// Pending a nicknek vagy a fiókjának szóló üzenetek, régebbi elöl. A fiókhoz
+// kötött üzenetet csak a fiókba belépett címzett kapja meg, a nick önmagában kevés.
+func (r *MemoRepo) Pending(nick, account string) ([]Memo, error) {
+	return r.list(`SELECT `+memoColumns+` FROM memos
+		WHERE (target_account = '' AND target = ?) OR (target_account != '' AND target_account = ?) ORDER BY id`, nick, account)
+}
+
+// Outgoing a feladó által küldött, még át nem adott üzenetek (lásd memoOwner)
+func (r *MemoRepo) Outgoing(nick, account string) ([]Memo, error) {
+	return r.list(`SELECT `+memoColumns+` FROM memos WHERE `+memoOwner+` ORDER BY id`, account, nick)
+}
+
+// Delete törli az (átadott) üzenetet
+func (r *MemoRepo) Delete(id int64) error {
+	_, err := r.db.Exec(`DELETE FROM memos WHERE id = ?`, id)
+	return err
+}
+
+// CancelID a feladó (nick vagy fiók) egy üzenetét törli; a törölt sorok száma
+func (r *MemoRepo) CancelID(id int64, nick, account string) (int64, error) {
+	return r.exec(`DELETE FROM memos WHERE id = ? AND `+memoOwner, id, account, nick)
+}
+
+// CancelTarget a feladó egy címzettnek szóló összes üzenetét törli
+func (r *MemoRepo) CancelTarget(target, nick, account string) (int64, error) {
+	return r.exec(`DELETE FROM memos WHERE target = ? AND `+memoOwner, target, account, nick)
+}
+
+// PurgeBefore törli a cutoff előtt felvett (túl régóta átadatlan) üzeneteket
+func (r *MemoRepo) PurgeBefore(cutoff time.Time) error {
+	_, err := r.db.Exec(`DELETE FROM memos WHERE created < ?`, cutoff.Unix())
+	return err
+}
+
+// memoOwner a feladó szűrője (paraméterei: fiók, nick). A fiókból küldött
+// üzenet csak azé a fióké, a nick önmagában kevés; fiók nélkül a nick dönt.
+const memoOwner = `((sender_account != '' AND sender_account = ?) OR (sender_account = '' AND sender = ?))`
+
+func (r *MemoRepo) exec(query string, args ...interface{}) (int64, error) {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *MemoRepo) list(query string, args ...interface{}) ([]Memo, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var memos []Memo
+	for rows.Next() {
+		var m Memo
+		var created int64
+		if err := rows.Scan(&m.ID, &m.Sender, &m.SenderAccount, &m.Target, &m.TargetAccount, &m.Text, &m.Private, &created); err != nil {
+			return nil, err
+		}
+		m.Created = time.Unix(created, 0)
+		memos = append(memos, m)
+	}
+	return memos, rows.Err()
+}
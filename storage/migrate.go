@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// migration egy sémaváltozás. A már kiadott migrációkat nem szabad módosítani;
+// minden változás új, nagyobb sorszámú bejegyzés a lista végén.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+var migrations = []migration{
+	{1, "admins", `
+		CREATE TABLE admins (
+			nick     TEXT PRIMARY KEY,
+			hostmask TEXT NOT NULL DEFAULT '',
+			level    INTEGER NOT NULL,
+			added_by TEXT NOT NULL DEFAULT '',
+			added_at INTEGER NOT NULL DEFAULT 0
+		);`},
+	{2, "movies", `
+		CREATE TABLE movies (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			title        TEXT NOT NULL,
+			pin          TEXT NOT NULL UNIQUE,
+			year         INTEGER NOT NULL,
+			requested_by TEXT NOT NULL,
+			status       TEXT NOT NULL DEFAULT 'Nem',
+			requested_at INTEGER NOT NULL DEFAULT 0,
+			completed_at INTEGER
+		);
+		CREATE INDEX movies_title ON movies(title);
+		CREATE INDEX movies_status ON movies(status);`},
+	{3, "reminders", `
+		CREATE TABLE reminders (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			nick       TEXT NOT NULL,
+			message    TEXT NOT NULL,
+			remind_at  INTEGER NOT NULL,
+			created_at INTEGER NOT NULL DEFAULT 0,
+			status     TEXT NOT NULL DEFAULT 'active'
+		);
+		CREATE INDEX reminders_status ON reminders(status);`},
+	{4, "joke_status", `
+		CREATE TABLE joke_status (
+			id        INTEGER PRIMARY KEY CHECK (id = 1),
+			last_sent TEXT NOT NULL DEFAULT '',
+			last_joke TEXT NOT NULL DEFAULT ''
+		);`},
+	{5, "media_sent", `
+		CREATE TABLE media_sent (
+			created TEXT PRIMARY KEY,
+			sent_at INTEGER NOT NULL DEFAULT 0
+		);`},
+	{6, "pets", `
+		CREATE TABLE pets (
+			channel    TEXT PRIMARY KEY,
+			data       TEXT NOT NULL,
+			updated_at INTEGER NOT NULL DEFAULT 0
+		);`},
+	{7, "seen", `
+		CREATE TABLE seen (
+			nick    TEXT NOT NULL COLLATE NOCASE,
+			channel TEXT NOT NULL COLLATE NOCASE,
+			account TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+			host    TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+			mask    TEXT NOT NULL DEFAULT '',
+			action  TEXT NOT NULL,
+			text    TEXT NOT NULL DEFAULT '',
+			other   TEXT NOT NULL DEFAULT '',
+			ts      INTEGER NOT NULL,
+			PRIMARY KEY (nick, channel)
+		);
+		CREATE INDEX seen_host ON seen(host);
+		CREATE INDEX seen_account ON seen(account);
+		CREATE TABLE seen_optout (
+			key  TEXT PRIMARY KEY COLLATE NOCASE, -- "account:fiók" vagy "nick:alice!user@host"
+			nick TEXT NOT NULL DEFAULT '' COLLATE NOCASE, -- csak a !seen válaszához
+			ts   INTEGER NOT NULL
+		);
+		CREATE INDEX seen_optout_nick ON seen_optout(nick);`},
+	{8, "memos", `
+		CREATE TABLE memos (
+			id             INTEGER PRIMARY KEY AUTOINCREMENT,
+			sender         TEXT NOT NULL,
+			sender_account TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+			target         TEXT NOT NULL COLLATE NOCASE,
+			target_account TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+			text           TEXT NOT NULL,
+			private        INTEGER NOT NULL DEFAULT 0,
+			created        INTEGER NOT NULL
+		);
+		CREATE INDEX memos_target ON memos(target);
+		CREATE INDEX memos_target_account ON memos(target_account);`},
+	{9, "imports", `
+		CREATE TABLE imports (
+			source      TEXT PRIMARY KEY, -- pl. "admins.json", "movies.db"
+			path        TEXT NOT NULL,
+			rows        INTEGER NOT NULL,
+			imported_at INTEGER NOT NULL
+		);`},
+	{10, "languages", `
+		CREATE TABLE languages (
+			key  TEXT PRIMARY KEY COLLATE NOCASE, -- "nick:alice" vagy "account:fiók"
+			lang TEXT NOT NULL,
+			ts   INTEGER NOT NULL
+		);`},
+}
+
+// LatestVersion a program által ismert legújabb séma
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate sorban lefuttatja a még nem alkalmazott migrációkat, mindegyiket
+// külön tranzakcióban
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+	current, err := currentVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > LatestVersion() {
+		return fmt.Errorf("az adatbázis sémája (%d) újabb, mint amit ez a verzió ismer (%d)", current, LatestVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := apply(db, m); err != nil {
+			return fmt.Errorf("migráció %d (%s): %w", m.version, m.name, err)
+		}
+		log.Printf("✅ Adatbázis migráció: %d (%s)", m.version, m.name)
+	}
+	return nil
+}
+
+func apply(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations(version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().Unix()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func currentVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// CheckFile ellenőrzi egy (pl. mentésből visszaállítandó) adatbázis fájl
+// épségét, és visszaadja a séma verzióját. Az ennél a verziónál újabb sémájú
+// adatbázist elutasítja; a régebbit a következő Open frissíti.
+func CheckFile(path string) (int, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil {
+		return 0, err
+	}
+	if result != "ok" {
+		return 0, fmt.Errorf("sérült adatbázis: %s", result)
+	}
+	version, err := currentVersion(db)
+	if err != nil {
+		return 0, fmt.Errorf("nem a bot adatbázisa: %w", err)
+	}
+	if version > LatestVersion() {
+		return version, fmt.Errorf("az adatbázis sémája (%d) újabb, mint amit ez a verzió ismer (%d)", version, LatestVersion())
+	}
+	return version, nil
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// a movies.status értékei (az Elutasítva állapotot kézzel vagy külső eszköz állítja)
+const (
+	MoviePending   = "Nem"
+	MovieCompleted = "Igen"
+	MovieRejected  = "Elutasítva"
+)
+
+// Movie egy filmkérés
+type Movie struct {
+	ID            int64
+	Title         string
+	PIN           string
+	RequestedBy   string
+	Year          int
+	Status        string
+	UploadDate    time.Time  // a kérés ideje
+	CompletedDate *time.Time // nil, ha még nincs teljesítve
+}
+
+// MovieRepo a filmkérések (!kell, !keresek, !ok, !del és a webes lista)
+type MovieRepo struct {
+	db *sql.DB
+}
+
+const movieColumns = `id, title, pin, requested_by, year, status, requested_at, completed_at`
+
+// Add új, függő kérést vesz fel
+func (r *MovieRepo) Add(title, pin, requester string, year int) error {
+	_, err := r.db.Exec(`INSERT INTO movies(title, pin, requested_by, year, status, requested_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		title, pin, requester, year, MoviePending, time.Now().Unix())
+	return err
+}
+
+// ByPIN a PIN-hez tartozó kérés (nil, ha nincs)
+func (r *MovieRepo) ByPIN(pin string) (*Movie, error) {
+	return r.one(`SELECT `+movieColumns+` FROM movies WHERE pin = ?`, pin)
+}
+
+// ByTitle a címmel (pontos egyezés) felvett első kérés (nil, ha nincs)
+func (r *MovieRepo) ByTitle(title string) (*Movie, error) {
+	return r.one(`SELECT `+movieColumns+` FROM movies WHERE title = ? ORDER BY id LIMIT 1`, title)
+}
+
+// PINs a már kiadott PIN-ek
+func (r *MovieRepo) PINs() ([]string, error) {
+	rows, err := r.db.Query(`SELECT pin FROM movies`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var pins []string
+	for rows.Next() {
+		var pin string
+		if err := rows.Scan(&pin); err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	return pins, rows.Err()
+}
+
+// List a kérések a kérés ideje szerint növekvő sorrendben; üres status: mind
+func (r *MovieRepo) List(status string) ([]Movie, error) {
+	query := `SELECT ` + movieColumns + ` FROM movies`
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(query+` ORDER BY requested_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Movie
+	for rows.Next() {
+		m, err := scanMovie(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// Complete teljesítettnek jelöli a kérést; false, ha nincs ilyen PIN
+func (r *MovieRepo) Complete(pin string, at time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE movies SET status = ?, completed_at = ? WHERE pin = ?`, MovieCompleted, at.Unix(), pin)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Delete törli a kérést; false, ha nincs ilyen PIN
+func (r *MovieRepo) Delete(pin string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM movies WHERE pin = ?`, pin)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *MovieRepo) one(query string, args ...interface{}) (*Movie, error) {
+	m, err := scanMovie(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func scanMovie(row rowScanner) (Movie, error) {
+	var m Movie
+	var requested int64
+	var completed sql.NullInt64
+	err := row.Scan(&m.ID, &m.Title, &m.PIN, &m.RequestedBy, &m.Year, &m.Status, &requested, &completed)
+	m.UploadDate = fromUnix(requested)
+	if completed.Valid && completed.Int64 != 0 {
+		t := time.Unix(completed.Int64, 0)
+		m.CompletedDate = &t
+	}
+	return m, err
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PetRepo a csatornánkénti kisállatok; az állapotot a plugin JSON-ként adja át
+type PetRepo struct {
+	db *sql.DB
+}
+
+// All csatorna → kisállat JSON
+func (r *PetRepo) All() (map[string][]byte, error) {
+	rows, err := r.db.Query(`SELECT channel, data FROM pets`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	pets := make(map[string][]byte)
+	for rows.Next() {
+		var channel string
+		var data []byte
+		if err := rows.Scan(&channel, &data); err != nil {
+			return nil, err
+		}
+		pets[channel] = data
+	}
+	return pets, rows.Err()
+}
+
+// SaveAll egy tranzakcióban lecseréli az összes kisállatot
+func (r *PetRepo) SaveAll(pets map[string][]byte) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM pets`); err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for channel, data := range pets {
+		if _, err := tx.Exec(`INSERT INTO pets(channel, data, updated_at) VALUES (?, ?, ?)`, channel, string(data), now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
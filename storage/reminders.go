@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Reminder egy !ora emlékeztető
+type Reminder struct {
+	ID        int64
+	Nick      string
+	Message   string
+	RemindAt  time.Time
+	CreatedAt time.Time
+	Expired   bool // már elküldve
+}
+
+// ReminderRepo az emlékeztetők
+type ReminderRepo struct {
+	db *sql.DB
+}
+
+const reminderColumns = `id, nick, message, remind_at, created_at, status`
+
+// Add felveszi az emlékeztetőt, és visszaadja az azonosítóját
+func (r *ReminderRepo) Add(nick, message string, remindAt, createdAt time.Time) (int64, error) {
+	res, err := r.db.Exec(`INSERT INTO reminders(nick, message, remind_at, created_at) VALUES (?, ?, ?, ?)`,
+		nick, message, remindAt.Unix(), createdAt.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Active a még el nem küldött emlékeztetők
+func (r *ReminderRepo) Active() ([]Reminder, error) {
+	return r.list(`SELECT ` + reminderColumns + ` FROM reminders WHERE status = 'active' ORDER BY remind_at`)
+}
+
+// All minden emlékeztető az esedékesség szerint
+func (r *ReminderRepo) All() ([]Reminder, error) {
+	return r.list(`SELECT ` + reminderColumns + ` FROM reminders ORDER BY remind_at`)
+}
+
+// Get az azonosítóhoz tartozó emlékeztető (nil, ha nincs)
+func (r *ReminderRepo) Get(id int64) (*Reminder, error) {
+	rem, err := scanReminder(r.db.QueryRow(`SELECT `+reminderColumns+` FROM reminders WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rem, nil
+}
+
+// Expire elküldöttnek jelöli az emlékeztetőt
+func (r *ReminderRepo) Expire(id int64) error {
+	_, err := r.db.Exec(`UPDATE reminders SET status = 'expired' WHERE id = ?`, id)
+	return err
+}
+
+// Delete törli az emlékeztetőt; false, ha nem létezett
+func (r *ReminderRepo) Delete(id int64) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM reminders WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *ReminderRepo) list(query string) ([]Reminder, error) {
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Reminder
+	for rows.Next() {
+		rem, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rem)
+	}
+	return list, rows.Err()
+}
+
+func scanReminder(row rowScanner) (Reminder, error) {
+	var rem Reminder
+	var remindAt, createdAt int64
+	var status string
+	err := row.Scan(&rem.ID, &rem.Nick, &rem.Message, &remindAt, &createdAt, &status)
+	rem.RemindAt = fromUnix(remindAt)
+	rem.CreatedAt = fromUnix(createdAt)
+	if rem.CreatedAt.IsZero() {
+		rem.CreatedAt = rem.RemindAt // a régi sorokban nincs beállítási idő
+	}
+	rem.Expired = status != "active"
+	return rem, err
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// SeenRecord egy nick utolsó tevékenysége egy csatornán (QUIT és NICK esetén csatorna nélkül)
+type SeenRecord struct {
+	Nick    string
+	Channel string
+	Account string
+	Host    string // user@host (a "~" nélkül), a nickek csoportosításához
+	Mask    string // nick!user@host
+	Action  string // message, action, join, part, quit, nick, renamed, kick
+	Text    string // üzenet vagy indok
+	Other   string // nick: az új nick, renamed: a korábbi nick, kick: aki kirúgta
+	Time    time.Time
+}
+
+// SeenRepo a !seen nyilvántartása és a kimaradást kérők listája
+type SeenRepo struct {
+	db *sql.DB
+}
+
+const seenColumns = `nick, channel, account, host, mask, action, text, other, ts`
+
+// Put felveszi vagy felülírja a nick bejegyzését az adott csatornán
+func (r *SeenRepo) Put(s SeenRecord) error {
+	_, err := r.db.Exec(`INSERT INTO seen(`+seenColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(nick, channel) DO UPDATE SET account = excluded.account, host = excluded.host,
+			mask = excluded.mask, action = excluded.action, text = excluded.text,
+			other = excluded.other, ts = excluded.ts`,
+		s.Nick, s.Channel, s.Account, s.Host, s.Mask, s.Action, s.Text, s.Other, s.Time.Unix())
+	return err
+}
+
+// Account a nick legutóbb ismert fiókja ("" ha nincs)
+func (r *SeenRepo) Account(nick string) (string, error) {
+	var account string
+	err := r.db.QueryRow(`SELECT account FROM seen WHERE nick = ? AND account != '' ORDER BY ts DESC LIMIT 1`, nick).Scan(&account)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return account, err
+}
+
+// optOutFilter kizárja a kimaradást kérők sorait (a seen tábla álneve s):
+// fiók, illetve nick!user@host szerint
+const optOutFilter = `NOT EXISTS (SELECT 1 FROM seen_optout o WHERE
+	(s.account != '' AND o.key = 'account:' || s.account)
+	OR o.key = 'nick:' || s.nick || '!' || s.host)`
+
+// Latest a nickek közül a legutóbbi bejegyzés (bármely csatornán)
+func (r *SeenRepo) Latest(nicks ...string) (SeenRecord, bool, error) {
+	if len(nicks) == 0 {
+		return SeenRecord{}, false, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(nicks)), ", ")
+	args := make([]interface{}, len(nicks))
+	for i, n := range nicks {
+		args[i] = n
+	}
+	return r.one(`SELECT `+seenColumns+` FROM seen s WHERE nick IN (`+placeholders+`) AND `+optOutFilter+`
+		ORDER BY ts DESC LIMIT 1`, args...)
+}
+
+// LatestInGroup a személy (azonos fiók vagy user@host) más nickjeinek legutóbbi bejegyzése
+func (r *SeenRepo) LatestInGroup(nick, account, host string) (SeenRecord, bool, error) {
+	if account == "" && host == "" {
+		return SeenRecord{}, false, nil
+	}
+	return r.one(`SELECT `+seenColumns+` FROM seen s
+		WHERE nick != ? AND ((account != '' AND account = ?) OR (host != '' AND host = ?)) AND `+optOutFilter+`
+		ORDER BY ts DESC LIMIT 1`, nick, account, host)
+}
+
+// WithMask a maszkkal rendelkező bejegyzések, a legfrissebb elöl (maszkos kereséshez)
+func (r *SeenRepo) WithMask() ([]SeenRecord, error) {
+	rows, err := r.db.Query(`SELECT ` + seenColumns + ` FROM seen s WHERE mask != '' AND ` + optOutFilter + `
+		ORDER BY ts DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []SeenRecord
+	for rows.Next() {
+		s, err := scanSeen(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// OptOutKey a kimaradás kulcsa: a fiók, ha ismert, különben a nick és a
+// user@host együtt, így más nem jelentheti ki (és vissza) a felhasználót
+func OptOutKey(nick, account, host string) string {
+	if account != "" {
+		return "account:" + strings.ToLower(account)
+	}
+	return "nick:" + strings.ToLower(nick) + "!" + strings.ToLower(host)
+}
+
+// OptedOut igaz, ha a felhasználó (fiók, különben nick!user@host szerint) kimaradást kért
+func (r *SeenRepo) OptedOut(nick, account, host string) (bool, error) {
+	var out bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM seen_optout WHERE key = ?)`,
+		OptOutKey(nick, account, host)).Scan(&out)
+	return out, err
+}
+
+// NickOptedOut igaz, ha valaki ezen a nicken kérte a kimaradást (a !seen válaszához)
+func (r *SeenRepo) NickOptedOut(nick string) (bool, error) {
+	var out bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM seen_optout WHERE nick = ?)`, nick).Scan(&out)
+	return out, err
+}
+
+// OptOut felveszi a kimaradást, és törli a felhasználó saját bejegyzéseit:
+// fiók esetén a fiókhoz tartozókat, különben az azonos nick és host sorait
+func (r *SeenRepo) OptOut(nick, account, host string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`INSERT INTO seen_optout(key, nick, ts) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET nick = excluded.nick`, OptOutKey(nick, account, host), nick, at.Unix())
+	if err != nil {
+		return err
+	}
+	if account != "" {
+		_, err = tx.Exec(`DELETE FROM seen WHERE account = ?`, account)
+	} else {
+		_, err = tx.Exec(`DELETE FROM seen WHERE nick = ? AND host = ?`, nick, host)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// OptIn törli a felhasználó kimaradását
+func (r *SeenRepo) OptIn(nick, account, host string) error {
+	_, err := r.db.Exec(`DELETE FROM seen_optout WHERE key = ?`, OptOutKey(nick, account, host))
+	return err
+}
+
+func (r *SeenRepo) one(query string, args ...interface{}) (SeenRecord, bool, error) {
+	s, err := scanSeen(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return SeenRecord{}, false, nil
+	}
+	return s, err == nil, err
+}
+
+func scanSeen(row rowScanner) (SeenRecord, error) {
+	var s SeenRecord
+	var ts int64
+	err := row.Scan(&s.Nick, &s.Channel, &s.Account, &s.Host, &s.Mask, &s.Action, &s.Text, &s.Other, &ts)
+	s.Time = time.Unix(ts, 0)
+	return s, err
+}
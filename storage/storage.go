@@ -0,0 +1,153 @@
+// ============================================================================
+//  Szerzői jog © 2025 Markus (markus@ynm.hu)
+//  https://ynm.hu   – főoldal
+//  https://forum.ynm.hu   – hivatalos fórum
+//  https://bot.ynm.hu     – bot oldala és dokumentáció
+//
+//  Minden jog fenntartva. A kód Markus tulajdona, tilos terjeszteni vagy
+//  módosítani a szerző írásos engedélye nélkül.
+//
+//  Ez a fájl a YnM-Go IRC-bot rendszerének része.
+// ============================================================================
+
+// Package storage a bot saját adatainak közös tárolója: egyetlen SQLite
+// adatbázis (WAL módban) verziózott sémamigrációkkal, és területenként egy
+// típusos repository (adminok, filmkérések, emlékeztetők, napi vicc, feltöltés
+// bejelentések, kisállatok, seen, tell, a felhasználók nyelve). A pluginok a
+// pluginapi.Context-en át kapják meg a sajátjukat. A korábbi JSON fájlokat és
+// külön adatbázisokat az Import egyszer átveszi.
+//
+// Az időpontok Unix másodpercként (INTEGER) tárolódnak.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// DB a megnyitott adatbázis a repository-kkal
+type DB struct {
+	db   *sql.DB
+	path string
+
+	Admins    *AdminRepo
+	Movies    *MovieRepo
+	Reminders *ReminderRepo
+	Jokes     *JokeRepo
+	Uploads   *UploadRepo
+	Pets      *PetRepo
+	Seen      *SeenRepo
+	Memos     *MemoRepo
+	Languages *LanguageRepo
+}
+
+// Open megnyitja (létrehozza) az adatbázist, és lefuttatja a hiányzó migrációkat
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	// _txlock=immediate: az írási tranzakciók rögtön zárolnak, így két
+	// párhuzamos írás nem akad össze SQLITE_BUSY-val a zárolás emelésekor
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("adatbázis (%s): %w", path, err)
+	}
+
+	return &DB{
+		db:        db,
+		path:      path,
+		Admins:    &AdminRepo{db: db},
+		Movies:    &MovieRepo{db: db},
+		Reminders: &ReminderRepo{db: db},
+		Jokes:     &JokeRepo{db: db},
+		Uploads:   &UploadRepo{db: db},
+		Pets:      &PetRepo{db: db},
+		Seen:      &SeenRepo{db: db},
+		Memos:     &MemoRepo{db: db},
+		Languages: &LanguageRepo{db: db},
+	}, nil
+}
+
+// Path az adatbázis fájl helye
+func (d *DB) Path() string {
+	return d.path
+}
+
+// Version az utolsó lefutott migráció száma
+func (d *DB) Version() (int, error) {
+	return currentVersion(d.db)
+}
+
+// Backup az SQLite online mentési API-jával konzisztens másolatot ír a target
+// fájlba; a bot futása közben is használható. A target nem létezhet.
+func (d *DB) Backup(target string) error {
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s már létezik", target)
+	}
+	dst, err := sql.Open("sqlite3", target)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	ctx := context.Background()
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+	srcConn, err := d.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return dstConn.Raw(func(dc interface{}) error {
+		return srcConn.Raw(func(sc interface{}) error {
+			b, err := dc.(*sqlite3.SQLiteConn).Backup("main", sc.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err := b.Step(-1); err != nil {
+				b.Finish()
+				return err
+			}
+			return b.Finish()
+		})
+	})
+}
+
+// Close lezárja az adatbázist
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// unix a tárolt időpont; a nulla idő 0
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// fromUnix a tárolt időpont; a 0 a nulla idő
+func fromUnix(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
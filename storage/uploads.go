@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UploadRepo a már bejelentett Jellyfin feltöltések (a DateCreated értékük szerint)
+type UploadRepo struct {
+	db *sql.DB
+}
+
+// Sent igaz, ha a feltöltést már bejelentettük
+func (r *UploadRepo) Sent(created string) (bool, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM media_sent WHERE created = ?`, created).Scan(&n)
+	return n > 0, err
+}
+
+// MarkSent bejelentettnek jelöli a feltöltést
+func (r *UploadRepo) MarkSent(created string) error {
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO media_sent(created, sent_at) VALUES (?, ?)`, created, time.Now().Unix())
+	return err
+}